@@ -0,0 +1,155 @@
+// Command reencrypt seals every stored credential to its row with the current master key. Run it after
+// adding a new master key and switching SECRET_KEY_VERSION to it, or once to encrypt credentials stored in
+// plaintext or sealed before values were bound to their row. The older key can be dropped from
+// SECRET_MASTER_KEYS when it finished. Plaintext values that merely look sealed are reported and encrypted,
+// sealed values that do not open are reported and left as they are.
+package main
+
+import (
+	"flag"
+	"strconv"
+
+	"gorm.io/gorm"
+	"site_builder_backend/configs"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/postgres"
+	"site_builder_backend/pkg/secret"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 100, "rows read per query")
+	dryRun := flag.Bool("dry-run", false, "count the values to re-encrypt without writing them")
+	flag.Parse()
+
+	l := logger.NewLoggerFromConfig("info", "console", "stdout")
+	if *batchSize < 1 {
+		l.Fatal("reencrypt: batch must be at least 1")
+	}
+
+	cfg, err := configs.NewConfig()
+	if err != nil {
+		l.Fatal("Config error: %s", err)
+	}
+	keyring, err := secret.Load(cfg.Secret.MasterKeys, cfg.Secret.KeyVersion)
+	if err != nil {
+		l.Fatal("reencrypt - secret.Load: %v", err)
+	}
+	secret.RegisterSerializer(keyring)
+
+	pg, err := postgres.New(cfg.PG.URL, l)
+	if err != nil {
+		l.Fatal("reencrypt - postgres.New: %v", err)
+	}
+	defer pg.Close()
+
+	r := &reencrypter{db: pg.DB, keyring: keyring, batchSize: *batchSize, dryRun: *dryRun, l: l}
+	for _, model := range []interface{}{&payment_entity.GatewayEntity{}, &user_entity.UserEntity{}} {
+		if err := r.run(model); err != nil {
+			l.Fatal("reencrypt: %v", err)
+		}
+	}
+}
+
+type reencrypter struct {
+	db        *gorm.DB
+	keyring   *secret.Keyring
+	batchSize int
+	dryRun    bool
+	l         *logger.ZapLogger
+}
+
+// run walks the table of the model by id and rewrites the values of its secret columns that are in
+// plaintext or sealed with an older master key. The raw values are read and written past the serializer.
+func (r *reencrypter) run(model interface{}) error {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	var columns []string
+	for _, field := range stmt.Schema.Fields {
+		if field.TagSettings["SERIALIZER"] == secret.SerializerName {
+			columns = append(columns, field.DBName)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	var lastId, rewritten, unreadable int64
+	for {
+		var rows []map[string]interface{}
+		err := r.db.Table(stmt.Schema.Table).
+			Select(append([]string{"Id"}, columns...)).
+			Where(`"Id" > ?`, lastId).
+			Order(`"Id"`).
+			Limit(r.batchSize).
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			lastId = toInt64(row["Id"])
+			updates := make(map[string]interface{})
+			for _, column := range columns {
+				stored := toString(row[column])
+				if r.keyring.Current(stored) {
+					continue
+				}
+				if secret.LooksSealed(stored) {
+					r.l.Warn("reencrypt - %s %d: %s is plaintext that looks sealed, encrypting it as it is", stmt.Schema.Table, lastId, column)
+				}
+				aad := secret.AdditionalData(stmt.Schema.Table, column, strconv.FormatInt(lastId, 10))
+				plaintext, err := r.keyring.Decrypt(stored, aad)
+				if err != nil {
+					r.l.Error("reencrypt - %s %d: %s does not open, left as it is: %v", stmt.Schema.Table, lastId, column, err)
+					unreadable++
+					continue
+				}
+				sealed, err := r.keyring.Encrypt(plaintext, aad)
+				if err != nil {
+					return err
+				}
+				updates[column] = sealed
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			rewritten++
+			if r.dryRun {
+				continue
+			}
+			if err := r.db.Table(stmt.Schema.Table).Where(`"Id" = ?`, lastId).UpdateColumns(updates).Error; err != nil {
+				return err
+			}
+		}
+		if len(rows) < r.batchSize {
+			break
+		}
+	}
+	r.l.Info("reencrypt - %s: %d rows to re-encrypt, %d values that do not open, dry run %t", stmt.Schema.Table, rewritten, unreadable, r.dryRun)
+	return nil
+}
+
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	}
+	return 0
+}
@@ -20,6 +20,19 @@ type (
 		Metrics       Metrics
 		Swagger       Swagger
 		JWT           JWT
+		Jobs          Jobs
+		Pricing       Pricing
+		Basket        Basket
+		Payment       Payment
+		Shipping      Shipping
+		Plan          Plan
+		Metering      Metering
+		Sms           Sms
+		Email         Email
+		Notification  Notification
+		Storage       Storage
+		Image         Image
+		Secret        Secret
 	}
 
 	// App -.
@@ -90,6 +103,146 @@ type (
 		RefreshTokenExpiration time.Duration `env:"JWT_REFRESH_TOKEN_EXPIRATION" envDefault:"720h"` // 30 days
 		Issuer                 string        `env:"JWT_ISSUER" envDefault:"site_builder_backend"`
 	}
+
+	// Pricing - Order in which product coupons and discount codes are applied to a line.
+	// When stacking is disabled only the offer saving the customer the most is applied.
+	Pricing struct {
+		StackingOrder []string `env:"PRICING_STACKING_ORDER" envDefault:"coupon,discount"`
+		AllowStacking bool     `env:"PRICING_ALLOW_STACKING" envDefault:"true"`
+	}
+
+	// Basket - Guest baskets live in Redis until they are merged at login or expire
+	Basket struct {
+		GuestTTL time.Duration `env:"BASKET_GUEST_TTL" envDefault:"720h"` // 30 days
+	}
+
+	// Payment - Gateways send customers back to the API at CallbackBaseUrl. The virtual gateway accepts
+	// every payment without charging anyone and is meant for local development only. Payments pending
+	// for longer than ReconcileAfter are settled with the gateway by the reconciler job.
+	Payment struct {
+		CallbackBaseUrl string        `env:"PAYMENT_CALLBACK_BASE_URL" envDefault:"http://localhost:8080"`
+		GatewayTimeout  time.Duration `env:"PAYMENT_GATEWAY_TIMEOUT" envDefault:"15s"`
+		VirtualGateway  bool          `env:"PAYMENT_VIRTUAL_GATEWAY" envDefault:"false"`
+		ReconcileAfter  time.Duration `env:"PAYMENT_RECONCILE_AFTER" envDefault:"1h"`
+	}
+
+	// Shipping - Shipping methods of the post provider are priced by the rate service at PostUrl, the
+	// provider is not offered while it is empty
+	Shipping struct {
+		PostUrl    string        `env:"SHIPPING_POST_URL"`
+		PostApiKey string        `env:"SHIPPING_POST_API_KEY"`
+		Timeout    time.Duration `env:"SHIPPING_TIMEOUT" envDefault:"10s"`
+	}
+
+	// Plan - Plan purchases and credit top-ups are paid to the gateways of the platform's own site
+	// PaymentSiteId, zero disables both. Owners are reminded ReminderBefore their plan expires.
+	Plan struct {
+		PaymentSiteId  int64         `env:"PLAN_PAYMENT_SITE_ID" envDefault:"0"`
+		ReminderBefore time.Duration `env:"PLAN_REMINDER_BEFORE" envDefault:"72h"`
+	}
+
+	// Metering - Reservations neither committed nor released within ReservationTTL are dropped, their credits
+	// come back once the cached counter is loaded from the database again after CounterTTL.
+	Metering struct {
+		ReservationTTL time.Duration `env:"METERING_RESERVATION_TTL" envDefault:"10m"`
+		CounterTTL     time.Duration `env:"METERING_COUNTER_TTL" envDefault:"1h"`
+	}
+
+	// Sms - Provider is one of kavenegar, http or log. The log provider sends nothing and appends the
+	// messages to LogFile when it is set, for development. A message is failed after MaxAttempts, a failed
+	// attempt is retried after RetryDelay, doubled on every further attempt.
+	Sms struct {
+		Provider        string        `env:"SMS_PROVIDER" envDefault:"log"`
+		Sender          string        `env:"SMS_SENDER"`
+		KavenegarApiKey string        `env:"SMS_KAVENEGAR_API_KEY"`
+		HttpUrl         string        `env:"SMS_HTTP_URL"`
+		HttpToken       string        `env:"SMS_HTTP_TOKEN"`
+		LogFile         string        `env:"SMS_LOG_FILE"`
+		Timeout         time.Duration `env:"SMS_TIMEOUT" envDefault:"10s"`
+		MaxAttempts     int           `env:"SMS_MAX_ATTEMPTS" envDefault:"5"`
+		RetryDelay      time.Duration `env:"SMS_RETRY_DELAY" envDefault:"30s"`
+	}
+
+	// Email - The platform SMTP sends the mail of the platform and of owners without an SMTP of their own.
+	// Any local fake SMTP server such as Mailpit works for development, with SSL off and no username.
+	// A message is failed after MaxAttempts, a failed attempt is retried after RetryDelay, doubled on every
+	// further attempt.
+	Email struct {
+		SmtpHost      string        `env:"EMAIL_SMTP_HOST" envDefault:"localhost"`
+		SmtpPort      int           `env:"EMAIL_SMTP_PORT" envDefault:"1025"`
+		SmtpUsername  string        `env:"EMAIL_SMTP_USERNAME"`
+		SmtpPassword  string        `env:"EMAIL_SMTP_PASSWORD"`
+		SmtpEnableSsl bool          `env:"EMAIL_SMTP_ENABLE_SSL" envDefault:"false"`
+		From          string        `env:"EMAIL_FROM" envDefault:"no-reply@localhost"`
+		Timeout       time.Duration `env:"EMAIL_TIMEOUT" envDefault:"30s"`
+		MaxAttempts   int           `env:"EMAIL_MAX_ATTEMPTS" envDefault:"5"`
+		RetryDelay    time.Duration `env:"EMAIL_RETRY_DELAY" envDefault:"1m"`
+	}
+
+	// Notification - DefaultLocale (fa or en) is the locale of notifications sent without one. Owners are
+	// notified in their inbox when an order takes the stock of a variant down to LowStockThreshold. Open
+	// inbox streams get a comment every StreamHeartbeat, so proxies do not close them as idle.
+	Notification struct {
+		DefaultLocale     string        `env:"NOTIFICATION_DEFAULT_LOCALE" envDefault:"fa"`
+		LowStockThreshold int           `env:"NOTIFICATION_LOW_STOCK_THRESHOLD" envDefault:"5"`
+		StreamHeartbeat   time.Duration `env:"NOTIFICATION_STREAM_HEARTBEAT" envDefault:"25s"`
+	}
+
+	// Storage - Drive files are kept in Bucket of an S3-compatible store, MinIO in development, or under
+	// LocalPath with the local backend. Files up to MaxFormSize can be sent in one request, larger ones
+	// are uploaded in parts of PartSize bytes, at least 5MB, and must be completed within UploadTTL.
+	// Uploads, downloads and requests to the store get TransferTimeout in place of the HTTP server
+	// timeouts.
+	Storage struct {
+		Backend         string        `env:"STORAGE_BACKEND" envDefault:"s3"`
+		S3Endpoint      string        `env:"STORAGE_S3_ENDPOINT" envDefault:"http://localhost:9000"`
+		S3Region        string        `env:"STORAGE_S3_REGION" envDefault:"us-east-1"`
+		S3AccessKey     string        `env:"STORAGE_S3_ACCESS_KEY"`
+		S3SecretKey     string        `env:"STORAGE_S3_SECRET_KEY"`
+		S3PathStyle     bool          `env:"STORAGE_S3_PATH_STYLE" envDefault:"true"`
+		LocalPath       string        `env:"STORAGE_LOCAL_PATH" envDefault:"./storage"`
+		Bucket          string        `env:"STORAGE_BUCKET" envDefault:"drive"`
+		MaxFormSize     int64         `env:"STORAGE_MAX_FORM_SIZE" envDefault:"33554432"`   // 32MB
+		MaxFileSize     int64         `env:"STORAGE_MAX_FILE_SIZE" envDefault:"5368709120"` // 5GB
+		PartSize        int64         `env:"STORAGE_PART_SIZE" envDefault:"8388608"`        // 8MB
+		UploadTTL       time.Duration `env:"STORAGE_UPLOAD_TTL" envDefault:"24h"`
+		TransferTimeout time.Duration `env:"STORAGE_TRANSFER_TIMEOUT" envDefault:"10m"`
+		// PublicUrl is where the "media/" keys of Bucket are read by anyone, the API itself by default.
+		// A CDN or the store can serve them instead, with a public-read policy on media/* of the bucket.
+		PublicUrl string `env:"STORAGE_PUBLIC_URL" envDefault:"/Public/Drive"`
+	}
+
+	// Image - Uploaded JPEG, PNG and GIF images get a ThumbnailSize square thumbnail and a size of each
+	// of Widths, encoded with Encoder, "webp" through the cwebp tool at CwebpPath or "jpeg". Images of
+	// more than MaxPixels pixels are not processed. Images still pending after RequeueAfter are queued
+	// again.
+	Image struct {
+		Encoder       string        `env:"IMAGE_ENCODER" envDefault:"webp"`
+		CwebpPath     string        `env:"IMAGE_CWEBP_PATH" envDefault:"cwebp"`
+		Quality       int           `env:"IMAGE_QUALITY" envDefault:"80"`
+		Widths        []int         `env:"IMAGE_WIDTHS" envDefault:"320,640,1024,1600"`
+		ThumbnailSize int           `env:"IMAGE_THUMBNAIL_SIZE" envDefault:"200"`
+		MaxPixels     int           `env:"IMAGE_MAX_PIXELS" envDefault:"40000000"`
+		RequeueAfter  time.Duration `env:"IMAGE_REQUEUE_AFTER" envDefault:"15m"`
+	}
+
+	// Secret - Master keys sealing the credentials stored in the database, written as "1:<base64>,2:<base64>"
+	// with 32 byte keys. New values are sealed with KeyVersion, the other keys are kept to read older values
+	// until cmd/reencrypt has moved them to the current key.
+	Secret struct {
+		MasterKeys string `env:"SECRET_MASTER_KEYS,required"`
+		KeyVersion uint32 `env:"SECRET_KEY_VERSION" envDefault:"1"`
+	}
+
+	// Jobs - Background job intervals, a zero interval disables the job
+	Jobs struct {
+		VisitFlushInterval       time.Duration `env:"JOB_VISIT_FLUSH_INTERVAL" envDefault:"1m"`
+		PaymentReconcileInterval time.Duration `env:"JOB_PAYMENT_RECONCILE_INTERVAL" envDefault:"5m"`
+		PlanExpiryInterval       time.Duration `env:"JOB_PLAN_EXPIRY_INTERVAL" envDefault:"10m"`
+		PlanReminderInterval     time.Duration `env:"JOB_PLAN_REMINDER_INTERVAL" envDefault:"1h"`
+		UploadExpiryInterval     time.Duration `env:"JOB_UPLOAD_EXPIRY_INTERVAL" envDefault:"1h"`
+		ImageRequeueInterval     time.Duration `env:"JOB_IMAGE_REQUEUE_INTERVAL" envDefault:"10m"`
+	}
 )
 
 // NewConfig returns app config.
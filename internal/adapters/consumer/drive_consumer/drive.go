@@ -0,0 +1,40 @@
+package drive_consumer
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type DriveConsumer struct {
+	imageUseCase *drive_use_case.ImageUseCase
+	l            *logger.ZapLogger
+}
+
+func NewDriveConsumer(imageUseCase *drive_use_case.ImageUseCase, l *logger.ZapLogger) *DriveConsumer {
+	return &DriveConsumer{
+		imageUseCase: imageUseCase,
+		l:            l,
+	}
+}
+
+// ImageConsume renders the variants of a stored image. Returning an error requeues the message,
+// malformed messages are logged and dropped.
+func (c *DriveConsumer) ImageConsume(ctx context.Context, msg amqp.Delivery) error {
+	var event event_dto.ImageStoredEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		c.l.Error("drive_consumer - DriveConsumer - ImageConsume: %v", err)
+		return nil
+	}
+	id, err := strconv.ParseInt(event.FileItemId, 10, 64)
+	if err != nil {
+		c.l.Error("drive_consumer - DriveConsumer - ImageConsume: %v", err)
+		return nil
+	}
+	return c.imageUseCase.Process(ctx, id)
+}
@@ -0,0 +1,107 @@
+package notification_consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/notification/inbox_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/pkg/logger"
+)
+
+type NotificationConsumer struct {
+	inboxUseCase *notification_use_case.InboxUseCase
+	l            *logger.ZapLogger
+}
+
+func NewNotificationConsumer(inboxUseCase *notification_use_case.InboxUseCase, l *logger.ZapLogger) *NotificationConsumer {
+	return &NotificationConsumer{
+		inboxUseCase: inboxUseCase,
+		l:            l,
+	}
+}
+
+// OrderPlacedConsume notifies the owner about a new order and the stock it ran low. Returning an error
+// requeues the message, malformed messages are logged and dropped.
+func (c *NotificationConsumer) OrderPlacedConsume(ctx context.Context, msg amqp.Delivery) error {
+	var event event_dto.OrderStatusChangedEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		c.l.Error("notification_consumer - NotificationConsumer - OrderPlacedConsume: %v", err)
+		return nil
+	}
+	orderId, _ := strconv.ParseInt(event.OrderId, 10, 64)
+	return c.inboxUseCase.OrderPlaced(ctx, orderId)
+}
+
+// ReturnRequestedConsume notifies the owner that a customer asked to return an item
+func (c *NotificationConsumer) ReturnRequestedConsume(ctx context.Context, msg amqp.Delivery) error {
+	var event event_dto.ReturnRequestedEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		c.l.Error("notification_consumer - NotificationConsumer - ReturnRequestedConsume: %v", err)
+		return nil
+	}
+	ownerId, _ := strconv.ParseInt(event.OwnerUserId, 10, 64)
+	siteId, _ := strconv.ParseInt(event.SiteId, 10, 64)
+	returnId, _ := strconv.ParseInt(event.ReturnId, 10, 64)
+
+	body := "A customer asked to return an item of order " + event.OrderId + "."
+	if event.ReturnReason != "" {
+		body += " Reason: " + event.ReturnReason
+	}
+	return c.notify(ctx, inbox_dto.NotifyDto{
+		UserId:        ownerId,
+		SiteId:        siteId,
+		Type:          user_entity.NotificationTypeReturnRequested,
+		Title:         "Return requested",
+		Body:          body,
+		ReferenceType: user_entity.NotificationReferenceReturnItem,
+		ReferenceId:   returnId,
+		EventKey:      "return_requested:" + event.ReturnId,
+	})
+}
+
+// PlanConsume notifies the owner that their plan is about to expire or has expired
+func (c *NotificationConsumer) PlanConsume(ctx context.Context, msg amqp.Delivery) error {
+	var event event_dto.PlanSubscriptionEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		c.l.Error("notification_consumer - NotificationConsumer - PlanConsume: %v", err)
+		return nil
+	}
+	var notificationType, title, body string
+	switch msg.RoutingKey {
+	case event_dto.RoutingKeyPlanExpiring:
+		notificationType, title = user_entity.NotificationTypePlanExpiring, "Your plan expires soon"
+		body = "Your " + event.PlanName + " plan expires on " + event.ExpiresAt.Format("2006-01-02") + ". Renew it to keep your sites running."
+	case event_dto.RoutingKeyPlanExpired:
+		notificationType, title = user_entity.NotificationTypePlanExpired, "Your plan has expired"
+		body = "Your " + event.PlanName + " plan expired on " + event.ExpiresAt.Format("2006-01-02") + "."
+	default:
+		return nil
+	}
+	userId, _ := strconv.ParseInt(event.UserId, 10, 64)
+	planId, _ := strconv.ParseInt(event.PlanId, 10, 64)
+
+	return c.notify(ctx, inbox_dto.NotifyDto{
+		UserId:        userId,
+		Type:          notificationType,
+		Title:         title,
+		Body:          body,
+		ReferenceType: user_entity.NotificationReferencePlan,
+		ReferenceId:   planId,
+		EventKey:      notificationType + ":" + event.UserId + ":" + event.ExpiresAt.UTC().Format("20060102150405"),
+	})
+}
+
+func (c *NotificationConsumer) notify(ctx context.Context, dto inbox_dto.NotifyDto) error {
+	err := c.inboxUseCase.Notify(ctx, dto)
+	if errors.Is(err, notification_use_case.ErrNotificationInvalid) {
+		c.l.Error("notification_consumer - NotificationConsumer - %s: %v", dto.EventKey, err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package payment_consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type PaymentConsumer struct {
+	paymentUseCase *payment_use_case.PaymentUseCase
+	returnUseCase  *order_use_case.ReturnUseCase
+	l              *logger.ZapLogger
+}
+
+func NewPaymentConsumer(paymentUseCase *payment_use_case.PaymentUseCase, returnUseCase *order_use_case.ReturnUseCase, l *logger.ZapLogger) *PaymentConsumer {
+	return &PaymentConsumer{
+		paymentUseCase: paymentUseCase,
+		returnUseCase:  returnUseCase,
+		l:              l,
+	}
+}
+
+// RefundConsume pays an approved return back and marks it refunded. Returning an error requeues the
+// message, so only failures worth another try are returned, the rest is logged and dropped. Nothing is
+// requeued once the gateway was called: a refund it refused is retried by the owner, one whose outcome is
+// unknown has to be checked at the gateway.
+func (c *PaymentConsumer) RefundConsume(ctx context.Context, msg amqp.Delivery) error {
+	var event event_dto.RefundRequestedEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		c.l.Error("payment_consumer - PaymentConsumer - RefundConsume: %v", err)
+		return nil
+	}
+	orderId, _ := strconv.ParseInt(event.OrderId, 10, 64)
+	returnId, _ := strconv.ParseInt(event.ReturnId, 10, 64)
+
+	err := c.paymentUseCase.Refund(ctx, orderId, event.Amount, "return-"+event.ReturnId)
+	switch {
+	case err == nil:
+	case errors.Is(err, payment_use_case.ErrRefundNotSupported):
+		// The owner issues the refund from the gateway's panel and confirms the return afterwards
+		c.l.Warn("payment_consumer - PaymentConsumer - RefundConsume - return %s: %v", event.ReturnId, err)
+		return nil
+	case errors.Is(err, payment_use_case.ErrRefundPending):
+		// Sending it again could pay the customer twice
+		c.l.Error("payment_consumer - PaymentConsumer - RefundConsume - return %s: %v", event.ReturnId, err)
+		return nil
+	case errors.Is(err, payment_use_case.ErrGatewayFailed),
+		errors.Is(err, payment_use_case.ErrPaymentNotFound), errors.Is(err, payment_use_case.ErrPaymentNotPaid),
+		errors.Is(err, payment_use_case.ErrRefundExceedsPayment), errors.Is(err, payment_use_case.ErrGatewayUnavailable):
+		c.l.Error("payment_consumer - PaymentConsumer - RefundConsume - return %s: %v", event.ReturnId, err)
+		return nil
+	default:
+		return err
+	}
+
+	err = c.returnUseCase.MarkRefunded(ctx, returnId)
+	if errors.Is(err, order_use_case.ErrReturnNotFound) || errors.Is(err, order_use_case.ErrReturnChanged) {
+		// A redelivered event of a return that was marked already
+		return nil
+	}
+	return err
+}
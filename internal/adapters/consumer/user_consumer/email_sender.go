@@ -0,0 +1,44 @@
+package user_consumer
+
+import (
+	"context"
+
+	"site_builder_backend/internal/application/dto/user/email_dto"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/rabbitmq"
+)
+
+// EmailSender is responsible for queueing emails via RabbitMQ
+type EmailSender struct {
+	client *rabbitmq.Client
+	logger logger.Logger
+}
+
+// NewEmailSender creates a new email sender
+func NewEmailSender(client *rabbitmq.Client, logger logger.Logger) *EmailSender {
+	return &EmailSender{
+		client: client,
+		logger: logger,
+	}
+}
+
+// SendEmail queues an email for the email consumer, a MessageId is filled in when it is empty
+func (s *EmailSender) SendEmail(ctx context.Context, email email_dto.EmailDto) error {
+	if email.MessageId == "" {
+		email.MessageId = newMessageId()
+	}
+
+	err := s.client.Publisher("user_exchange").
+		RoutingKey("user.email").
+		Type("direct").
+		Config(true, false, false, false).
+		PublishJSON(ctx, email)
+
+	if err != nil {
+		s.logger.Error("Failed to publish email message: %v", err)
+		return err
+	}
+
+	s.logger.Info("Email message published successfully to %s", email.To)
+	return nil
+}
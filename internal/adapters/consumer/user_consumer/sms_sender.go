@@ -2,6 +2,8 @@ package user_consumer
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"site_builder_backend/pkg/logger"
 	"site_builder_backend/pkg/rabbitmq"
@@ -21,18 +23,23 @@ func NewSmsSender(client *rabbitmq.Client, logger logger.Logger) *SmsSender {
 	}
 }
 
-// SmsRequest represents a request to send an SMS
+// SmsRequest represents a request to send an SMS. MessageId lets the consumer skip redelivered messages
+// and is filled in when empty. UserId is the site owner paying for the message with SMS credits, zero
+// for messages of the platform.
 type SmsRequest struct {
-	Phone   string `json:"phone"`
-	Message string `json:"message"`
+	MessageId string `json:"message_id"`
+	UserId    int64  `json:"user_id,omitempty"`
+	Phone     string `json:"phone"`
+	Message   string `json:"message"`
 }
 
 // SendSms sends an SMS message via RabbitMQ
 func (s *SmsSender) SendSms(ctx context.Context, phone, message string) error {
 	// Create the SMS request
 	request := SmsRequest{
-		Phone:   phone,
-		Message: message,
+		MessageId: newMessageId(),
+		Phone:     phone,
+		Message:   message,
 	}
 
 	// Publish the message using the builder pattern
@@ -56,6 +63,9 @@ func (s *SmsSender) SendBulkSms(ctx context.Context, messages []SmsRequest) erro
 	// Convert messages to JSON
 	var jsonMessages [][]byte
 	for _, msg := range messages {
+		if msg.MessageId == "" {
+			msg.MessageId = newMessageId()
+		}
 		jsonData, err := json.Marshal(msg)
 		if err != nil {
 			s.logger.Error("Failed to marshal SMS message: %v", err)
@@ -83,4 +93,11 @@ func (s *SmsSender) SendBulkSms(ctx context.Context, messages []SmsRequest) erro
 
 	s.logger.Info("Bulk SMS messages published successfully (%d messages)", len(jsonMessages))
 	return nil
-} 
\ No newline at end of file
+}
+
+// newMessageId returns a random id for a message
+func newMessageId() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -2,34 +2,115 @@ package user_consumer
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
-	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"errors"
+	"strings"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/user/email_dto"
+	"site_builder_backend/internal/application/dto/user/sms_dto"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/rabbitmq"
 )
 
+// planTemplates are the email templates of the plan events
+var planTemplates = map[string]string{
+	event_dto.RoutingKeyPlanActivated: "plan_activated",
+	event_dto.RoutingKeyPlanExpiring:  "plan_expiring",
+	event_dto.RoutingKeyPlanExpired:   "plan_expired",
+}
+
 type UserConsumer struct {
-	useCase *user_use_case.UserUseCase
+	smsUseCase   *user_use_case.SmsUseCase
+	emailUseCase *user_use_case.EmailUseCase
+	l            *logger.ZapLogger
 }
 
-func NewUserConsumer(useCase *user_use_case.UserUseCase) *UserConsumer {
+func NewUserConsumer(smsUseCase *user_use_case.SmsUseCase, emailUseCase *user_use_case.EmailUseCase, l *logger.ZapLogger) *UserConsumer {
 	return &UserConsumer{
-		useCase: useCase,
+		smsUseCase:   smsUseCase,
+		emailUseCase: emailUseCase,
+		l:            l,
 	}
 }
 
-// SendSmsConsume handles SMS notification messages from RabbitMQ
+// SendSmsConsume handles single and bulk SMS messages from RabbitMQ. Returning an error retries the
+// message after a delay, malformed messages are logged and dropped.
 func (c *UserConsumer) SendSmsConsume(ctx context.Context, msg amqp.Delivery) error {
-	// Parse message body
-	var smsRequest struct {
-		Phone   string `json:"phone"`
-		Message string `json:"message"`
+	var smsRequest sms_dto.SmsDto
+	if err := json.Unmarshal(msg.Body, &smsRequest); err != nil {
+		c.l.Error("user_consumer - UserConsumer - SendSmsConsume: %v", err)
+		return nil
 	}
 
-	if err := json.Unmarshal(msg.Body, &smsRequest); err != nil {
-		return err
+	err := c.smsUseCase.Send(ctx, smsRequest)
+	if errors.Is(err, user_use_case.ErrSmsInvalid) {
+		c.l.Error("user_consumer - UserConsumer - SendSmsConsume - message %s: %v", smsRequest.MessageId, err)
+		return nil
+	}
+	return err
+}
+
+// SendEmailConsume handles queued emails. Returning an error retries the message after a delay, malformed
+// messages are logged and dropped.
+func (c *UserConsumer) SendEmailConsume(ctx context.Context, msg amqp.Delivery) error {
+	var emailRequest email_dto.EmailDto
+	if err := json.Unmarshal(msg.Body, &emailRequest); err != nil {
+		c.l.Error("user_consumer - UserConsumer - SendEmailConsume: %v", err)
+		return nil
+	}
+	return c.sendEmail(ctx, emailRequest)
+}
+
+// PlanNotifyConsume emails the owner about the activation, the coming expiry or the expiry of their plan.
+// The message id follows from the event, so a redelivered or retried event is not mailed twice.
+func (c *UserConsumer) PlanNotifyConsume(ctx context.Context, msg amqp.Delivery) error {
+	email, ok := c.planEmail(msg)
+	if !ok {
+		return nil
+	}
+	return c.sendEmail(ctx, email)
+}
+
+// planEmail builds the email of a plan event. Retried events come back with the queue name as routing
+// key, the template is chosen by the key the event was published with.
+func (c *UserConsumer) planEmail(msg amqp.Delivery) (email_dto.EmailDto, bool) {
+	routingKey := rabbitmq.RoutingKey(msg)
+	template, ok := planTemplates[routingKey]
+	if !ok {
+		c.l.Error("user_consumer - UserConsumer - PlanNotifyConsume: no template for routing key %q", routingKey)
+		return email_dto.EmailDto{}, false
+	}
+	var event event_dto.PlanSubscriptionEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		c.l.Error("user_consumer - UserConsumer - PlanNotifyConsume: %v", err)
+		return email_dto.EmailDto{}, false
+	}
+	if event.Email == "" {
+		return email_dto.EmailDto{}, false
 	}
 
-	// Call use case to send SMS
-	return c.useCase.SendSms(ctx, smsRequest.Phone, smsRequest.Message)
+	sum := sha256.Sum256([]byte(strings.Join([]string{routingKey, event.UserId, event.PlanId, event.OccurredAt.String()}, "|")))
+	return email_dto.EmailDto{
+		MessageId: hex.EncodeToString(sum[:16]),
+		To:        event.Email,
+		Template:  template,
+		Data: map[string]interface{}{
+			"PlanName":  event.PlanName,
+			"ExpiresAt": event.ExpiresAt.Format("2006-01-02"),
+		},
+	}, true
+}
+
+func (c *UserConsumer) sendEmail(ctx context.Context, email email_dto.EmailDto) error {
+	err := c.emailUseCase.Send(ctx, email)
+	if errors.Is(err, user_use_case.ErrEmailInvalid) {
+		c.l.Error("user_consumer - UserConsumer - sendEmail - message %s: %v", email.MessageId, err)
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,47 @@
+package user_consumer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/pkg/logger"
+)
+
+func TestPlanEmailOfARetriedEvent(t *testing.T) {
+	consumer := NewUserConsumer(nil, nil, logger.NewLoggerFromConfig("error", "json", "stdout"))
+	body, _ := json.Marshal(event_dto.PlanSubscriptionEvent{
+		UserId:     "7",
+		PlanId:     "2",
+		PlanName:   "Pro",
+		Email:      "owner@example.com",
+		ExpiresAt:  time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		OccurredAt: time.Date(2026, 10, 25, 8, 0, 0, 0, time.UTC),
+	})
+
+	published := amqp.Delivery{RoutingKey: event_dto.RoutingKeyPlanExpiring, Body: body}
+	// The same event back from the retry queue, dead-lettered with the queue name as routing key
+	retried := amqp.Delivery{
+		RoutingKey: "plan_notification_queue",
+		Headers:    amqp.Table{"x-retries": int32(1), "x-original-routing-key": event_dto.RoutingKeyPlanExpiring},
+		Body:       body,
+	}
+
+	first, ok := consumer.planEmail(published)
+	if !ok || first.Template != "plan_expiring" {
+		t.Fatalf("published event: email = %+v, %v", first, ok)
+	}
+	again, ok := consumer.planEmail(retried)
+	if !ok || again.Template != "plan_expiring" {
+		t.Fatalf("retried event: email = %+v, %v", again, ok)
+	}
+	if again.MessageId != first.MessageId {
+		t.Fatalf("retried event has message id %s, the first delivery %s", again.MessageId, first.MessageId)
+	}
+
+	if _, ok := consumer.planEmail(amqp.Delivery{RoutingKey: "plan_notification_queue", Body: body}); ok {
+		t.Fatal("an event without its routing key was mailed")
+	}
+}
@@ -0,0 +1,227 @@
+package blog_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/blog/article_dto"
+	"site_builder_backend/internal/application/use_cases/blog_use_case"
+	"site_builder_backend/internal/interfaces/search_db/article_search_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type ArticleController struct {
+	useCase *blog_use_case.ArticleUseCase
+	l       *logger.ZapLogger
+}
+
+func NewArticleController(useCase *blog_use_case.ArticleUseCase, l *logger.ZapLogger) *ArticleController {
+	return &ArticleController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (a *ArticleController) CreateArticle(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto article_dto.CreateArticleDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := a.useCase.Create(c.Request.Context(), userId, dto)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, article)
+}
+
+func (a *ArticleController) UpdateArticle(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto article_dto.UpdateArticleDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := a.useCase.Update(c.Request.Context(), userId, dto)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+func (a *ArticleController) ChangeArticleStatus(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto article_dto.ChangeArticleStatusDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := a.useCase.ChangeStatus(c.Request.Context(), userId, dto)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+func (a *ArticleController) DeleteArticle(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := a.useCase.Delete(c.Request.Context(), userId, id); err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (a *ArticleController) GetArticle(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	article, err := a.useCase.Get(c.Request.Context(), userId, id)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+func (a *ArticleController) GetAllArticles(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto article_dto.ArticleFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := a.useCase.GetAll(c.Request.Context(), userId, dto)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (a *ArticleController) PublicGetAllArticles(c *gin.Context) {
+	var dto article_dto.PublicArticleFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := a.useCase.PublicGetAll(c.Request.Context(), dto)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (a *ArticleController) PublicGetArticle(c *gin.Context) {
+	siteId, err := http_helper.ParamId(c, "site_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := a.useCase.PublicGet(c.Request.Context(), siteId, c.Param("slug"))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+func (a *ArticleController) PublicSearchArticles(c *gin.Context) {
+	var dto article_dto.SearchArticleDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := a.useCase.PublicSearch(c.Request.Context(), dto)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (a *ArticleController) ReindexArticles(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	siteId, err := http_helper.ParamId(c, "site_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	indexed, err := a.useCase.Reindex(c.Request.Context(), userId, siteId)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"indexed": indexed})
+}
+
+func (a *ArticleController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, blog_use_case.ErrArticleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, blog_use_case.ErrSiteAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, blog_use_case.ErrArticleSlugExists), errors.Is(err, blog_use_case.ErrArticleChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, blog_use_case.ErrInvalidSchedule), errors.Is(err, blog_use_case.ErrInvalidSlug):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, article_search_inter.ErrSearchDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		a.l.Error("blog_controller - ArticleController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
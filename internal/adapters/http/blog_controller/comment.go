@@ -0,0 +1,130 @@
+package blog_controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/blog/comment_dto"
+	"site_builder_backend/internal/application/use_cases/blog_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type CommentController struct {
+	useCase *blog_use_case.CommentUseCase
+	l       *logger.ZapLogger
+}
+
+func NewCommentController(useCase *blog_use_case.CommentUseCase, l *logger.ZapLogger) *CommentController {
+	return &CommentController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (cc *CommentController) CreateComment(c *gin.Context) {
+	var dto comment_dto.CreateCommentDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	author := comment_dto.CommentAuthorDto{
+		IpAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if customerId, err := http_helper.CustomerId(c); err == nil {
+		author.CustomerId = customerId
+	}
+
+	comment, err := cc.useCase.Create(c.Request.Context(), author, dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+func (cc *CommentController) GetAllComments(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto comment_dto.CommentFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.GetAll(c.Request.Context(), userId, dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CommentController) ApproveComment(c *gin.Context) {
+	cc.moderate(c, cc.useCase.Approve)
+}
+
+func (cc *CommentController) MarkCommentSpam(c *gin.Context) {
+	cc.moderate(c, cc.useCase.MarkSpam)
+}
+
+func (cc *CommentController) DeleteComment(c *gin.Context) {
+	cc.moderate(c, cc.useCase.Delete)
+}
+
+func (cc *CommentController) PublicGetAllComments(c *gin.Context) {
+	var dto comment_dto.PublicCommentFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.PublicGetAll(c.Request.Context(), dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CommentController) moderate(c *gin.Context, action func(ctx context.Context, userId int64, id int64) error) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := action(c.Request.Context(), userId, id); err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (cc *CommentController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, blog_use_case.ErrArticleNotFound), errors.Is(err, blog_use_case.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, blog_use_case.ErrSiteAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, blog_use_case.ErrGuestIdentity), errors.Is(err, blog_use_case.ErrRatingOnReply):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, blog_use_case.ErrCommentRateLimited):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		cc.l.Error("blog_controller - CommentController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,114 @@
+package drive_controller
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type FileController struct {
+	useCase         *drive_use_case.FileUseCase
+	transferTimeout time.Duration
+	l               *logger.ZapLogger
+}
+
+// NewFileController gives downloads transferTimeout to be sent, and copies to be made
+func NewFileController(useCase *drive_use_case.FileUseCase, transferTimeout time.Duration, l *logger.ZapLogger) *FileController {
+	return &FileController{
+		useCase:         useCase,
+		transferTimeout: transferTimeout,
+		l:               l,
+	}
+}
+
+func (fc *FileController) GetFile(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.GetFile(c.Request.Context(), userId, id)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (fc *FileController) Download(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, object, err := fc.useCase.Download(c.Request.Context(), userId, id)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	defer object.Body.Close()
+
+	transfer(c, fc.transferTimeout, fc.l)
+	c.DataFromReader(http.StatusOK, object.Size, item.MimeType, object.Body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": item.Name}),
+		"Cache-Control":          "private, no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// transfer gives the request timeout to send or receive a file, the timeouts of the server are meant
+// for small requests
+func transfer(c *gin.Context, timeout time.Duration, l *logger.ZapLogger) {
+	deadline := time.Now().Add(timeout)
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		l.Warn("drive_controller - transfer: %v", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		l.Warn("drive_controller - transfer: %v", err)
+	}
+}
+
+func handleError(c *gin.Context, l *logger.ZapLogger, err error) {
+	switch {
+	case errors.Is(err, drive_use_case.ErrFileNotFound),
+		errors.Is(err, drive_use_case.ErrUploadNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, drive_use_case.ErrNameConflict),
+		errors.Is(err, drive_use_case.ErrUploadClosed),
+		errors.Is(err, drive_use_case.ErrCycle):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, drive_use_case.ErrFileTooLarge),
+		errors.Is(err, drive_use_case.ErrCopyTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, drive_use_case.ErrQuotaExceeded):
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": err.Error()})
+	case errors.Is(err, drive_use_case.ErrParentNotFound),
+		errors.Is(err, drive_use_case.ErrInvalidName),
+		errors.Is(err, drive_use_case.ErrInvalidPart),
+		errors.Is(err, drive_use_case.ErrPartSize),
+		errors.Is(err, drive_use_case.ErrUploadIncomplete):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		l.Error("drive_controller: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,127 @@
+package drive_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+)
+
+func (fc *FileController) CreateFolder(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.CreateFolderDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.CreateFolder(c.Request.Context(), userId, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+// List returns the items of the folder parent_id, the root when it is left out
+func (fc *FileController) List(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.FolderFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.List(c.Request.Context(), userId, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (fc *FileController) Move(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.DestinationDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.Move(c.Request.Context(), userId, id, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (fc *FileController) Copy(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.DestinationDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Copying the objects of a large folder takes a while
+	transfer(c, fc.transferTimeout, fc.l)
+	result, err := fc.useCase.Copy(c.Request.Context(), userId, id, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (fc *FileController) Rename(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.RenameDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.Rename(c.Request.Context(), userId, id, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,39 @@
+package drive_controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+// MediaController serves the image variants under media/ to anyone. It is used when the object store
+// itself is not reachable by the public, as with the local storage.
+type MediaController struct {
+	useCase *drive_use_case.ImageUseCase
+	l       *logger.ZapLogger
+}
+
+func NewMediaController(useCase *drive_use_case.ImageUseCase, l *logger.ZapLogger) *MediaController {
+	return &MediaController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (mc *MediaController) Get(c *gin.Context) {
+	object, err := mc.useCase.Open(c.Request.Context(), strings.TrimPrefix(c.Param("key"), "/"))
+	if err != nil {
+		handleError(c, mc.l, err)
+		return
+	}
+	defer object.Body.Close()
+
+	// Variant keys are never written twice with other content
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, object.Body, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -0,0 +1,109 @@
+package drive_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+)
+
+// Trash moves an item with the items below it to the trash
+func (fc *FileController) Trash(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := fc.useCase.Trash(c.Request.Context(), userId, id); err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (fc *FileController) ListTrash(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.TrashFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.ListTrash(c.Request.Context(), userId, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (fc *FileController) Restore(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.RestoreDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := fc.useCase.Restore(c.Request.Context(), userId, id, dto)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (fc *FileController) Purge(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := fc.useCase.Purge(c.Request.Context(), userId, id); err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (fc *FileController) EmptyTrash(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer(c, fc.transferTimeout, fc.l)
+	purged, err := fc.useCase.EmptyTrash(c.Request.Context(), userId)
+	if err != nil {
+		handleError(c, fc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"purged": purged})
+}
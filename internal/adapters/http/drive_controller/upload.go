@@ -0,0 +1,196 @@
+package drive_controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+// _formOverhead is what a multipart form may take besides the file it carries
+const _formOverhead = 1 << 20
+
+type UploadController struct {
+	useCase         *drive_use_case.UploadUseCase
+	maxFormSize     int64
+	transferTimeout time.Duration
+	l               *logger.ZapLogger
+}
+
+// NewUploadController takes files of up to maxFormSize bytes in one request, larger files are uploaded
+// in parts. Uploads get transferTimeout to be received.
+func NewUploadController(useCase *drive_use_case.UploadUseCase, maxFormSize int64, transferTimeout time.Duration, l *logger.ZapLogger) *UploadController {
+	return &UploadController{
+		useCase:         useCase,
+		maxFormSize:     maxFormSize,
+		transferTimeout: transferTimeout,
+		l:               l,
+	}
+}
+
+// Upload stores the "file" field of a multipart form
+func (uc *UploadController) Upload(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	transfer(c, uc.transferTimeout, uc.l)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, uc.maxFormSize+_formOverhead)
+	var dto file_dto.UploadFileDto
+	if err := c.ShouldBind(&dto); err != nil {
+		uc.badForm(c, err)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		uc.badForm(c, err)
+		return
+	}
+	if header.Size > uc.maxFormSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large for a single request, upload it in parts"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		uc.badForm(c, err)
+		return
+	}
+	defer file.Close()
+
+	result, err := uc.useCase.Upload(c.Request.Context(), userId, dto, header.Filename, file, header.Size, header.Header.Get("Content-Type"))
+	if err != nil {
+		handleError(c, uc.l, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (uc *UploadController) StartUpload(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto file_dto.StartUploadDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := uc.useCase.StartUpload(c.Request.Context(), userId, dto)
+	if err != nil {
+		handleError(c, uc.l, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (uc *UploadController) GetUpload(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := uc.useCase.GetUpload(c.Request.Context(), userId, id)
+	if err != nil {
+		handleError(c, uc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// UploadPart stores the raw request body as a part of the upload, its Content-Length must be the size
+// of the part
+func (uc *UploadController) UploadPart(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid number"})
+		return
+	}
+	if c.Request.ContentLength < 0 {
+		c.JSON(http.StatusLengthRequired, gin.H{"error": "parts need a Content-Length"})
+		return
+	}
+
+	transfer(c, uc.transferTimeout, uc.l)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, c.Request.ContentLength)
+	result, err := uc.useCase.UploadPart(c.Request.Context(), userId, id, number, body, c.Request.ContentLength)
+	if err != nil {
+		handleError(c, uc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (uc *UploadController) CompleteUpload(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Joining the parts of a large file takes the store a while
+	transfer(c, uc.transferTimeout, uc.l)
+	result, err := uc.useCase.CompleteUpload(c.Request.Context(), userId, id)
+	if err != nil {
+		handleError(c, uc.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (uc *UploadController) AbortUpload(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := uc.useCase.AbortUpload(c.Request.Context(), userId, id); err != nil {
+		handleError(c, uc.l, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (uc *UploadController) badForm(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large for a single request, upload it in parts"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,80 @@
+package http_helper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CustomerRole is the role claim of tokens issued to storefront customers
+const CustomerRole = "customer"
+
+var (
+	ErrUnauthenticated = errors.New("authenticated user not found in context")
+	ErrNotCustomer     = errors.New("authenticated subject is not a customer")
+	ErrNotOwner        = errors.New("authenticated subject is not a site owner")
+)
+
+// UserId returns the id of the authenticated subject that AuthMiddleware stored in the context
+func UserId(c *gin.Context) (int64, error) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return 0, ErrUnauthenticated
+	}
+	subject, ok := value.(string)
+	if !ok {
+		return 0, ErrUnauthenticated
+	}
+	id, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	return id, nil
+}
+
+// ParamId parses a numeric path parameter
+func ParamId(c *gin.Context, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
+
+// VisitorId identifies the client for visit deduplication. Authenticated users are identified by their role and id,
+// as customer and owner ids come from different tables, anonymous clients by the visitor_id cookie set by the
+// storefront and otherwise by a hash of address and user agent.
+func VisitorId(c *gin.Context) string {
+	if userId, err := UserId(c); err == nil {
+		role, _ := c.Get("user_role")
+		roleName, _ := role.(string)
+		return "u:" + roleName + ":" + strconv.FormatInt(userId, 10)
+	}
+	if cookie, err := c.Cookie("visitor_id"); err == nil && cookie != "" {
+		return "c:" + cookie
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:16])
+}
+
+// OwnerId returns the id of the authenticated site owner. Tokens issued to customers are rejected, their ids
+// come from another table and may equal an owner's.
+func OwnerId(c *gin.Context) (int64, error) {
+	if role, _ := c.Get("user_role"); role == CustomerRole {
+		return 0, ErrNotOwner
+	}
+	return UserId(c)
+}
+
+// CustomerId returns the id of the authenticated storefront customer, whose tokens are issued by the
+// Public/Customer login. Tokens issued to site owners are rejected.
+func CustomerId(c *gin.Context) (int64, error) {
+	if role, _ := c.Get("user_role"); role != CustomerRole {
+		return 0, ErrNotCustomer
+	}
+	return UserId(c)
+}
@@ -0,0 +1,29 @@
+package http_helper
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/application/use_cases/pricing_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+// HandlePricingError maps pricing errors to responses, for every controller that prices products, baskets
+// and orders
+func HandlePricingError(c *gin.Context, l *logger.ZapLogger, err error) {
+	switch {
+	case errors.Is(err, pricing_use_case.ErrProductNotFound), errors.Is(err, pricing_use_case.ErrDiscountNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, pricing_use_case.ErrDiscountNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, pricing_use_case.ErrOfferExhausted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, pricing_use_case.ErrDiscountExpired), errors.Is(err, pricing_use_case.ErrDiscountExhausted),
+		errors.Is(err, pricing_use_case.ErrDiscountNotApplicable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		l.Error("http_helper - pricing: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
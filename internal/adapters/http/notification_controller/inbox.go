@@ -0,0 +1,141 @@
+package notification_controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/notification/inbox_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type InboxController struct {
+	useCase   *notification_use_case.InboxUseCase
+	heartbeat time.Duration
+	l         *logger.ZapLogger
+}
+
+// NewInboxController writes a comment to open streams every heartbeat
+func NewInboxController(useCase *notification_use_case.InboxUseCase, heartbeat time.Duration, l *logger.ZapLogger) *InboxController {
+	return &InboxController{
+		useCase:   useCase,
+		heartbeat: heartbeat,
+		l:         l,
+	}
+}
+
+func (ic *InboxController) GetNotifications(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto inbox_dto.NotificationFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := ic.useCase.Notifications(c.Request.Context(), userId, dto)
+	if err != nil {
+		ic.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (ic *InboxController) GetUnreadCount(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := ic.useCase.UnreadCount(c.Request.Context(), userId)
+	if err != nil {
+		ic.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (ic *InboxController) MarkRead(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto inbox_dto.MarkReadDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := ic.useCase.MarkRead(c.Request.Context(), userId, dto)
+	if err != nil {
+		ic.internalError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Stream pushes the user's notifications as Server-Sent Events. The stream starts with an "unread" event
+// carrying the unread count, then sends a "notification" event per new notification and a "read" event
+// when another dashboard of the user read some. The token goes in the Authorization header as for any
+// other request, so browsers need a fetch based EventSource rather than the built-in one.
+func (ic *InboxController) Stream(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	// Subscribe before counting, so nothing notified in between is missed
+	messages, cancel := ic.useCase.Subscribe(userId)
+	defer cancel()
+	unread, err := ic.useCase.UnreadCount(c.Request.Context(), userId)
+	if err != nil {
+		ic.internalError(c, err)
+		return
+	}
+
+	// The stream outlives the write timeout of the server
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		ic.l.Warn("notification_controller - InboxController - Stream: %v", err)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.SSEvent("unread", unread)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(ic.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				// The server is shutting down, the client reconnects to another replica
+				return
+			}
+			c.SSEvent(message.Kind, message)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func (ic *InboxController) internalError(c *gin.Context, err error) {
+	ic.l.Error("notification_controller - InboxController: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
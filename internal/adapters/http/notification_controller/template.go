@@ -0,0 +1,123 @@
+package notification_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/notification/template_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type TemplateController struct {
+	useCase *notification_use_case.TemplateUseCase
+	l       *logger.ZapLogger
+}
+
+func NewTemplateController(useCase *notification_use_case.TemplateUseCase, l *logger.ZapLogger) *TemplateController {
+	return &TemplateController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (tc *TemplateController) GetCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, tc.useCase.Catalog(c.Request.Context()))
+}
+
+func (tc *TemplateController) GetAllTemplates(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto template_dto.TemplateFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tc.useCase.Templates(c.Request.Context(), userId, dto.SiteId)
+	if err != nil {
+		tc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (tc *TemplateController) SaveTemplate(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto template_dto.SaveTemplateDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tc.useCase.Save(c.Request.Context(), userId, dto)
+	if err != nil {
+		tc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (tc *TemplateController) ResetTemplate(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto template_dto.TemplateKeyDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tc.useCase.Reset(c.Request.Context(), userId, dto)
+	if err != nil {
+		tc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (tc *TemplateController) PreviewTemplate(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto template_dto.PreviewTemplateDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := tc.useCase.Preview(c.Request.Context(), userId, dto)
+	if err != nil {
+		tc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (tc *TemplateController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, notification_use_case.ErrSiteAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, notification_use_case.ErrUnknownEvent),
+		errors.Is(err, notification_use_case.ErrUnknownVariable),
+		errors.Is(err, notification_use_case.ErrMalformedTemplate),
+		errors.Is(err, notification_use_case.ErrSubjectRequired):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		tc.l.Error("notification_controller - TemplateController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,155 @@
+package order_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/order/basket_dto"
+	"site_builder_backend/internal/application/use_cases/basket_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+// BasketTokenHeader carries the token of a guest basket in both directions
+const BasketTokenHeader = "X-Basket-Token"
+
+type BasketController struct {
+	useCase *basket_use_case.BasketUseCase
+	l       *logger.ZapLogger
+}
+
+func NewBasketController(useCase *basket_use_case.BasketUseCase, l *logger.ZapLogger) *BasketController {
+	return &BasketController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (b *BasketController) GetBasket(c *gin.Context) {
+	var dto basket_dto.GetBasketDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	basket, err := b.useCase.Get(c.Request.Context(), b.owner(c, false), dto)
+	if err != nil {
+		b.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+func (b *BasketController) AddBasketItem(c *gin.Context) {
+	var dto basket_dto.BasketItemDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	basket, err := b.useCase.AddItem(c.Request.Context(), b.owner(c, true), dto)
+	if err != nil {
+		b.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+func (b *BasketController) UpdateBasketItem(c *gin.Context) {
+	var dto basket_dto.BasketItemDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	basket, err := b.useCase.UpdateItem(c.Request.Context(), b.owner(c, false), dto)
+	if err != nil {
+		b.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+func (b *BasketController) RemoveBasketItem(c *gin.Context) {
+	var dto basket_dto.RemoveBasketItemDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	basket, err := b.useCase.RemoveItem(c.Request.Context(), b.owner(c, false), dto)
+	if err != nil {
+		b.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+func (b *BasketController) ApplyBasketDiscount(c *gin.Context) {
+	var dto basket_dto.ApplyDiscountDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	basket, err := b.useCase.ApplyDiscount(c.Request.Context(), b.owner(c, true), dto)
+	if err != nil {
+		b.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+// MergeBasket is called by the storefront right after a customer signs in, with the token of the guest basket
+func (b *BasketController) MergeBasket(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto basket_dto.MergeBasketDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	basket, err := b.useCase.Merge(c.Request.Context(), customerId, dto)
+	if err != nil {
+		b.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, basket)
+}
+
+// owner identifies the basket of the request. Customers are identified by their token, guests by the
+// basket token header; a guest without a valid one gets a new token when issue is set.
+func (b *BasketController) owner(c *gin.Context, issue bool) basket_use_case.Owner {
+	if customerId, err := http_helper.CustomerId(c); err == nil {
+		return basket_use_case.Owner{CustomerId: customerId}
+	}
+
+	token := c.GetHeader(BasketTokenHeader)
+	if _, err := uuid.Parse(token); err != nil {
+		token = ""
+		if issue {
+			token = uuid.NewString()
+		}
+	}
+	if token != "" {
+		c.Header(BasketTokenHeader, token)
+	}
+	return basket_use_case.Owner{GuestToken: token}
+}
+
+func (b *BasketController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, basket_use_case.ErrItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, basket_use_case.ErrOutOfStock), errors.Is(err, basket_use_case.ErrQuantityLimit):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		http_helper.HandlePricingError(c, b.l, err)
+	}
+}
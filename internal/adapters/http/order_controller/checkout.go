@@ -0,0 +1,97 @@
+package order_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/order/checkout_dto"
+	"site_builder_backend/internal/application/dto/order/shipping_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/application/use_cases/shipping_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+// IdempotencyKeyHeader lets a client retry a checkout without placing a second order
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 100
+
+type CheckoutController struct {
+	useCase *order_use_case.CheckoutUseCase
+	l       *logger.ZapLogger
+}
+
+func NewCheckoutController(useCase *order_use_case.CheckoutUseCase, l *logger.ZapLogger) *CheckoutController {
+	return &CheckoutController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (cc *CheckoutController) Checkout(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	key := c.GetHeader(IdempotencyKeyHeader)
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a valid Idempotency-Key header is required"})
+		return
+	}
+	var dto checkout_dto.CheckoutDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	client := checkout_dto.CheckoutClientDto{
+		IdempotencyKey: key,
+		ClientIp:       c.ClientIP(),
+	}
+	order, created, err := cc.useCase.Checkout(c.Request.Context(), customerId, dto, client)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	if !created {
+		c.JSON(http.StatusOK, order)
+		return
+	}
+	c.JSON(http.StatusCreated, order)
+}
+
+func (cc *CheckoutController) ShippingOptions(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto shipping_dto.ShippingQuoteDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.ShippingOptions(c.Request.Context(), customerId, dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CheckoutController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, order_use_case.ErrAddressNotFound), errors.Is(err, order_use_case.ErrSiteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrBasketEmpty), errors.Is(err, shipping_use_case.ErrCourierUnavailable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrOutOfStock):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		http_helper.HandlePricingError(c, cc.l, err)
+	}
+}
@@ -0,0 +1,170 @@
+package order_controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/order/order_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/pkg/logger"
+)
+
+type OrderController struct {
+	useCase *order_use_case.OrderUseCase
+	l       *logger.ZapLogger
+}
+
+func NewOrderController(useCase *order_use_case.OrderUseCase, l *logger.ZapLogger) *OrderController {
+	return &OrderController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (o *OrderController) GetAllOrders(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto order_dto.OrderFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := o.useCase.GetAll(c.Request.Context(), userId, dto)
+	if err != nil {
+		o.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (o *OrderController) GetOrder(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := o.useCase.Get(c.Request.Context(), userId, id)
+	if err != nil {
+		o.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (o *OrderController) ChangeOrderStatus(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto order_dto.ChangeOrderStatusDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := o.useCase.ChangeStatus(c.Request.Context(), userId, dto)
+	if err != nil {
+		o.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func (o *OrderController) CustomerGetAllOrders(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto order_dto.CustomerOrderFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := o.useCase.CustomerGetAll(c.Request.Context(), customerId, dto)
+	if err != nil {
+		o.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (o *OrderController) CustomerGetOrder(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := o.useCase.CustomerGet(c.Request.Context(), customerId, id)
+	if err != nil {
+		o.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (o *OrderController) CancelOrder(c *gin.Context) {
+	o.customerAction(c, o.useCase.Cancel)
+}
+
+func (o *OrderController) ConfirmOrderDelivery(c *gin.Context) {
+	o.customerAction(c, o.useCase.ConfirmDelivery)
+}
+
+func (o *OrderController) customerAction(c *gin.Context, action func(ctx context.Context, customerId int64, id int64) (*order_entity.OrderEntity, error)) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := action(c.Request.Context(), customerId, id)
+	if err != nil {
+		o.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func (o *OrderController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, order_use_case.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrSiteAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrInvalidTransition), errors.Is(err, order_use_case.ErrTrackingCodeRequired):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrOrderChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		o.l.Error("order_controller - OrderController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
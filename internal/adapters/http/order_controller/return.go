@@ -0,0 +1,225 @@
+package order_controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/order/return_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type ReturnController struct {
+	useCase *order_use_case.ReturnUseCase
+	l       *logger.ZapLogger
+}
+
+func NewReturnController(useCase *order_use_case.ReturnUseCase, l *logger.ZapLogger) *ReturnController {
+	return &ReturnController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (rc *ReturnController) RequestReturn(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto return_dto.RequestReturnDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := rc.useCase.Request(c.Request.Context(), customerId, dto)
+	if err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (rc *ReturnController) CancelReturn(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rc.useCase.Cancel(c.Request.Context(), customerId, id); err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (rc *ReturnController) CustomerGetAllReturns(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto return_dto.CustomerReturnFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := rc.useCase.CustomerGetAll(c.Request.Context(), customerId, dto)
+	if err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (rc *ReturnController) GetAllReturns(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto return_dto.ReturnFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := rc.useCase.GetAll(c.Request.Context(), userId, dto)
+	if err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (rc *ReturnController) ApproveReturn(c *gin.Context) {
+	rc.review(c, rc.useCase.Approve)
+}
+
+func (rc *ReturnController) RejectReturn(c *gin.Context) {
+	rc.review(c, rc.useCase.Reject)
+}
+
+func (rc *ReturnController) RetryReturnRefund(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rc.useCase.RetryRefund(c.Request.Context(), userId, id); err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func (rc *ReturnController) ConfirmReturnRefund(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rc.useCase.ConfirmRefund(c.Request.Context(), userId, id); err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (rc *ReturnController) GetReturnWindow(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	siteId, err := http_helper.ParamId(c, "site_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	days, err := rc.useCase.GetWindow(c.Request.Context(), userId, siteId)
+	if err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"site_id": siteId, "days": days})
+}
+
+func (rc *ReturnController) UpdateReturnWindow(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto return_dto.UpdateReturnWindowDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rc.useCase.UpdateWindow(c.Request.Context(), userId, dto); err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (rc *ReturnController) review(c *gin.Context, action func(ctx context.Context, userId int64, dto return_dto.ReviewReturnDto) error) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto return_dto.ReviewReturnDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := action(c.Request.Context(), userId, dto); err != nil {
+		rc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (rc *ReturnController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, order_use_case.ErrReturnNotFound), errors.Is(err, order_use_case.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrSiteAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrReturnExists), errors.Is(err, order_use_case.ErrReturnChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, order_use_case.ErrReturnNotAllowed), errors.Is(err, order_use_case.ErrReturnWindowClosed),
+		errors.Is(err, order_use_case.ErrReturnState):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		rc.l.Error("order_controller - ReturnController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
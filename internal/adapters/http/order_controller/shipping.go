@@ -0,0 +1,119 @@
+package order_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/order/shipping_dto"
+	"site_builder_backend/internal/application/use_cases/shipping_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type ShippingController struct {
+	useCase *shipping_use_case.ShippingUseCase
+	l       *logger.ZapLogger
+}
+
+func NewShippingController(useCase *shipping_use_case.ShippingUseCase, l *logger.ZapLogger) *ShippingController {
+	return &ShippingController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (sc *ShippingController) CreateShippingMethod(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto shipping_dto.CreateShippingMethodDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := sc.useCase.Create(c.Request.Context(), userId, dto)
+	if err != nil {
+		sc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (sc *ShippingController) UpdateShippingMethod(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto shipping_dto.UpdateShippingMethodDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := sc.useCase.Update(c.Request.Context(), userId, dto)
+	if err != nil {
+		sc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (sc *ShippingController) DeleteShippingMethod(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sc.useCase.Delete(c.Request.Context(), userId, id); err != nil {
+		sc.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (sc *ShippingController) GetAllShippingMethods(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto shipping_dto.ShippingMethodFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := sc.useCase.GetAll(c.Request.Context(), userId, dto)
+	if err != nil {
+		sc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (sc *ShippingController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shipping_use_case.ErrShippingMethodNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, shipping_use_case.ErrSiteAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, shipping_use_case.ErrShippingCodeExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, shipping_use_case.ErrUnknownProvider):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		sc.l.Error("order_controller - ShippingController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,154 @@
+package payment_controller
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/payment/payment_dto"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/infrastructures/impl/payment/parbad_virtual"
+	"site_builder_backend/pkg/logger"
+)
+
+// virtualGatewayPage lets a developer accept or cancel a payment of the virtual gateway
+var virtualGatewayPage = template.Must(template.New("virtual").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Virtual gateway</title></head>
+<body>
+<h1>Virtual gateway</h1>
+<p>Payment {{.TrackingNumber}} of {{.Amount}} rials. Nothing is charged.</p>
+<form method="post" action="{{.Callback}}">
+<input type="hidden" name="` + parbad_virtual.CallbackToken + `" value="{{.Token}}">
+<button type="submit" name="` + parbad_virtual.CallbackStatus + `" value="` + parbad_virtual.StatusSucceeded + `">Pay</button>
+<button type="submit" name="` + parbad_virtual.CallbackStatus + `" value="` + parbad_virtual.StatusCancelled + `">Cancel</button>
+</form>
+</body>
+</html>`))
+
+type PaymentController struct {
+	useCase *payment_use_case.PaymentUseCase
+	l       *logger.ZapLogger
+}
+
+func NewPaymentController(useCase *payment_use_case.PaymentUseCase, l *logger.ZapLogger) *PaymentController {
+	return &PaymentController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (pc *PaymentController) GetGateways(c *gin.Context) {
+	var dto payment_dto.GatewayFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := pc.useCase.Gateways(c.Request.Context(), dto.SiteId)
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"gateways": result})
+}
+
+func (pc *PaymentController) StartPayment(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto payment_dto.StartPaymentDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := pc.useCase.Start(c.Request.Context(), customerId, dto, c.ClientIP())
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// VerifyPayment is the callback gateways send the customer back to, with a GET or a form POST. The
+// customer then continues to the return url of the payment with its outcome in the query.
+func (pc *PaymentController) VerifyPayment(c *gin.Context) {
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	trackingNumber, err := http_helper.ParamId(c, "tracking_number")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	callback := make(map[string]string, len(c.Request.Form))
+	for key := range c.Request.Form {
+		callback[key] = c.Request.Form.Get(key)
+	}
+
+	payment, err := pc.useCase.Verify(c.Request.Context(), id, trackingNumber, callback)
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	returnUrl, err := url.Parse(payment.ReturnUrl)
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	query := returnUrl.Query()
+	query.Set("payment_id", payment.Id)
+	query.Set("order_id", strconv.FormatInt(payment.OrderId, 10))
+	query.Set("status", payment.PaymentStatusEnum)
+	returnUrl.RawQuery = query.Encode()
+	c.Redirect(http.StatusSeeOther, returnUrl.String())
+}
+
+func (pc *PaymentController) VirtualGateway(c *gin.Context) {
+	var dto payment_dto.VirtualGatewayDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := pc.useCase.CheckCallbackUrl(dto.Callback); err != nil {
+		pc.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := virtualGatewayPage.Execute(c.Writer, dto); err != nil {
+		pc.l.Error("payment_controller - PaymentController - VirtualGateway: %v", err)
+	}
+}
+
+func (pc *PaymentController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, payment_use_case.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrPaymentChanged), errors.Is(err, payment_use_case.ErrPaymentClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrGatewayUnavailable), errors.Is(err, payment_use_case.ErrInvalidPaymentSite):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrInvalidCallbackUrl):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrGatewayFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		pc.l.Error("payment_controller - PaymentController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
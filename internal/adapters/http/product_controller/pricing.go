@@ -0,0 +1,39 @@
+package product_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/product/pricing_dto"
+	"site_builder_backend/internal/application/use_cases/pricing_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type PricingController struct {
+	useCase *pricing_use_case.PricingUseCase
+	l       *logger.ZapLogger
+}
+
+func NewPricingController(useCase *pricing_use_case.PricingUseCase, l *logger.ZapLogger) *PricingController {
+	return &PricingController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (p *PricingController) Quote(c *gin.Context) {
+	var dto pricing_dto.QuoteDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	customerId, _ := http_helper.CustomerId(c)
+
+	result, err := p.useCase.Quote(c.Request.Context(), customerId, dto)
+	if err != nil {
+		http_helper.HandlePricingError(c, p.l, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
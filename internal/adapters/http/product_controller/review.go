@@ -0,0 +1,210 @@
+package product_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/product/review_dto"
+	"site_builder_backend/internal/application/use_cases/product_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type ReviewController struct {
+	useCase *product_use_case.ReviewUseCase
+	l       *logger.ZapLogger
+}
+
+func NewReviewController(useCase *product_use_case.ReviewUseCase, l *logger.ZapLogger) *ReviewController {
+	return &ReviewController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (r *ReviewController) CreateReview(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto review_dto.CreateReviewDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := r.useCase.Create(c.Request.Context(), customerId, dto)
+	if err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, review)
+}
+
+func (r *ReviewController) UpdateReview(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto review_dto.UpdateReviewDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := r.useCase.Update(c.Request.Context(), customerId, dto)
+	if err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+func (r *ReviewController) DeleteReview(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := r.useCase.Delete(c.Request.Context(), customerId, id); err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (r *ReviewController) VoteReview(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto review_dto.VoteReviewDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := r.useCase.Vote(c.Request.Context(), customerId, dto); err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (r *ReviewController) RemoveReviewVote(c *gin.Context) {
+	customerId, err := http_helper.CustomerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := r.useCase.RemoveVote(c.Request.Context(), customerId, id); err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (r *ReviewController) GetAllReviews(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto review_dto.ReviewFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := r.useCase.GetAll(c.Request.Context(), userId, dto)
+	if err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *ReviewController) ApproveReview(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := r.useCase.Approve(c.Request.Context(), userId, id); err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (r *ReviewController) RejectReview(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := r.useCase.Reject(c.Request.Context(), userId, id); err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (r *ReviewController) PublicGetAllReviews(c *gin.Context) {
+	var dto review_dto.PublicReviewFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := r.useCase.PublicGetAll(c.Request.Context(), dto)
+	if err != nil {
+		r.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (r *ReviewController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, product_use_case.ErrProductNotFound), errors.Is(err, product_use_case.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, product_use_case.ErrSiteAccessDenied), errors.Is(err, product_use_case.ErrNotPurchased):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, product_use_case.ErrReviewExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, product_use_case.ErrOwnVote):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		r.l.Error("product_controller - ReviewController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,145 @@
+package user_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/user/credit_dto"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type CreditController struct {
+	useCase         *user_use_case.CreditUseCase
+	meteringUseCase *user_use_case.MeteringUseCase
+	l               *logger.ZapLogger
+}
+
+func NewCreditController(useCase *user_use_case.CreditUseCase, meteringUseCase *user_use_case.MeteringUseCase, l *logger.ZapLogger) *CreditController {
+	return &CreditController{
+		useCase:         useCase,
+		meteringUseCase: meteringUseCase,
+		l:               l,
+	}
+}
+
+func (cc *CreditController) GetUnitPrices(c *gin.Context) {
+	result, err := cc.useCase.UnitPrices(c.Request.Context())
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CreditController) GetBalance(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.Balance(c.Request.Context(), userId)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CreditController) GetLedger(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto credit_dto.LedgerFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.Ledger(c.Request.Context(), userId, dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CreditController) GetUsage(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto credit_dto.UsageFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.meteringUseCase.Usage(c.Request.Context(), userId, dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CreditController) QuoteTopUp(c *gin.Context) {
+	var dto credit_dto.TopUpQuoteDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.Quote(c.Request.Context(), dto)
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (cc *CreditController) TopUp(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto credit_dto.TopUpDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cc.useCase.TopUp(c.Request.Context(), userId, dto, c.ClientIP())
+	if err != nil {
+		cc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (cc *CreditController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user_use_case.ErrUnitPriceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, user_use_case.ErrTopUpDaysRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, user_use_case.ErrTopUpFree), errors.Is(err, user_use_case.ErrTopUpDisabled),
+		errors.Is(err, payment_use_case.ErrGatewayUnavailable), errors.Is(err, payment_use_case.ErrInvalidPaymentSite):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrPaymentChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrGatewayFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		cc.l.Error("user_controller - CreditController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
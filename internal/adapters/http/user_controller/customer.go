@@ -0,0 +1,115 @@
+package user_controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/user/customer_dto"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/domain/user_entity"
+	authImpl "site_builder_backend/internal/infrastructures/impl/auth"
+	"site_builder_backend/internal/interfaces/auth_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// CustomerController signs storefront customers in. The tokens it issues carry the customer role,
+// which is what http_helper.CustomerId accepts.
+type CustomerController struct {
+	useCase    *user_use_case.CustomerUseCase
+	jwtService auth_inter.JWTService
+	l          *logger.ZapLogger
+}
+
+func NewCustomerController(useCase *user_use_case.CustomerUseCase, jwtService auth_inter.JWTService, l *logger.ZapLogger) *CustomerController {
+	return &CustomerController{
+		useCase:    useCase,
+		jwtService: jwtService,
+		l:          l,
+	}
+}
+
+func (u *CustomerController) Register(c *gin.Context) {
+	var dto customer_dto.RegisterCustomerDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	customer, err := u.useCase.Register(c.Request.Context(), dto)
+	if err != nil {
+		u.handleError(c, err)
+		return
+	}
+	u.issueTokens(c, http.StatusCreated, customer)
+}
+
+func (u *CustomerController) Login(c *gin.Context) {
+	var dto customer_dto.LoginCustomerDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	customer, err := u.useCase.Login(c.Request.Context(), dto)
+	if err != nil {
+		u.handleError(c, err)
+		return
+	}
+	u.issueTokens(c, http.StatusOK, customer)
+}
+
+// RefreshToken trades a refresh token for new tokens, the customer role is carried over
+func (u *CustomerController) RefreshToken(c *gin.Context) {
+	var dto customer_dto.RefreshCustomerTokenDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := u.jwtService.ValidateToken(c.Request.Context(), dto.RefreshToken, auth_inter.RefreshToken)
+	if err != nil || claims["role"] != http_helper.CustomerRole {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+	tokens, err := u.jwtService.RefreshToken(c.Request.Context(), dto.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (u *CustomerController) issueTokens(c *gin.Context, status int, customer *user_entity.CustomerEntity) {
+	siteId, _ := strconv.ParseInt(customer.SiteId, 10, 64)
+	claimsBuilder := authImpl.NewClaimsBuilder().
+		WithSubject(customer.Id).
+		WithCustomClaim("role", http_helper.CustomerRole).
+		WithCustomClaim("site_id", siteId)
+
+	tokens, err := u.jwtService.Generate(c.Request.Context(), claimsBuilder)
+	if err != nil {
+		u.l.Error("user_controller - CustomerController - issueTokens: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(status, tokens)
+}
+
+func (u *CustomerController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user_use_case.ErrCustomerSiteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, user_use_case.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, user_use_case.ErrCustomerInactive):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, user_use_case.ErrCustomerExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		u.l.Error("user_controller - CustomerController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
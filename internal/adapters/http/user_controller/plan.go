@@ -0,0 +1,107 @@
+package user_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/user/plan_dto"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type PlanController struct {
+	useCase *user_use_case.PlanUseCase
+	l       *logger.ZapLogger
+}
+
+func NewPlanController(useCase *user_use_case.PlanUseCase, l *logger.ZapLogger) *PlanController {
+	return &PlanController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (pc *PlanController) GetAllPlans(c *gin.Context) {
+	result, err := pc.useCase.Plans(c.Request.Context())
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (pc *PlanController) GetSubscription(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := pc.useCase.Subscription(c.Request.Context(), userId)
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (pc *PlanController) QuotePlan(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := pc.useCase.Quote(c.Request.Context(), userId, id)
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (pc *PlanController) PurchasePlan(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto plan_dto.PurchasePlanDto
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := pc.useCase.Purchase(c.Request.Context(), userId, dto, c.ClientIP())
+	if err != nil {
+		pc.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
+func (pc *PlanController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user_use_case.ErrPlanNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, user_use_case.ErrPlanDowngrade), errors.Is(err, user_use_case.ErrPlanFree),
+		errors.Is(err, user_use_case.ErrPlanPaymentDisabled), errors.Is(err, payment_use_case.ErrGatewayUnavailable),
+		errors.Is(err, payment_use_case.ErrInvalidPaymentSite):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrPaymentChanged), errors.Is(err, user_use_case.ErrPlanPurchasePending):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, payment_use_case.ErrGatewayFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		pc.l.Error("user_controller - PlanController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,44 @@
+package user_controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/user/sms_dto"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type SmsController struct {
+	useCase *user_use_case.SmsUseCase
+	l       *logger.ZapLogger
+}
+
+func NewSmsController(useCase *user_use_case.SmsUseCase, l *logger.ZapLogger) *SmsController {
+	return &SmsController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (sc *SmsController) GetMessages(c *gin.Context) {
+	userId, err := http_helper.OwnerId(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	var dto sms_dto.MessageFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := sc.useCase.Messages(c.Request.Context(), userId, dto)
+	if err != nil {
+		sc.l.Error("user_controller - SmsController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,114 @@
+package visit_controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"site_builder_backend/internal/adapters/http/http_helper"
+	"site_builder_backend/internal/application/dto/visit/visit_dto"
+	"site_builder_backend/internal/application/use_cases/visit_use_case"
+	"site_builder_backend/pkg/logger"
+)
+
+type VisitController struct {
+	useCase *visit_use_case.VisitUseCase
+	l       *logger.ZapLogger
+}
+
+func NewVisitController(useCase *visit_use_case.VisitUseCase, l *logger.ZapLogger) *VisitController {
+	return &VisitController{
+		useCase: useCase,
+		l:       l,
+	}
+}
+
+func (v *VisitController) TrackProductVisit(c *gin.Context) {
+	siteId, id, ok := v.pathIds(c)
+	if !ok {
+		return
+	}
+	if err := v.useCase.TrackProduct(c.Request.Context(), siteId, id, http_helper.VisitorId(c)); err != nil {
+		v.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (v *VisitController) TrackArticleVisit(c *gin.Context) {
+	siteId, id, ok := v.pathIds(c)
+	if !ok {
+		return
+	}
+	if err := v.useCase.TrackArticle(c.Request.Context(), siteId, id, http_helper.VisitorId(c)); err != nil {
+		v.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (v *VisitController) MostViewedProducts(c *gin.Context) {
+	siteId, err := http_helper.ParamId(c, "site_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dto visit_dto.MostViewedFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := v.useCase.MostViewedProducts(c.Request.Context(), siteId, dto)
+	if err != nil {
+		v.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (v *VisitController) MostViewedArticles(c *gin.Context) {
+	siteId, err := http_helper.ParamId(c, "site_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var dto visit_dto.MostViewedFilterDto
+	if err := c.ShouldBindQuery(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := v.useCase.MostViewedArticles(c.Request.Context(), siteId, dto)
+	if err != nil {
+		v.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (v *VisitController) pathIds(c *gin.Context) (int64, int64, bool) {
+	siteId, err := http_helper.ParamId(c, "site_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+	id, err := http_helper.ParamId(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+	return siteId, id, true
+}
+
+func (v *VisitController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, visit_use_case.ErrInvalidVisitor):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, visit_use_case.ErrPageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		v.l.Error("visit_controller - VisitController: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	}
+}
@@ -0,0 +1,32 @@
+package drive_job
+
+import (
+	"context"
+	"time"
+
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+)
+
+type DriveJob struct {
+	useCase      *drive_use_case.UploadUseCase
+	imageUseCase *drive_use_case.ImageUseCase
+}
+
+func NewDriveJob(useCase *drive_use_case.UploadUseCase, imageUseCase *drive_use_case.ImageUseCase) *DriveJob {
+	return &DriveJob{
+		useCase:      useCase,
+		imageUseCase: imageUseCase,
+	}
+}
+
+// ExpireUploads drops the uploads left open past their expiry
+func (j *DriveJob) ExpireUploads(ctx context.Context) error {
+	_, err := j.useCase.ExpireUploads(ctx, time.Now())
+	return err
+}
+
+// RequeueImages queues again the images whose processing was lost
+func (j *DriveJob) RequeueImages(ctx context.Context) error {
+	_, err := j.imageUseCase.RequeuePending(ctx, time.Now())
+	return err
+}
@@ -0,0 +1,26 @@
+package payment_job
+
+import (
+	"context"
+	"time"
+
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+)
+
+type PaymentJob struct {
+	useCase        *payment_use_case.PaymentUseCase
+	reconcileAfter time.Duration
+}
+
+func NewPaymentJob(useCase *payment_use_case.PaymentUseCase, reconcileAfter time.Duration) *PaymentJob {
+	return &PaymentJob{
+		useCase:        useCase,
+		reconcileAfter: reconcileAfter,
+	}
+}
+
+// Reconcile settles or fails the payments left pending for longer than reconcileAfter
+func (j *PaymentJob) Reconcile(ctx context.Context) error {
+	_, err := j.useCase.Reconcile(ctx, time.Now().Add(-j.reconcileAfter))
+	return err
+}
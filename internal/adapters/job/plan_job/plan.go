@@ -0,0 +1,32 @@
+package plan_job
+
+import (
+	"context"
+	"time"
+
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+)
+
+type PlanJob struct {
+	useCase        *user_use_case.PlanUseCase
+	reminderBefore time.Duration
+}
+
+func NewPlanJob(useCase *user_use_case.PlanUseCase, reminderBefore time.Duration) *PlanJob {
+	return &PlanJob{
+		useCase:        useCase,
+		reminderBefore: reminderBefore,
+	}
+}
+
+// Expire downgrades the owners whose plan ran out
+func (j *PlanJob) Expire(ctx context.Context) error {
+	_, err := j.useCase.ExpirePlans(ctx, time.Now())
+	return err
+}
+
+// Remind lets owners know their plan runs out within reminderBefore
+func (j *PlanJob) Remind(ctx context.Context) error {
+	_, err := j.useCase.RemindExpiring(ctx, time.Now(), j.reminderBefore)
+	return err
+}
@@ -0,0 +1,22 @@
+package visit_job
+
+import (
+	"context"
+
+	"site_builder_backend/internal/application/use_cases/visit_use_case"
+)
+
+type VisitJob struct {
+	useCase *visit_use_case.VisitUseCase
+}
+
+func NewVisitJob(useCase *visit_use_case.VisitUseCase) *VisitJob {
+	return &VisitJob{
+		useCase: useCase,
+	}
+}
+
+// FlushVisits writes the visit counters buffered in Redis to the database
+func (j *VisitJob) FlushVisits(ctx context.Context) error {
+	return j.useCase.Flush(ctx)
+}
@@ -0,0 +1,90 @@
+package article_dto
+
+import (
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/blog_entity"
+)
+
+type CreateArticleDto struct {
+	SiteId      int64     `json:"site_id" binding:"required"`
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	Slug        string    `json:"slug"`
+	Badges      string    `json:"badges"`
+	SeoTags     string    `json:"seo_tags"`
+	CategoryIds []int64   `json:"category_ids"`
+	Status      string    `json:"status" binding:"omitempty,oneof=draft published scheduled"`
+	PublishAt   time.Time `json:"publish_at"`
+}
+
+type UpdateArticleDto struct {
+	Id          int64     `json:"id" binding:"required"`
+	Title       string    `json:"title" binding:"required"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	Slug        string    `json:"slug"`
+	Badges      string    `json:"badges"`
+	SeoTags     string    `json:"seo_tags"`
+	CategoryIds []int64   `json:"category_ids"`
+	Status      string    `json:"status" binding:"omitempty,oneof=draft published scheduled"`
+	PublishAt   time.Time `json:"publish_at"`
+}
+
+type ChangeArticleStatusDto struct {
+	Id        int64     `json:"id" binding:"required"`
+	Status    string    `json:"status" binding:"required,oneof=draft published scheduled"`
+	PublishAt time.Time `json:"publish_at"`
+}
+
+type ArticleFilterDto struct {
+	common_dto.PaginationDto
+	SiteId     int64  `form:"site_id"`
+	CategoryId int64  `form:"category_id"`
+	Status     string `form:"status" binding:"omitempty,oneof=draft published scheduled"`
+}
+
+type PublicArticleFilterDto struct {
+	common_dto.PaginationDto
+	SiteId     int64 `form:"site_id" binding:"required"`
+	CategoryId int64 `form:"category_id"`
+}
+
+type ArticleDetailDto struct {
+	Article *blog_entity.ArticleEntity  `json:"article"`
+	Related []blog_entity.ArticleEntity `json:"related"`
+}
+
+func (d CreateArticleDto) ToArticleEntity() *blog_entity.ArticleEntity {
+	return &blog_entity.ArticleEntity{
+		Title:       d.Title,
+		Description: d.Description,
+		Body:        d.Body,
+		Slug:        d.Slug,
+		SiteId:      strconv.FormatInt(d.SiteId, 10),
+		Badges:      d.Badges,
+		SeoTags:     d.SeoTags,
+		Status:      d.Status,
+	}
+}
+
+// ApplyTo copies the editable fields onto an existing article, the slug is left to the use case
+func (d UpdateArticleDto) ApplyTo(entity *blog_entity.ArticleEntity) {
+	entity.Title = d.Title
+	entity.Description = d.Description
+	entity.Body = d.Body
+	entity.Badges = d.Badges
+	entity.SeoTags = d.SeoTags
+	if d.Status != "" {
+		entity.Status = d.Status
+	}
+}
+
+type SearchArticleDto struct {
+	common_dto.PaginationDto
+	SiteId int64  `form:"site_id" binding:"required"`
+	Query  string `form:"q" binding:"required"`
+}
@@ -0,0 +1,65 @@
+package comment_dto
+
+import (
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/blog_entity"
+)
+
+type CreateCommentDto struct {
+	ArticleId  int64  `json:"article_id" binding:"required"`
+	ParentId   int64  `json:"parent_id"`
+	GuestName  string `json:"guest_name" binding:"max=100"`
+	GuestEmail string `json:"guest_email" binding:"omitempty,email,max=255"`
+	Body       string `json:"body" binding:"required,max=5000"`
+	Rating     int    `json:"rating" binding:"omitempty,min=1,max=5"`
+}
+
+type CommentFilterDto struct {
+	common_dto.PaginationDto
+	SiteId    int64  `form:"site_id" binding:"required"`
+	ArticleId int64  `form:"article_id"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending approved spam"`
+}
+
+type PublicCommentFilterDto struct {
+	common_dto.PaginationDto
+	ArticleId int64 `form:"article_id" binding:"required"`
+}
+
+// CommentAuthorDto describes who posts a comment. CustomerId is zero for guests.
+type CommentAuthorDto struct {
+	CustomerId int64
+	IpAddress  string
+	UserAgent  string
+}
+
+// CommentThreadDto is an approved comment with its approved replies
+type CommentThreadDto struct {
+	blog_entity.ArticleCommentEntity
+	Replies []*CommentThreadDto `json:"replies"`
+}
+
+func (dto CreateCommentDto) ToArticleCommentEntity(article *blog_entity.ArticleEntity, author CommentAuthorDto) *blog_entity.ArticleCommentEntity {
+	now := time.Now()
+	entity := &blog_entity.ArticleCommentEntity{
+		ArticleId: article.Id,
+		SiteId:    article.SiteId,
+		Body:      dto.Body,
+		Rating:    dto.Rating,
+		Status:    blog_entity.CommentStatusPending,
+		IpAddress: author.IpAddress,
+		UserAgent: author.UserAgent,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if author.CustomerId > 0 {
+		entity.CustomerId = strconv.FormatInt(author.CustomerId, 10)
+	} else {
+		entity.GuestName = dto.GuestName
+		entity.GuestEmail = dto.GuestEmail
+	}
+	return entity
+}
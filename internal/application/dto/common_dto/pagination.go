@@ -0,0 +1,54 @@
+package common_dto
+
+const (
+	_defaultPageSize = 20
+	_maxPageSize     = 100
+)
+
+type PaginationDto struct {
+	Page     int `json:"page" form:"page"`
+	PageSize int `json:"page_size" form:"page_size"`
+}
+
+// Normalize clamps the page to at least 1 and the page size to the allowed range
+func (p PaginationDto) Normalize() PaginationDto {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PageSize < 1 {
+		p.PageSize = _defaultPageSize
+	}
+	if p.PageSize > _maxPageSize {
+		p.PageSize = _maxPageSize
+	}
+	return p
+}
+
+func (p PaginationDto) Offset() int {
+	n := p.Normalize()
+	return (n.Page - 1) * n.PageSize
+}
+
+func (p PaginationDto) Limit() int {
+	return p.Normalize().PageSize
+}
+
+type PaginatedDto[T any] struct {
+	Items    []T   `json:"items"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+}
+
+func NewPaginatedDto[T any](items []T, total int64, pagination PaginationDto) PaginatedDto[T] {
+	n := pagination.Normalize()
+	if items == nil {
+		items = []T{}
+	}
+	return PaginatedDto[T]{
+		Items:    items,
+		Total:    total,
+		Page:     n.Page,
+		PageSize: n.PageSize,
+	}
+}
@@ -0,0 +1,63 @@
+package file_dto
+
+import (
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/drive_entity"
+)
+
+// UploadFileDto uploads a file in one request into the folder ParentId, the root when zero. Name
+// defaults to the name of the uploaded file.
+type UploadFileDto struct {
+	ParentId int64  `form:"parent_id"`
+	Name     string `form:"name" binding:"max=255"`
+}
+
+// StartUploadDto starts an upload in parts of a file of Size bytes
+type StartUploadDto struct {
+	ParentId int64  `json:"parent_id"`
+	Name     string `json:"name" binding:"required,max=255"`
+	Size     int64  `json:"size" binding:"required,min=1"`
+	MimeType string `json:"mime_type" binding:"max=255"`
+}
+
+// UploadDto is an upload with the numbers of the parts still to be sent, for clients resuming it
+type UploadDto struct {
+	drive_entity.UploadEntity
+	PartCount    int   `json:"part_count"`
+	MissingParts []int `json:"missing_parts"`
+}
+
+// CreateFolderDto creates a folder in the folder ParentId, the root when zero
+type CreateFolderDto struct {
+	ParentId int64  `json:"parent_id"`
+	Name     string `json:"name" binding:"required,max=255"`
+}
+
+// FolderFilterDto lists the items of the folder ParentId, the root when zero
+type FolderFilterDto struct {
+	common_dto.PaginationDto
+	ParentId int64 `form:"parent_id"`
+}
+
+// TrashFilterDto lists the deleted items of the user
+type TrashFilterDto struct {
+	common_dto.PaginationDto
+}
+
+// DestinationDto moves or copies an item into the folder ParentId, the root when zero. When the folder
+// has an item of the same name OnConflict "rename" numbers the name, "fail", the default, refuses.
+type DestinationDto struct {
+	ParentId   int64  `json:"parent_id"`
+	OnConflict string `json:"on_conflict" binding:"omitempty,oneof=fail rename"`
+}
+
+// RenameDto renames an item, OnConflict is as for DestinationDto
+type RenameDto struct {
+	Name       string `json:"name" binding:"required,max=255"`
+	OnConflict string `json:"on_conflict" binding:"omitempty,oneof=fail rename"`
+}
+
+// RestoreDto restores a deleted item, OnConflict is as for DestinationDto
+type RestoreDto struct {
+	OnConflict string `form:"on_conflict" binding:"omitempty,oneof=fail rename"`
+}
@@ -0,0 +1,21 @@
+package event_dto
+
+import "time"
+
+const (
+	RoutingKeyArticleCommentCreated = "blog.comment.created"
+)
+
+// ArticleCommentCreatedEvent lets the site owner know a comment is waiting for moderation
+type ArticleCommentCreatedEvent struct {
+	CommentId    string    `json:"comment_id"`
+	ArticleId    string    `json:"article_id"`
+	ArticleTitle string    `json:"article_title"`
+	SiteId       string    `json:"site_id"`
+	OwnerUserId  string    `json:"owner_user_id"`
+	AuthorName   string    `json:"author_name,omitempty"`
+	CustomerId   string    `json:"customer_id,omitempty"`
+	Excerpt      string    `json:"excerpt"`
+	Rating       int       `json:"rating,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
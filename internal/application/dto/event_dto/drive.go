@@ -0,0 +1,12 @@
+package event_dto
+
+import "time"
+
+const RoutingKeyImageStored = "drive.image.stored"
+
+// ImageStoredEvent asks for the thumbnail and responsive sizes of an image file stored in a drive
+type ImageStoredEvent struct {
+	FileItemId string    `json:"file_item_id"`
+	UserId     string    `json:"user_id"`
+	StoredAt   time.Time `json:"stored_at"`
+}
@@ -0,0 +1,38 @@
+package event_dto
+
+import "time"
+
+// Order events use "order.<status>" as routing key, so consumers can bind "order.*" or a single status
+const (
+	RoutingKeyOrderPrefix = "order."
+	RoutingKeyOrderPlaced = "order.placed"
+	// Return keys have three words and never match "order.*"
+	RoutingKeyReturnRequested = "order.return.requested"
+)
+
+// OrderStatusChangedEvent is published for every status transition of an order and, with the
+// order.placed key and an empty FromStatus, when the order is placed
+type OrderStatusChangedEvent struct {
+	OrderId         string    `json:"order_id"`
+	SiteId          string    `json:"site_id"`
+	CustomerId      string    `json:"customer_id"`
+	FromStatus      string    `json:"from_status,omitempty"`
+	ToStatus        string    `json:"to_status"`
+	ActorType       string    `json:"actor_type"`
+	ActorId         string    `json:"actor_id,omitempty"`
+	TrackingCode    string    `json:"tracking_code,omitempty"`
+	TotalFinalPrice int64     `json:"total_final_price"`
+	ChangedAt       time.Time `json:"changed_at"`
+}
+
+// ReturnRequestedEvent lets the site owner know a customer asked to return an item
+type ReturnRequestedEvent struct {
+	ReturnId     string    `json:"return_id"`
+	OrderId      string    `json:"order_id"`
+	OrderItemId  string    `json:"order_item_id"`
+	SiteId       string    `json:"site_id"`
+	OwnerUserId  string    `json:"owner_user_id"`
+	CustomerId   string    `json:"customer_id"`
+	ReturnReason string    `json:"return_reason"`
+	RequestedAt  time.Time `json:"requested_at"`
+}
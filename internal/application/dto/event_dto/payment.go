@@ -0,0 +1,39 @@
+package event_dto
+
+import "time"
+
+const (
+	RoutingKeyRefundRequested      = "payment.refund.requested"
+	RoutingKeyReconciliationReport = "payment.reconciliation.reported"
+)
+
+// RefundRequestedEvent asks the payment subsystem to pay an amount of an order back to the customer.
+// ReturnId identifies the request, so a republished event must not refund twice.
+type RefundRequestedEvent struct {
+	ReturnId    string    `json:"return_id"`
+	OrderId     string    `json:"order_id"`
+	OrderItemId string    `json:"order_item_id"`
+	SiteId      string    `json:"site_id"`
+	CustomerId  string    `json:"customer_id"`
+	Amount      int64     `json:"amount"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// ReconciliationReportEvent sums up a run of the reconciler over payments left pending, or a callback
+// settling a payment its order could not take. Mismatches are payments the gateway and the orders disagree
+// on, they need someone to look at them.
+type ReconciliationReportEvent struct {
+	Checked      int               `json:"checked"`
+	Settled      int               `json:"settled"`
+	Failed       int               `json:"failed"`
+	Unreachable  int               `json:"unreachable"`
+	Mismatches   []PaymentMismatch `json:"mismatches"`
+	ReconciledAt time.Time         `json:"reconciled_at"`
+}
+
+type PaymentMismatch struct {
+	PaymentId      string `json:"payment_id"`
+	OrderId        int64  `json:"order_id"`
+	TrackingNumber int64  `json:"tracking_number"`
+	Reason         string `json:"reason"`
+}
@@ -0,0 +1,25 @@
+package event_dto
+
+import "time"
+
+const (
+	RoutingKeyPlanActivated = "plan.subscription.activated"
+	// RoutingKeyPlanExpiring is published once per subscription period, ahead of its expiry
+	RoutingKeyPlanExpiring = "plan.subscription.expiring"
+	RoutingKeyPlanExpired  = "plan.subscription.expired"
+	// RoutingKeyPlanSubscriptions binds a queue to all of the above
+	RoutingKeyPlanSubscriptions = "plan.subscription.*"
+)
+
+// PlanSubscriptionEvent tells notification consumers about the plan of a site owner. Action is the
+// purchase action for activations and empty otherwise.
+type PlanSubscriptionEvent struct {
+	UserId     string    `json:"user_id"`
+	PlanId     string    `json:"plan_id"`
+	PlanName   string    `json:"plan_name"`
+	Action     string    `json:"action,omitempty"`
+	Email      string    `json:"email"`
+	Phone      string    `json:"phone,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,30 @@
+package inbox_dto
+
+import "site_builder_backend/internal/application/dto/common_dto"
+
+type NotificationFilterDto struct {
+	common_dto.PaginationDto
+	Unread bool `form:"unread"`
+}
+
+// MarkReadDto marks the notifications among Ids as read, all of the user's when Ids is empty
+type MarkReadDto struct {
+	Ids []int64 `json:"ids" binding:"max=500"`
+}
+
+type UnreadDto struct {
+	Unread int64 `json:"unread"`
+}
+
+// NotifyDto is a notification for the inbox of UserId. EventKey identifies what it was made for, a
+// second notification with the same key is ignored. SiteId is zero for notifications about the account.
+type NotifyDto struct {
+	UserId        int64
+	SiteId        int64
+	Type          string
+	Title         string
+	Body          string
+	ReferenceType string
+	ReferenceId   int64
+	EventKey      string
+}
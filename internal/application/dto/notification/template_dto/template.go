@@ -0,0 +1,65 @@
+package template_dto
+
+import "time"
+
+type TemplateFilterDto struct {
+	SiteId int64 `form:"site_id" binding:"required"`
+}
+
+type TemplateKeyDto struct {
+	SiteId  int64  `json:"site_id" form:"site_id" binding:"required"`
+	Channel string `json:"channel" form:"channel" binding:"required,oneof=sms email"`
+	Event   string `json:"event" form:"event" binding:"required,max=50"`
+	Locale  string `json:"locale" form:"locale" binding:"required,oneof=fa en"`
+}
+
+// SaveTemplateDto replaces the default text of an event. Subject and Html are used by email only, an
+// email without Html gets the Body as its HTML.
+type SaveTemplateDto struct {
+	TemplateKeyDto
+	Subject string `json:"subject" binding:"max=250"`
+	Body    string `json:"body" binding:"required,max=2000"`
+	Html    string `json:"html" binding:"max=100000"`
+}
+
+// PreviewTemplateDto renders the saved or default text of an event, or a draft when Body is set.
+// Variables missing from Data are filled with example values.
+type PreviewTemplateDto struct {
+	TemplateKeyDto
+	Subject string                 `json:"subject" binding:"max=250"`
+	Body    string                 `json:"body" binding:"max=2000"`
+	Html    string                 `json:"html" binding:"max=100000"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+// TemplateDto is the text an event is sent with, Customized is false while the default text is used
+type TemplateDto struct {
+	Channel    string     `json:"channel"`
+	Event      string     `json:"event"`
+	Locale     string     `json:"locale"`
+	Subject    string     `json:"subject,omitempty"`
+	Body       string     `json:"body"`
+	Html       string     `json:"html,omitempty"`
+	Customized bool       `json:"customized"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+type RenderedDto struct {
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body"`
+	Html    string `json:"html,omitempty"`
+	// Missing lists the variables that had no usable value and were filled with their fallback
+	Missing []string `json:"missing,omitempty"`
+}
+
+type VariableDto struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Example string `json:"example"`
+}
+
+type EventDto struct {
+	Event     string        `json:"event"`
+	Channels  []string      `json:"channels"`
+	Variables []VariableDto `json:"variables"`
+}
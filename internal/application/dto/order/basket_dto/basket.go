@@ -0,0 +1,30 @@
+package basket_dto
+
+// MaxItemQuantity bounds the quantity of a single variant in a basket
+const MaxItemQuantity = 1000
+
+type GetBasketDto struct {
+	SiteId int64 `form:"site_id" binding:"required"`
+}
+
+type BasketItemDto struct {
+	SiteId           int64 `json:"site_id" binding:"required"`
+	ProductVariantId int64 `json:"product_variant_id" binding:"required"`
+	Quantity         int   `json:"quantity" binding:"required,min=1,max=1000"`
+}
+
+type RemoveBasketItemDto struct {
+	SiteId           int64 `json:"site_id" binding:"required"`
+	ProductVariantId int64 `json:"product_variant_id" binding:"required"`
+}
+
+// ApplyDiscountDto attaches a discount code to the basket, an empty code removes it
+type ApplyDiscountDto struct {
+	SiteId int64  `json:"site_id" binding:"required"`
+	Code   string `json:"code" binding:"max=100"`
+}
+
+type MergeBasketDto struct {
+	SiteId     int64  `json:"site_id" binding:"required"`
+	GuestToken string `json:"guest_token" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package checkout_dto
+
+type CheckoutDto struct {
+	SiteId    int64 `json:"site_id" binding:"required"`
+	AddressId int64 `json:"address_id" binding:"required"`
+	// Courier is the code of one of the site's shipping methods
+	Courier     string `json:"courier" binding:"required,max=50"`
+	Description string `json:"description" binding:"max=1000"`
+	// ReturnUrl is where the payment gateway sends the customer back to
+	ReturnUrl string `json:"return_url" binding:"required,url"`
+}
+
+// CheckoutClientDto carries what the controller knows about the request itself
+type CheckoutClientDto struct {
+	IdempotencyKey string
+	ClientIp       string
+}
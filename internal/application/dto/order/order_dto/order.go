@@ -0,0 +1,31 @@
+package order_dto
+
+import (
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/order_entity"
+)
+
+type OrderFilterDto struct {
+	common_dto.PaginationDto
+	SiteId int64  `form:"site_id" binding:"required"`
+	Status string `form:"status" binding:"omitempty,oneof=pending_payment paid processing shipped delivered cancelled refunded"`
+}
+
+type CustomerOrderFilterDto struct {
+	common_dto.PaginationDto
+	SiteId int64  `form:"site_id" binding:"required"`
+	Status string `form:"status" binding:"omitempty,oneof=pending_payment paid processing shipped delivered cancelled refunded"`
+}
+
+// ChangeOrderStatusDto is used by site owners. Shipping an order requires its tracking code.
+type ChangeOrderStatusDto struct {
+	Id           int64  `json:"id" binding:"required"`
+	Status       string `json:"status" binding:"required,oneof=processing shipped delivered cancelled refunded"`
+	TrackingCode string `json:"tracking_code" binding:"max=100"`
+	Note         string `json:"note" binding:"max=500"`
+}
+
+type OrderDetailsDto struct {
+	Order   *order_entity.OrderEntity               `json:"order"`
+	History []order_entity.OrderStatusHistoryEntity `json:"history"`
+}
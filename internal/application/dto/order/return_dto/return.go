@@ -0,0 +1,46 @@
+package return_dto
+
+import (
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/order_entity"
+)
+
+type RequestReturnDto struct {
+	OrderItemId  int64  `json:"order_item_id" binding:"required"`
+	ReturnReason string `json:"return_reason" binding:"required,max=2000"`
+}
+
+type ReturnFilterDto struct {
+	common_dto.PaginationDto
+	SiteId int64  `form:"site_id" binding:"required"`
+	Status string `form:"status" binding:"omitempty,oneof=requested approved rejected refunded cancelled"`
+}
+
+type CustomerReturnFilterDto struct {
+	common_dto.PaginationDto
+	SiteId int64 `form:"site_id" binding:"required"`
+}
+
+// ReviewReturnDto approves or rejects a requested return
+type ReviewReturnDto struct {
+	Id   int64  `json:"id" binding:"required"`
+	Note string `json:"note" binding:"max=500"`
+}
+
+type UpdateReturnWindowDto struct {
+	SiteId int64 `json:"site_id" binding:"required"`
+	Days   *int  `json:"days" binding:"required,min=0,max=365"`
+}
+
+// ReturnDto adds the name of the state to a return
+type ReturnDto struct {
+	order_entity.ReturnItemEntity
+	Status string `json:"status"`
+}
+
+func NewReturnDto(entity order_entity.ReturnItemEntity) ReturnDto {
+	return ReturnDto{
+		ReturnItemEntity: entity,
+		Status:           order_entity.ReturnStatusName(entity.OrderStatus),
+	}
+}
@@ -0,0 +1,99 @@
+package shipping_dto
+
+import (
+	"strconv"
+
+	"site_builder_backend/internal/domain/order_entity"
+)
+
+type ShippingRateDto struct {
+	// ProvinceId limits the rate to one province, zero applies it everywhere
+	ProvinceId int64 `json:"province_id" binding:"min=0"`
+	// MaxWeight in grams, zero covers any weight
+	MaxWeight int   `json:"max_weight" binding:"min=0"`
+	Price     int64 `json:"price" binding:"min=0"`
+}
+
+type CreateShippingMethodDto struct {
+	SiteId         int64             `json:"site_id" binding:"required"`
+	Code           string            `json:"code" binding:"required,max=50"`
+	Title          string            `json:"title" binding:"required,max=100"`
+	Provider       string            `json:"provider" binding:"required,max=30"`
+	BasePrice      int64             `json:"base_price" binding:"min=0"`
+	EstimatedDays  int               `json:"estimated_days" binding:"min=0,max=365"`
+	FreeOverAmount int64             `json:"free_over_amount" binding:"min=0"`
+	IsActive       *bool             `json:"is_active"`
+	Rates          []ShippingRateDto `json:"rates" binding:"max=500,dive"`
+}
+
+type UpdateShippingMethodDto struct {
+	Id             int64             `json:"id" binding:"required"`
+	Code           string            `json:"code" binding:"required,max=50"`
+	Title          string            `json:"title" binding:"required,max=100"`
+	Provider       string            `json:"provider" binding:"required,max=30"`
+	BasePrice      int64             `json:"base_price" binding:"min=0"`
+	EstimatedDays  int               `json:"estimated_days" binding:"min=0,max=365"`
+	FreeOverAmount int64             `json:"free_over_amount" binding:"min=0"`
+	IsActive       *bool             `json:"is_active"`
+	Rates          []ShippingRateDto `json:"rates" binding:"max=500,dive"`
+}
+
+type ShippingMethodFilterDto struct {
+	SiteId int64 `form:"site_id" binding:"required"`
+}
+
+// ShippingQuoteDto asks for the couriers able to ship the customer's basket to an address
+type ShippingQuoteDto struct {
+	SiteId    int64 `form:"site_id" binding:"required"`
+	AddressId int64 `form:"address_id" binding:"required"`
+}
+
+type ShippingOptionDto struct {
+	Code          string `json:"code"`
+	Title         string `json:"title"`
+	Price         int64  `json:"price"`
+	EstimatedDays int    `json:"estimated_days"`
+	FreeShipping  bool   `json:"free_shipping"`
+	// TotalFinalPrice is what the order would cost with this courier
+	TotalFinalPrice int64 `json:"total_final_price,omitempty"`
+}
+
+type ShippingQuoteResultDto struct {
+	TotalPriceWithCouponDiscount int64               `json:"total_price_with_coupon_discount"`
+	TotalWeight                  int                 `json:"total_weight"`
+	Options                      []ShippingOptionDto `json:"options"`
+}
+
+func (d CreateShippingMethodDto) ToShippingMethodEntity() *order_entity.ShippingMethodEntity {
+	entity := &order_entity.ShippingMethodEntity{
+		SiteId:   strconv.FormatInt(d.SiteId, 10),
+		IsActive: d.IsActive == nil || *d.IsActive,
+	}
+	applyMethod(entity, d.Code, d.Title, d.Provider, d.BasePrice, d.EstimatedDays, d.FreeOverAmount, d.Rates)
+	return entity
+}
+
+// ApplyTo copies the editable fields onto an existing method, its rates are replaced
+func (d UpdateShippingMethodDto) ApplyTo(entity *order_entity.ShippingMethodEntity) {
+	if d.IsActive != nil {
+		entity.IsActive = *d.IsActive
+	}
+	applyMethod(entity, d.Code, d.Title, d.Provider, d.BasePrice, d.EstimatedDays, d.FreeOverAmount, d.Rates)
+}
+
+func applyMethod(entity *order_entity.ShippingMethodEntity, code, title, provider string, basePrice int64, estimatedDays int, freeOverAmount int64, rates []ShippingRateDto) {
+	entity.Code = code
+	entity.Title = title
+	entity.Provider = provider
+	entity.BasePrice = basePrice
+	entity.EstimatedDays = estimatedDays
+	entity.FreeOverAmount = freeOverAmount
+	entity.Rates = make([]order_entity.ShippingRateEntity, 0, len(rates))
+	for _, rate := range rates {
+		item := order_entity.ShippingRateEntity{MaxWeight: rate.MaxWeight, Price: rate.Price}
+		if rate.ProvinceId > 0 {
+			item.ProvinceId = strconv.FormatInt(rate.ProvinceId, 10)
+		}
+		entity.Rates = append(entity.Rates, item)
+	}
+}
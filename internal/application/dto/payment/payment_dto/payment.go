@@ -0,0 +1,27 @@
+package payment_dto
+
+import "site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+
+type GatewayFilterDto struct {
+	SiteId int64 `form:"site_id" binding:"required"`
+}
+
+// StartPaymentDto sends the pending payment of an order to one of the site's gateways
+type StartPaymentDto struct {
+	OrderId int64  `json:"order_id" binding:"required"`
+	Gateway string `json:"gateway" binding:"required,max=30"`
+}
+
+type StartPaymentResultDto struct {
+	PaymentId      int64                           `json:"payment_id"`
+	TrackingNumber int64                           `json:"tracking_number"`
+	Redirect       *payment_gateway_inter.Redirect `json:"redirect"`
+}
+
+// VirtualGatewayDto is what the virtual gateway page is opened with
+type VirtualGatewayDto struct {
+	TrackingNumber int64  `form:"tracking_number" binding:"required"`
+	Token          string `form:"token" binding:"required,max=100"`
+	Amount         int64  `form:"amount"`
+	Callback       string `form:"callback" binding:"required,url"`
+}
@@ -0,0 +1,12 @@
+package pricing_dto
+
+type QuoteItemDto struct {
+	ProductVariantId int64 `json:"product_variant_id" binding:"required"`
+	Quantity         int   `json:"quantity" binding:"required,min=1"`
+}
+
+type QuoteDto struct {
+	SiteId       int64          `json:"site_id" binding:"required"`
+	Items        []QuoteItemDto `json:"items" binding:"required,min=1,dive"`
+	DiscountCode string         `json:"discount_code"`
+}
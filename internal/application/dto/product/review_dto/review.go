@@ -0,0 +1,57 @@
+package review_dto
+
+import (
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/product_entity"
+)
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+)
+
+type CreateReviewDto struct {
+	ProductId  int64  `json:"product_id" binding:"required"`
+	Rating     int    `json:"rating" binding:"required,min=1,max=5"`
+	ReviewText string `json:"review_text" binding:"required,max=4000"`
+}
+
+type UpdateReviewDto struct {
+	Id         int64  `json:"id" binding:"required"`
+	Rating     int    `json:"rating" binding:"required,min=1,max=5"`
+	ReviewText string `json:"review_text" binding:"required,max=4000"`
+}
+
+type VoteReviewDto struct {
+	Id   int64  `json:"id" binding:"required"`
+	Vote string `json:"vote" binding:"required,oneof=like dislike"`
+}
+
+type ReviewFilterDto struct {
+	common_dto.PaginationDto
+	SiteId    int64  `form:"site_id" binding:"required"`
+	ProductId int64  `form:"product_id"`
+	Status    string `form:"status" binding:"omitempty,oneof=pending approved"`
+}
+
+type PublicReviewFilterDto struct {
+	common_dto.PaginationDto
+	ProductId int64 `form:"product_id" binding:"required"`
+}
+
+func (dto CreateReviewDto) ToProductReviewEntity(product *product_entity.ProductEntity, customerId int64) *product_entity.ProductReviewEntity {
+	now := time.Now()
+	return &product_entity.ProductReviewEntity{
+		Rating:     dto.Rating,
+		ReviewText: dto.ReviewText,
+		ProductId:  product.Id,
+		SiteId:     product.SiteId,
+		UserId:     product.UserId,
+		CustomerId: strconv.FormatInt(customerId, 10),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
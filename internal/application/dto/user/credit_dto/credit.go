@@ -0,0 +1,61 @@
+package credit_dto
+
+import (
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+)
+
+// TopUpQuoteDto prices a quantity of credits. Days is required by unit prices that are per day and
+// ignored by the others.
+type TopUpQuoteDto struct {
+	UnitPriceId int64 `json:"unit_price_id" form:"unit_price_id" binding:"required"`
+	Quantity    int   `json:"quantity" form:"quantity" binding:"required,min=1,max=1000000"`
+	Days        int   `json:"days" form:"days" binding:"min=0,max=3650"`
+}
+
+type TopUpDto struct {
+	TopUpQuoteDto
+	Gateway string `json:"gateway" binding:"required,max=30"`
+	// ReturnUrl is where the payment gateway sends the owner back to
+	ReturnUrl string `json:"return_url" binding:"required,url"`
+}
+
+type TopUpQuoteResultDto struct {
+	UnitPriceId int64  `json:"unit_price_id"`
+	CreditType  string `json:"credit_type"`
+	Quantity    int    `json:"quantity"`
+	Days        int    `json:"days,omitempty"`
+	// UnitPrice is the price of one unit, or of one unit for one day, after discount
+	UnitPrice int64 `json:"unit_price"`
+	Amount    int64 `json:"amount"`
+}
+
+type TopUpResultDto struct {
+	TopUpId        int64                           `json:"top_up_id"`
+	Quote          TopUpQuoteResultDto             `json:"quote"`
+	PaymentId      int64                           `json:"payment_id"`
+	TrackingNumber int64                           `json:"tracking_number"`
+	Redirect       *payment_gateway_inter.Redirect `json:"redirect"`
+}
+
+// BalanceDto is what is left of each credit of the owner
+type BalanceDto struct {
+	Sms                int        `json:"sms"`
+	Email              int        `json:"email"`
+	StorageMb          int        `json:"storage_mb"`
+	StorageMbExpiresAt *time.Time `json:"storage_mb_expires_at,omitempty"`
+	Ai                 int        `json:"ai"`
+	AiImage            int        `json:"ai_image"`
+}
+
+type LedgerFilterDto struct {
+	common_dto.PaginationDto
+	CreditType string `form:"credit_type" binding:"omitempty,oneof=sms email storage_mb ai ai_image"`
+}
+
+type UsageFilterDto struct {
+	common_dto.PaginationDto
+	CreditType string `form:"credit_type" binding:"omitempty,oneof=sms email storage_mb ai ai_image"`
+}
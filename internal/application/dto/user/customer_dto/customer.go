@@ -0,0 +1,50 @@
+package customer_dto
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+const CustomerStatusActive = "active"
+
+type RegisterCustomerDto struct {
+	SiteId    int64  `json:"site_id" binding:"required"`
+	Email     string `json:"email" binding:"required,email,max=255"`
+	Password  string `json:"password" binding:"required,min=8,max=128"`
+	FirstName string `json:"first_name" binding:"max=100"`
+	LastName  string `json:"last_name" binding:"max=100"`
+	Phone     string `json:"phone" binding:"omitempty,max=20"`
+}
+
+type LoginCustomerDto struct {
+	SiteId   int64  `json:"site_id" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshCustomerTokenDto struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ToCustomerEntity builds an active customer without credentials, the use case hashes the password
+func (d RegisterCustomerDto) ToCustomerEntity() *user_entity.CustomerEntity {
+	now := time.Now()
+	return &user_entity.CustomerEntity{
+		SiteId:    strconv.FormatInt(d.SiteId, 10),
+		Email:     NormalizeEmail(d.Email),
+		FirstName: strings.TrimSpace(d.FirstName),
+		LastName:  strings.TrimSpace(d.LastName),
+		Phone:     strings.TrimSpace(d.Phone),
+		IsActive:  CustomerStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// NormalizeEmail lower cases the address so registration and login agree on it
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
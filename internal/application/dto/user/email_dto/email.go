@@ -0,0 +1,26 @@
+package email_dto
+
+import "site_builder_backend/internal/application/dto/common_dto"
+
+// EmailDto is an email queued for the email consumer. It is either rendered from the platform Template
+// or from the site's text of a notification Event in Locale with Data, or carries its Subject with an
+// HTML and a text body. Messages of a site owner carry their UserId and go through the owner's SMTP when
+// it is enabled, messages of the platform leave it zero.
+type EmailDto struct {
+	MessageId string                 `json:"message_id"`
+	UserId    int64                  `json:"user_id,omitempty"`
+	To        string                 `json:"to"`
+	Template  string                 `json:"template,omitempty"`
+	SiteId    int64                  `json:"site_id,omitempty"`
+	Event     string                 `json:"event,omitempty"`
+	Locale    string                 `json:"locale,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Subject   string                 `json:"subject,omitempty"`
+	Html      string                 `json:"html,omitempty"`
+	Text      string                 `json:"text,omitempty"`
+}
+
+type MessageFilterDto struct {
+	common_dto.PaginationDto
+	Status string `form:"status" binding:"omitempty,oneof=queued sent failed"`
+}
@@ -0,0 +1,41 @@
+package plan_dto
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+)
+
+// PurchasePlanDto buys, renews or upgrades to the plan, which of them follows from the owner's current plan
+type PurchasePlanDto struct {
+	PlanId  int64  `json:"plan_id" binding:"required"`
+	Gateway string `json:"gateway" binding:"required,max=30"`
+	// ReturnUrl is where the payment gateway sends the owner back to
+	ReturnUrl string `json:"return_url" binding:"required,url"`
+}
+
+// PlanQuoteDto is what a purchase of the plan costs the owner right now
+type PlanQuoteDto struct {
+	PlanId int64  `json:"plan_id"`
+	Action string `json:"action"`
+	Price  int64  `json:"price"`
+	// Credit is what is left of the current plan on an upgrade
+	Credit int64 `json:"credit"`
+	Amount int64 `json:"amount"`
+}
+
+type PurchasePlanResultDto struct {
+	PurchaseId     int64                           `json:"purchase_id"`
+	Quote          PlanQuoteDto                    `json:"quote"`
+	PaymentId      int64                           `json:"payment_id"`
+	TrackingNumber int64                           `json:"tracking_number"`
+	Redirect       *payment_gateway_inter.Redirect `json:"redirect"`
+}
+
+// SubscriptionDto is the owner's current plan, Plan is nil without one
+type SubscriptionDto struct {
+	Plan      *user_entity.PlanEntity `json:"plan"`
+	StartedAt *time.Time              `json:"started_at,omitempty"`
+	ExpiresAt *time.Time              `json:"expires_at,omitempty"`
+}
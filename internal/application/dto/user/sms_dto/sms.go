@@ -0,0 +1,22 @@
+package sms_dto
+
+import "site_builder_backend/internal/application/dto/common_dto"
+
+// SmsDto is a text message queued for the SMS consumer. Messages of a site owner carry their UserId and
+// are paid with the owner's SMS credits, messages of the platform leave it zero. A message with an Event
+// is rendered from the site's text of the notification event in Locale with Data, instead of Message.
+type SmsDto struct {
+	MessageId string                 `json:"message_id"`
+	UserId    int64                  `json:"user_id,omitempty"`
+	Phone     string                 `json:"phone"`
+	Message   string                 `json:"message,omitempty"`
+	SiteId    int64                  `json:"site_id,omitempty"`
+	Event     string                 `json:"event,omitempty"`
+	Locale    string                 `json:"locale,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+type MessageFilterDto struct {
+	common_dto.PaginationDto
+	Status string `form:"status" binding:"omitempty,oneof=queued sent failed"`
+}
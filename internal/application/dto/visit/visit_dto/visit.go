@@ -0,0 +1,27 @@
+package visit_dto
+
+const (
+	_defaultMostViewedLimit = 10
+	_maxMostViewedLimit     = 50
+)
+
+type MostViewedFilterDto struct {
+	Limit int `form:"limit"`
+}
+
+// Normalize clamps the limit to the allowed range
+func (f MostViewedFilterDto) Normalize() MostViewedFilterDto {
+	if f.Limit < 1 {
+		f.Limit = _defaultMostViewedLimit
+	}
+	if f.Limit > _maxMostViewedLimit {
+		f.Limit = _maxMostViewedLimit
+	}
+	return f
+}
+
+// MostViewedDto pairs an item with the unique visits it received in the ranking window
+type MostViewedDto[T any] struct {
+	Item   T     `json:"item"`
+	Visits int64 `json:"visits"`
+}
@@ -0,0 +1,372 @@
+package basket_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"site_builder_backend/internal/application/dto/order/basket_dto"
+	"site_builder_backend/internal/application/use_cases/pricing_use_case"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/interfaces/cache/basket_cache_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrOutOfStock    = errors.New("not enough stock for the requested quantity")
+	ErrQuantityLimit = errors.New("quantity exceeds the limit for a single item")
+	ErrItemNotFound  = errors.New("item is not in the basket")
+)
+
+// Owner is either a signed in customer or a guest identified by the token of their basket
+type Owner struct {
+	CustomerId int64
+	GuestToken string
+}
+
+func (o Owner) isGuest() bool {
+	return o.CustomerId == 0
+}
+
+type BasketUseCase struct {
+	pricingUseCase  *pricing_use_case.PricingUseCase
+	variantReadRepo product_repo_inter.ProductVariantReadRepository
+	basketReadRepo  order_repo_inter.BasketReadRepository
+	basketWriteRepo order_repo_inter.BasketWriteRepository
+	guestStore      basket_cache_inter.GuestBasketStore
+	l               *logger.ZapLogger
+}
+
+func NewBasketUseCase(pricingUseCase *pricing_use_case.PricingUseCase, variantReadRepo product_repo_inter.ProductVariantReadRepository, basketReadRepo order_repo_inter.BasketReadRepository, basketWriteRepo order_repo_inter.BasketWriteRepository, guestStore basket_cache_inter.GuestBasketStore, l *logger.ZapLogger) *BasketUseCase {
+	return &BasketUseCase{
+		pricingUseCase:  pricingUseCase,
+		variantReadRepo: variantReadRepo,
+		basketReadRepo:  basketReadRepo,
+		basketWriteRepo: basketWriteRepo,
+		guestStore:      guestStore,
+		l:               l,
+	}
+}
+
+// basketState is what the customer chose. Prices and totals are always derived from it, never stored input.
+type basketState struct {
+	siteId     int64
+	discountId int64
+	items      []basketLine
+	// entity is the stored basket of a customer, nil for guests and customers without one
+	entity *order_entity.BasketEntity
+}
+
+type basketLine struct {
+	productVariantId int64
+	quantity         int
+}
+
+func (s *basketState) find(productVariantId int64) int {
+	for i, item := range s.items {
+		if item.productVariantId == productVariantId {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the basket priced with the current variant prices and offers
+func (u *BasketUseCase) Get(ctx context.Context, owner Owner, dto basket_dto.GetBasketDto) (*order_entity.BasketEntity, error) {
+	state, err := u.load(ctx, owner, dto.SiteId)
+	if err != nil {
+		return nil, err
+	}
+	return u.price(ctx, owner, state, false)
+}
+
+func (u *BasketUseCase) AddItem(ctx context.Context, owner Owner, dto basket_dto.BasketItemDto) (*order_entity.BasketEntity, error) {
+	state, err := u.load(ctx, owner, dto.SiteId)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := dto.Quantity
+	i := state.find(dto.ProductVariantId)
+	if i >= 0 {
+		quantity += state.items[i].quantity
+	}
+	if quantity > basket_dto.MaxItemQuantity {
+		return nil, ErrQuantityLimit
+	}
+	if err := u.checkVariant(dto.SiteId, dto.ProductVariantId, quantity); err != nil {
+		return nil, err
+	}
+
+	if i >= 0 {
+		state.items[i].quantity = quantity
+	} else {
+		state.items = append(state.items, basketLine{productVariantId: dto.ProductVariantId, quantity: quantity})
+	}
+	return u.save(ctx, owner, state, false)
+}
+
+func (u *BasketUseCase) UpdateItem(ctx context.Context, owner Owner, dto basket_dto.BasketItemDto) (*order_entity.BasketEntity, error) {
+	state, err := u.load(ctx, owner, dto.SiteId)
+	if err != nil {
+		return nil, err
+	}
+	i := state.find(dto.ProductVariantId)
+	if i < 0 {
+		return nil, ErrItemNotFound
+	}
+	if err := u.checkVariant(dto.SiteId, dto.ProductVariantId, dto.Quantity); err != nil {
+		return nil, err
+	}
+
+	state.items[i].quantity = dto.Quantity
+	return u.save(ctx, owner, state, false)
+}
+
+func (u *BasketUseCase) RemoveItem(ctx context.Context, owner Owner, dto basket_dto.RemoveBasketItemDto) (*order_entity.BasketEntity, error) {
+	state, err := u.load(ctx, owner, dto.SiteId)
+	if err != nil {
+		return nil, err
+	}
+	i := state.find(dto.ProductVariantId)
+	if i < 0 {
+		return nil, ErrItemNotFound
+	}
+
+	state.items = append(state.items[:i], state.items[i+1:]...)
+	return u.save(ctx, owner, state, false)
+}
+
+// ApplyDiscount attaches a discount code. Unlike a stored discount that stopped being valid, which is
+// silently dropped on the next recalculation, a code the customer is applying right now must be usable.
+func (u *BasketUseCase) ApplyDiscount(ctx context.Context, owner Owner, dto basket_dto.ApplyDiscountDto) (*order_entity.BasketEntity, error) {
+	state, err := u.load(ctx, owner, dto.SiteId)
+	if err != nil {
+		return nil, err
+	}
+
+	state.discountId = 0
+	if dto.Code != "" {
+		discount, err := u.pricingUseCase.FindDiscount(ctx, dto.SiteId, owner.CustomerId, dto.Code)
+		if err != nil {
+			return nil, err
+		}
+		state.discountId, _ = strconv.ParseInt(discount.Id, 10, 64)
+	}
+	return u.save(ctx, owner, state, true)
+}
+
+// Merge moves the guest basket into the basket of the customer who just signed in. Quantities of the
+// same variant are added up and the discount of the customer's basket wins over the guest's one.
+func (u *BasketUseCase) Merge(ctx context.Context, customerId int64, dto basket_dto.MergeBasketDto) (*order_entity.BasketEntity, error) {
+	owner := Owner{CustomerId: customerId}
+	state, err := u.load(ctx, owner, dto.SiteId)
+	if err != nil {
+		return nil, err
+	}
+	guest, err := u.guestStore.Get(ctx, dto.GuestToken)
+	if err != nil {
+		return nil, err
+	}
+	if guest == nil || guest.SiteId != dto.SiteId || len(guest.Items) == 0 {
+		return u.price(ctx, owner, state, false)
+	}
+
+	for _, item := range guest.Items {
+		if i := state.find(item.ProductVariantId); i >= 0 {
+			state.items[i].quantity = min(state.items[i].quantity+item.Quantity, basket_dto.MaxItemQuantity)
+		} else {
+			state.items = append(state.items, basketLine{productVariantId: item.ProductVariantId, quantity: item.Quantity})
+		}
+	}
+	if state.discountId == 0 {
+		state.discountId = guest.DiscountId
+	}
+
+	basket, err := u.save(ctx, owner, state, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.guestStore.Delete(ctx, dto.GuestToken); err != nil {
+		// The basket is merged already, a leftover guest basket only expires later
+		u.l.Warn("basket_use_case - BasketUseCase - Merge - guestStore.Delete: %v", err)
+	}
+	return basket, nil
+}
+
+func (u *BasketUseCase) load(ctx context.Context, owner Owner, siteId int64) (*basketState, error) {
+	state := &basketState{siteId: siteId}
+
+	if owner.isGuest() {
+		if owner.GuestToken == "" {
+			return state, nil
+		}
+		guest, err := u.guestStore.Get(ctx, owner.GuestToken)
+		if err != nil {
+			return nil, err
+		}
+		// A token is bound to the site it was issued on
+		if guest == nil || guest.SiteId != siteId {
+			return state, nil
+		}
+		state.discountId = guest.DiscountId
+		for _, item := range guest.Items {
+			state.items = append(state.items, basketLine{productVariantId: item.ProductVariantId, quantity: item.Quantity})
+		}
+		return state, nil
+	}
+
+	basket, err := u.basketReadRepo.FindByCustomer(siteId, owner.CustomerId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state.entity = basket
+	state.discountId, _ = strconv.ParseInt(basket.DiscountId, 10, 64)
+	for _, item := range basket.BasketItems {
+		variantId, _ := strconv.ParseInt(item.ProductVariantId, 10, 64)
+		state.items = append(state.items, basketLine{productVariantId: variantId, quantity: item.Quantity})
+	}
+	return state, nil
+}
+
+func (u *BasketUseCase) save(ctx context.Context, owner Owner, state *basketState, strict bool) (*order_entity.BasketEntity, error) {
+	basket, err := u.price(ctx, owner, state, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	if !owner.isGuest() {
+		if err := u.basketWriteRepo.Save(basket); err != nil {
+			return nil, err
+		}
+		return basket, nil
+	}
+
+	guest := &basket_cache_inter.GuestBasket{
+		SiteId:     state.siteId,
+		DiscountId: state.discountId,
+		Items:      make([]basket_cache_inter.GuestBasketItem, 0, len(state.items)),
+	}
+	for _, item := range state.items {
+		guest.Items = append(guest.Items, basket_cache_inter.GuestBasketItem{ProductVariantId: item.productVariantId, Quantity: item.quantity})
+	}
+	if err := u.guestStore.Save(ctx, owner.GuestToken, guest); err != nil {
+		return nil, err
+	}
+	return basket, nil
+}
+
+// price recalculates every amount of the basket from the current variant prices and offers.
+// Variants that were deleted since they were added are dropped. When strict is false a discount
+// that is no longer usable is dropped as well instead of failing the whole basket.
+func (u *BasketUseCase) price(ctx context.Context, owner Owner, state *basketState, strict bool) (*order_entity.BasketEntity, error) {
+	ids := make([]int64, 0, len(state.items))
+	for _, item := range state.items {
+		ids = append(ids, item.productVariantId)
+	}
+	variants, err := u.variantReadRepo.FindByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[int64]product_entity.ProductVariantEntity, len(variants))
+	for _, variant := range variants {
+		id, _ := strconv.ParseInt(variant.Id, 10, 64)
+		byId[id] = variant
+	}
+
+	siteId := strconv.FormatInt(state.siteId, 10)
+	items := state.items[:0]
+	lines := make([]pricing_use_case.PriceLine, 0, len(state.items))
+	for _, item := range state.items {
+		variant, ok := byId[item.productVariantId]
+		if !ok || variant.IsDeleted || variant.Product.IsDeleted || variant.Product.SiteId != siteId {
+			continue
+		}
+		items = append(items, item)
+		lines = append(lines, pricing_use_case.PriceLine{
+			ProductId:        variant.ProductId,
+			ProductVariantId: variant.Id,
+			UnitPrice:        variant.Price,
+			Quantity:         item.quantity,
+		})
+	}
+	state.items = items
+
+	result, err := u.pricingUseCase.PriceWithDiscount(ctx, state.siteId, owner.CustomerId, lines, state.discountId)
+	if err != nil && !strict && state.discountId > 0 && isDiscountError(err) {
+		state.discountId = 0
+		result, err = u.pricingUseCase.PriceWithDiscount(ctx, state.siteId, owner.CustomerId, lines, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	basket := state.entity
+	if basket == nil {
+		basket = &order_entity.BasketEntity{SiteId: siteId}
+		if !owner.isGuest() {
+			basket.CustomerId = strconv.FormatInt(owner.CustomerId, 10)
+		}
+	}
+	basket.TotalRawPrice = result.TotalRawPrice
+	basket.TotalCouponDiscount = result.TotalCouponDiscount
+	basket.TotalPriceWithCouponDiscount = result.TotalPriceWithCouponDiscount
+	basket.DiscountId = ""
+	if state.discountId > 0 {
+		basket.DiscountId = strconv.FormatInt(state.discountId, 10)
+	}
+
+	existing := make(map[string]order_entity.BasketItemEntity, len(basket.BasketItems))
+	for _, item := range basket.BasketItems {
+		existing[item.ProductVariantId] = item
+	}
+	basket.BasketItems = make([]order_entity.BasketItemEntity, 0, len(result.Lines))
+	for _, line := range result.Lines {
+		item := existing[line.ProductVariantId]
+		item.BasketId = basket.Id
+		item.ProductId = line.ProductId
+		item.ProductVariantId = line.ProductVariantId
+		item.Quantity = line.Quantity
+		item.RawPrice = line.UnitPrice
+		item.FinalRawPrice = line.FinalRawPrice
+		item.JustCouponPrice = line.JustCouponPrice
+		item.JustDiscountPrice = line.JustDiscountPrice
+		item.FinalPriceWithCouponDiscount = line.FinalPriceWithCouponDiscount
+		basket.BasketItems = append(basket.BasketItems, item)
+	}
+	return basket, nil
+}
+
+// checkVariant makes sure the variant is sold on the site and the quantity is in stock
+func (u *BasketUseCase) checkVariant(siteId int64, productVariantId int64, quantity int) error {
+	variants, err := u.variantReadRepo.FindByIds([]int64{productVariantId})
+	if err != nil {
+		return err
+	}
+	if len(variants) == 0 {
+		return pricing_use_case.ErrProductNotFound
+	}
+	variant := variants[0]
+	if variant.IsDeleted || variant.Product.IsDeleted || variant.Product.SiteId != strconv.FormatInt(siteId, 10) {
+		return pricing_use_case.ErrProductNotFound
+	}
+	if quantity > variant.Stock {
+		return ErrOutOfStock
+	}
+	return nil
+}
+
+func isDiscountError(err error) bool {
+	return errors.Is(err, pricing_use_case.ErrDiscountNotFound) ||
+		errors.Is(err, pricing_use_case.ErrDiscountExpired) ||
+		errors.Is(err, pricing_use_case.ErrDiscountExhausted) ||
+		errors.Is(err, pricing_use_case.ErrDiscountNotAllowed) ||
+		errors.Is(err, pricing_use_case.ErrDiscountNotApplicable)
+}
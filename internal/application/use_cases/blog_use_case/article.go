@@ -0,0 +1,295 @@
+package blog_use_case
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/blog/article_dto"
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/search_db/article_search_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const _relatedArticlesLimit = 4
+
+var (
+	ErrArticleNotFound   = errors.New("article not found")
+	ErrArticleSlugExists = errors.New("an article with this slug already exists on the site")
+	ErrSiteAccessDenied  = site_repo_inter.ErrNotOwner
+	ErrInvalidSchedule   = errors.New("scheduled articles need a publish time in the future")
+	ErrInvalidSlug       = errors.New("slug must contain at least one letter or digit")
+	ErrArticleChanged    = errors.New("the article was changed by another request, reload it and retry")
+)
+
+var _slugInvalidChars = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+type ArticleUseCase struct {
+	siteReadRepo     site_repo_inter.SiteReadRepository
+	articleReadRepo  blog_repo_inter.ArticleReadRepository
+	articleWriteRepo blog_repo_inter.ArticleWriteRepository
+	articleSearch    article_search_inter.ArticleSearch
+	imageUseCase     *drive_use_case.ImageUseCase
+	l                *logger.ZapLogger
+}
+
+func NewArticleUseCase(siteReadRepo site_repo_inter.SiteReadRepository, articleReadRepo blog_repo_inter.ArticleReadRepository, articleWriteRepo blog_repo_inter.ArticleWriteRepository, articleSearch article_search_inter.ArticleSearch, imageUseCase *drive_use_case.ImageUseCase, l *logger.ZapLogger) *ArticleUseCase {
+	return &ArticleUseCase{
+		siteReadRepo:     siteReadRepo,
+		articleReadRepo:  articleReadRepo,
+		articleWriteRepo: articleWriteRepo,
+		articleSearch:    articleSearch,
+		imageUseCase:     imageUseCase,
+		l:                l,
+	}
+}
+
+// Create stores a new article for a site owned by the user
+func (u *ArticleUseCase) Create(ctx context.Context, userId int64, dto article_dto.CreateArticleDto) (*blog_entity.ArticleEntity, error) {
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return nil, err
+	}
+
+	entity := dto.ToArticleEntity()
+	entity.UserId = strconv.FormatInt(userId, 10)
+	entity.Slug = Slugify(dto.Slug, dto.Title)
+	if entity.Status == "" {
+		entity.Status = blog_entity.ArticleStatusDraft
+	}
+	if err := applyStatus(entity, entity.Status, dto.PublishAt, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := u.checkSlug(dto.SiteId, entity.Slug, 0); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	// The check above gives the common answer, the index settles concurrent creates
+	err := u.articleWriteRepo.Create(entity, dto.CategoryIds)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrArticleSlugExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.reloadAndIndex(ctx, mustParseId(entity.Id))
+}
+
+// Update edits an article owned by the user and replaces its categories
+func (u *ArticleUseCase) Update(ctx context.Context, userId int64, dto article_dto.UpdateArticleDto) (*blog_entity.ArticleEntity, error) {
+	entity, err := u.findOwned(userId, dto.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	dto.ApplyTo(entity)
+	if strings.TrimSpace(dto.Slug) != "" {
+		entity.Slug = Slugify(dto.Slug, dto.Title)
+	}
+	if dto.Status != "" || !dto.PublishAt.IsZero() {
+		if err := applyStatus(entity, entity.Status, dto.PublishAt, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.checkSlug(mustParseId(entity.SiteId), entity.Slug, dto.Id); err != nil {
+		return nil, err
+	}
+
+	entity.UpdatedAt = time.Now()
+	err = u.articleWriteRepo.Update(entity, dto.CategoryIds)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrArticleSlugExists
+	}
+	if errors.Is(err, repositories.ErrStale) {
+		return nil, ErrArticleChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.reloadAndIndex(ctx, dto.Id)
+}
+
+// ChangeStatus moves an article between draft, published and scheduled
+func (u *ArticleUseCase) ChangeStatus(ctx context.Context, userId int64, dto article_dto.ChangeArticleStatusDto) (*blog_entity.ArticleEntity, error) {
+	entity, err := u.findOwned(userId, dto.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyStatus(entity, dto.Status, dto.PublishAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	entity.UpdatedAt = time.Now()
+	categoryIds := make([]int64, 0, len(entity.Categories))
+	for _, category := range entity.Categories {
+		categoryIds = append(categoryIds, mustParseId(category.Id))
+	}
+	err = u.articleWriteRepo.Update(entity, categoryIds)
+	if errors.Is(err, repositories.ErrStale) {
+		return nil, ErrArticleChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.index(ctx, entity)
+	return entity, nil
+}
+
+func (u *ArticleUseCase) Delete(ctx context.Context, userId int64, id int64) error {
+	if _, err := u.findOwned(userId, id); err != nil {
+		return err
+	}
+	if err := u.articleWriteRepo.Delete(id); err != nil {
+		return err
+	}
+	if err := u.articleSearch.Delete(ctx, strconv.FormatInt(id, 10)); err != nil {
+		u.l.Warn("blog_use_case - ArticleUseCase - Delete - articleSearch.Delete: %v", err)
+	}
+	return nil
+}
+
+func (u *ArticleUseCase) Get(ctx context.Context, userId int64, id int64) (*blog_entity.ArticleEntity, error) {
+	return u.findOwned(userId, id)
+}
+
+// GetAll lists the user's articles in any state
+func (u *ArticleUseCase) GetAll(ctx context.Context, userId int64, dto article_dto.ArticleFilterDto) (common_dto.PaginatedDto[blog_entity.ArticleEntity], error) {
+	items, total, err := u.articleReadRepo.FindAll(blog_repo_inter.ArticleFilter{
+		SiteId:     dto.SiteId,
+		UserId:     userId,
+		CategoryId: dto.CategoryId,
+		Status:     dto.Status,
+		Offset:     dto.Offset(),
+		Limit:      dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[blog_entity.ArticleEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(items, total, dto.PaginationDto), nil
+}
+
+// PublicGetAll lists the articles of a site that are visible on the storefront
+func (u *ArticleUseCase) PublicGetAll(ctx context.Context, dto article_dto.PublicArticleFilterDto) (common_dto.PaginatedDto[blog_entity.ArticleEntity], error) {
+	items, total, err := u.articleReadRepo.FindVisible(blog_repo_inter.ArticleFilter{
+		SiteId:     dto.SiteId,
+		CategoryId: dto.CategoryId,
+		Offset:     dto.Offset(),
+		Limit:      dto.Limit(),
+	}, time.Now())
+	if err != nil {
+		return common_dto.PaginatedDto[blog_entity.ArticleEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(items, total, dto.PaginationDto), nil
+}
+
+// PublicGet returns a visible article by slug together with related articles
+func (u *ArticleUseCase) PublicGet(ctx context.Context, siteId int64, slug string) (*article_dto.ArticleDetailDto, error) {
+	entity, err := u.articleReadRepo.FindBySlug(siteId, slug)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !entity.IsVisibleAt(now) {
+		return nil, ErrArticleNotFound
+	}
+
+	u.attachImages(ctx, entity.Media)
+	return &article_dto.ArticleDetailDto{Article: entity, Related: u.related(ctx, entity, now)}, nil
+}
+
+// attachImages fills in the processed images of the media, the article is shown with plain media ids
+// when they cannot be read
+func (u *ArticleUseCase) attachImages(ctx context.Context, media []blog_entity.ArticleMediaEntity) {
+	if len(media) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(media))
+	for _, m := range media {
+		ids = append(ids, m.MediaId)
+	}
+	images, err := u.imageUseCase.Images(ctx, ids)
+	if err != nil {
+		u.l.Error("blog_use_case - ArticleUseCase - attachImages: %v", err)
+		return
+	}
+	for i := range media {
+		media[i].Image = images[media[i].MediaId]
+	}
+}
+
+func (u *ArticleUseCase) findOwned(userId int64, id int64) (*blog_entity.ArticleEntity, error) {
+	entity, err := u.articleReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entity.UserId != strconv.FormatInt(userId, 10) {
+		return nil, ErrArticleNotFound
+	}
+	return entity, nil
+}
+
+func (u *ArticleUseCase) checkSlug(siteId int64, slug string, excludeId int64) error {
+	if slug == "" {
+		return ErrInvalidSlug
+	}
+	exists, err := u.articleReadRepo.ExistsBySlug(siteId, slug, excludeId)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrArticleSlugExists
+	}
+	return nil
+}
+
+// applyStatus sets the status and the publish moment according to the workflow rules:
+// publishing stamps the current time unless one is already set, scheduling requires a future time
+// and moving back to draft keeps the previous publish time for reference.
+func applyStatus(entity *blog_entity.ArticleEntity, status string, publishAt time.Time, now time.Time) error {
+	switch status {
+	case blog_entity.ArticleStatusPublished:
+		if entity.Status != blog_entity.ArticleStatusPublished || entity.PublishedAt.IsZero() || entity.PublishedAt.After(now) {
+			entity.PublishedAt = now
+		}
+	case blog_entity.ArticleStatusScheduled:
+		if !publishAt.After(now) {
+			return ErrInvalidSchedule
+		}
+		entity.PublishedAt = publishAt
+	}
+	entity.Status = status
+	return nil
+}
+
+// Slugify builds a URL friendly slug from the requested slug, falling back to the title.
+// Letters of any script are kept so Persian titles produce readable slugs.
+func Slugify(slug string, title string) string {
+	source := strings.TrimSpace(slug)
+	if source == "" {
+		source = title
+	}
+	result := _slugInvalidChars.ReplaceAllString(strings.ToLower(source), "-")
+	return strings.Trim(result, "-")
+}
+
+func mustParseId(id string) int64 {
+	value, _ := strconv.ParseInt(id, 10, 64)
+	return value
+}
@@ -0,0 +1,129 @@
+package blog_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/blog/article_dto"
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/internal/interfaces/search_db/article_search_inter"
+)
+
+const _reindexBatchSize = 100
+
+// PublicSearch runs a full-text search over the visible articles of a site
+func (u *ArticleUseCase) PublicSearch(ctx context.Context, dto article_dto.SearchArticleDto) (common_dto.PaginatedDto[article_search_inter.ArticleSearchHit], error) {
+	result, err := u.articleSearch.Search(ctx, article_search_inter.ArticleSearchQuery{
+		SiteId: dto.SiteId,
+		Query:  dto.Query,
+		Offset: dto.Offset(),
+		Limit:  dto.Limit(),
+	}, time.Now())
+	if err != nil {
+		return common_dto.PaginatedDto[article_search_inter.ArticleSearchHit]{}, err
+	}
+	return common_dto.NewPaginatedDto(result.Hits, result.Total, dto.PaginationDto), nil
+}
+
+// Reindex pushes every article of a site owned by the user to the search index.
+// It is used to backfill the index for articles written before indexing existed.
+func (u *ArticleUseCase) Reindex(ctx context.Context, userId int64, siteId int64) (int, error) {
+	if err := u.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	for offset := 0; ; offset += _reindexBatchSize {
+		articles, _, err := u.articleReadRepo.FindAll(blog_repo_inter.ArticleFilter{
+			SiteId: siteId,
+			UserId: userId,
+			Offset: offset,
+			Limit:  _reindexBatchSize,
+		})
+		if err != nil {
+			return indexed, err
+		}
+		for i := range articles {
+			if err := u.articleSearch.Index(ctx, toDocument(&articles[i])); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(articles) < _reindexBatchSize {
+			return indexed, nil
+		}
+	}
+}
+
+// related prefers the "more like this" query of the search index and falls back to
+// shared categories when the index is unavailable or has nothing similar.
+func (u *ArticleUseCase) related(ctx context.Context, article *blog_entity.ArticleEntity, now time.Time) []blog_entity.ArticleEntity {
+	hits, err := u.articleSearch.MoreLikeThis(ctx, mustParseId(article.SiteId), article.Id, _relatedArticlesLimit, now)
+	if err != nil && !errors.Is(err, article_search_inter.ErrSearchDisabled) {
+		u.l.Warn("blog_use_case - ArticleUseCase - related - MoreLikeThis: %v", err)
+	}
+	if len(hits) > 0 {
+		related := make([]blog_entity.ArticleEntity, 0, len(hits))
+		for _, hit := range hits {
+			related = append(related, blog_entity.ArticleEntity{
+				Id:          hit.Id,
+				Title:       hit.Title,
+				Description: hit.Description,
+				Slug:        hit.Slug,
+				SiteId:      article.SiteId,
+				PublishedAt: hit.PublishedAt,
+			})
+		}
+		return related
+	}
+
+	related, err := u.articleReadRepo.FindRelated(article, _relatedArticlesLimit, now)
+	if err != nil {
+		u.l.Warn("blog_use_case - ArticleUseCase - related - FindRelated: %v", err)
+	}
+	if related == nil {
+		related = []blog_entity.ArticleEntity{}
+	}
+	return related
+}
+
+func (u *ArticleUseCase) reloadAndIndex(ctx context.Context, id int64) (*blog_entity.ArticleEntity, error) {
+	entity, err := u.articleReadRepo.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	u.index(ctx, entity)
+	return entity, nil
+}
+
+// index keeps the search index in sync. Failures are logged and do not fail the write,
+// the Reindex endpoint can repair a drifted index.
+func (u *ArticleUseCase) index(ctx context.Context, entity *blog_entity.ArticleEntity) {
+	if err := u.articleSearch.Index(ctx, toDocument(entity)); err != nil {
+		u.l.Warn("blog_use_case - ArticleUseCase - index: %v", err)
+	}
+}
+
+func toDocument(article *blog_entity.ArticleEntity) article_search_inter.ArticleDocument {
+	categories := make([]string, 0, len(article.Categories))
+	for _, category := range article.Categories {
+		categories = append(categories, category.Name)
+	}
+	siteId, _ := strconv.ParseInt(article.SiteId, 10, 64)
+	return article_search_inter.ArticleDocument{
+		Id:          article.Id,
+		SiteId:      siteId,
+		Title:       article.Title,
+		Description: article.Description,
+		Body:        article.Body,
+		Slug:        article.Slug,
+		Categories:  categories,
+		Badges:      article.BadgeList(),
+		Status:      article.Status,
+		PublishedAt: article.PublishedAt,
+	}
+}
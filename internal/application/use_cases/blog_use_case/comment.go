@@ -0,0 +1,278 @@
+package blog_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"site_builder_backend/internal/application/dto/blog/comment_dto"
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/interfaces/cache/rate_limiter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	_commentRateLimit  = 5
+	_commentRateWindow = 10 * time.Minute
+	_maxCommentLinks   = 2
+	_excerptLength     = 200
+)
+
+var _linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+
+var (
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrGuestIdentity      = errors.New("guests must provide a name and an email")
+	ErrRatingOnReply      = errors.New("replies cannot carry a rating")
+	ErrCommentRateLimited = errors.New("too many comments, try again later")
+)
+
+type CommentUseCase struct {
+	siteReadRepo     site_repo_inter.SiteReadRepository
+	articleReadRepo  blog_repo_inter.ArticleReadRepository
+	commentReadRepo  blog_repo_inter.ArticleCommentReadRepository
+	commentWriteRepo blog_repo_inter.ArticleCommentWriteRepository
+	rateLimiter      rate_limiter_inter.RateLimiter
+	eventPublisher   event_publisher_inter.EventPublisher
+	l                *logger.ZapLogger
+}
+
+func NewCommentUseCase(siteReadRepo site_repo_inter.SiteReadRepository, articleReadRepo blog_repo_inter.ArticleReadRepository, commentReadRepo blog_repo_inter.ArticleCommentReadRepository, commentWriteRepo blog_repo_inter.ArticleCommentWriteRepository, rateLimiter rate_limiter_inter.RateLimiter, eventPublisher event_publisher_inter.EventPublisher, l *logger.ZapLogger) *CommentUseCase {
+	return &CommentUseCase{
+		siteReadRepo:     siteReadRepo,
+		articleReadRepo:  articleReadRepo,
+		commentReadRepo:  commentReadRepo,
+		commentWriteRepo: commentWriteRepo,
+		rateLimiter:      rateLimiter,
+		eventPublisher:   eventPublisher,
+		l:                l,
+	}
+}
+
+// Create posts a comment or a reply on a visible article. Comments wait for moderation,
+// those that look like spam are stored with the spam status and the owner is not notified.
+func (u *CommentUseCase) Create(ctx context.Context, author comment_dto.CommentAuthorDto, dto comment_dto.CreateCommentDto) (*blog_entity.ArticleCommentEntity, error) {
+	if author.CustomerId == 0 && (dto.GuestName == "" || dto.GuestEmail == "") {
+		return nil, ErrGuestIdentity
+	}
+
+	article, err := u.articleReadRepo.FindById(dto.ArticleId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !article.IsVisibleAt(time.Now()) {
+		return nil, ErrArticleNotFound
+	}
+
+	if err := u.checkRate(ctx, article.SiteId, author); err != nil {
+		return nil, err
+	}
+
+	entity := dto.ToArticleCommentEntity(article, author)
+	if dto.ParentId > 0 {
+		if dto.Rating > 0 {
+			return nil, ErrRatingOnReply
+		}
+		parent, err := u.commentReadRepo.FindById(dto.ParentId)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrCommentNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.ArticleId != article.Id || parent.Status != blog_entity.CommentStatusApproved {
+			return nil, ErrCommentNotFound
+		}
+		entity.ParentId = parent.Id
+		entity.RootId = parent.RootId
+		if entity.RootId == "" {
+			entity.RootId = parent.Id
+		}
+	}
+	if looksLikeSpam(entity) {
+		entity.Status = blog_entity.CommentStatusSpam
+	}
+
+	if err := u.commentWriteRepo.Create(entity); err != nil {
+		return nil, err
+	}
+	if entity.Status != blog_entity.CommentStatusSpam {
+		u.publishCreated(ctx, article, entity)
+	}
+	return entity, nil
+}
+
+// GetAll lists the comments of a site for moderation
+func (u *CommentUseCase) GetAll(ctx context.Context, userId int64, dto comment_dto.CommentFilterDto) (common_dto.PaginatedDto[blog_entity.ArticleCommentEntity], error) {
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return common_dto.PaginatedDto[blog_entity.ArticleCommentEntity]{}, err
+	}
+	comments, total, err := u.commentReadRepo.FindAll(blog_repo_inter.ArticleCommentFilter{
+		SiteId:    dto.SiteId,
+		ArticleId: dto.ArticleId,
+		Status:    dto.Status,
+		Offset:    dto.Offset(),
+		Limit:     dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[blog_entity.ArticleCommentEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(comments, total, dto.PaginationDto), nil
+}
+
+// Approve publishes a comment, its rating is folded into the article
+func (u *CommentUseCase) Approve(ctx context.Context, userId int64, id int64) error {
+	return u.moderate(userId, id, func() error {
+		return u.commentWriteRepo.ChangeStatus(id, blog_entity.CommentStatusApproved)
+	})
+}
+
+// MarkSpam hides a comment and removes its rating from the article
+func (u *CommentUseCase) MarkSpam(ctx context.Context, userId int64, id int64) error {
+	return u.moderate(userId, id, func() error {
+		return u.commentWriteRepo.ChangeStatus(id, blog_entity.CommentStatusSpam)
+	})
+}
+
+// Delete removes a comment and its replies
+func (u *CommentUseCase) Delete(ctx context.Context, userId int64, id int64) error {
+	return u.moderate(userId, id, func() error {
+		return u.commentWriteRepo.Delete(id)
+	})
+}
+
+// PublicGetAll returns the approved comment threads of an article, newest threads first
+func (u *CommentUseCase) PublicGetAll(ctx context.Context, dto comment_dto.PublicCommentFilterDto) (common_dto.PaginatedDto[*comment_dto.CommentThreadDto], error) {
+	roots, total, err := u.commentReadRepo.FindAll(blog_repo_inter.ArticleCommentFilter{
+		ArticleId: dto.ArticleId,
+		Status:    blog_entity.CommentStatusApproved,
+		RootsOnly: true,
+		Offset:    dto.Offset(),
+		Limit:     dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[*comment_dto.CommentThreadDto]{}, err
+	}
+
+	rootIds := make([]int64, 0, len(roots))
+	for _, root := range roots {
+		rootIds = append(rootIds, mustParseId(root.Id))
+	}
+	replies, err := u.commentReadRepo.FindReplies(rootIds, blog_entity.CommentStatusApproved)
+	if err != nil {
+		return common_dto.PaginatedDto[*comment_dto.CommentThreadDto]{}, err
+	}
+
+	return common_dto.NewPaginatedDto(buildThreads(roots, replies), total, dto.PaginationDto), nil
+}
+
+func (u *CommentUseCase) moderate(userId int64, id int64, action func() error) error {
+	comment, err := u.commentReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := u.siteReadRepo.CheckOwner(mustParseId(comment.SiteId), userId); err != nil {
+		return err
+	}
+	if err := action(); errors.Is(err, repositories.ErrNotFound) {
+		return ErrCommentNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkRate limits comments per author and site. Customers are keyed by id, guests by address.
+// The limiter fails open so a Redis outage does not block commenting.
+func (u *CommentUseCase) checkRate(ctx context.Context, siteId string, author comment_dto.CommentAuthorDto) error {
+	key := fmt.Sprintf("comment:%s:ip:%s", siteId, author.IpAddress)
+	if author.CustomerId > 0 {
+		key = fmt.Sprintf("comment:%s:customer:%d", siteId, author.CustomerId)
+	}
+	allowed, err := u.rateLimiter.Allow(ctx, key, _commentRateLimit, _commentRateWindow)
+	if err != nil {
+		u.l.Warn("blog_use_case - CommentUseCase - checkRate: %v", err)
+		return nil
+	}
+	if !allowed {
+		return ErrCommentRateLimited
+	}
+	return nil
+}
+
+func (u *CommentUseCase) publishCreated(ctx context.Context, article *blog_entity.ArticleEntity, comment *blog_entity.ArticleCommentEntity) {
+	site, err := u.siteReadRepo.FindById(mustParseId(article.SiteId))
+	if err != nil {
+		u.l.Warn("blog_use_case - CommentUseCase - publishCreated - siteReadRepo.FindById: %v", err)
+		return
+	}
+	event := event_dto.ArticleCommentCreatedEvent{
+		CommentId:    comment.Id,
+		ArticleId:    article.Id,
+		ArticleTitle: article.Title,
+		SiteId:       article.SiteId,
+		OwnerUserId:  site.UserId,
+		AuthorName:   comment.GuestName,
+		CustomerId:   comment.CustomerId,
+		Excerpt:      excerpt(comment.Body, _excerptLength),
+		Rating:       comment.Rating,
+		CreatedAt:    comment.CreatedAt,
+	}
+	if err := u.eventPublisher.Publish(ctx, event_publisher_inter.BlogExchange, event_dto.RoutingKeyArticleCommentCreated, event); err != nil {
+		u.l.Warn("blog_use_case - CommentUseCase - publishCreated: %v", err)
+	}
+}
+
+// looksLikeSpam flags comments stuffed with links, or guests using a link as their name
+func looksLikeSpam(comment *blog_entity.ArticleCommentEntity) bool {
+	if len(_linkPattern.FindAllStringIndex(comment.Body, -1)) > _maxCommentLinks {
+		return true
+	}
+	return _linkPattern.MatchString(comment.GuestName)
+}
+
+// buildThreads nests the replies under their parents. Replies whose parent is missing are attached to the root.
+func buildThreads(roots []blog_entity.ArticleCommentEntity, replies []blog_entity.ArticleCommentEntity) []*comment_dto.CommentThreadDto {
+	nodes := make(map[string]*comment_dto.CommentThreadDto, len(roots)+len(replies))
+	threads := make([]*comment_dto.CommentThreadDto, 0, len(roots))
+	for _, root := range roots {
+		node := &comment_dto.CommentThreadDto{ArticleCommentEntity: root, Replies: []*comment_dto.CommentThreadDto{}}
+		nodes[root.Id] = node
+		threads = append(threads, node)
+	}
+	for _, reply := range replies {
+		nodes[reply.Id] = &comment_dto.CommentThreadDto{ArticleCommentEntity: reply, Replies: []*comment_dto.CommentThreadDto{}}
+	}
+	for _, reply := range replies {
+		parent, ok := nodes[reply.ParentId]
+		if !ok {
+			parent, ok = nodes[reply.RootId]
+		}
+		if ok {
+			parent.Replies = append(parent.Replies, nodes[reply.Id])
+		}
+	}
+	return threads
+}
+
+func excerpt(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length]) + "…"
+}
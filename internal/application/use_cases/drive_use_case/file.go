@@ -0,0 +1,132 @@
+package drive_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const _maxNameLength = 255
+
+var (
+	ErrFileNotFound   = errors.New("file not found")
+	ErrParentNotFound = errors.New("parent folder not found")
+	ErrInvalidName    = errors.New("names cannot be empty, . or .., contain slashes or control characters, or be longer than 255 bytes")
+	ErrNameConflict   = errors.New("the folder has an item with this name already")
+)
+
+// FileUseCase reads and arranges the items of the users' drives. Deleted items stay in the trash, and
+// keep taking storage, until they are restored or purged.
+type FileUseCase struct {
+	quota
+	fileItemReadRepo  drive_repo_inter.FileItemReadRepository
+	fileItemWriteRepo drive_repo_inter.FileItemWriteRepository
+	storage           object_storage_inter.ObjectStorage
+	eventPublisher    event_publisher_inter.EventPublisher
+	l                 *logger.ZapLogger
+}
+
+func NewFileUseCase(fileItemReadRepo drive_repo_inter.FileItemReadRepository, fileItemWriteRepo drive_repo_inter.FileItemWriteRepository, storage object_storage_inter.ObjectStorage, meteringUseCase *user_use_case.MeteringUseCase, eventPublisher event_publisher_inter.EventPublisher, l *logger.ZapLogger) *FileUseCase {
+	return &FileUseCase{
+		quota:             quota{meteringUseCase: meteringUseCase, l: l},
+		fileItemReadRepo:  fileItemReadRepo,
+		fileItemWriteRepo: fileItemWriteRepo,
+		storage:           storage,
+		eventPublisher:    eventPublisher,
+		l:                 l,
+	}
+}
+
+// GetFile returns a live item of the user
+func (u *FileUseCase) GetFile(ctx context.Context, userId int64, id int64) (*drive_entity.FileItemEntity, error) {
+	return findItem(u.fileItemReadRepo, userId, id)
+}
+
+// Download opens the content of a file of the user, the caller closes its Body
+func (u *FileUseCase) Download(ctx context.Context, userId int64, id int64) (*drive_entity.FileItemEntity, *object_storage_inter.Object, error) {
+	item, err := findItem(u.fileItemReadRepo, userId, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if item.IsDirectory {
+		return nil, nil, ErrFileNotFound
+	}
+	object, err := u.storage.Get(ctx, item.BucketName, item.ServerKey)
+	if errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		u.l.Error("drive_use_case - FileUseCase - Download - item %s: object %s is missing", item.Id, item.ServerKey)
+		return nil, nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return item, object, nil
+}
+
+// findItem returns a live item of the user, ErrFileNotFound for items of others and deleted ones
+func findItem(fileItemReadRepo drive_repo_inter.FileItemReadRepository, userId int64, id int64) (*drive_entity.FileItemEntity, error) {
+	item, err := fileItemReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if item.UserId != strconv.FormatInt(userId, 10) || item.IsDeleted {
+		return nil, ErrFileNotFound
+	}
+	return item, nil
+}
+
+// findFolder returns a live folder of the user, nil for the root when parentId is zero
+func findFolder(fileItemReadRepo drive_repo_inter.FileItemReadRepository, userId int64, parentId int64) (*drive_entity.FileItemEntity, error) {
+	if parentId == 0 {
+		return nil, nil
+	}
+	folder, err := findItem(fileItemReadRepo, userId, parentId)
+	if errors.Is(err, ErrFileNotFound) {
+		return nil, ErrParentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !folder.IsDirectory {
+		return nil, ErrParentNotFound
+	}
+	return folder, nil
+}
+
+// cleanName trims the name and checks it can name an item of a folder
+func cleanName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." || len(name) > _maxNameLength || strings.ContainsAny(name, `/\`) {
+		return "", ErrInvalidName
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", ErrInvalidName
+	}
+	return name, nil
+}
+
+// parentIdOf is the id of the item's folder, zero for the root
+func parentIdOf(item *drive_entity.FileItemEntity) int64 {
+	parentId, _ := strconv.ParseInt(item.ParentId, 10, 64)
+	return parentId
+}
+
+// itemPath is the path of an item named name in folder, the root when folder is nil
+func itemPath(folder *drive_entity.FileItemEntity, name string) string {
+	if folder == nil {
+		return "/" + name
+	}
+	return strings.TrimSuffix(folder.FilePath, "/") + "/" + name
+}
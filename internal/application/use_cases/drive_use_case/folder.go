@@ -0,0 +1,325 @@
+package drive_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+)
+
+// What to do when an item is put in a folder that has an item of the same name
+const (
+	ConflictFail   = "fail"
+	ConflictRename = "rename"
+)
+
+const (
+	// A copy makes at most _maxCopyItems items
+	_maxCopyItems = 1000
+	// Names are numbered up to _maxRenames before giving up
+	_maxRenames = 100
+)
+
+var (
+	ErrCycle        = errors.New("a folder cannot be moved or copied into itself or a folder below it")
+	ErrCopyTooLarge = errors.New("folder has too many items to copy")
+)
+
+// CreateFolder creates an empty folder in the user's folder ParentId
+func (u *FileUseCase) CreateFolder(ctx context.Context, userId int64, dto file_dto.CreateFolderDto) (*drive_entity.FileItemEntity, error) {
+	name, err := cleanName(dto.Name)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := findFolder(u.fileItemReadRepo, userId, dto.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	item := newItem(userId, folder, name, "", 0, "", "")
+	item.IsDirectory = true
+	err = u.fileItemWriteRepo.Create(item)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrNameConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// List returns the live items of the user's folder ParentId, folders first
+func (u *FileUseCase) List(ctx context.Context, userId int64, dto file_dto.FolderFilterDto) (common_dto.PaginatedDto[drive_entity.FileItemEntity], error) {
+	if _, err := findFolder(u.fileItemReadRepo, userId, dto.ParentId); err != nil {
+		return common_dto.PaginatedDto[drive_entity.FileItemEntity]{}, err
+	}
+	items, total, err := u.fileItemReadRepo.FindChildren(drive_repo_inter.FileItemFilter{
+		UserId:   userId,
+		ParentId: dto.ParentId,
+		Offset:   dto.Offset(),
+		Limit:    dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[drive_entity.FileItemEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(items, total, dto.PaginationDto), nil
+}
+
+// Move puts an item of the user in the folder ParentId with the items below it. Objects are kept under
+// their keys, only the paths change.
+func (u *FileUseCase) Move(ctx context.Context, userId int64, id int64, dto file_dto.DestinationDto) (*drive_entity.FileItemEntity, error) {
+	item, err := findItem(u.fileItemReadRepo, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := u.destination(userId, item, dto.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	if parentIdOf(item) == dto.ParentId {
+		return item, nil
+	}
+	name, err := u.freeName(userId, dto.ParentId, item.Name, item.IsDirectory, dto.OnConflict)
+	if err != nil {
+		return nil, err
+	}
+	err = u.fileItemWriteRepo.Move(id, dto.ParentId, name, itemPath(folder, name))
+	return u.arranged(userId, id, err)
+}
+
+// Rename renames an item of the user in its folder
+func (u *FileUseCase) Rename(ctx context.Context, userId int64, id int64, dto file_dto.RenameDto) (*drive_entity.FileItemEntity, error) {
+	item, err := findItem(u.fileItemReadRepo, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	name, err := cleanName(dto.Name)
+	if err != nil {
+		return nil, err
+	}
+	if name == item.Name {
+		return item, nil
+	}
+	parentId := parentIdOf(item)
+	if name, err = u.freeName(userId, parentId, name, item.IsDirectory, dto.OnConflict); err != nil {
+		return nil, err
+	}
+	folderPath := item.FilePath[:strings.LastIndex(item.FilePath, "/")]
+	err = u.fileItemWriteRepo.Move(id, parentId, name, folderPath+"/"+name)
+	return u.arranged(userId, id, err)
+}
+
+// Copy copies an item of the user into the folder ParentId with the live items below it. Files are
+// copied to new objects, so their storage is taken from the user's quota again.
+func (u *FileUseCase) Copy(ctx context.Context, userId int64, id int64, dto file_dto.DestinationDto) (*drive_entity.FileItemEntity, error) {
+	item, err := findItem(u.fileItemReadRepo, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := u.destination(userId, item, dto.ParentId)
+	if err != nil {
+		return nil, err
+	}
+	sources, parents, err := u.copySources(id)
+	if err != nil {
+		return nil, err
+	}
+	name, err := u.freeName(userId, dto.ParentId, item.Name, item.IsDirectory, dto.OnConflict)
+	if err != nil {
+		return nil, err
+	}
+
+	var kb int64
+	for _, source := range sources {
+		if !source.IsDirectory {
+			kb += sizeKb(source.Size)
+		}
+	}
+	var reservationId string
+	if kb > 0 {
+		if reservationId, err = u.reserve(ctx, userId, kb, ActionStorageCopy); err != nil {
+			return nil, err
+		}
+	}
+
+	copies := make([]*drive_entity.FileItemEntity, 0, len(sources))
+	for i, source := range sources {
+		folderOf, nameOf := folder, name
+		if i > 0 {
+			folderOf, nameOf = copies[parents[i]], source.Name
+		}
+		if source.IsDirectory {
+			dir := newItem(userId, folderOf, nameOf, "", 0, "", "")
+			dir.IsDirectory = true
+			copies = append(copies, dir)
+			continue
+		}
+		key, err := serverKey(userId, source.Name)
+		if err == nil {
+			err = u.storage.Copy(ctx, source.BucketName, source.ServerKey, key)
+		}
+		if err != nil {
+			u.dropObjects(ctx, copies)
+			u.release(ctx, reservationId)
+			if errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+				u.l.Error("drive_use_case - FileUseCase - Copy - item %s: object %s is missing", source.Id, source.ServerKey)
+				return nil, ErrFileNotFound
+			}
+			return nil, err
+		}
+		copies = append(copies, newItem(userId, folderOf, nameOf, source.MimeType, source.Size, source.BucketName, key))
+	}
+	if err := u.charge(ctx, userId, reservationId, kb, ActionStorageCopy); err != nil {
+		u.dropObjects(ctx, copies)
+		u.release(ctx, reservationId)
+		return nil, err
+	}
+
+	err = u.fileItemWriteRepo.CreateTree(copies, parents)
+	if err != nil {
+		u.dropObjects(ctx, copies)
+		u.free(ctx, userId, kb)
+		switch {
+		case errors.Is(err, repositories.ErrConflict):
+			return nil, ErrNameConflict
+		case errors.Is(err, repositories.ErrNotFound):
+			return nil, ErrParentNotFound
+		}
+		return nil, err
+	}
+	// Variants are rendered again for the new objects, under keys of their own
+	for _, item := range copies {
+		queueImage(ctx, u.eventPublisher, u.l, item)
+	}
+	return copies[0], nil
+}
+
+// destination returns the folder an item is moved or copied into, refusing folders below the item
+func (u *FileUseCase) destination(userId int64, item *drive_entity.FileItemEntity, parentId int64) (*drive_entity.FileItemEntity, error) {
+	folder, err := findFolder(u.fileItemReadRepo, userId, parentId)
+	if err != nil || folder == nil || !item.IsDirectory {
+		return folder, err
+	}
+	id, _ := strconv.ParseInt(item.Id, 10, 64)
+	within, err := u.fileItemReadRepo.IsWithin(parentId, id)
+	if err != nil {
+		return nil, err
+	}
+	if within {
+		return nil, ErrCycle
+	}
+	return folder, nil
+}
+
+// copySources returns the live items of the tree of the item, folders before their items, with the
+// position of the folder of each among them
+func (u *FileUseCase) copySources(id int64) ([]drive_entity.FileItemEntity, []int, error) {
+	tree, err := u.fileItemReadRepo.FindTree(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	positions := make(map[string]int, len(tree))
+	sources := make([]drive_entity.FileItemEntity, 0, len(tree))
+	parents := make([]int, 0, len(tree))
+	for _, item := range tree {
+		if item.IsDeleted {
+			continue
+		}
+		parent := 0
+		if len(sources) > 0 {
+			// Items below deleted folders are left out with them
+			var ok bool
+			if parent, ok = positions[item.ParentId]; !ok {
+				continue
+			}
+		}
+		if len(sources) == _maxCopyItems {
+			return nil, nil, ErrCopyTooLarge
+		}
+		positions[item.Id] = len(sources)
+		sources = append(sources, item)
+		parents = append(parents, parent)
+	}
+	if len(sources) == 0 {
+		return nil, nil, ErrFileNotFound
+	}
+	return sources, parents, nil
+}
+
+// arranged returns the item once moved, renamed or restored, mapping the errors of the repository
+func (u *FileUseCase) arranged(userId int64, id int64, err error) (*drive_entity.FileItemEntity, error) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return nil, ErrFileNotFound
+	case errors.Is(err, repositories.ErrConflict):
+		return nil, ErrNameConflict
+	case errors.Is(err, drive_repo_inter.ErrCycle):
+		return nil, ErrCycle
+	case err != nil:
+		return nil, err
+	}
+	return findItem(u.fileItemReadRepo, userId, id)
+}
+
+// freeName returns name unless the folder has a live item of that name. Then onConflict "rename"
+// numbers the name as in "name (2).ext", anything else fails with ErrNameConflict.
+func (u *FileUseCase) freeName(userId int64, parentId int64, name string, isDirectory bool, onConflict string) (string, error) {
+	taken, err := u.fileItemReadRepo.NameTaken(userId, parentId, name)
+	if err != nil {
+		return "", err
+	}
+	if !taken {
+		return name, nil
+	}
+	if onConflict != ConflictRename {
+		return "", ErrNameConflict
+	}
+
+	base, ext := name, ""
+	if !isDirectory && path.Ext(name) != name {
+		ext = path.Ext(name)
+		base = strings.TrimSuffix(name, ext)
+	}
+	for n := 2; n <= _maxRenames; n++ {
+		suffix := fmt.Sprintf(" (%d)%s", n, ext)
+		if len(suffix) >= _maxNameLength {
+			break
+		}
+		// Long names are cut at a rune so the number fits
+		cut := base
+		for len(cut)+len(suffix) > _maxNameLength {
+			_, size := utf8.DecodeLastRuneInString(cut)
+			cut = cut[:len(cut)-size]
+		}
+		candidate := cut + suffix
+		taken, err := u.fileItemReadRepo.NameTaken(userId, parentId, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", ErrNameConflict
+}
+
+// dropObjects deletes the objects of copies that were not recorded
+func (u *FileUseCase) dropObjects(ctx context.Context, items []*drive_entity.FileItemEntity) {
+	for _, item := range items {
+		if item.IsDirectory {
+			continue
+		}
+		if err := u.storage.Delete(ctx, item.BucketName, item.ServerKey); err != nil {
+			u.l.Error("drive_use_case - FileUseCase - dropObjects - %s: %v", item.ServerKey, err)
+		}
+	}
+}
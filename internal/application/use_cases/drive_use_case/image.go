@@ -0,0 +1,287 @@
+package drive_use_case
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/media/image_processor_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// ImageBatchSize is how many pending images are queued again per run of the job
+const ImageBatchSize = 100
+
+// Variants are kept under _mediaPrefix, which the store or the API serves to anyone
+const _mediaPrefix = "media/"
+
+// ImageUseCase renders the thumbnail and the responsive sizes of the images stored in the drives and
+// tells sites where to find them. Variants take no storage from the user's quota.
+type ImageUseCase struct {
+	fileItemReadRepo  drive_repo_inter.FileItemReadRepository
+	fileItemWriteRepo drive_repo_inter.FileItemWriteRepository
+	storage           object_storage_inter.ObjectStorage
+	processor         image_processor_inter.ImageProcessor
+	eventPublisher    event_publisher_inter.EventPublisher
+	sizes             []image_processor_inter.Size
+	bucket            string
+	publicUrl         string
+	requeueAfter      time.Duration
+	l                 *logger.ZapLogger
+}
+
+// NewImageUseCase renders a thumbnailSize square thumbnail and a size of each of widths. Variants are
+// linked under publicUrl, and served from bucket by Open. Images still pending requeueAfter after they
+// were stored are queued again.
+func NewImageUseCase(fileItemReadRepo drive_repo_inter.FileItemReadRepository, fileItemWriteRepo drive_repo_inter.FileItemWriteRepository, storage object_storage_inter.ObjectStorage, processor image_processor_inter.ImageProcessor, eventPublisher event_publisher_inter.EventPublisher, widths []int, thumbnailSize int, bucket string, publicUrl string, requeueAfter time.Duration, l *logger.ZapLogger) *ImageUseCase {
+	sizes := []image_processor_inter.Size{{Name: drive_entity.VariantThumbnail, Width: thumbnailSize, Height: thumbnailSize}}
+	for _, width := range widths {
+		if width > 0 {
+			sizes = append(sizes, image_processor_inter.Size{Name: fmt.Sprintf("w%d", width), Width: width})
+		}
+	}
+	return &ImageUseCase{
+		fileItemReadRepo:  fileItemReadRepo,
+		fileItemWriteRepo: fileItemWriteRepo,
+		storage:           storage,
+		processor:         processor,
+		eventPublisher:    eventPublisher,
+		sizes:             sizes,
+		bucket:            bucket,
+		publicUrl:         strings.TrimSuffix(publicUrl, "/"),
+		requeueAfter:      requeueAfter,
+		l:                 l,
+	}
+}
+
+// Process renders the variants of a pending or private image. Variants are public, so images that sites
+// may not show are marked private and keep none until they are published. Images that cannot be decoded
+// are marked failed and keep no variants. An error means the image can be processed again later.
+func (u *ImageUseCase) Process(ctx context.Context, id int64) error {
+	item, err := u.fileItemReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// Purged before its turn
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if item.ImageStatus != drive_entity.ImageStatusPending && item.ImageStatus != drive_entity.ImageStatusPrivate {
+		return nil
+	}
+	published, err := u.fileItemReadRepo.IsPublished(id)
+	if err != nil {
+		return err
+	}
+	if !published {
+		if item.ImageStatus == drive_entity.ImageStatusPrivate {
+			return nil
+		}
+		return u.save(id, drive_entity.ImageStatusPrivate, nil)
+	}
+
+	object, err := u.storage.Get(ctx, item.BucketName, item.ServerKey)
+	if errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		u.l.Error("drive_use_case - ImageUseCase - Process - item %d: object %s is missing", id, item.ServerKey)
+		return u.save(id, drive_entity.ImageStatusFailed, nil)
+	}
+	if err != nil {
+		return err
+	}
+	renditions, err := u.processor.Render(ctx, object.Body, u.sizes)
+	object.Body.Close()
+	if errors.Is(err, image_processor_inter.ErrUnsupportedImage) || errors.Is(err, image_processor_inter.ErrImageTooLarge) {
+		u.l.Info("drive_use_case - ImageUseCase - Process - item %d: %v", id, err)
+		return u.save(id, drive_entity.ImageStatusFailed, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keys follow from the item, so a retry overwrites the variants of an attempt that failed half way
+	variants := make([]drive_entity.FileVariantEntity, 0, len(renditions))
+	for _, rendition := range renditions {
+		key := variantKey(item.ServerKey, rendition.Name, rendition.Ext)
+		err := u.storage.Put(ctx, item.BucketName, key, bytes.NewReader(rendition.Data), int64(len(rendition.Data)), rendition.MimeType)
+		if err != nil {
+			return err
+		}
+		variants = append(variants, drive_entity.FileVariantEntity{
+			Name:       rendition.Name,
+			Width:      rendition.Width,
+			Height:     rendition.Height,
+			Size:       int64(len(rendition.Data)),
+			MimeType:   rendition.MimeType,
+			BucketName: item.BucketName,
+			ServerKey:  key,
+		})
+	}
+	err = u.fileItemWriteRepo.SaveImage(id, drive_entity.ImageStatusReady, variants)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// Purged while it was rendered, its variants were not known then
+		for _, variant := range variants {
+			if err := u.storage.Delete(ctx, variant.BucketName, variant.ServerKey); err != nil {
+				u.l.Error("drive_use_case - ImageUseCase - Process - %s: %v", variant.ServerKey, err)
+			}
+		}
+		return nil
+	}
+	if errors.Is(err, repositories.ErrConflict) {
+		// Processed by another delivery of the event
+		return nil
+	}
+	return err
+}
+
+// RequeuePending queues again the images pending for longer than they should, as when their event was
+// lost, and the private images that were published since. It returns how many were queued.
+func (u *ImageUseCase) RequeuePending(ctx context.Context, now time.Time) (int, error) {
+	items, err := u.fileItemReadRepo.FindPendingImages(now.Add(-u.requeueAfter), ImageBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for i := range items {
+		if err := publishImage(ctx, u.eventPublisher, &items[i]); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// Images returns the ready images of the drive items ids by id. Ids that are not ready images are left
+// out, so media show their plain file until then.
+func (u *ImageUseCase) Images(ctx context.Context, ids []string) (map[string]*drive_entity.MediaImage, error) {
+	images := make(map[string]*drive_entity.MediaImage, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	fileIds := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		fileId, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || seen[fileId] {
+			continue
+		}
+		seen[fileId] = true
+		fileIds = append(fileIds, fileId)
+	}
+	if len(fileIds) == 0 {
+		return images, nil
+	}
+
+	items, err := u.fileItemReadRepo.FindImages(fileIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if image := u.mediaImage(item.Variants); image != nil {
+			images[item.Id] = image
+		}
+	}
+	return images, nil
+}
+
+// Open opens a variant under its key for anyone, the caller closes its Body. Keys outside the media are
+// not found.
+func (u *ImageUseCase) Open(ctx context.Context, key string) (*object_storage_inter.Object, error) {
+	if !strings.HasPrefix(key, _mediaPrefix) || path.Clean("/"+key) != "/"+key {
+		return nil, ErrFileNotFound
+	}
+	object, err := u.storage.Get(ctx, u.bucket, key)
+	if errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+func (u *ImageUseCase) save(id int64, status string, variants []drive_entity.FileVariantEntity) error {
+	err := u.fileItemWriteRepo.SaveImage(id, status, variants)
+	if errors.Is(err, repositories.ErrNotFound) || errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	return err
+}
+
+// mediaImage links the variants of an image, nil when it has no responsive size
+func (u *ImageUseCase) mediaImage(variants []drive_entity.FileVariantEntity) *drive_entity.MediaImage {
+	image := &drive_entity.MediaImage{}
+	var widths []drive_entity.FileVariantEntity
+	for _, variant := range variants {
+		if variant.Name == drive_entity.VariantThumbnail {
+			image.ThumbnailUrl = u.url(variant.ServerKey)
+			continue
+		}
+		widths = append(widths, variant)
+	}
+	if len(widths) == 0 {
+		return nil
+	}
+	sort.Slice(widths, func(i, j int) bool {
+		return widths[i].Width < widths[j].Width
+	})
+
+	srcSet := make([]string, 0, len(widths))
+	for _, variant := range widths {
+		srcSet = append(srcSet, fmt.Sprintf("%s %dw", u.url(variant.ServerKey), variant.Width))
+	}
+	largest := widths[len(widths)-1]
+	image.Url = u.url(largest.ServerKey)
+	image.SrcSet = strings.Join(srcSet, ", ")
+	image.Width = largest.Width
+	image.Height = largest.Height
+	return image
+}
+
+func (u *ImageUseCase) url(key string) string {
+	return u.publicUrl + "/" + key
+}
+
+// isImage reports whether files of the MIME type get variants
+func isImage(mimeType string) bool {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// variantKey is the key of a variant of the object key. The user's files stay private under "users/",
+// their variants go under the media, next to each other.
+func variantKey(key string, name string, ext string) string {
+	base := strings.TrimPrefix(strings.TrimSuffix(key, path.Ext(key)), "users/")
+	return _mediaPrefix + base + "/" + name + ext
+}
+
+// publishImage asks for the variants of a pending image
+func publishImage(ctx context.Context, eventPublisher event_publisher_inter.EventPublisher, item *drive_entity.FileItemEntity) error {
+	return eventPublisher.Publish(ctx, event_publisher_inter.DriveExchange, event_dto.RoutingKeyImageStored, event_dto.ImageStoredEvent{
+		FileItemId: item.Id,
+		UserId:     item.UserId,
+		StoredAt:   item.UpdatedAt,
+	})
+}
+
+// queueImage publishes the event of a stored image. A lost event leaves the image pending until the job
+// queues it again, so the file is stored all the same.
+func queueImage(ctx context.Context, eventPublisher event_publisher_inter.EventPublisher, l *logger.ZapLogger, item *drive_entity.FileItemEntity) {
+	if item.ImageStatus != drive_entity.ImageStatusPending {
+		return
+	}
+	if err := publishImage(ctx, eventPublisher, item); err != nil {
+		l.Warn("drive_use_case - queueImage - item %s: %v", item.Id, err)
+	}
+}
@@ -0,0 +1,132 @@
+package drive_use_case
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/media/image_processor_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// imageDrive keeps one image item of the drive and the objects of its bucket
+type imageDrive struct {
+	item      drive_entity.FileItemEntity
+	published bool
+	objects   map[string][]byte
+	renders   int
+}
+
+type imageReadRepo struct {
+	drive_repo_inter.FileItemReadRepository
+	d *imageDrive
+}
+
+func (r imageReadRepo) FindById(id int64) (*drive_entity.FileItemEntity, error) {
+	item := r.d.item
+	return &item, nil
+}
+
+func (r imageReadRepo) IsPublished(id int64) (bool, error) {
+	return r.d.published, nil
+}
+
+type imageWriteRepo struct {
+	drive_repo_inter.FileItemWriteRepository
+	d *imageDrive
+}
+
+func (r imageWriteRepo) SaveImage(id int64, status string, variants []drive_entity.FileVariantEntity) error {
+	r.d.item.ImageStatus = status
+	r.d.item.Variants = variants
+	return nil
+}
+
+type imageStorage struct {
+	object_storage_inter.ObjectStorage
+	d *imageDrive
+}
+
+func (o imageStorage) Get(ctx context.Context, bucket string, key string) (*object_storage_inter.Object, error) {
+	content, ok := o.d.objects[key]
+	if !ok {
+		return nil, object_storage_inter.ErrObjectNotFound
+	}
+	return &object_storage_inter.Object{Body: io.NopCloser(bytes.NewReader(content)), Size: int64(len(content))}, nil
+}
+
+func (o imageStorage) Put(ctx context.Context, bucket string, key string, body io.Reader, size int64, contentType string) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	o.d.objects[key] = content
+	return nil
+}
+
+type imageRenderer struct{ d *imageDrive }
+
+func (p imageRenderer) Render(ctx context.Context, src io.Reader, sizes []image_processor_inter.Size) ([]image_processor_inter.Rendition, error) {
+	p.d.renders++
+	renditions := make([]image_processor_inter.Rendition, 0, len(sizes))
+	for _, size := range sizes {
+		renditions = append(renditions, image_processor_inter.Rendition{
+			Name:     size.Name,
+			Width:    size.Width,
+			Height:   size.Width,
+			Data:     []byte(size.Name),
+			MimeType: "image/webp",
+			Ext:      ".webp",
+		})
+	}
+	return renditions, nil
+}
+
+func newTestImageUseCase(d *imageDrive) *ImageUseCase {
+	return NewImageUseCase(imageReadRepo{d: d}, imageWriteRepo{d: d}, imageStorage{d: d}, imageRenderer{d: d}, eventPublisher{},
+		[]int{640}, 150, "files", "https://cdn.example.com", time.Hour, logger.NewLoggerFromConfig("error", "json", "stdout"))
+}
+
+func TestProcessRendersNoVariantsOfUnpublishedImages(t *testing.T) {
+	d := &imageDrive{
+		item: drive_entity.FileItemEntity{
+			Id:          "7",
+			Permission:  drive_entity.FilePermissionPrivate,
+			ImageStatus: drive_entity.ImageStatusPending,
+			BucketName:  "files",
+			ServerKey:   "users/5/photo.jpg",
+		},
+		objects: map[string][]byte{"users/5/photo.jpg": []byte("jpeg")},
+	}
+	useCase := newTestImageUseCase(d)
+
+	if err := useCase.Process(context.Background(), 7); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if d.item.ImageStatus != drive_entity.ImageStatusPrivate || d.renders != 0 || len(d.objects) != 1 {
+		t.Fatalf("status %q, %d renders, %d objects: want a private image without variants", d.item.ImageStatus, d.renders, len(d.objects))
+	}
+
+	// Once a site uses it, the job queues it again
+	d.published = true
+	if err := useCase.Process(context.Background(), 7); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if d.item.ImageStatus != drive_entity.ImageStatusReady || len(d.item.Variants) != 2 {
+		t.Fatalf("status %q with %d variants, want ready with 2", d.item.ImageStatus, len(d.item.Variants))
+	}
+	for _, variant := range d.item.Variants {
+		if !strings.HasPrefix(variant.ServerKey, _mediaPrefix) {
+			t.Errorf("variant key %q is not under the media", variant.ServerKey)
+		}
+		if _, ok := d.objects[variant.ServerKey]; !ok {
+			t.Errorf("variant %s was not stored", variant.ServerKey)
+		}
+	}
+}
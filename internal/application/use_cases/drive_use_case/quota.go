@@ -0,0 +1,63 @@
+package drive_use_case
+
+import (
+	"context"
+	"errors"
+
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/pkg/logger"
+)
+
+// quota takes the storage of drive files from the users' storage credits, in KB
+type quota struct {
+	meteringUseCase *user_use_case.MeteringUseCase
+	l               *logger.ZapLogger
+}
+
+func (q quota) reserve(ctx context.Context, userId int64, kb int64, action string) (string, error) {
+	reservation, err := q.meteringUseCase.Reserve(ctx, userId, user_entity.CreditStorageMb, kb, action)
+	if errors.Is(err, user_use_case.ErrInsufficientCredits) {
+		return "", ErrQuotaExceeded
+	}
+	if err != nil {
+		return "", err
+	}
+	return reservation.Id, nil
+}
+
+// charge pays the storage of a stored file from its reservation. Reservations time out, so the file
+// of a long upload is checked against the quota again instead.
+func (q quota) charge(ctx context.Context, userId int64, reservationId string, kb int64, action string) error {
+	if kb == 0 {
+		return nil
+	}
+	err := q.meteringUseCase.Commit(ctx, reservationId)
+	if !errors.Is(err, user_use_case.ErrReservationNotFound) {
+		return err
+	}
+	reservationId, err = q.reserve(ctx, userId, kb, action)
+	if err != nil {
+		return err
+	}
+	return q.meteringUseCase.Commit(ctx, reservationId)
+}
+
+func (q quota) release(ctx context.Context, reservationId string) {
+	if reservationId == "" {
+		return
+	}
+	if err := q.meteringUseCase.Release(ctx, reservationId); err != nil {
+		q.l.Error("drive_use_case - quota - release - reservation %s: %v", reservationId, err)
+	}
+}
+
+// free gives back kb of storage charged for files that are not kept
+func (q quota) free(ctx context.Context, userId int64, kb int64) {
+	if kb == 0 {
+		return
+	}
+	if err := q.meteringUseCase.FreeStorage(ctx, userId, kb); err != nil {
+		q.l.Error("drive_use_case - quota - free - user %d: %v", userId, err)
+	}
+}
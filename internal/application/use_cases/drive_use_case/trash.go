@@ -0,0 +1,137 @@
+package drive_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+)
+
+// TrashBatchSize is how many deleted items are purged per page when the trash is emptied
+const TrashBatchSize = 100
+
+// Trash deletes a live item of the user with the live items below it, they can be restored until purged
+func (u *FileUseCase) Trash(ctx context.Context, userId int64, id int64) error {
+	if _, err := findItem(u.fileItemReadRepo, userId, id); err != nil {
+		return err
+	}
+	err := u.fileItemWriteRepo.Trash(id, time.Now())
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrFileNotFound
+	}
+	return err
+}
+
+// ListTrash returns the items the user deleted, but those deleted together with their folder
+func (u *FileUseCase) ListTrash(ctx context.Context, userId int64, dto file_dto.TrashFilterDto) (common_dto.PaginatedDto[drive_entity.FileItemEntity], error) {
+	items, total, err := u.fileItemReadRepo.FindTrash(userId, dto.Offset(), dto.Limit())
+	if err != nil {
+		return common_dto.PaginatedDto[drive_entity.FileItemEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(items, total, dto.PaginationDto), nil
+}
+
+// Restore brings a deleted item of the user back with the items deleted together with it. It goes back
+// in its folder, or in the root when the folder is deleted too.
+func (u *FileUseCase) Restore(ctx context.Context, userId int64, id int64, dto file_dto.RestoreDto) (*drive_entity.FileItemEntity, error) {
+	item, err := u.findDeleted(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	parentId := parentIdOf(item)
+	folder, err := findFolder(u.fileItemReadRepo, userId, parentId)
+	if errors.Is(err, ErrParentNotFound) {
+		parentId = 0
+	} else if err != nil {
+		return nil, err
+	}
+	name, err := u.freeName(userId, parentId, item.Name, item.IsDirectory, dto.OnConflict)
+	if err != nil {
+		return nil, err
+	}
+	err = u.fileItemWriteRepo.Restore(id, parentId, name, itemPath(folder, name))
+	return u.arranged(userId, id, err)
+}
+
+// Purge removes a deleted item of the user and everything below it for good, with the variants of
+// images. Their storage is given back to the user's quota.
+func (u *FileUseCase) Purge(ctx context.Context, userId int64, id int64) error {
+	if _, err := u.findDeleted(userId, id); err != nil {
+		return err
+	}
+	items, err := u.fileItemWriteRepo.Purge(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrFileNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var kb int64
+	for _, item := range items {
+		if item.IsDirectory {
+			continue
+		}
+		kb += sizeKb(item.Size)
+		// The rows are gone, an object left behind only wastes space in the store
+		if err := u.storage.Delete(ctx, item.BucketName, item.ServerKey); err != nil {
+			u.l.Error("drive_use_case - FileUseCase - Purge - item %s: %v", item.Id, err)
+		}
+		for _, variant := range item.Variants {
+			if err := u.storage.Delete(ctx, variant.BucketName, variant.ServerKey); err != nil {
+				u.l.Error("drive_use_case - FileUseCase - Purge - item %s variant %s: %v", item.Id, variant.Name, err)
+			}
+		}
+	}
+	u.free(ctx, userId, kb)
+	return nil
+}
+
+// EmptyTrash purges every deleted item of the user and returns how many were purged from the trash
+func (u *FileUseCase) EmptyTrash(ctx context.Context, userId int64) (int, error) {
+	purged := 0
+	for {
+		items, _, err := u.fileItemReadRepo.FindTrash(userId, 0, TrashBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		if len(items) == 0 {
+			return purged, nil
+		}
+		for _, item := range items {
+			if err := ctx.Err(); err != nil {
+				return purged, err
+			}
+			id, _ := strconv.ParseInt(item.Id, 10, 64)
+			err := u.Purge(ctx, userId, id)
+			if errors.Is(err, ErrFileNotFound) {
+				// Restored or purged meanwhile
+				continue
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+}
+
+// findDeleted returns a deleted item of the user
+func (u *FileUseCase) findDeleted(userId int64, id int64) (*drive_entity.FileItemEntity, error) {
+	item, err := u.fileItemReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if item.UserId != strconv.FormatInt(userId, 10) || !item.IsDeleted {
+		return nil, ErrFileNotFound
+	}
+	return item, nil
+}
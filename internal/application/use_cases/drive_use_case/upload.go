@@ -0,0 +1,520 @@
+package drive_use_case
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// Actions of usages taking storage for drive files
+const (
+	ActionStorageUpload = "storage.upload"
+	ActionStorageCopy   = "storage.copy"
+)
+
+// UploadBatchSize is how many expired uploads are ended per run of the job
+const UploadBatchSize = 100
+
+const (
+	// Object stores take at most this many parts of at least _minPartSize bytes, but the last one
+	_maxParts    = 10000
+	_minPartSize = 5 << 20
+)
+
+var (
+	ErrFileTooLarge     = errors.New("file is larger than allowed")
+	ErrQuotaExceeded    = errors.New("storage quota exceeded")
+	ErrUploadNotFound   = errors.New("upload not found")
+	ErrUploadClosed     = errors.New("upload is completed, aborted or expired")
+	ErrInvalidPart      = errors.New("part number out of range")
+	ErrPartSize         = errors.New("part has the wrong size")
+	ErrUploadIncomplete = errors.New("upload has parts missing")
+)
+
+// UploadUseCase stores files in the object store and records them in the user's drive. The storage a
+// file takes is reserved from the user's quota before it is sent and paid once it is stored, so
+// concurrent uploads cannot go over the quota together.
+type UploadUseCase struct {
+	quota
+	fileItemReadRepo  drive_repo_inter.FileItemReadRepository
+	fileItemWriteRepo drive_repo_inter.FileItemWriteRepository
+	uploadReadRepo    drive_repo_inter.UploadReadRepository
+	uploadWriteRepo   drive_repo_inter.UploadWriteRepository
+	storage           object_storage_inter.ObjectStorage
+	eventPublisher    event_publisher_inter.EventPublisher
+	bucket            string
+	partSize          int64
+	maxFileSize       int64
+	uploadTTL         time.Duration
+	l                 *logger.ZapLogger
+}
+
+// NewUploadUseCase stores files in bucket. Uploads in parts are cut in parts of partSize bytes, raised
+// to what object stores accept, and expire uploadTTL after they started.
+func NewUploadUseCase(fileItemReadRepo drive_repo_inter.FileItemReadRepository, fileItemWriteRepo drive_repo_inter.FileItemWriteRepository, uploadReadRepo drive_repo_inter.UploadReadRepository, uploadWriteRepo drive_repo_inter.UploadWriteRepository, storage object_storage_inter.ObjectStorage, meteringUseCase *user_use_case.MeteringUseCase, eventPublisher event_publisher_inter.EventPublisher, bucket string, partSize int64, maxFileSize int64, uploadTTL time.Duration, l *logger.ZapLogger) *UploadUseCase {
+	if partSize < _minPartSize {
+		partSize = _minPartSize
+	}
+	return &UploadUseCase{
+		quota:             quota{meteringUseCase: meteringUseCase, l: l},
+		fileItemReadRepo:  fileItemReadRepo,
+		fileItemWriteRepo: fileItemWriteRepo,
+		uploadReadRepo:    uploadReadRepo,
+		uploadWriteRepo:   uploadWriteRepo,
+		storage:           storage,
+		eventPublisher:    eventPublisher,
+		bucket:            bucket,
+		partSize:          partSize,
+		maxFileSize:       maxFileSize,
+		uploadTTL:         uploadTTL,
+		l:                 l,
+	}
+}
+
+// Upload stores a file sent in one request. Name defaults to fileName, the MIME type to the one of
+// its extension when contentType is empty.
+func (u *UploadUseCase) Upload(ctx context.Context, userId int64, dto file_dto.UploadFileDto, fileName string, body io.Reader, size int64, contentType string) (*drive_entity.FileItemEntity, error) {
+	if dto.Name == "" {
+		dto.Name = fileName
+	}
+	name, folder, err := u.checkNew(userId, dto.ParentId, dto.Name, size)
+	if err != nil {
+		return nil, err
+	}
+
+	kb := sizeKb(size)
+	var reservationId string
+	if kb > 0 {
+		if reservationId, err = u.reserve(ctx, userId, kb, ActionStorageUpload); err != nil {
+			return nil, err
+		}
+	}
+	key, err := serverKey(userId, name)
+	if err != nil {
+		u.release(ctx, reservationId)
+		return nil, err
+	}
+	mimeType := fileMimeType(name, contentType)
+	if err := u.storage.Put(ctx, u.bucket, key, body, size, mimeType); err != nil {
+		u.release(ctx, reservationId)
+		return nil, err
+	}
+	if err := u.charge(ctx, userId, reservationId, kb, ActionStorageUpload); err != nil {
+		u.release(ctx, reservationId)
+		u.discard(ctx, userId, key, 0)
+		return nil, err
+	}
+
+	item := newItem(userId, folder, name, mimeType, size, u.bucket, key)
+	if err := u.fileItemWriteRepo.Create(item); err != nil {
+		u.discard(ctx, userId, key, kb)
+		if errors.Is(err, repositories.ErrConflict) {
+			return nil, ErrNameConflict
+		}
+		return nil, err
+	}
+	queueImage(ctx, u.eventPublisher, u.l, item)
+	return item, nil
+}
+
+// StartUpload reserves the storage of a file and opens an upload for its parts. The parts can be sent
+// in any order and again after a failure, GetUpload tells which are missing.
+func (u *UploadUseCase) StartUpload(ctx context.Context, userId int64, dto file_dto.StartUploadDto) (*file_dto.UploadDto, error) {
+	name, folder, err := u.checkNew(userId, dto.ParentId, dto.Name, dto.Size)
+	if err != nil {
+		return nil, err
+	}
+	key, err := serverKey(userId, name)
+	if err != nil {
+		return nil, err
+	}
+	reservationId, err := u.reserve(ctx, userId, sizeKb(dto.Size), ActionStorageUpload)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := fileMimeType(name, dto.MimeType)
+	storageUploadId, err := u.storage.CreateMultipart(ctx, u.bucket, key, mimeType)
+	if err != nil {
+		u.release(ctx, reservationId)
+		return nil, err
+	}
+
+	upload := &drive_entity.UploadEntity{
+		UserId:          strconv.FormatInt(userId, 10),
+		Name:            name,
+		MimeType:        mimeType,
+		Size:            dto.Size,
+		PartSize:        u.partSizeOf(dto.Size),
+		BucketName:      u.bucket,
+		ServerKey:       key,
+		StorageUploadId: storageUploadId,
+		ReservationId:   reservationId,
+		Status:          drive_entity.UploadStatusUploading,
+		ExpiresAt:       time.Now().Add(u.uploadTTL),
+	}
+	if folder != nil {
+		upload.ParentId = folder.Id
+	}
+	if err := u.uploadWriteRepo.Create(upload); err != nil {
+		if err := u.storage.AbortMultipart(ctx, u.bucket, key, storageUploadId); err != nil {
+			u.l.Error("drive_use_case - UploadUseCase - StartUpload - abort %s: %v", key, err)
+		}
+		u.release(ctx, reservationId)
+		return nil, err
+	}
+	return uploadDto(upload), nil
+}
+
+// GetUpload returns an upload of the user with the parts still to be sent
+func (u *UploadUseCase) GetUpload(ctx context.Context, userId int64, id int64) (*file_dto.UploadDto, error) {
+	upload, err := u.findUpload(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return uploadDto(upload), nil
+}
+
+// UploadPart stores part number of an open upload. Every part is PartSize bytes but the last one,
+// which is the rest of the file. Sending a part again replaces it.
+func (u *UploadUseCase) UploadPart(ctx context.Context, userId int64, id int64, number int, body io.Reader, size int64) (*drive_entity.UploadPartEntity, error) {
+	upload, err := u.findUpload(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isOpen(upload) {
+		return nil, ErrUploadClosed
+	}
+	if number < 1 || number > partCount(upload) {
+		return nil, fmt.Errorf("%w: parts are numbered 1 to %d", ErrInvalidPart, partCount(upload))
+	}
+	if expected := partLength(upload, number); size != expected {
+		return nil, fmt.Errorf("%w: part %d must be %d bytes", ErrPartSize, number, expected)
+	}
+
+	etag, err := u.storage.UploadPart(ctx, upload.BucketName, upload.ServerKey, upload.StorageUploadId, number, body, size)
+	if errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		return nil, ErrUploadClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+	part := &drive_entity.UploadPartEntity{
+		UploadId:   upload.Id,
+		PartNumber: number,
+		Size:       size,
+		ETag:       etag,
+	}
+	if err := u.uploadWriteRepo.SavePart(part); err != nil {
+		return nil, err
+	}
+	return part, nil
+}
+
+// CompleteUpload joins the parts of an upload into the file and records it in the drive. Completing a
+// completed upload returns its file again. When the quota was used up meanwhile the upload stays open,
+// so it can be completed once storage is freed.
+func (u *UploadUseCase) CompleteUpload(ctx context.Context, userId int64, id int64) (*drive_entity.FileItemEntity, error) {
+	upload, err := u.findUpload(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status == drive_entity.UploadStatusCompleted {
+		return u.uploadedFile(upload)
+	}
+	if !isOpen(upload) {
+		return nil, ErrUploadClosed
+	}
+	if missing := missingParts(upload); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %v", ErrUploadIncomplete, missing)
+	}
+	var parentId int64
+	if upload.ParentId != "" {
+		parentId, _ = strconv.ParseInt(upload.ParentId, 10, 64)
+	}
+	folder, err := findFolder(u.fileItemReadRepo, userId, parentId)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkName(userId, parentId, upload.Name); err != nil {
+		return nil, err
+	}
+
+	kb := sizeKb(upload.Size)
+	if err := u.charge(ctx, userId, upload.ReservationId, kb, ActionStorageUpload); err != nil {
+		return nil, err
+	}
+	parts := make([]object_storage_inter.Part, 0, len(upload.Parts))
+	for _, part := range upload.Parts {
+		parts = append(parts, object_storage_inter.Part{Number: part.PartNumber, ETag: part.ETag})
+	}
+	err = u.storage.CompleteMultipart(ctx, upload.BucketName, upload.ServerKey, upload.StorageUploadId, parts)
+	if err != nil {
+		u.free(ctx, userId, kb)
+		if errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+			// Completed by a concurrent request, or dropped by the store
+			return u.settled(userId, id)
+		}
+		return nil, err
+	}
+
+	item := newItem(userId, folder, upload.Name, upload.MimeType, upload.Size, upload.BucketName, upload.ServerKey)
+	err = u.uploadWriteRepo.Complete(id, item)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// A concurrent request recorded the same object, only the storage paid twice is given back
+		u.free(ctx, userId, kb)
+		return u.settled(userId, id)
+	}
+	if err != nil {
+		// The parts are gone once joined, the upload cannot be completed again
+		u.discard(ctx, userId, upload.ServerKey, kb)
+		if err := u.uploadWriteRepo.End(id, drive_entity.UploadStatusAborted); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			u.l.Error("drive_use_case - UploadUseCase - CompleteUpload - upload %d: %v", id, err)
+		}
+		if errors.Is(err, repositories.ErrConflict) {
+			return nil, ErrNameConflict
+		}
+		return nil, err
+	}
+	queueImage(ctx, u.eventPublisher, u.l, item)
+	return item, nil
+}
+
+// AbortUpload drops an open upload with its parts and gives its reserved storage back
+func (u *UploadUseCase) AbortUpload(ctx context.Context, userId int64, id int64) error {
+	upload, err := u.findUpload(userId, id)
+	if err != nil {
+		return err
+	}
+	if upload.Status != drive_entity.UploadStatusUploading {
+		return ErrUploadClosed
+	}
+	return u.end(ctx, upload, drive_entity.UploadStatusAborted)
+}
+
+// ExpireUploads drops the uploads left open past their expiry, so their parts stop taking space in the
+// object store. It returns how many expired.
+func (u *UploadUseCase) ExpireUploads(ctx context.Context, now time.Time) (int, error) {
+	uploads, err := u.uploadReadRepo.FindExpired(now, UploadBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for i := range uploads {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := u.end(ctx, &uploads[i], drive_entity.UploadStatusExpired); err != nil {
+			if !errors.Is(err, ErrUploadClosed) {
+				u.l.Error("drive_use_case - UploadUseCase - ExpireUploads - upload %s: %v", uploads[i].Id, err)
+			}
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// end closes an open upload with status, then drops its parts and reservation
+func (u *UploadUseCase) end(ctx context.Context, upload *drive_entity.UploadEntity, status string) error {
+	id, _ := strconv.ParseInt(upload.Id, 10, 64)
+	err := u.uploadWriteRepo.End(id, status)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrUploadClosed
+	}
+	if err != nil {
+		return err
+	}
+	err = u.storage.AbortMultipart(ctx, upload.BucketName, upload.ServerKey, upload.StorageUploadId)
+	if err != nil && !errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		u.l.Error("drive_use_case - UploadUseCase - end - upload %s: %v", upload.Id, err)
+	}
+	u.release(ctx, upload.ReservationId)
+	return nil
+}
+
+// checkNew checks a new file can be stored under name in the user's folder parentId
+func (u *UploadUseCase) checkNew(userId int64, parentId int64, name string, size int64) (string, *drive_entity.FileItemEntity, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", nil, err
+	}
+	if size < 0 || size > u.maxFileSize {
+		return "", nil, ErrFileTooLarge
+	}
+	folder, err := findFolder(u.fileItemReadRepo, userId, parentId)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := u.checkName(userId, parentId, name); err != nil {
+		return "", nil, err
+	}
+	return name, folder, nil
+}
+
+func (u *UploadUseCase) checkName(userId int64, parentId int64, name string) error {
+	taken, err := u.fileItemReadRepo.NameTaken(userId, parentId, name)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrNameConflict
+	}
+	return nil
+}
+
+// discard deletes a stored object that was not recorded and gives back the kb charged for it
+func (u *UploadUseCase) discard(ctx context.Context, userId int64, key string, kb int64) {
+	if err := u.storage.Delete(ctx, u.bucket, key); err != nil {
+		u.l.Error("drive_use_case - UploadUseCase - discard - %s: %v", key, err)
+	}
+	u.free(ctx, userId, kb)
+}
+
+func (u *UploadUseCase) findUpload(userId int64, id int64) (*drive_entity.UploadEntity, error) {
+	upload, err := u.uploadReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if upload.UserId != strconv.FormatInt(userId, 10) {
+		return nil, ErrUploadNotFound
+	}
+	return upload, nil
+}
+
+// settled returns the file of an upload another request completed, ErrUploadClosed when it was not
+func (u *UploadUseCase) settled(userId int64, id int64) (*drive_entity.FileItemEntity, error) {
+	upload, err := u.findUpload(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status != drive_entity.UploadStatusCompleted {
+		return nil, ErrUploadClosed
+	}
+	return u.uploadedFile(upload)
+}
+
+func (u *UploadUseCase) uploadedFile(upload *drive_entity.UploadEntity) (*drive_entity.FileItemEntity, error) {
+	userId, _ := strconv.ParseInt(upload.UserId, 10, 64)
+	fileItemId, _ := strconv.ParseInt(upload.FileItemId, 10, 64)
+	return findItem(u.fileItemReadRepo, userId, fileItemId)
+}
+
+// partSizeOf raises the part size for files that would take more parts than object stores accept
+func (u *UploadUseCase) partSizeOf(size int64) int64 {
+	if least := (size + _maxParts - 1) / _maxParts; least > u.partSize {
+		return least
+	}
+	return u.partSize
+}
+
+func isOpen(upload *drive_entity.UploadEntity) bool {
+	return upload.Status == drive_entity.UploadStatusUploading && time.Now().Before(upload.ExpiresAt)
+}
+
+func partCount(upload *drive_entity.UploadEntity) int {
+	return int((upload.Size + upload.PartSize - 1) / upload.PartSize)
+}
+
+func partLength(upload *drive_entity.UploadEntity, number int) int64 {
+	if number < partCount(upload) {
+		return upload.PartSize
+	}
+	return upload.Size - int64(number-1)*upload.PartSize
+}
+
+func missingParts(upload *drive_entity.UploadEntity) []int {
+	received := make(map[int]bool, len(upload.Parts))
+	for _, part := range upload.Parts {
+		received[part.PartNumber] = true
+	}
+	missing := []int{}
+	for number := 1; number <= partCount(upload); number++ {
+		if !received[number] {
+			missing = append(missing, number)
+		}
+	}
+	return missing
+}
+
+func uploadDto(upload *drive_entity.UploadEntity) *file_dto.UploadDto {
+	return &file_dto.UploadDto{
+		UploadEntity: *upload,
+		PartCount:    partCount(upload),
+		MissingParts: missingParts(upload),
+	}
+}
+
+func newItem(userId int64, folder *drive_entity.FileItemEntity, name string, mimeType string, size int64, bucket string, key string) *drive_entity.FileItemEntity {
+	item := &drive_entity.FileItemEntity{
+		Name:       name,
+		BucketName: bucket,
+		ServerKey:  key,
+		FilePath:   itemPath(folder, name),
+		Size:       size,
+		MimeType:   mimeType,
+		Permission: drive_entity.FilePermissionPrivate,
+		UserId:     strconv.FormatInt(userId, 10),
+	}
+	if folder != nil {
+		item.ParentId = folder.Id
+	}
+	if isImage(mimeType) {
+		item.ImageStatus = drive_entity.ImageStatusPending
+	}
+	return item
+}
+
+// sizeKb is the storage a file of size bytes takes from the quota, in KB rounded up
+func sizeKb(size int64) int64 {
+	return (size + 1023) / 1024
+}
+
+// serverKey is a new key of a file of the user in the object store. The name of the file is not part of
+// it, so renaming and moving never touch the object.
+func serverKey(userId int64, name string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	// Only plain extensions are kept, for the store to serve the object with a sensible type
+	ext := strings.ToLower(path.Ext(name))
+	if len(ext) > 10 || strings.IndexFunc(strings.TrimPrefix(ext, "."), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) >= 0 {
+		ext = ""
+	}
+	return fmt.Sprintf("users/%d/%s/%s%s", userId, time.Now().UTC().Format("2006/01"), hex.EncodeToString(buf), ext), nil
+}
+
+// fileMimeType is the declared type of a file, or the type of its extension
+func fileMimeType(name string, declared string) string {
+	if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+		return mediaType
+	}
+	if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+		if mediaType, _, err := mime.ParseMediaType(byExt); err == nil {
+			return mediaType
+		}
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,506 @@
+package drive_use_case
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"site_builder_backend/internal/application/dto/drive/file_dto"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/cache/credit_meter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	testUserId  = 5
+	testQuotaKb = 10
+)
+
+// store keeps the user's storage, the meter and the drive in memory, as Redis, the repositories and
+// the object store would
+type store struct {
+	mu           sync.Mutex
+	usedKb       int64
+	counter      *int64
+	reservations map[string]credit_meter_inter.Reservation
+	usages       []user_entity.CreditUsageEntity
+	items        []drive_entity.FileItemEntity
+	uploads      map[int64]*drive_entity.UploadEntity
+	objects      map[string][]byte
+	parts        map[string]map[int][]byte
+	putErr       error
+	createErr    error
+}
+
+func newStore() *store {
+	return &store{
+		reservations: make(map[string]credit_meter_inter.Reservation),
+		uploads:      make(map[int64]*drive_entity.UploadEntity),
+		objects:      make(map[string][]byte),
+		parts:        make(map[string]map[int][]byte),
+	}
+}
+
+// left is what the user can still reserve, the counter once it is loaded
+func (s *store) left() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counter == nil {
+		return testQuotaKb - s.usedKb
+	}
+	return *s.counter
+}
+
+// meter is a CreditMeter of one counter, the user's storage
+type meter struct{ s *store }
+
+func (m meter) Load(ctx context.Context, userId int64, creditType string, available int64, ttl time.Duration) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	if m.s.counter != nil {
+		return nil
+	}
+	for _, reservation := range m.s.reservations {
+		available -= reservation.Amount
+	}
+	m.s.counter = &available
+	return nil
+}
+
+func (m meter) Reserve(ctx context.Context, reservation credit_meter_inter.Reservation, ttl time.Duration) (bool, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	if m.s.counter == nil {
+		return false, credit_meter_inter.ErrNotLoaded
+	}
+	if *m.s.counter < reservation.Amount {
+		return false, nil
+	}
+	*m.s.counter -= reservation.Amount
+	m.s.reservations[reservation.Id] = reservation
+	return true, nil
+}
+
+func (m meter) Take(ctx context.Context, id string) (*credit_meter_inter.Reservation, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	reservation, ok := m.s.reservations[id]
+	if !ok {
+		return nil, credit_meter_inter.ErrReservationNotFound
+	}
+	delete(m.s.reservations, id)
+	return &reservation, nil
+}
+
+func (m meter) Restore(ctx context.Context, reservation credit_meter_inter.Reservation, ttl time.Duration) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	m.s.reservations[reservation.Id] = reservation
+	return nil
+}
+
+func (m meter) Settle(ctx context.Context, reservation credit_meter_inter.Reservation) error {
+	return nil
+}
+
+func (m meter) Release(ctx context.Context, id string) (*credit_meter_inter.Reservation, error) {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	reservation, ok := m.s.reservations[id]
+	if !ok {
+		return nil, credit_meter_inter.ErrReservationNotFound
+	}
+	delete(m.s.reservations, id)
+	if m.s.counter != nil {
+		*m.s.counter += reservation.Amount
+	}
+	return &reservation, nil
+}
+
+func (m meter) Credit(ctx context.Context, userId int64, creditType string, amount int64) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	if m.s.counter != nil {
+		*m.s.counter += amount
+	}
+	return nil
+}
+
+func (m meter) Invalidate(ctx context.Context, userId int64, creditTypes []string) error {
+	m.s.mu.Lock()
+	defer m.s.mu.Unlock()
+	m.s.counter = nil
+	return nil
+}
+
+type meteringReadRepo struct {
+	user_repo_inter.MeteringReadRepository
+	s *store
+}
+
+func (r meteringReadRepo) Available(userId int64, creditType string, now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return testQuotaKb - r.s.usedKb, nil
+}
+
+type meteringWriteRepo struct{ s *store }
+
+func (r meteringWriteRepo) Consume(usage *user_entity.CreditUsageEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.usages = append(r.s.usages, *usage)
+	r.s.usedKb = max(r.s.usedKb+usage.Amount, 0)
+	return nil
+}
+
+type fileItemReadRepo struct {
+	drive_repo_inter.FileItemReadRepository
+	s *store
+}
+
+func (r fileItemReadRepo) NameTaken(userId int64, parentId int64, name string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, item := range r.s.items {
+		if item.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fileItemWriteRepo struct {
+	drive_repo_inter.FileItemWriteRepository
+	s *store
+}
+
+func (r fileItemWriteRepo) Create(item *drive_entity.FileItemEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.s.createErr != nil {
+		return r.s.createErr
+	}
+	item.Id = strconv.Itoa(len(r.s.items) + 1)
+	r.s.items = append(r.s.items, *item)
+	return nil
+}
+
+type uploadReadRepo struct {
+	drive_repo_inter.UploadReadRepository
+	s *store
+}
+
+func (r uploadReadRepo) FindById(id int64) (*drive_entity.UploadEntity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	upload, ok := r.s.uploads[id]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	copied := *upload
+	copied.Parts = append([]drive_entity.UploadPartEntity(nil), upload.Parts...)
+	return &copied, nil
+}
+
+type uploadWriteRepo struct{ s *store }
+
+func (r uploadWriteRepo) Create(upload *drive_entity.UploadEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	id := int64(len(r.s.uploads) + 1)
+	upload.Id = strconv.FormatInt(id, 10)
+	copied := *upload
+	r.s.uploads[id] = &copied
+	return nil
+}
+
+func (r uploadWriteRepo) SavePart(part *drive_entity.UploadPartEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	id, _ := strconv.ParseInt(part.UploadId, 10, 64)
+	upload := r.s.uploads[id]
+	upload.Parts = append(upload.Parts, *part)
+	return nil
+}
+
+func (r uploadWriteRepo) Complete(id int64, item *drive_entity.FileItemEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	upload := r.s.uploads[id]
+	if upload.Status != drive_entity.UploadStatusUploading {
+		return repositories.ErrNotFound
+	}
+	item.Id = strconv.Itoa(len(r.s.items) + 1)
+	r.s.items = append(r.s.items, *item)
+	upload.Status = drive_entity.UploadStatusCompleted
+	upload.FileItemId = item.Id
+	return nil
+}
+
+func (r uploadWriteRepo) End(id int64, status string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	upload, ok := r.s.uploads[id]
+	if !ok || upload.Status != drive_entity.UploadStatusUploading {
+		return repositories.ErrNotFound
+	}
+	upload.Status = status
+	return nil
+}
+
+type objectStorage struct {
+	object_storage_inter.ObjectStorage
+	s *store
+}
+
+func (o objectStorage) Put(ctx context.Context, bucket string, key string, body io.Reader, size int64, contentType string) error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	if o.s.putErr != nil {
+		return o.s.putErr
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	o.s.objects[key] = content
+	return nil
+}
+
+func (o objectStorage) Delete(ctx context.Context, bucket string, key string) error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	delete(o.s.objects, key)
+	return nil
+}
+
+func (o objectStorage) CreateMultipart(ctx context.Context, bucket string, key string, contentType string) (string, error) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	uploadId := "upload-" + key
+	o.s.parts[uploadId] = make(map[int][]byte)
+	return uploadId, nil
+}
+
+func (o objectStorage) UploadPart(ctx context.Context, bucket string, key string, uploadId string, number int, body io.Reader, size int64) (string, error) {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	parts, ok := o.s.parts[uploadId]
+	if !ok {
+		return "", object_storage_inter.ErrObjectNotFound
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	parts[number] = content
+	return `"` + strconv.Itoa(number) + `"`, nil
+}
+
+func (o objectStorage) CompleteMultipart(ctx context.Context, bucket string, key string, uploadId string, parts []object_storage_inter.Part) error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	stored, ok := o.s.parts[uploadId]
+	if !ok {
+		return object_storage_inter.ErrObjectNotFound
+	}
+	var content bytes.Buffer
+	for _, part := range parts {
+		content.Write(stored[part.Number])
+	}
+	o.s.objects[key] = content.Bytes()
+	delete(o.s.parts, uploadId)
+	return nil
+}
+
+func (o objectStorage) AbortMultipart(ctx context.Context, bucket string, key string, uploadId string) error {
+	o.s.mu.Lock()
+	defer o.s.mu.Unlock()
+	if _, ok := o.s.parts[uploadId]; !ok {
+		return object_storage_inter.ErrObjectNotFound
+	}
+	delete(o.s.parts, uploadId)
+	return nil
+}
+
+type eventPublisher struct{}
+
+func (eventPublisher) Publish(ctx context.Context, exchange string, routingKey string, payload interface{}) error {
+	return nil
+}
+
+func newTestUploadUseCase(s *store) *UploadUseCase {
+	l := logger.NewLoggerFromConfig("error", "json", "stdout")
+	metering := user_use_case.NewMeteringUseCase(meter{s}, meteringReadRepo{s: s}, meteringWriteRepo{s}, time.Minute, time.Hour, l)
+	return NewUploadUseCase(fileItemReadRepo{s: s}, fileItemWriteRepo{s: s}, uploadReadRepo{s: s}, uploadWriteRepo{s}, objectStorage{s: s}, metering, eventPublisher{}, "drive", 0, 1<<30, time.Hour, l)
+}
+
+// assertSettled checks nothing is held aside anymore and usedKb was charged in total
+func assertSettled(t *testing.T, s *store, usedKb int64) {
+	t.Helper()
+	s.mu.Lock()
+	reservations, used := len(s.reservations), s.usedKb
+	s.mu.Unlock()
+	if reservations != 0 {
+		t.Fatalf("%d reservations left open", reservations)
+	}
+	if used != usedKb {
+		t.Fatalf("used %d KB, want %d", used, usedKb)
+	}
+	if left := s.left(); left != testQuotaKb-usedKb {
+		t.Fatalf("counter = %d KB, want %d", left, testQuotaKb-usedKb)
+	}
+}
+
+func TestUploadChargesTheReservation(t *testing.T) {
+	s := newStore()
+	u := newTestUploadUseCase(s)
+
+	body := strings.Repeat("x", 3000)
+	item, err := u.Upload(context.Background(), testUserId, file_dto.UploadFileDto{}, "notes.txt", strings.NewReader(body), int64(len(body)), "text/plain")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if item.Name != "notes.txt" || item.Size != 3000 {
+		t.Fatalf("item = %+v", item)
+	}
+	if len(s.usages) != 1 || s.usages[0].Amount != 3 || s.usages[0].Action != ActionStorageUpload {
+		t.Fatalf("usages = %+v, want one of 3 KB", s.usages)
+	}
+	assertSettled(t, s, 3)
+}
+
+func TestUploadOverQuotaStoresNothing(t *testing.T) {
+	s := newStore()
+	u := newTestUploadUseCase(s)
+
+	body := strings.Repeat("x", (testQuotaKb+1)*1024)
+	_, err := u.Upload(context.Background(), testUserId, file_dto.UploadFileDto{}, "big.bin", strings.NewReader(body), int64(len(body)), "")
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrQuotaExceeded", err)
+	}
+	if len(s.objects) != 0 || len(s.items) != 0 {
+		t.Fatalf("stored %d objects and %d items, want none", len(s.objects), len(s.items))
+	}
+	assertSettled(t, s, 0)
+}
+
+func TestUploadReleasesTheReservationWhenTheStoreFails(t *testing.T) {
+	s := newStore()
+	s.putErr = errors.New("store unavailable")
+	u := newTestUploadUseCase(s)
+
+	body := strings.Repeat("x", 4096)
+	if _, err := u.Upload(context.Background(), testUserId, file_dto.UploadFileDto{}, "a.txt", strings.NewReader(body), int64(len(body)), ""); err == nil {
+		t.Fatal("Upload: want the store's error")
+	}
+	if len(s.usages) != 0 {
+		t.Fatalf("usages = %+v, want none", s.usages)
+	}
+	assertSettled(t, s, 0)
+}
+
+func TestUploadGivesTheStorageBackWhenTheItemIsNotRecorded(t *testing.T) {
+	s := newStore()
+	s.createErr = repositories.ErrConflict
+	u := newTestUploadUseCase(s)
+
+	body := strings.Repeat("x", 2048)
+	_, err := u.Upload(context.Background(), testUserId, file_dto.UploadFileDto{}, "a.txt", strings.NewReader(body), int64(len(body)), "")
+	if !errors.Is(err, ErrNameConflict) {
+		t.Fatalf("err = %v, want ErrNameConflict", err)
+	}
+	if len(s.objects) != 0 {
+		t.Fatalf("%d objects kept, want the stored one deleted", len(s.objects))
+	}
+	if len(s.usages) != 2 || s.usages[1].Amount != -2 || s.usages[1].Action != user_use_case.ActionStorageFree {
+		t.Fatalf("usages = %+v, want the charge and its refund", s.usages)
+	}
+	assertSettled(t, s, 0)
+}
+
+func TestMultipartUploadReservesThenCharges(t *testing.T) {
+	s := newStore()
+	u := newTestUploadUseCase(s)
+	ctx := context.Background()
+
+	body := strings.Repeat("y", 5000)
+	upload, err := u.StartUpload(ctx, testUserId, file_dto.StartUploadDto{Name: "video.mp4", Size: int64(len(body))})
+	if err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+	uploadId, _ := strconv.ParseInt(upload.Id, 10, 64)
+	if left := s.left(); left != testQuotaKb-5 {
+		t.Fatalf("counter = %d KB after starting, want %d reserved", left, 5)
+	}
+	if len(s.usages) != 0 {
+		t.Fatalf("charged before completion: %+v", s.usages)
+	}
+
+	if _, err := u.UploadPart(ctx, testUserId, uploadId, 1, strings.NewReader(body), int64(len(body))); err != nil {
+		t.Fatalf("UploadPart: %v", err)
+	}
+	item, err := u.CompleteUpload(ctx, testUserId, uploadId)
+	if err != nil {
+		t.Fatalf("CompleteUpload: %v", err)
+	}
+	if string(s.objects[item.ServerKey]) != body {
+		t.Fatal("stored object differs from the parts sent")
+	}
+	if len(s.usages) != 1 || s.usages[0].Amount != 5 {
+		t.Fatalf("usages = %+v, want one of 5 KB", s.usages)
+	}
+	assertSettled(t, s, 5)
+}
+
+func TestMultipartAbortReleasesTheReservation(t *testing.T) {
+	s := newStore()
+	u := newTestUploadUseCase(s)
+	ctx := context.Background()
+
+	upload, err := u.StartUpload(ctx, testUserId, file_dto.StartUploadDto{Name: "video.mp4", Size: 8000})
+	if err != nil {
+		t.Fatalf("StartUpload: %v", err)
+	}
+	uploadId, _ := strconv.ParseInt(upload.Id, 10, 64)
+	if err := u.AbortUpload(ctx, testUserId, uploadId); err != nil {
+		t.Fatalf("AbortUpload: %v", err)
+	}
+	if len(s.parts) != 0 {
+		t.Fatalf("%d multipart uploads left in the store", len(s.parts))
+	}
+	if err := u.AbortUpload(ctx, testUserId, uploadId); !errors.Is(err, ErrUploadClosed) {
+		t.Fatalf("second AbortUpload: %v, want ErrUploadClosed", err)
+	}
+	if len(s.usages) != 0 {
+		t.Fatalf("usages = %+v, want none", s.usages)
+	}
+	assertSettled(t, s, 0)
+}
+
+func TestMultipartStartOverQuotaOpensNothing(t *testing.T) {
+	s := newStore()
+	u := newTestUploadUseCase(s)
+
+	_, err := u.StartUpload(context.Background(), testUserId, file_dto.StartUploadDto{Name: "big.bin", Size: (testQuotaKb + 1) * 1024})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrQuotaExceeded", err)
+	}
+	if len(s.uploads) != 0 || len(s.parts) != 0 {
+		t.Fatalf("opened %d uploads, want none", len(s.uploads))
+	}
+	assertSettled(t, s, 0)
+}
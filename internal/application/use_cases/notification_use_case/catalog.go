@@ -0,0 +1,147 @@
+package notification_use_case
+
+import "site_builder_backend/internal/domain/site_entity"
+
+// Notification events
+const (
+	EventOrderPlaced    = "order_placed"
+	EventOrderShipped   = "order_shipped"
+	EventOrderDelivered = "order_delivered"
+	EventOtp            = "otp"
+)
+
+// Variable types. A number is formatted with the digits and separators of the locale, text is used as is.
+const (
+	VariableText   = "text"
+	VariableNumber = "number"
+)
+
+// Variable is a value a template may use as {{Name}}. Fallback replaces it per locale when the value is
+// missing or not of its type, so a message never goes out with a placeholder in it.
+type Variable struct {
+	Name     string
+	Type     string
+	Example  string
+	Fallback map[string]string
+}
+
+// Text is the default text of an event on a channel in a locale
+type Text struct {
+	Subject string
+	Body    string
+}
+
+type Event struct {
+	Name      string
+	Variables []Variable
+	Texts     map[string]map[string]Text
+}
+
+var (
+	varSiteName = Variable{Name: "SiteName", Type: VariableText, Example: "Digi Shop",
+		Fallback: map[string]string{site_entity.LocaleFa: "فروشگاه", site_entity.LocaleEn: "our store"}}
+	varCustomerName = Variable{Name: "CustomerName", Type: VariableText, Example: "Sara Ahmadi",
+		Fallback: map[string]string{site_entity.LocaleFa: "مشتری گرامی", site_entity.LocaleEn: "customer"}}
+	varOrderNumber = Variable{Name: "OrderNumber", Type: VariableText, Example: "100245",
+		Fallback: map[string]string{site_entity.LocaleFa: "-", site_entity.LocaleEn: "-"}}
+	varTotalPrice = Variable{Name: "TotalPrice", Type: VariableNumber, Example: "1250000",
+		Fallback: map[string]string{site_entity.LocaleFa: "-", site_entity.LocaleEn: "-"}}
+	varTrackingCode = Variable{Name: "TrackingCode", Type: VariableText, Example: "IR123456789",
+		Fallback: map[string]string{site_entity.LocaleFa: "به‌زودی اعلام می‌شود", site_entity.LocaleEn: "to be announced"}}
+	varOtpCode = Variable{Name: "OtpCode", Type: VariableText, Example: "48213",
+		Fallback: map[string]string{site_entity.LocaleFa: "-", site_entity.LocaleEn: "-"}}
+	varExpiresInMinutes = Variable{Name: "ExpiresInMinutes", Type: VariableNumber, Example: "2",
+		Fallback: map[string]string{site_entity.LocaleFa: "چند", site_entity.LocaleEn: "a few"}}
+)
+
+// Catalog lists the events owners can customize, in the order they are shown
+var Catalog = []Event{
+	{
+		Name:      EventOrderPlaced,
+		Variables: []Variable{varSiteName, varCustomerName, varOrderNumber, varTotalPrice},
+		Texts: map[string]map[string]Text{
+			site_entity.NotificationChannelSms: {
+				site_entity.LocaleFa: {Body: "{{CustomerName}} عزیز، سفارش {{OrderNumber}} به مبلغ {{TotalPrice}} تومان در {{SiteName}} ثبت شد."},
+				site_entity.LocaleEn: {Body: "Dear {{CustomerName}}, your order {{OrderNumber}} of {{TotalPrice}} at {{SiteName}} has been placed."},
+			},
+			site_entity.NotificationChannelEmail: {
+				site_entity.LocaleFa: {Subject: "سفارش {{OrderNumber}} ثبت شد",
+					Body: "{{CustomerName}} عزیز،\nسفارش شما به شماره {{OrderNumber}} و مبلغ {{TotalPrice}} تومان ثبت شد.\nاز خرید شما از {{SiteName}} سپاسگزاریم."},
+				site_entity.LocaleEn: {Subject: "Your order {{OrderNumber}} has been placed",
+					Body: "Dear {{CustomerName}},\nyour order {{OrderNumber}} of {{TotalPrice}} has been placed.\nThank you for shopping at {{SiteName}}."},
+			},
+		},
+	},
+	{
+		Name:      EventOrderShipped,
+		Variables: []Variable{varSiteName, varCustomerName, varOrderNumber, varTrackingCode},
+		Texts: map[string]map[string]Text{
+			site_entity.NotificationChannelSms: {
+				site_entity.LocaleFa: {Body: "{{CustomerName}} عزیز، سفارش {{OrderNumber}} ارسال شد. کد رهگیری: {{TrackingCode}}\n{{SiteName}}"},
+				site_entity.LocaleEn: {Body: "Dear {{CustomerName}}, your order {{OrderNumber}} has been shipped. Tracking code: {{TrackingCode}}\n{{SiteName}}"},
+			},
+			site_entity.NotificationChannelEmail: {
+				site_entity.LocaleFa: {Subject: "سفارش {{OrderNumber}} ارسال شد",
+					Body: "{{CustomerName}} عزیز،\nسفارش {{OrderNumber}} ارسال شد.\nکد رهگیری مرسوله: {{TrackingCode}}\n{{SiteName}}"},
+				site_entity.LocaleEn: {Subject: "Your order {{OrderNumber}} has been shipped",
+					Body: "Dear {{CustomerName}},\nyour order {{OrderNumber}} has been shipped.\nTracking code: {{TrackingCode}}\n{{SiteName}}"},
+			},
+		},
+	},
+	{
+		Name:      EventOrderDelivered,
+		Variables: []Variable{varSiteName, varCustomerName, varOrderNumber},
+		Texts: map[string]map[string]Text{
+			site_entity.NotificationChannelSms: {
+				site_entity.LocaleFa: {Body: "{{CustomerName}} عزیز، سفارش {{OrderNumber}} تحویل داده شد. از خرید شما از {{SiteName}} سپاسگزاریم."},
+				site_entity.LocaleEn: {Body: "Dear {{CustomerName}}, your order {{OrderNumber}} has been delivered. Thank you for shopping at {{SiteName}}."},
+			},
+			site_entity.NotificationChannelEmail: {
+				site_entity.LocaleFa: {Subject: "سفارش {{OrderNumber}} تحویل داده شد",
+					Body: "{{CustomerName}} عزیز،\nسفارش {{OrderNumber}} تحویل داده شد.\nاز خرید شما از {{SiteName}} سپاسگزاریم."},
+				site_entity.LocaleEn: {Subject: "Your order {{OrderNumber}} has been delivered",
+					Body: "Dear {{CustomerName}},\nyour order {{OrderNumber}} has been delivered.\nThank you for shopping at {{SiteName}}."},
+			},
+		},
+	},
+	{
+		Name:      EventOtp,
+		Variables: []Variable{varSiteName, varOtpCode, varExpiresInMinutes},
+		Texts: map[string]map[string]Text{
+			site_entity.NotificationChannelSms: {
+				site_entity.LocaleFa: {Body: "کد تایید شما در {{SiteName}}: {{OtpCode}}\nاین کد تا {{ExpiresInMinutes}} دقیقه معتبر است."},
+				site_entity.LocaleEn: {Body: "Your {{SiteName}} verification code: {{OtpCode}}\nIt expires in {{ExpiresInMinutes}} minutes."},
+			},
+			site_entity.NotificationChannelEmail: {
+				site_entity.LocaleFa: {Subject: "کد تایید {{SiteName}}",
+					Body: "کد تایید شما: {{OtpCode}}\nاین کد تا {{ExpiresInMinutes}} دقیقه معتبر است."},
+				site_entity.LocaleEn: {Subject: "Your {{SiteName}} verification code",
+					Body: "Your verification code: {{OtpCode}}\nIt expires in {{ExpiresInMinutes}} minutes."},
+			},
+		},
+	},
+}
+
+// Locales are the locales every event has a default text in
+var Locales = []string{site_entity.LocaleFa, site_entity.LocaleEn}
+
+// Channels are the channels every event has a default text on
+var Channels = []string{site_entity.NotificationChannelSms, site_entity.NotificationChannelEmail}
+
+func findEvent(name string) (*Event, bool) {
+	for i := range Catalog {
+		if Catalog[i].Name == name {
+			return &Catalog[i], true
+		}
+	}
+	return nil, false
+}
+
+func (e *Event) variable(name string) (Variable, bool) {
+	for _, v := range e.Variables {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variable{}, false
+}
@@ -0,0 +1,231 @@
+package notification_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/notification/inbox_dto"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/cache/notification_hub_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var ErrNotificationInvalid = errors.New("notification needs a user, a type, a title and an event key")
+
+type InboxUseCase struct {
+	notificationReadRepo   user_repo_inter.NotificationReadRepository
+	notificationWriteRepo  user_repo_inter.NotificationWriteRepository
+	siteReadRepo           site_repo_inter.SiteReadRepository
+	orderReadRepo          order_repo_inter.OrderReadRepository
+	productVariantReadRepo product_repo_inter.ProductVariantReadRepository
+	hub                    notification_hub_inter.NotificationHub
+	lowStockThreshold      int
+	l                      *logger.ZapLogger
+}
+
+// NewInboxUseCase notifies owners of the variants whose stock an order takes down to lowStockThreshold
+func NewInboxUseCase(notificationReadRepo user_repo_inter.NotificationReadRepository, notificationWriteRepo user_repo_inter.NotificationWriteRepository, siteReadRepo site_repo_inter.SiteReadRepository, orderReadRepo order_repo_inter.OrderReadRepository, productVariantReadRepo product_repo_inter.ProductVariantReadRepository, hub notification_hub_inter.NotificationHub, lowStockThreshold int, l *logger.ZapLogger) *InboxUseCase {
+	return &InboxUseCase{
+		notificationReadRepo:   notificationReadRepo,
+		notificationWriteRepo:  notificationWriteRepo,
+		siteReadRepo:           siteReadRepo,
+		orderReadRepo:          orderReadRepo,
+		productVariantReadRepo: productVariantReadRepo,
+		hub:                    hub,
+		lowStockThreshold:      lowStockThreshold,
+		l:                      l,
+	}
+}
+
+// Notify stores a notification in the user's inbox and pushes it to their open dashboards. A
+// notification already made for the event key is skipped, so redelivered events are notified once.
+func (u *InboxUseCase) Notify(ctx context.Context, dto inbox_dto.NotifyDto) error {
+	if dto.UserId == 0 || dto.Type == "" || dto.Title == "" || dto.EventKey == "" {
+		return ErrNotificationInvalid
+	}
+	notification := &user_entity.NotificationEntity{
+		UserId:        strconv.FormatInt(dto.UserId, 10),
+		SiteId:        strconv.FormatInt(dto.SiteId, 10),
+		Type:          dto.Type,
+		Title:         dto.Title,
+		Body:          dto.Body,
+		ReferenceType: dto.ReferenceType,
+		EventKey:      dto.EventKey,
+	}
+	if dto.ReferenceId != 0 {
+		notification.ReferenceId = strconv.FormatInt(dto.ReferenceId, 10)
+	}
+	err := u.notificationWriteRepo.Create(notification)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The notification is stored, a dashboard that misses the push sees it on its next load
+	u.push(ctx, dto.UserId, notification_hub_inter.Message{Kind: notification_hub_inter.KindNotification, Notification: notification})
+	return nil
+}
+
+// Notifications lists the user's inbox, newest first
+func (u *InboxUseCase) Notifications(ctx context.Context, userId int64, dto inbox_dto.NotificationFilterDto) (*common_dto.PaginatedDto[user_entity.NotificationEntity], error) {
+	notifications, total, err := u.notificationReadRepo.FindNotifications(user_repo_inter.NotificationFilter{
+		UserId: userId,
+		Unread: dto.Unread,
+		Offset: dto.Offset(),
+		Limit:  dto.Limit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := common_dto.NewPaginatedDto(notifications, total, dto.PaginationDto)
+	return &result, nil
+}
+
+func (u *InboxUseCase) UnreadCount(ctx context.Context, userId int64) (*inbox_dto.UnreadDto, error) {
+	unread, err := u.notificationReadRepo.CountUnread(userId)
+	if err != nil {
+		return nil, err
+	}
+	return &inbox_dto.UnreadDto{Unread: unread}, nil
+}
+
+// MarkRead marks notifications of the user as read and lets the other open dashboards of the user
+// update their badge
+func (u *InboxUseCase) MarkRead(ctx context.Context, userId int64, dto inbox_dto.MarkReadDto) (*inbox_dto.UnreadDto, error) {
+	changed, err := u.notificationWriteRepo.MarkRead(userId, dto.Ids, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	unread, err := u.notificationReadRepo.CountUnread(userId)
+	if err != nil {
+		return nil, err
+	}
+	if changed > 0 {
+		u.publish(ctx, userId, notification_hub_inter.Message{Kind: notification_hub_inter.KindRead, Unread: unread})
+	}
+	return &inbox_dto.UnreadDto{Unread: unread}, nil
+}
+
+// Subscribe returns what is pushed to the user's dashboards until cancel is called
+func (u *InboxUseCase) Subscribe(userId int64) (<-chan notification_hub_inter.Message, func()) {
+	return u.hub.Subscribe(userId)
+}
+
+// OrderPlaced notifies the owner of the site about a new order, and about the variants the order took
+// down to the low stock threshold
+func (u *InboxUseCase) OrderPlaced(ctx context.Context, orderId int64) error {
+	order, err := u.orderReadRepo.FindById(orderId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	siteId, _ := strconv.ParseInt(order.SiteId, 10, 64)
+	ownerId, err := u.siteOwner(siteId)
+	if err != nil || ownerId == 0 {
+		return err
+	}
+
+	err = u.Notify(ctx, inbox_dto.NotifyDto{
+		UserId:        ownerId,
+		SiteId:        siteId,
+		Type:          user_entity.NotificationTypeOrderPlaced,
+		Title:         "New order",
+		Body:          fmt.Sprintf("Order %s of %s was placed.", order.Id, formatNumber(strconv.FormatInt(order.TotalFinalPrice, 10), site_entity.LocaleEn)),
+		ReferenceType: user_entity.NotificationReferenceOrder,
+		ReferenceId:   orderId,
+		EventKey:      "order_placed:" + order.Id,
+	})
+	if err != nil {
+		return err
+	}
+	return u.lowStock(ctx, ownerId, siteId, order.Id, order.OrderItems)
+}
+
+// lowStock notifies about the variants of the items whose stock was above the threshold before the order
+// and is not anymore, so a variant is reported once rather than on every order after
+func (u *InboxUseCase) lowStock(ctx context.Context, ownerId int64, siteId int64, orderId string, items []order_entity.OrderItemEntity) error {
+	if len(items) == 0 {
+		return nil
+	}
+	quantities := make(map[string]int, len(items))
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		if _, ok := quantities[item.ProductVariantId]; !ok {
+			id, _ := strconv.ParseInt(item.ProductVariantId, 10, 64)
+			ids = append(ids, id)
+		}
+		quantities[item.ProductVariantId] += item.Quantity
+	}
+	variants, err := u.productVariantReadRepo.FindByIds(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, variant := range variants {
+		if variant.Stock > u.lowStockThreshold || variant.Stock+quantities[variant.Id] <= u.lowStockThreshold {
+			continue
+		}
+		variantId, _ := strconv.ParseInt(variant.Id, 10, 64)
+		name := variant.Product.Name
+		if variant.Name != "" {
+			name += " - " + variant.Name
+		}
+		err := u.Notify(ctx, inbox_dto.NotifyDto{
+			UserId:        ownerId,
+			SiteId:        siteId,
+			Type:          user_entity.NotificationTypeLowStock,
+			Title:         "Low stock",
+			Body:          fmt.Sprintf("%s has %d left in stock.", name, variant.Stock),
+			ReferenceType: user_entity.NotificationReferenceProductVariant,
+			ReferenceId:   variantId,
+			EventKey:      "low_stock:" + orderId + ":" + variant.Id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *InboxUseCase) siteOwner(siteId int64) (int64, error) {
+	site, err := u.siteReadRepo.FindById(siteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	ownerId, _ := strconv.ParseInt(site.UserId, 10, 64)
+	return ownerId, nil
+}
+
+// push publishes a message with the current unread count
+func (u *InboxUseCase) push(ctx context.Context, userId int64, message notification_hub_inter.Message) {
+	unread, err := u.notificationReadRepo.CountUnread(userId)
+	if err != nil {
+		return
+	}
+	message.Unread = unread
+	u.publish(ctx, userId, message)
+}
+
+func (u *InboxUseCase) publish(ctx context.Context, userId int64, message notification_hub_inter.Message) {
+	if err := u.hub.Publish(ctx, userId, message); err != nil {
+		u.l.Warn("notification_use_case - InboxUseCase - publish - user %d: %v", userId, err)
+	}
+}
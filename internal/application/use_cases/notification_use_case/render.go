@@ -0,0 +1,181 @@
+package notification_use_case
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"site_builder_backend/internal/application/dto/notification/template_dto"
+	"site_builder_backend/internal/domain/site_entity"
+)
+
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z][A-Za-z0-9_]*)\s*\}\}`)
+
+// validate checks every placeholder of text is a variable of the event
+func validate(event *Event, text string) error {
+	for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+		if _, ok := event.variable(match[1]); !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownVariable, match[1])
+		}
+	}
+	if strings.Contains(placeholder.ReplaceAllString(text, ""), "{{") {
+		return ErrMalformedTemplate
+	}
+	return nil
+}
+
+// render fills the texts of an event with data. The Html of an email is generated from its body when
+// empty, values are escaped in it.
+func render(event *Event, channel string, locale string, text Text, htmlBody string, data map[string]interface{}) *template_dto.RenderedDto {
+	r := &renderer{event: event, locale: locale, data: data, missing: map[string]bool{}}
+	rendered := &template_dto.RenderedDto{
+		Subject: r.substitute(text.Subject, false),
+		Body:    r.substitute(text.Body, false),
+	}
+	if channel == site_entity.NotificationChannelEmail {
+		if htmlBody == "" {
+			htmlBody = bodyHtml(text.Body, locale)
+		}
+		rendered.Html = r.substitute(htmlBody, true)
+	}
+	for _, v := range event.Variables {
+		if r.missing[v.Name] {
+			rendered.Missing = append(rendered.Missing, v.Name)
+		}
+	}
+	return rendered
+}
+
+type renderer struct {
+	event   *Event
+	locale  string
+	data    map[string]interface{}
+	missing map[string]bool
+}
+
+func (r *renderer) substitute(text string, escape bool) string {
+	return placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		v, ok := r.event.variable(name)
+		if !ok {
+			// Only a text saved before the variable was dropped from the catalog gets here
+			return ""
+		}
+		value, ok := format(v, r.locale, r.data[name])
+		if !ok {
+			value = v.Fallback[r.locale]
+			r.missing[name] = true
+		}
+		if escape {
+			value = html.EscapeString(value)
+		}
+		return value
+	})
+}
+
+// bodyHtml turns a plain text body into HTML, keeping its line breaks
+func bodyHtml(body string, locale string) string {
+	dir := "ltr"
+	if locale == site_entity.LocaleFa {
+		dir = "rtl"
+	}
+	return `<div dir="` + dir + `">` + strings.ReplaceAll(html.EscapeString(body), "\n", "<br>\n") + "</div>"
+}
+
+// format writes a value the way its variable is shown in the locale, false when it is missing or not
+// of the variable's type
+func format(v Variable, locale string, value interface{}) (string, bool) {
+	if v.Type == VariableNumber {
+		n, ok := number(value)
+		if !ok {
+			return "", false
+		}
+		return formatNumber(n, locale), true
+	}
+	var text string
+	switch value := value.(type) {
+	case string:
+		text = strings.TrimSpace(value)
+	case json.Number:
+		text = value.String()
+	case float64, float32, int, int32, int64, uint, uint32, uint64:
+		n, _ := number(value)
+		text = n
+	default:
+		return "", false
+	}
+	return text, text != ""
+}
+
+// number returns value as a plain decimal number
+func number(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case int:
+		return strconv.FormatInt(int64(value), 10), true
+	case int32:
+		return strconv.FormatInt(int64(value), 10), true
+	case int64:
+		return strconv.FormatInt(value, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(value), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(value), 10), true
+	case uint64:
+		return strconv.FormatUint(value, 10), true
+	case float32:
+		return number(float64(value))
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case json.Number:
+		return number(value.String())
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "", false
+		}
+		return number(f)
+	}
+	return "", false
+}
+
+// formatNumber groups the digits of n by thousands, with Persian digits and separators in fa
+func formatNumber(n string, locale string) string {
+	sign := ""
+	if strings.HasPrefix(n, "-") {
+		sign, n = "-", n[1:]
+	}
+	integer, fraction, _ := strings.Cut(n, ".")
+	thousands, decimal := ",", "."
+	if locale == site_entity.LocaleFa {
+		thousands, decimal = "٬", "٫"
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(decimal)
+		b.WriteString(fraction)
+	}
+	if locale != site_entity.LocaleFa {
+		return b.String()
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '۰' + r - '0'
+		}
+		return r
+	}, b.String())
+}
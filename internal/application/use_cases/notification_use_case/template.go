@@ -0,0 +1,254 @@
+package notification_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"site_builder_backend/internal/application/dto/notification/template_dto"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrSiteAccessDenied  = site_repo_inter.ErrNotOwner
+	ErrUnknownEvent      = errors.New("unknown notification event")
+	ErrUnknownChannel    = errors.New("unknown notification channel")
+	ErrUnknownVariable   = errors.New("unknown template variable")
+	ErrMalformedTemplate = errors.New("template has an unclosed {{ placeholder")
+	ErrSubjectRequired   = errors.New("an email template needs a subject")
+)
+
+type TemplateUseCase struct {
+	siteReadRepo      site_repo_inter.SiteReadRepository
+	templateReadRepo  site_repo_inter.NotificationTemplateReadRepository
+	templateWriteRepo site_repo_inter.NotificationTemplateWriteRepository
+	defaultLocale     string
+	l                 *logger.ZapLogger
+}
+
+// NewTemplateUseCase renders notifications without a locale of their own in defaultLocale
+func NewTemplateUseCase(siteReadRepo site_repo_inter.SiteReadRepository, templateReadRepo site_repo_inter.NotificationTemplateReadRepository, templateWriteRepo site_repo_inter.NotificationTemplateWriteRepository, defaultLocale string, l *logger.ZapLogger) *TemplateUseCase {
+	if !supportedLocale(defaultLocale) {
+		defaultLocale = site_entity.LocaleFa
+	}
+	return &TemplateUseCase{
+		siteReadRepo:      siteReadRepo,
+		templateReadRepo:  templateReadRepo,
+		templateWriteRepo: templateWriteRepo,
+		defaultLocale:     defaultLocale,
+		l:                 l,
+	}
+}
+
+// Catalog lists the events with the variables their templates may use
+func (u *TemplateUseCase) Catalog(ctx context.Context) []template_dto.EventDto {
+	events := make([]template_dto.EventDto, 0, len(Catalog))
+	for _, event := range Catalog {
+		variables := make([]template_dto.VariableDto, 0, len(event.Variables))
+		for _, v := range event.Variables {
+			variables = append(variables, template_dto.VariableDto{Name: v.Name, Type: v.Type, Example: v.Example})
+		}
+		events = append(events, template_dto.EventDto{Event: event.Name, Channels: Channels, Variables: variables})
+	}
+	return events
+}
+
+// Templates lists the text of every event, channel and locale the site sends, customized or default
+func (u *TemplateUseCase) Templates(ctx context.Context, userId int64, siteId int64) ([]template_dto.TemplateDto, error) {
+	if err := u.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return nil, err
+	}
+	saved, err := u.templateReadRepo.FindBySiteId(siteId)
+	if err != nil {
+		return nil, err
+	}
+	custom := make(map[[3]string]*site_entity.NotificationTemplateEntity, len(saved))
+	for i := range saved {
+		custom[[3]string{saved[i].Channel, saved[i].Event, saved[i].Locale}] = &saved[i]
+	}
+
+	templates := make([]template_dto.TemplateDto, 0, len(Catalog)*len(Channels)*len(Locales))
+	for _, event := range Catalog {
+		for _, channel := range Channels {
+			for _, locale := range Locales {
+				templates = append(templates, templateDto(&event, channel, locale, custom[[3]string{channel, event.Name, locale}]))
+			}
+		}
+	}
+	return templates, nil
+}
+
+// Save replaces the default text of an event with the owner's. Every placeholder must be a variable of
+// the event.
+func (u *TemplateUseCase) Save(ctx context.Context, userId int64, dto template_dto.SaveTemplateDto) (*template_dto.TemplateDto, error) {
+	event, err := u.checkTemplate(userId, dto.TemplateKeyDto)
+	if err != nil {
+		return nil, err
+	}
+	subject, htmlBody := dto.Subject, dto.Html
+	if dto.Channel == site_entity.NotificationChannelSms {
+		subject, htmlBody = "", ""
+	} else if subject == "" {
+		return nil, ErrSubjectRequired
+	}
+	for _, text := range []string{subject, dto.Body, htmlBody} {
+		if err := validate(event, text); err != nil {
+			return nil, err
+		}
+	}
+
+	template := &site_entity.NotificationTemplateEntity{
+		SiteId:  strconv.FormatInt(dto.SiteId, 10),
+		UserId:  strconv.FormatInt(userId, 10),
+		Channel: dto.Channel,
+		Event:   dto.Event,
+		Locale:  dto.Locale,
+		Subject: subject,
+		Body:    dto.Body,
+		Html:    htmlBody,
+	}
+	if err := u.templateWriteRepo.Save(template); err != nil {
+		return nil, err
+	}
+	result := templateDto(event, dto.Channel, dto.Locale, template)
+	return &result, nil
+}
+
+// Reset drops the owner's text of an event so the default text is sent again
+func (u *TemplateUseCase) Reset(ctx context.Context, userId int64, dto template_dto.TemplateKeyDto) (*template_dto.TemplateDto, error) {
+	event, err := u.checkTemplate(userId, dto)
+	if err != nil {
+		return nil, err
+	}
+	err = u.templateWriteRepo.Delete(dto.SiteId, dto.Channel, dto.Event, dto.Locale)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	result := templateDto(event, dto.Channel, dto.Locale, nil)
+	return &result, nil
+}
+
+// Preview renders a draft when its Body is set, otherwise the text the event is sent with. Variables
+// missing from the data show their example value.
+func (u *TemplateUseCase) Preview(ctx context.Context, userId int64, dto template_dto.PreviewTemplateDto) (*template_dto.RenderedDto, error) {
+	event, err := u.checkTemplate(userId, dto.TemplateKeyDto)
+	if err != nil {
+		return nil, err
+	}
+	var text Text
+	var htmlBody string
+	if dto.Body != "" {
+		text, htmlBody = Text{Subject: dto.Subject, Body: dto.Body}, dto.Html
+		if dto.Channel == site_entity.NotificationChannelSms {
+			text.Subject, htmlBody = "", ""
+		}
+		for _, value := range []string{text.Subject, text.Body, htmlBody} {
+			if err := validate(event, value); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		text, htmlBody, err = u.text(event, dto.SiteId, dto.Channel, dto.Locale)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := make(map[string]interface{}, len(event.Variables))
+	for _, v := range event.Variables {
+		data[v.Name] = v.Example
+	}
+	for name, value := range dto.Data {
+		data[name] = value
+	}
+	return render(event, dto.Channel, dto.Locale, text, htmlBody, data), nil
+}
+
+// Render fills the site's text of an event with data, the default text when the owner has not
+// customized it. An unsupported locale falls back to the default locale, missing or mistyped values
+// to the fallback of their variable. SiteName is taken from the site when data has none.
+func (u *TemplateUseCase) Render(ctx context.Context, siteId int64, channel string, eventName string, locale string, data map[string]interface{}) (*template_dto.RenderedDto, error) {
+	event, ok := findEvent(eventName)
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	if channel != site_entity.NotificationChannelSms && channel != site_entity.NotificationChannelEmail {
+		return nil, ErrUnknownChannel
+	}
+	if !supportedLocale(locale) {
+		locale = u.defaultLocale
+	}
+	text, htmlBody, err := u.text(event, siteId, channel, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := data["SiteName"]; !ok {
+		site, err := u.siteReadRepo.FindById(siteId)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, err
+		}
+		if site != nil {
+			values := make(map[string]interface{}, len(data)+1)
+			for name, value := range data {
+				values[name] = value
+			}
+			values["SiteName"] = site.Name
+			data = values
+		}
+	}
+	return render(event, channel, locale, text, htmlBody, data), nil
+}
+
+// text returns the owner's text of an event, or its default text
+func (u *TemplateUseCase) text(event *Event, siteId int64, channel string, locale string) (Text, string, error) {
+	template, err := u.templateReadRepo.Find(siteId, channel, event.Name, locale)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return event.Texts[channel][locale], "", nil
+	}
+	if err != nil {
+		return Text{}, "", err
+	}
+	return Text{Subject: template.Subject, Body: template.Body}, template.Html, nil
+}
+
+func (u *TemplateUseCase) checkTemplate(userId int64, dto template_dto.TemplateKeyDto) (*Event, error) {
+	event, ok := findEvent(dto.Event)
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func templateDto(event *Event, channel string, locale string, template *site_entity.NotificationTemplateEntity) template_dto.TemplateDto {
+	if template == nil {
+		text := event.Texts[channel][locale]
+		return template_dto.TemplateDto{Channel: channel, Event: event.Name, Locale: locale, Subject: text.Subject, Body: text.Body}
+	}
+	updatedAt := template.UpdatedAt
+	return template_dto.TemplateDto{
+		Channel:    channel,
+		Event:      event.Name,
+		Locale:     locale,
+		Subject:    template.Subject,
+		Body:       template.Body,
+		Html:       template.Html,
+		Customized: true,
+		UpdatedAt:  &updatedAt,
+	}
+}
+
+func supportedLocale(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
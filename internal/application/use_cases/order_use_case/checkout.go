@@ -0,0 +1,310 @@
+package order_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/order/checkout_dto"
+	"site_builder_backend/internal/application/dto/order/shipping_dto"
+	"site_builder_backend/internal/application/use_cases/pricing_use_case"
+	"site_builder_backend/internal/application/use_cases/shipping_use_case"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	PaymentServiceName    = "order"
+	PaymentActionCheckout = "checkout"
+)
+
+var (
+	ErrBasketEmpty     = errors.New("basket is empty")
+	ErrAddressNotFound = errors.New("address not found")
+	ErrSiteNotFound    = errors.New("site not found")
+	ErrOutOfStock      = errors.New("some items are no longer in stock, please review the basket")
+)
+
+type CheckoutUseCase struct {
+	siteReadRepo    site_repo_inter.SiteReadRepository
+	addressReadRepo user_repo_inter.AddressReadRepository
+	variantReadRepo product_repo_inter.ProductVariantReadRepository
+	basketReadRepo  order_repo_inter.BasketReadRepository
+	orderReadRepo   order_repo_inter.OrderReadRepository
+	orderWriteRepo  order_repo_inter.OrderWriteRepository
+	pricingUseCase  *pricing_use_case.PricingUseCase
+	shippingUseCase *shipping_use_case.ShippingUseCase
+	eventPublisher  event_publisher_inter.EventPublisher
+	l               *logger.ZapLogger
+}
+
+// basketLines are the basket items priced at the current variant prices
+type basketLines struct {
+	lines []pricing_use_case.PriceLine
+	// weight of the whole basket and of the products that are not sent for free, in grams
+	weight        int
+	shippedWeight int
+	// freeSend is set when every product is sent for free
+	freeSend bool
+}
+
+// checkoutBasket is everything checkout needs, loaded and priced
+type checkoutBasket struct {
+	basket  *order_entity.BasketEntity
+	address *user_entity.AddressEntity
+	site    *site_entity.SiteEntity
+	lines   *basketLines
+	result  *pricing_use_case.PriceResult
+}
+
+func NewCheckoutUseCase(siteReadRepo site_repo_inter.SiteReadRepository, addressReadRepo user_repo_inter.AddressReadRepository, variantReadRepo product_repo_inter.ProductVariantReadRepository, basketReadRepo order_repo_inter.BasketReadRepository, orderReadRepo order_repo_inter.OrderReadRepository, orderWriteRepo order_repo_inter.OrderWriteRepository, pricingUseCase *pricing_use_case.PricingUseCase, shippingUseCase *shipping_use_case.ShippingUseCase, eventPublisher event_publisher_inter.EventPublisher, l *logger.ZapLogger) *CheckoutUseCase {
+	return &CheckoutUseCase{
+		siteReadRepo:    siteReadRepo,
+		addressReadRepo: addressReadRepo,
+		variantReadRepo: variantReadRepo,
+		basketReadRepo:  basketReadRepo,
+		orderReadRepo:   orderReadRepo,
+		orderWriteRepo:  orderWriteRepo,
+		pricingUseCase:  pricingUseCase,
+		shippingUseCase: shippingUseCase,
+		eventPublisher:  eventPublisher,
+		l:               l,
+	}
+}
+
+// Checkout turns the customer's basket into a pending order with a pending payment. Prices are taken
+// from the current variants and offers, not from the basket. Repeating a checkout with the same
+// idempotency key returns the order placed the first time and reports it as not created.
+func (u *CheckoutUseCase) Checkout(ctx context.Context, customerId int64, dto checkout_dto.CheckoutDto, client checkout_dto.CheckoutClientDto) (*order_entity.OrderEntity, bool, error) {
+	existing, err := u.orderReadRepo.FindByIdempotencyKey(dto.SiteId, customerId, client.IdempotencyKey)
+	if err == nil {
+		return existing, false, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, false, err
+	}
+
+	prepared, err := u.prepare(ctx, customerId, dto.SiteId, dto.AddressId)
+	if err != nil {
+		return nil, false, err
+	}
+	basket, address, site, result := prepared.basket, prepared.address, prepared.site, prepared.result
+	shipping, err := u.shippingUseCase.Quote(ctx, dto.SiteId, dto.Courier, prepared.parcel())
+	if err != nil {
+		return nil, false, err
+	}
+
+	order := &order_entity.OrderEntity{
+		SiteId:                       site.Id,
+		TotalRawPrice:                result.TotalRawPrice,
+		TotalCouponDiscount:          result.TotalCouponDiscount,
+		TotalPriceWithCouponDiscount: result.TotalPriceWithCouponDiscount,
+		CourierPrice:                 shipping.Price,
+		Courier:                      shipping.Code,
+		OrderStatus:                  order_entity.OrderStatusPendingPayment,
+		TotalFinalPrice:              result.TotalPriceWithCouponDiscount + shipping.Price,
+		Description:                  dto.Description,
+		TotalWeight:                  prepared.lines.weight,
+		BasketId:                     basket.Id,
+		DiscountId:                   result.DiscountId,
+		AddressId:                    address.Id,
+		CustomerId:                   strconv.FormatInt(customerId, 10),
+		IdempotencyKey:               client.IdempotencyKey,
+		OrderItems:                   make([]order_entity.OrderItemEntity, 0, len(result.Lines)),
+	}
+	for _, line := range result.Lines {
+		order.OrderItems = append(order.OrderItems, order_entity.OrderItemEntity{
+			Quantity:                     line.Quantity,
+			RawPrice:                     line.UnitPrice,
+			FinalRawPrice:                line.FinalRawPrice,
+			FinalPriceWithCouponDiscount: line.FinalPriceWithCouponDiscount,
+			JustCouponPrice:              line.JustCouponPrice,
+			JustDiscountPrice:            line.JustDiscountPrice,
+			ProductId:                    line.ProductId,
+			ProductVariantId:             line.ProductVariantId,
+		})
+	}
+
+	payment := &payment_entity.PaymentEntity{
+		SiteId:            site.Id,
+		PaymentStatusEnum: payment_entity.PaymentStatusPending,
+		Amount:            order.TotalFinalPrice,
+		ServiceName:       PaymentServiceName,
+		ServiceAction:     PaymentActionCheckout,
+		ReturnUrl:         dto.ReturnUrl,
+		ClientIp:          client.ClientIp,
+		UserId:            site.UserId,
+		CustomerId:        order.CustomerId,
+	}
+
+	history := &order_entity.OrderStatusHistoryEntity{
+		SiteId:    site.Id,
+		ToStatus:  order.OrderStatus,
+		ActorType: order_entity.ActorTypeCustomer,
+		ActorId:   order.CustomerId,
+	}
+
+	basketId, _ := strconv.ParseInt(basket.Id, 10, 64)
+	usedDiscountId, couponUnits := pricing_use_case.Usages(result)
+	err = u.orderWriteRepo.Place(order_repo_inter.PlaceOrder{
+		Order:       order,
+		Payment:     payment,
+		DiscountId:  usedDiscountId,
+		CouponUnits: couponUnits,
+		BasketId:    basketId,
+		History:     history,
+	})
+	switch {
+	case err == nil:
+		publishStatusChanged(ctx, u.eventPublisher, u.l, event_dto.RoutingKeyOrderPlaced, order, history)
+		return order, true, nil
+	case errors.Is(err, repositories.ErrConflict):
+		// A concurrent request with the same key won the race
+		existing, err := u.orderReadRepo.FindByIdempotencyKey(dto.SiteId, customerId, client.IdempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	case errors.Is(err, repositories.ErrOutOfStock):
+		return nil, false, ErrOutOfStock
+	case errors.Is(err, repositories.ErrExhausted):
+		return nil, false, pricing_use_case.ErrOfferExhausted
+	default:
+		return nil, false, err
+	}
+}
+
+// ShippingOptions lists the couriers able to ship the customer's basket to the address, with the price
+// the order would have with each of them
+func (u *CheckoutUseCase) ShippingOptions(ctx context.Context, customerId int64, dto shipping_dto.ShippingQuoteDto) (*shipping_dto.ShippingQuoteResultDto, error) {
+	prepared, err := u.prepare(ctx, customerId, dto.SiteId, dto.AddressId)
+	if err != nil {
+		return nil, err
+	}
+	options, err := u.shippingUseCase.Options(ctx, dto.SiteId, prepared.parcel())
+	if err != nil {
+		return nil, err
+	}
+	for i := range options {
+		options[i].TotalFinalPrice = prepared.result.TotalPriceWithCouponDiscount + options[i].Price
+	}
+	return &shipping_dto.ShippingQuoteResultDto{
+		TotalPriceWithCouponDiscount: prepared.result.TotalPriceWithCouponDiscount,
+		TotalWeight:                  prepared.lines.weight,
+		Options:                      options,
+	}, nil
+}
+
+// prepare loads the customer's basket, the delivery address and the site and prices the basket
+func (u *CheckoutUseCase) prepare(ctx context.Context, customerId int64, siteId int64, addressId int64) (*checkoutBasket, error) {
+	basket, err := u.basketReadRepo.FindByCustomer(siteId, customerId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrBasketEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(basket.BasketItems) == 0 {
+		return nil, ErrBasketEmpty
+	}
+
+	address, err := u.addressReadRepo.FindById(addressId)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && (address.IsDeleted || address.CustomerId != strconv.FormatInt(customerId, 10))) {
+		return nil, ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	site, err := u.siteReadRepo.FindById(siteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrSiteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := u.lines(siteId, basket.BasketItems)
+	if err != nil {
+		return nil, err
+	}
+	discountId, _ := strconv.ParseInt(basket.DiscountId, 10, 64)
+	result, err := u.pricingUseCase.PriceWithDiscount(ctx, siteId, customerId, lines.lines, discountId)
+	if err != nil {
+		return nil, err
+	}
+	return &checkoutBasket{basket: basket, address: address, site: site, lines: lines, result: result}, nil
+}
+
+// parcel describes the priced basket to the couriers
+func (b *checkoutBasket) parcel() shipping_use_case.Parcel {
+	siteId, _ := strconv.ParseInt(b.site.Id, 10, 64)
+	provinceId, _ := strconv.ParseInt(b.address.ProvinceId, 10, 64)
+	cityId, _ := strconv.ParseInt(b.address.CityId, 10, 64)
+	return shipping_use_case.Parcel{
+		Shipment: courier_inter.Shipment{
+			SiteId:     siteId,
+			ProvinceId: provinceId,
+			CityId:     cityId,
+			PostalCode: b.address.PostalCode,
+			Weight:     b.lines.shippedWeight,
+			Value:      b.result.TotalPriceWithCouponDiscount,
+		},
+		FreeSend: b.lines.freeSend,
+	}
+}
+
+// lines prices the basket items at the current variant prices and sums their weight. Stock is checked
+// here to fail early, the guarded decrement in the order transaction is what actually enforces it.
+func (u *CheckoutUseCase) lines(siteId int64, items []order_entity.BasketItemEntity) (*basketLines, error) {
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		id, _ := strconv.ParseInt(item.ProductVariantId, 10, 64)
+		ids = append(ids, id)
+	}
+	variants, err := u.variantReadRepo.FindByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]product_entity.ProductVariantEntity, len(variants))
+	for _, variant := range variants {
+		byId[variant.Id] = variant
+	}
+
+	site := strconv.FormatInt(siteId, 10)
+	result := &basketLines{lines: make([]pricing_use_case.PriceLine, 0, len(items)), freeSend: true}
+	for _, item := range items {
+		variant, ok := byId[item.ProductVariantId]
+		if !ok || variant.Product.IsDeleted || variant.Product.SiteId != site {
+			return nil, pricing_use_case.ErrProductNotFound
+		}
+		if item.Quantity > variant.Stock {
+			return nil, ErrOutOfStock
+		}
+		weight := variant.Product.Weight * item.Quantity
+		result.weight += weight
+		if !variant.Product.FreeSend {
+			result.shippedWeight += weight
+			result.freeSend = false
+		}
+		result.lines = append(result.lines, pricing_use_case.PriceLine{
+			ProductId:        variant.ProductId,
+			ProductVariantId: variant.Id,
+			UnitPrice:        variant.Price,
+			Quantity:         item.Quantity,
+		})
+	}
+	return result, nil
+}
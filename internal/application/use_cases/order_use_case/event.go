@@ -0,0 +1,34 @@
+package order_use_case
+
+import (
+	"context"
+	"time"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// publishStatusChanged notifies consumers of a placed order or a transition. A failed publish is
+// logged only, the change itself is committed already.
+func publishStatusChanged(ctx context.Context, publisher event_publisher_inter.EventPublisher, l *logger.ZapLogger, routingKey string, order *order_entity.OrderEntity, history *order_entity.OrderStatusHistoryEntity) {
+	event := event_dto.OrderStatusChangedEvent{
+		OrderId:         order.Id,
+		SiteId:          order.SiteId,
+		CustomerId:      order.CustomerId,
+		FromStatus:      history.FromStatus,
+		ToStatus:        history.ToStatus,
+		ActorType:       history.ActorType,
+		ActorId:         history.ActorId,
+		TrackingCode:    order.TrackingCode,
+		TotalFinalPrice: order.TotalFinalPrice,
+		ChangedAt:       history.CreatedAt,
+	}
+	if event.ChangedAt.IsZero() {
+		event.ChangedAt = time.Now()
+	}
+	if err := publisher.Publish(ctx, event_publisher_inter.OrderExchange, routingKey, event); err != nil {
+		l.Warn("order_use_case - publishStatusChanged - %s: %v", routingKey, err)
+	}
+}
@@ -0,0 +1,220 @@
+package order_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/order/order_dto"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrSiteAccessDenied     = site_repo_inter.ErrNotOwner
+	ErrInvalidTransition    = errors.New("order status cannot be changed this way")
+	ErrTrackingCodeRequired = errors.New("a tracking code is required to ship an order")
+	ErrOrderChanged         = errors.New("order was changed meanwhile, please reload it")
+)
+
+// Actor is who changes the status of an order. Id is zero for the system.
+type Actor struct {
+	Type string
+	Id   int64
+}
+
+type OrderUseCase struct {
+	siteReadRepo   site_repo_inter.SiteReadRepository
+	orderReadRepo  order_repo_inter.OrderReadRepository
+	orderWriteRepo order_repo_inter.OrderWriteRepository
+	eventPublisher event_publisher_inter.EventPublisher
+	l              *logger.ZapLogger
+}
+
+func NewOrderUseCase(siteReadRepo site_repo_inter.SiteReadRepository, orderReadRepo order_repo_inter.OrderReadRepository, orderWriteRepo order_repo_inter.OrderWriteRepository, eventPublisher event_publisher_inter.EventPublisher, l *logger.ZapLogger) *OrderUseCase {
+	return &OrderUseCase{
+		siteReadRepo:   siteReadRepo,
+		orderReadRepo:  orderReadRepo,
+		orderWriteRepo: orderWriteRepo,
+		eventPublisher: eventPublisher,
+		l:              l,
+	}
+}
+
+func (u *OrderUseCase) GetAll(ctx context.Context, userId int64, dto order_dto.OrderFilterDto) (common_dto.PaginatedDto[order_entity.OrderEntity], error) {
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return common_dto.PaginatedDto[order_entity.OrderEntity]{}, err
+	}
+	orders, total, err := u.orderReadRepo.FindAll(order_repo_inter.OrderFilter{
+		SiteId: dto.SiteId,
+		Status: dto.Status,
+		Offset: dto.Offset(),
+		Limit:  dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[order_entity.OrderEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(orders, total, dto.PaginationDto), nil
+}
+
+func (u *OrderUseCase) Get(ctx context.Context, userId int64, id int64) (*order_dto.OrderDetailsDto, error) {
+	order, err := u.ownedBySite(userId, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.details(order)
+}
+
+// ChangeStatus lets the site owner move an order along the state machine
+func (u *OrderUseCase) ChangeStatus(ctx context.Context, userId int64, dto order_dto.ChangeOrderStatusDto) (*order_entity.OrderEntity, error) {
+	order, err := u.ownedBySite(userId, dto.Id)
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, dto.Status, Actor{Type: order_entity.ActorTypeOwner, Id: userId}, dto.TrackingCode, dto.Note)
+}
+
+func (u *OrderUseCase) CustomerGetAll(ctx context.Context, customerId int64, dto order_dto.CustomerOrderFilterDto) (common_dto.PaginatedDto[order_entity.OrderEntity], error) {
+	orders, total, err := u.orderReadRepo.FindAll(order_repo_inter.OrderFilter{
+		SiteId:     dto.SiteId,
+		CustomerId: customerId,
+		Status:     dto.Status,
+		Offset:     dto.Offset(),
+		Limit:      dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[order_entity.OrderEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(orders, total, dto.PaginationDto), nil
+}
+
+func (u *OrderUseCase) CustomerGet(ctx context.Context, customerId int64, id int64) (*order_dto.OrderDetailsDto, error) {
+	order, err := u.ownedByCustomer(customerId, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.details(order)
+}
+
+// Cancel lets a customer cancel an order that is not paid yet
+func (u *OrderUseCase) Cancel(ctx context.Context, customerId int64, id int64) (*order_entity.OrderEntity, error) {
+	order, err := u.ownedByCustomer(customerId, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, order_entity.OrderStatusCancelled, Actor{Type: order_entity.ActorTypeCustomer, Id: customerId}, "", "")
+}
+
+// ConfirmDelivery lets a customer mark a shipped order as received
+func (u *OrderUseCase) ConfirmDelivery(ctx context.Context, customerId int64, id int64) (*order_entity.OrderEntity, error) {
+	order, err := u.ownedByCustomer(customerId, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, order_entity.OrderStatusDelivered, Actor{Type: order_entity.ActorTypeCustomer, Id: customerId}, "", "")
+}
+
+// Transition changes the status of an order on behalf of the system, such as a verified payment
+func (u *OrderUseCase) Transition(ctx context.Context, orderId int64, to string, note string) (*order_entity.OrderEntity, error) {
+	order, err := u.orderReadRepo.FindById(orderId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.transition(ctx, order, to, Actor{Type: order_entity.ActorTypeSystem}, "", note)
+}
+
+func (u *OrderUseCase) transition(ctx context.Context, order *order_entity.OrderEntity, to string, actor Actor, trackingCode string, note string) (*order_entity.OrderEntity, error) {
+	rule, ok := transitionFor(order.OrderStatus, to, actor.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.OrderStatus, to)
+	}
+	trackingCode = strings.TrimSpace(trackingCode)
+	if to == order_entity.OrderStatusShipped && trackingCode == "" && order.TrackingCode == "" {
+		return nil, ErrTrackingCodeRequired
+	}
+
+	orderId, _ := strconv.ParseInt(order.Id, 10, 64)
+	history := &order_entity.OrderStatusHistoryEntity{
+		OrderId:    order.Id,
+		SiteId:     order.SiteId,
+		FromStatus: order.OrderStatus,
+		ToStatus:   to,
+		ActorType:  actor.Type,
+		Note:       strings.TrimSpace(note),
+	}
+	if actor.Id > 0 {
+		history.ActorId = strconv.FormatInt(actor.Id, 10)
+	}
+	err := u.orderWriteRepo.ChangeStatus(order_repo_inter.StatusTransition{
+		OrderId:      orderId,
+		From:         order.OrderStatus,
+		To:           to,
+		TrackingCode: trackingCode,
+		Reservations: rule.reservations,
+		History:      history,
+	})
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrOrderChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	order.OrderStatus = to
+	if trackingCode != "" {
+		order.TrackingCode = trackingCode
+	}
+	publishStatusChanged(ctx, u.eventPublisher, u.l, event_dto.RoutingKeyOrderPrefix+to, order, history)
+	return order, nil
+}
+
+func (u *OrderUseCase) details(order *order_entity.OrderEntity) (*order_dto.OrderDetailsDto, error) {
+	orderId, _ := strconv.ParseInt(order.Id, 10, 64)
+	history, err := u.orderReadRepo.FindHistory(orderId)
+	if err != nil {
+		return nil, err
+	}
+	return &order_dto.OrderDetailsDto{Order: order, History: history}, nil
+}
+
+func (u *OrderUseCase) ownedBySite(userId int64, id int64) (*order_entity.OrderEntity, error) {
+	order, err := u.orderReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	siteId, _ := strconv.ParseInt(order.SiteId, 10, 64)
+	if err := u.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ownedByCustomer hides orders of other customers as if they did not exist
+func (u *OrderUseCase) ownedByCustomer(customerId int64, id int64) (*order_entity.OrderEntity, error) {
+	order, err := u.orderReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerId != strconv.FormatInt(customerId, 10) {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
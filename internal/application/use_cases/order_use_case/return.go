@@ -0,0 +1,374 @@
+package order_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/order/return_dto"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrReturnNotFound     = errors.New("return not found")
+	ErrReturnExists       = errors.New("a return was requested for this item already")
+	ErrReturnNotAllowed   = errors.New("only items of delivered orders can be returned")
+	ErrReturnWindowClosed = errors.New("the return window of this order has closed")
+	ErrReturnChanged      = errors.New("return was changed meanwhile, please reload it")
+	ErrReturnState        = errors.New("return is not in a state that allows this action")
+)
+
+type ReturnUseCase struct {
+	siteReadRepo      site_repo_inter.SiteReadRepository
+	settingsReadRepo  site_repo_inter.SettingsReadRepository
+	settingsWriteRepo site_repo_inter.SettingsWriteRepository
+	orderReadRepo     order_repo_inter.OrderReadRepository
+	returnReadRepo    order_repo_inter.ReturnItemReadRepository
+	returnWriteRepo   order_repo_inter.ReturnItemWriteRepository
+	orderUseCase      *OrderUseCase
+	eventPublisher    event_publisher_inter.EventPublisher
+	l                 *logger.ZapLogger
+}
+
+func NewReturnUseCase(siteReadRepo site_repo_inter.SiteReadRepository, settingsReadRepo site_repo_inter.SettingsReadRepository, settingsWriteRepo site_repo_inter.SettingsWriteRepository, orderReadRepo order_repo_inter.OrderReadRepository, returnReadRepo order_repo_inter.ReturnItemReadRepository, returnWriteRepo order_repo_inter.ReturnItemWriteRepository, orderUseCase *OrderUseCase, eventPublisher event_publisher_inter.EventPublisher, l *logger.ZapLogger) *ReturnUseCase {
+	return &ReturnUseCase{
+		siteReadRepo:      siteReadRepo,
+		settingsReadRepo:  settingsReadRepo,
+		settingsWriteRepo: settingsWriteRepo,
+		orderReadRepo:     orderReadRepo,
+		returnReadRepo:    returnReadRepo,
+		returnWriteRepo:   returnWriteRepo,
+		orderUseCase:      orderUseCase,
+		eventPublisher:    eventPublisher,
+		l:                 l,
+	}
+}
+
+// Request opens a return for an item of a delivered order while the return window of the site is open.
+// The window starts when the order was delivered.
+func (u *ReturnUseCase) Request(ctx context.Context, customerId int64, dto return_dto.RequestReturnDto) (*return_dto.ReturnDto, error) {
+	item, err := u.orderReadRepo.FindItemById(dto.OrderItemId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	order := item.Order
+	if order.CustomerId != strconv.FormatInt(customerId, 10) {
+		return nil, ErrOrderNotFound
+	}
+	if order.OrderStatus != order_entity.OrderStatusDelivered {
+		return nil, ErrReturnNotAllowed
+	}
+
+	siteId, _ := strconv.ParseInt(order.SiteId, 10, 64)
+	site, err := u.siteReadRepo.FindById(siteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkWindow(&order, siteId, time.Now()); err != nil {
+		return nil, err
+	}
+
+	entity := &order_entity.ReturnItemEntity{
+		ReturnReason: strings.TrimSpace(dto.ReturnReason),
+		OrderStatus:  order_entity.ReturnStatusRequested,
+		RefundAmount: item.FinalPriceWithCouponDiscount,
+		SiteId:       order.SiteId,
+		OrderId:      order.Id,
+		OrderItemId:  item.Id,
+		ProductId:    item.ProductId,
+		UserId:       site.UserId,
+		CustomerId:   order.CustomerId,
+	}
+	err = u.returnWriteRepo.Create(entity)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrReturnExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event := event_dto.ReturnRequestedEvent{
+		ReturnId:     entity.Id,
+		OrderId:      entity.OrderId,
+		OrderItemId:  entity.OrderItemId,
+		SiteId:       entity.SiteId,
+		OwnerUserId:  entity.UserId,
+		CustomerId:   entity.CustomerId,
+		ReturnReason: entity.ReturnReason,
+		RequestedAt:  entity.CreatedAt,
+	}
+	if err := u.eventPublisher.Publish(ctx, event_publisher_inter.OrderExchange, event_dto.RoutingKeyReturnRequested, event); err != nil {
+		u.l.Warn("order_use_case - ReturnUseCase - Request: %v", err)
+	}
+
+	result := return_dto.NewReturnDto(*entity)
+	return &result, nil
+}
+
+// Cancel withdraws a return the owner has not reviewed yet
+func (u *ReturnUseCase) Cancel(ctx context.Context, customerId int64, id int64) error {
+	entity, err := u.find(id)
+	if err != nil {
+		return err
+	}
+	if entity.CustomerId != strconv.FormatInt(customerId, 10) {
+		return ErrReturnNotFound
+	}
+	if entity.OrderStatus != order_entity.ReturnStatusRequested {
+		return ErrReturnState
+	}
+	return u.changeStatus(order_repo_inter.ReturnStatusChange{
+		Id:   id,
+		From: order_entity.ReturnStatusRequested,
+		To:   order_entity.ReturnStatusCancelled,
+	})
+}
+
+func (u *ReturnUseCase) CustomerGetAll(ctx context.Context, customerId int64, dto return_dto.CustomerReturnFilterDto) (common_dto.PaginatedDto[return_dto.ReturnDto], error) {
+	return u.list(order_repo_inter.ReturnItemFilter{
+		SiteId:     dto.SiteId,
+		CustomerId: customerId,
+		Offset:     dto.Offset(),
+		Limit:      dto.Limit(),
+	}, dto.PaginationDto)
+}
+
+func (u *ReturnUseCase) GetAll(ctx context.Context, userId int64, dto return_dto.ReturnFilterDto) (common_dto.PaginatedDto[return_dto.ReturnDto], error) {
+	if err := u.orderUseCase.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return common_dto.PaginatedDto[return_dto.ReturnDto]{}, err
+	}
+	return u.list(order_repo_inter.ReturnItemFilter{
+		SiteId: dto.SiteId,
+		Status: order_entity.ReturnStatusByName(dto.Status),
+		Offset: dto.Offset(),
+		Limit:  dto.Limit(),
+	}, dto.PaginationDto)
+}
+
+// Approve accepts a return, puts the item back in stock and asks the payment subsystem for the refund
+func (u *ReturnUseCase) Approve(ctx context.Context, userId int64, dto return_dto.ReviewReturnDto) error {
+	entity, err := u.ownedBySite(userId, dto.Id)
+	if err != nil {
+		return err
+	}
+	if entity.OrderStatus != order_entity.ReturnStatusRequested {
+		return ErrReturnState
+	}
+	err = u.changeStatus(order_repo_inter.ReturnStatusChange{
+		Id:        dto.Id,
+		From:      order_entity.ReturnStatusRequested,
+		To:        order_entity.ReturnStatusApproved,
+		OwnerNote: strings.TrimSpace(dto.Note),
+		Restock:   true,
+	})
+	if err != nil {
+		return err
+	}
+	// The approval stands even when the refund request is lost, it is logged and can be retried
+	_ = u.requestRefund(ctx, entity)
+	return nil
+}
+
+func (u *ReturnUseCase) Reject(ctx context.Context, userId int64, dto return_dto.ReviewReturnDto) error {
+	entity, err := u.ownedBySite(userId, dto.Id)
+	if err != nil {
+		return err
+	}
+	if entity.OrderStatus != order_entity.ReturnStatusRequested {
+		return ErrReturnState
+	}
+	return u.changeStatus(order_repo_inter.ReturnStatusChange{
+		Id:        dto.Id,
+		From:      order_entity.ReturnStatusRequested,
+		To:        order_entity.ReturnStatusRejected,
+		OwnerNote: strings.TrimSpace(dto.Note),
+	})
+}
+
+// RetryRefund asks for the refund of an approved return again, for when the first request was lost
+func (u *ReturnUseCase) RetryRefund(ctx context.Context, userId int64, id int64) error {
+	entity, err := u.ownedBySite(userId, id)
+	if err != nil {
+		return err
+	}
+	if entity.OrderStatus != order_entity.ReturnStatusApproved {
+		return ErrReturnState
+	}
+	return u.requestRefund(ctx, entity)
+}
+
+// ConfirmRefund lets the owner record a refund issued from the gateway's panel, for gateways that cannot
+// refund through their api
+func (u *ReturnUseCase) ConfirmRefund(ctx context.Context, userId int64, id int64) error {
+	entity, err := u.ownedBySite(userId, id)
+	if err != nil {
+		return err
+	}
+	if entity.OrderStatus != order_entity.ReturnStatusApproved {
+		return ErrReturnState
+	}
+	return u.MarkRefunded(ctx, id)
+}
+
+// MarkRefunded is called by the payment subsystem once the money is paid back. The order itself is
+// refunded when every item of it was returned and refunded.
+func (u *ReturnUseCase) MarkRefunded(ctx context.Context, id int64) error {
+	entity, err := u.find(id)
+	if err != nil {
+		return err
+	}
+	err = u.changeStatus(order_repo_inter.ReturnStatusChange{
+		Id:   id,
+		From: order_entity.ReturnStatusApproved,
+		To:   order_entity.ReturnStatusRefunded,
+	})
+	if err != nil {
+		return err
+	}
+
+	orderId, _ := strconv.ParseInt(entity.OrderId, 10, 64)
+	order, err := u.orderReadRepo.FindById(orderId)
+	if err != nil {
+		return err
+	}
+	returns, err := u.returnReadRepo.FindByOrderId(orderId)
+	if err != nil {
+		return err
+	}
+	refunded := 0
+	for _, item := range returns {
+		if item.OrderStatus == order_entity.ReturnStatusRefunded {
+			refunded++
+		}
+	}
+	if refunded < len(order.OrderItems) {
+		return nil
+	}
+	_, err = u.orderUseCase.Transition(ctx, orderId, order_entity.OrderStatusRefunded, "all items were returned")
+	return err
+}
+
+func (u *ReturnUseCase) GetWindow(ctx context.Context, userId int64, siteId int64) (int, error) {
+	if err := u.orderUseCase.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return 0, err
+	}
+	return u.windowDays(siteId)
+}
+
+func (u *ReturnUseCase) UpdateWindow(ctx context.Context, userId int64, dto return_dto.UpdateReturnWindowDto) error {
+	if err := u.orderUseCase.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return err
+	}
+	return u.settingsWriteRepo.SaveReturnWindow(dto.SiteId, userId, *dto.Days)
+}
+
+func (u *ReturnUseCase) requestRefund(ctx context.Context, entity *order_entity.ReturnItemEntity) error {
+	event := event_dto.RefundRequestedEvent{
+		ReturnId:    entity.Id,
+		OrderId:     entity.OrderId,
+		OrderItemId: entity.OrderItemId,
+		SiteId:      entity.SiteId,
+		CustomerId:  entity.CustomerId,
+		Amount:      entity.RefundAmount,
+		RequestedAt: time.Now(),
+	}
+	if err := u.eventPublisher.Publish(ctx, event_publisher_inter.PaymentExchange, event_dto.RoutingKeyRefundRequested, event); err != nil {
+		// The return stays approved, the owner can ask for the refund again with RetryRefund
+		u.l.Error("order_use_case - ReturnUseCase - requestRefund: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (u *ReturnUseCase) checkWindow(order *order_entity.OrderEntity, siteId int64, now time.Time) error {
+	days, err := u.windowDays(siteId)
+	if err != nil {
+		return err
+	}
+	if days == 0 {
+		return ErrReturnWindowClosed
+	}
+
+	orderId, _ := strconv.ParseInt(order.Id, 10, 64)
+	history, err := u.orderReadRepo.FindHistory(orderId)
+	if err != nil {
+		return err
+	}
+	deliveredAt := order.UpdatedAt
+	for _, entry := range history {
+		if entry.ToStatus == order_entity.OrderStatusDelivered {
+			deliveredAt = entry.CreatedAt
+		}
+	}
+	if now.After(deliveredAt.AddDate(0, 0, days)) {
+		return ErrReturnWindowClosed
+	}
+	return nil
+}
+
+func (u *ReturnUseCase) windowDays(siteId int64) (int, error) {
+	settings, err := u.settingsReadRepo.FindBySiteId(siteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return site_entity.DefaultReturnWindowDays, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return settings.ReturnWindowDays, nil
+}
+
+func (u *ReturnUseCase) list(filter order_repo_inter.ReturnItemFilter, pagination common_dto.PaginationDto) (common_dto.PaginatedDto[return_dto.ReturnDto], error) {
+	entities, total, err := u.returnReadRepo.FindAll(filter)
+	if err != nil {
+		return common_dto.PaginatedDto[return_dto.ReturnDto]{}, err
+	}
+	items := make([]return_dto.ReturnDto, 0, len(entities))
+	for _, entity := range entities {
+		items = append(items, return_dto.NewReturnDto(entity))
+	}
+	return common_dto.NewPaginatedDto(items, total, pagination), nil
+}
+
+func (u *ReturnUseCase) changeStatus(change order_repo_inter.ReturnStatusChange) error {
+	err := u.returnWriteRepo.ChangeStatus(change)
+	if errors.Is(err, repositories.ErrConflict) {
+		return ErrReturnChanged
+	}
+	return err
+}
+
+func (u *ReturnUseCase) find(id int64) (*order_entity.ReturnItemEntity, error) {
+	entity, err := u.returnReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrReturnNotFound
+	}
+	return entity, err
+}
+
+func (u *ReturnUseCase) ownedBySite(userId int64, id int64) (*order_entity.ReturnItemEntity, error) {
+	entity, err := u.find(id)
+	if err != nil {
+		return nil, err
+	}
+	siteId, _ := strconv.ParseInt(entity.SiteId, 10, 64)
+	if err := u.orderUseCase.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
@@ -0,0 +1,73 @@
+package order_use_case
+
+import (
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+)
+
+// transitionRule lists who may make a transition and what happens to the reserved stock
+type transitionRule struct {
+	actors       []string
+	reservations string
+}
+
+// orderTransitions is the order state machine. Cancelled and refunded orders are final.
+//
+//	pending_payment -> paid -> processing -> shipped -> delivered
+//	pending_payment -> cancelled
+//	paid, processing, delivered -> refunded
+var orderTransitions = map[string]map[string]transitionRule{
+	order_entity.OrderStatusPendingPayment: {
+		order_entity.OrderStatusPaid: {
+			actors:       []string{order_entity.ActorTypeSystem},
+			reservations: order_repo_inter.ReservationCommit,
+		},
+		order_entity.OrderStatusCancelled: {
+			actors:       []string{order_entity.ActorTypeOwner, order_entity.ActorTypeCustomer, order_entity.ActorTypeSystem},
+			reservations: order_repo_inter.ReservationRelease,
+		},
+	},
+	order_entity.OrderStatusPaid: {
+		order_entity.OrderStatusProcessing: {
+			actors: []string{order_entity.ActorTypeOwner},
+		},
+		order_entity.OrderStatusRefunded: {
+			actors:       []string{order_entity.ActorTypeOwner, order_entity.ActorTypeSystem},
+			reservations: order_repo_inter.ReservationRelease,
+		},
+	},
+	order_entity.OrderStatusProcessing: {
+		order_entity.OrderStatusShipped: {
+			actors: []string{order_entity.ActorTypeOwner},
+		},
+		order_entity.OrderStatusRefunded: {
+			actors:       []string{order_entity.ActorTypeOwner, order_entity.ActorTypeSystem},
+			reservations: order_repo_inter.ReservationRelease,
+		},
+	},
+	order_entity.OrderStatusShipped: {
+		order_entity.OrderStatusDelivered: {
+			actors: []string{order_entity.ActorTypeOwner, order_entity.ActorTypeCustomer, order_entity.ActorTypeSystem},
+		},
+	},
+	// Goods sent back are restocked item by item when their return is received, not here
+	order_entity.OrderStatusDelivered: {
+		order_entity.OrderStatusRefunded: {
+			actors: []string{order_entity.ActorTypeOwner, order_entity.ActorTypeSystem},
+		},
+	},
+}
+
+// transitionFor returns the rule for moving from one status to another by the actor
+func transitionFor(from string, to string, actorType string) (transitionRule, bool) {
+	rule, ok := orderTransitions[from][to]
+	if !ok {
+		return transitionRule{}, false
+	}
+	for _, actor := range rule.actors {
+		if actor == actorType {
+			return rule, true
+		}
+	}
+	return transitionRule{}, false
+}
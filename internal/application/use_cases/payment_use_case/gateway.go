@@ -0,0 +1,40 @@
+package payment_use_case
+
+import (
+	"sort"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+)
+
+// GatewayRegistry holds the gateway implementations by name. A site can use those of them it switched on.
+type GatewayRegistry map[string]payment_gateway_inter.PaymentGateway
+
+func NewGatewayRegistry(gateways ...payment_gateway_inter.PaymentGateway) GatewayRegistry {
+	registry := make(GatewayRegistry, len(gateways))
+	for _, gateway := range gateways {
+		registry[gateway.Name()] = gateway
+	}
+	return registry
+}
+
+// Available returns the names of the implemented gateways the account switched on, sorted
+func (r GatewayRegistry) Available(account *payment_entity.GatewayEntity) []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		if account.IsActive(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Active returns the gateway when it is implemented and switched on for the account
+func (r GatewayRegistry) Active(account *payment_entity.GatewayEntity, name string) (payment_gateway_inter.PaymentGateway, bool) {
+	gateway, ok := r[name]
+	if !ok || !account.IsActive(name) {
+		return nil, false
+	}
+	return gateway, true
+}
@@ -0,0 +1,499 @@
+package payment_use_case
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/payment/payment_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/payment_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// VerifyPath is where gateways send customers back to, followed by the payment id and the tracking number
+const VerifyPath = "/Public/Payment/Verify/"
+
+var (
+	ErrPaymentNotFound    = errors.New("payment not found")
+	ErrInvalidPaymentSite = errors.New("payment is not paid to a valid site")
+	ErrGatewayUnavailable = errors.New("payment gateway is not available for this site")
+	ErrPaymentClosed      = errors.New("payment is already settled or its order cannot be paid anymore")
+	ErrPaymentChanged     = errors.New("payment was changed meanwhile, please try again")
+	ErrGatewayFailed      = errors.New("payment gateway did not respond as expected, please try again")
+	ErrInvalidCallbackUrl = errors.New("callback does not point to this api")
+	ErrPaymentNotPaid     = errors.New("payment is not settled, there is nothing to refund")
+	// ErrRefundExceedsPayment means the refunds of a payment would add up to more than was paid
+	ErrRefundExceedsPayment = errors.New("refund exceeds what is left of the payment")
+	// ErrRefundNotSupported means the refund has to be issued from the gateway's panel
+	ErrRefundNotSupported = errors.New("gateway does not refund through its api, issue the refund from its panel")
+	// ErrRefundPending means a refund under the key was sent to the gateway without its outcome being recorded,
+	// it has to be checked at the gateway before anything is sent again
+	ErrRefundPending = errors.New("refund is pending at the gateway, check it there before refunding again")
+)
+
+// trackingNumberAttempts is how often a random tracking number is drawn before giving up
+const trackingNumberAttempts = 3
+
+// SettlementHandler settles what the payments of a service other than orders pay for. Payments of orders
+// are handled by the payment use case itself.
+type SettlementHandler interface {
+	// Paid is called once the payment is successful
+	Paid(ctx context.Context, payment *payment_entity.PaymentEntity) error
+	// Expired is called when the gateway did not verify the payment or the reconciler fails a payment
+	// left pending
+	Expired(ctx context.Context, payment *payment_entity.PaymentEntity) error
+}
+
+type PaymentUseCase struct {
+	paymentReadRepo  payment_repo_inter.PaymentReadRepository
+	paymentWriteRepo payment_repo_inter.PaymentWriteRepository
+	gatewayReadRepo  payment_repo_inter.GatewayReadRepository
+	orderReadRepo    order_repo_inter.OrderReadRepository
+	orderUseCase     *order_use_case.OrderUseCase
+	gateways         GatewayRegistry
+	eventPublisher   event_publisher_inter.EventPublisher
+	callbackBaseUrl  string
+	handlers         map[string]SettlementHandler
+	l                *logger.ZapLogger
+}
+
+func NewPaymentUseCase(paymentReadRepo payment_repo_inter.PaymentReadRepository, paymentWriteRepo payment_repo_inter.PaymentWriteRepository, gatewayReadRepo payment_repo_inter.GatewayReadRepository, orderReadRepo order_repo_inter.OrderReadRepository, orderUseCase *order_use_case.OrderUseCase, gateways GatewayRegistry, eventPublisher event_publisher_inter.EventPublisher, callbackBaseUrl string, l *logger.ZapLogger) *PaymentUseCase {
+	return &PaymentUseCase{
+		paymentReadRepo:  paymentReadRepo,
+		paymentWriteRepo: paymentWriteRepo,
+		gatewayReadRepo:  gatewayReadRepo,
+		orderReadRepo:    orderReadRepo,
+		orderUseCase:     orderUseCase,
+		gateways:         gateways,
+		eventPublisher:   eventPublisher,
+		callbackBaseUrl:  strings.TrimRight(callbackBaseUrl, "/"),
+		handlers:         make(map[string]SettlementHandler),
+		l:                l,
+	}
+}
+
+// RegisterHandler hands settled payments of the service to the handler
+func (u *PaymentUseCase) RegisterHandler(serviceName string, handler SettlementHandler) {
+	u.handlers[serviceName] = handler
+}
+
+// Gateways lists the gateways the storefront can offer for the site
+func (u *PaymentUseCase) Gateways(ctx context.Context, siteId int64) ([]string, error) {
+	account, err := u.gatewayReadRepo.FindBySiteId(siteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u.gateways.Available(account), nil
+}
+
+// Start sends the pending payment of the customer's order to the chosen gateway. A failed payment can be
+// started again, with the same or another gateway.
+func (u *PaymentUseCase) Start(ctx context.Context, customerId int64, dto payment_dto.StartPaymentDto, clientIp string) (*payment_dto.StartPaymentResultDto, error) {
+	payment, err := u.paymentReadRepo.FindByOrderId(order_use_case.PaymentServiceName, dto.OrderId)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && payment.CustomerId != strconv.FormatInt(customerId, 10)) {
+		return nil, ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if payment.PaymentStatusEnum == payment_entity.PaymentStatusSuccessful {
+		return nil, ErrPaymentClosed
+	}
+	order, err := u.orderReadRepo.FindById(dto.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.OrderStatus != order_entity.OrderStatusPendingPayment {
+		return nil, ErrPaymentClosed
+	}
+	return u.StartPayment(ctx, payment, dto.Gateway, fmt.Sprintf("Order %d", dto.OrderId), clientIp)
+}
+
+// StartPayment sends a pending or failed payment to the gateway of the site it is paid to. Services other
+// than orders start their payments with it after checking what is paid for.
+func (u *PaymentUseCase) StartPayment(ctx context.Context, payment *payment_entity.PaymentEntity, gatewayName string, description string, clientIp string) (*payment_dto.StartPaymentResultDto, error) {
+	siteId, err := strconv.ParseInt(payment.SiteId, 10, 64)
+	if err != nil || siteId <= 0 {
+		return nil, ErrInvalidPaymentSite
+	}
+	account, err := u.gatewayReadRepo.FindBySiteId(siteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrGatewayUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	gateway, ok := u.gateways.Active(account, gatewayName)
+	if !ok {
+		return nil, ErrGatewayUnavailable
+	}
+
+	trackingNumber, err := u.newTrackingNumber()
+	if err != nil {
+		return nil, err
+	}
+	paymentId, _ := strconv.ParseInt(payment.Id, 10, 64)
+	callbackUrl := u.callbackBaseUrl + VerifyPath + payment.Id + "/" + strconv.FormatInt(trackingNumber, 10)
+	token, err := gateway.Request(ctx, account, payment_gateway_inter.PaymentRequest{
+		TrackingNumber: trackingNumber,
+		Amount:         payment.Amount,
+		CallbackUrl:    callbackUrl,
+		Description:    description,
+	})
+	attempt := &payment_entity.ParbadPaymentEntity{
+		TrackingNumber:     trackingNumber,
+		Amount:             payment.Amount,
+		Token:              token,
+		GatewayName:        gateway.Name(),
+		GatewayAccountName: account.Id,
+		Transactions:       []payment_entity.ParbadTransactionEntity{transaction(payment_entity.ParbadTransactionRequest, payment.Amount, err, nil)},
+	}
+	if err != nil {
+		// The refused attempt is kept for the record, the payment itself stays as it was
+		attempt.IsCompleted = true
+		if err := u.paymentWriteRepo.CreateAttempt(attempt); err != nil {
+			return nil, err
+		}
+	}
+	if errors.Is(err, payment_gateway_inter.ErrMisconfigured) {
+		return nil, ErrGatewayUnavailable
+	}
+	if err != nil {
+		u.l.Error("payment_use_case - PaymentUseCase - StartPayment - %s: %v", gateway.Name(), err)
+		return nil, ErrGatewayFailed
+	}
+
+	err = u.paymentWriteRepo.Start(payment_repo_inter.PaymentStart{
+		Id:                 paymentId,
+		TrackingNumber:     trackingNumber,
+		Attempt:            attempt,
+		Gateway:            gateway.Name(),
+		GatewayAccountName: account.Id,
+		GatewayToken:       token,
+		CallVerifyUrl:      callbackUrl,
+		ClientIp:           clientIp,
+	})
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrPaymentChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	redirect, err := gateway.Redirect(account, payment_gateway_inter.RedirectRequest{
+		TrackingNumber: trackingNumber,
+		Token:          token,
+		Amount:         payment.Amount,
+		CallbackUrl:    callbackUrl,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &payment_dto.StartPaymentResultDto{PaymentId: paymentId, TrackingNumber: trackingNumber, Redirect: redirect}, nil
+}
+
+// Verify settles a payment when the gateway sends the customer back and marks its order as paid. Every
+// call to the gateway is stored as a verify transaction of the attempt. An order that cannot be marked
+// paid, such as one cancelled while the gateway page was open, is refunded and reported. Payments already settled, and
+// callbacks of an attempt replaced by a newer one, return the payment as it is, so a repeated callback
+// changes nothing. When the gateway cannot be reached the payment stays pending and the error is returned.
+func (u *PaymentUseCase) Verify(ctx context.Context, paymentId int64, trackingNumber int64, callback map[string]string) (*payment_entity.PaymentEntity, error) {
+	payment, err := u.paymentReadRepo.FindById(paymentId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if payment.PaymentStatusEnum != payment_entity.PaymentStatusPending || payment.TrackingNumber != trackingNumber {
+		return payment, nil
+	}
+	attempt, err := u.paymentReadRepo.FindAttempt(trackingNumber)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return payment, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if attempt.IsCompleted {
+		return payment, nil
+	}
+
+	// A site switching a gateway off must not strand the payments already sent to it
+	gateway, ok := u.gateways[attempt.GatewayName]
+	if !ok {
+		return nil, ErrGatewayUnavailable
+	}
+	siteId, _ := strconv.ParseInt(payment.SiteId, 10, 64)
+	account, err := u.gatewayReadRepo.FindBySiteId(siteId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := gateway.Verify(ctx, account, payment_gateway_inter.VerifyRequest{
+		TrackingNumber: trackingNumber,
+		Token:          attempt.Token,
+		Amount:         attempt.Amount,
+		Callback:       callback,
+	})
+	verified := transaction(payment_entity.ParbadTransactionVerify, attempt.Amount, err, callback)
+	attemptId, _ := strconv.ParseInt(attempt.Id, 10, 64)
+	completion := payment_repo_inter.PaymentCompletion{
+		Id:             paymentId,
+		TrackingNumber: trackingNumber,
+		AttemptId:      attemptId,
+		Transaction:    &verified,
+	}
+	switch {
+	case err == nil:
+		completion.Status = payment_entity.PaymentStatusSuccessful
+		completion.TransactionCode = result.ReferenceId
+		completion.GatewayResponseCode = result.ResponseCode
+		completion.Message = result.Message
+	case errors.Is(err, payment_gateway_inter.ErrNotVerified):
+		completion.Status = payment_entity.PaymentStatusFailed
+		completion.Message = err.Error()
+	default:
+		u.l.Error("payment_use_case - PaymentUseCase - Verify - %s: %v", gateway.Name(), err)
+		verified.PaymentId = attempt.Id
+		_ = u.paymentWriteRepo.AddTransaction(&verified)
+		return nil, ErrGatewayFailed
+	}
+
+	err = u.paymentWriteRepo.Complete(completion)
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		return nil, err
+	}
+	if err == nil && completion.Status == payment_entity.PaymentStatusSuccessful {
+		if err := u.paid(ctx, payment); err != nil {
+			u.report(ctx, payment, u.unfulfilled(ctx, payment, result.ReferenceId, err))
+		}
+	}
+	// An order can still be paid through another gateway, what other services sell is closed right away
+	if err == nil && completion.Status == payment_entity.PaymentStatusFailed && payment.ServiceName != order_use_case.PaymentServiceName {
+		u.expired(ctx, payment, func(reason string) {
+			u.l.Error("payment_use_case - PaymentUseCase - Verify - payment %s: %s", payment.Id, reason)
+		})
+	}
+	// Reload to return what was stored, also when a concurrent callback settled the payment first
+	return u.paymentReadRepo.FindById(paymentId)
+}
+
+// Refund pays an amount of the order's settled payment back through its gateway. The key identifies the
+// refund, asking again with a key that was refunded already changes nothing. The refund is stored pending
+// before the gateway is called: a key still pending, whose outcome was never recorded or lost with the
+// gateway's answer, returns ErrRefundPending rather than risk refunding twice. Once the gateway refunded, failing to record it is
+// only logged. Gateways that cannot refund through their api return ErrRefundNotSupported, the refund is
+// then issued from the gateway's panel.
+func (u *PaymentUseCase) Refund(ctx context.Context, orderId int64, amount int64, key string) error {
+	payment, err := u.paymentReadRepo.FindByOrderId(order_use_case.PaymentServiceName, orderId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrPaymentNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if payment.PaymentStatusEnum != payment_entity.PaymentStatusSuccessful {
+		return ErrPaymentNotPaid
+	}
+	attempt, err := u.paymentReadRepo.FindAttempt(payment.TrackingNumber)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrPaymentNotPaid
+	}
+	if err != nil {
+		return err
+	}
+
+	// Pending refunds count as paid back, they may have reached the gateway
+	var refunded int64
+	for _, t := range attempt.Transactions {
+		if t.Type != payment_entity.ParbadTransactionRefund || (!t.IsSucceed && !t.IsPending) {
+			continue
+		}
+		if t.IdempotencyKey == key && t.IsSucceed {
+			return nil
+		}
+		if t.IdempotencyKey == key {
+			return ErrRefundPending
+		}
+		refunded += t.Amount
+	}
+	if amount <= 0 || refunded+amount > attempt.Amount {
+		return ErrRefundExceedsPayment
+	}
+
+	gateway, ok := u.gateways[attempt.GatewayName]
+	if !ok {
+		return ErrGatewayUnavailable
+	}
+	siteId, _ := strconv.ParseInt(payment.SiteId, 10, 64)
+	account, err := u.gatewayReadRepo.FindBySiteId(siteId)
+	if err != nil {
+		return err
+	}
+
+	refund := transaction(payment_entity.ParbadTransactionRefund, amount, nil, nil)
+	refund.PaymentId = attempt.Id
+	refund.IdempotencyKey = key
+	err = u.paymentWriteRepo.StartRefund(&refund)
+	if errors.Is(err, repositories.ErrConflict) {
+		return ErrRefundPending
+	}
+	if err != nil {
+		return err
+	}
+	refundId, _ := strconv.ParseInt(refund.Id, 10, 64)
+
+	result, err := gateway.Refund(ctx, account, payment_gateway_inter.RefundRequest{
+		TrackingNumber: attempt.TrackingNumber,
+		Token:          attempt.Token,
+		ReferenceId:    attempt.TransactionCode,
+		Amount:         amount,
+	})
+	if err == nil {
+		if err := u.paymentWriteRepo.FinishRefund(refundId, true, result.ReferenceId); err != nil {
+			u.l.Error("payment_use_case - PaymentUseCase - Refund - refund %d was paid as %s but stays pending: %v", refundId, result.ReferenceId, err)
+		}
+		return nil
+	}
+	if !errors.Is(err, payment_gateway_inter.ErrRefundNotSupported) && !errors.Is(err, payment_gateway_inter.ErrNotVerified) {
+		// The gateway may have refunded before the answer was lost, the refund stays pending
+		u.l.Error("payment_use_case - PaymentUseCase - Refund - %s: refund %d: %v", gateway.Name(), refundId, err)
+		return ErrRefundPending
+	}
+	if err := u.paymentWriteRepo.FinishRefund(refundId, false, err.Error()); err != nil {
+		return err
+	}
+	if errors.Is(err, payment_gateway_inter.ErrRefundNotSupported) {
+		return ErrRefundNotSupported
+	}
+	u.l.Error("payment_use_case - PaymentUseCase - Refund - %s: %v", gateway.Name(), err)
+	return ErrGatewayFailed
+}
+
+// CheckCallbackUrl makes sure the virtual gateway only posts back to this api
+func (u *PaymentUseCase) CheckCallbackUrl(callbackUrl string) error {
+	if !strings.HasPrefix(callbackUrl, u.callbackBaseUrl+VerifyPath) {
+		return ErrInvalidCallbackUrl
+	}
+	return nil
+}
+
+// paid moves the order of a successful payment along, payments of other services go to their handler. The
+// money is taken at this point, so a failure is handed to unfulfilled rather than failing the callback.
+func (u *PaymentUseCase) paid(ctx context.Context, payment *payment_entity.PaymentEntity) error {
+	if payment.ServiceName != order_use_case.PaymentServiceName {
+		handler, ok := u.handlers[payment.ServiceName]
+		if !ok {
+			return nil
+		}
+		if err := handler.Paid(ctx, payment); err != nil {
+			u.l.Error("payment_use_case - PaymentUseCase - paid - %s %d: %v", payment.ServiceName, payment.OrderId, err)
+			return err
+		}
+		return nil
+	}
+	note := "payment " + payment.Id + " verified"
+	if _, err := u.orderUseCase.Transition(ctx, payment.OrderId, order_entity.OrderStatusPaid, note); err != nil {
+		u.l.Error("payment_use_case - PaymentUseCase - paid - order %d: %v", payment.OrderId, err)
+		return err
+	}
+	return nil
+}
+
+// unfulfilled deals with a payment settled at the gateway whose order could not be marked paid and returns
+// why, for the report. An order cancelled meanwhile, as by its customer while the gateway page was still
+// open, is paid back in full through the gateway.
+func (u *PaymentUseCase) unfulfilled(ctx context.Context, payment *payment_entity.PaymentEntity, referenceId string, cause error) string {
+	reason := fmt.Sprintf("paid at the gateway as %s but the %s was not marked paid: %v", referenceId, payment.ServiceName, cause)
+	if payment.ServiceName != order_use_case.PaymentServiceName {
+		return reason
+	}
+	order, err := u.orderReadRepo.FindById(payment.OrderId)
+	if err != nil || order.OrderStatus != order_entity.OrderStatusCancelled {
+		return reason
+	}
+	switch err := u.Refund(ctx, payment.OrderId, payment.Amount, "cancelled-"+payment.Id); {
+	case err == nil:
+		return reason + ", refunded as the order was cancelled"
+	case errors.Is(err, ErrRefundNotSupported):
+		return reason + ", the order was cancelled and has to be refunded from the gateway's panel"
+	default:
+		return fmt.Sprintf("%s, the order was cancelled and its refund failed: %v", reason, err)
+	}
+}
+
+// report publishes a payment settled outside the reconciler that needs someone to look at it
+func (u *PaymentUseCase) report(ctx context.Context, payment *payment_entity.PaymentEntity, reason string) {
+	u.l.Error("payment_use_case - PaymentUseCase - report - payment %s: %s", payment.Id, reason)
+	report := &event_dto.ReconciliationReportEvent{
+		Checked: 1,
+		Settled: 1,
+		Mismatches: []event_dto.PaymentMismatch{{
+			PaymentId:      payment.Id,
+			OrderId:        payment.OrderId,
+			TrackingNumber: payment.TrackingNumber,
+			Reason:         reason,
+		}},
+		ReconciledAt: time.Now(),
+	}
+	if err := u.eventPublisher.Publish(ctx, event_publisher_inter.PaymentExchange, event_dto.RoutingKeyReconciliationReport, report); err != nil {
+		u.l.Error("payment_use_case - PaymentUseCase - report - publish: %v", err)
+	}
+}
+
+// newTrackingNumber draws a random twelve digit number no attempt uses yet. Unlike the payment id it
+// tells nothing about the number of orders of the platform.
+func (u *PaymentUseCase) newTrackingNumber() (int64, error) {
+	for i := 0; i < trackingNumberAttempts; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(900000000000))
+		if err != nil {
+			return 0, err
+		}
+		trackingNumber := n.Int64() + 100000000000
+		exists, err := u.paymentReadRepo.TrackingNumberExists(trackingNumber)
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			return trackingNumber, nil
+		}
+	}
+	return 0, fmt.Errorf("payment_use_case - newTrackingNumber: no free tracking number after %d draws", trackingNumberAttempts)
+}
+
+// transaction records a call to the gateway, data is kept as json for the record
+func transaction(kind uint8, amount int64, err error, data map[string]string) payment_entity.ParbadTransactionEntity {
+	entity := payment_entity.ParbadTransactionEntity{
+		Amount:    amount,
+		Type:      kind,
+		IsSucceed: err == nil,
+		CreatedAt: time.Now(),
+	}
+	if err != nil {
+		entity.Message = err.Error()
+	}
+	if len(data) > 0 {
+		if encoded, err := json.Marshal(data); err == nil {
+			entity.AdditionalData = string(encoded)
+		}
+	}
+	return entity
+}
@@ -0,0 +1,176 @@
+package payment_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/payment_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+)
+
+// ReconcileBatchSize is how many stale payments one run of the reconciler looks at
+const ReconcileBatchSize = 50
+
+// Reconcile settles payments left pending since before, mostly by customers who closed the browser on the
+// gateway's page. The gateway is asked about each of them: paid ones mark their order paid as the callback
+// would, the others fail and cancel their order, which releases the reserved stock. Payments the gateway
+// cannot be asked about stay pending for the next run. Disagreements between the gateway and the orders
+// are published as a report.
+func (u *PaymentUseCase) Reconcile(ctx context.Context, before time.Time) (*event_dto.ReconciliationReportEvent, error) {
+	payments, err := u.paymentReadRepo.FindStale(before, ReconcileBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &event_dto.ReconciliationReportEvent{Mismatches: []event_dto.PaymentMismatch{}, ReconciledAt: time.Now()}
+	for i := range payments {
+		// A run cut short by its timeout leaves the rest for the next one
+		if ctx.Err() != nil {
+			break
+		}
+		report.Checked++
+		u.reconcile(ctx, &payments[i], report)
+	}
+
+	u.l.Info("payment_use_case - PaymentUseCase - Reconcile: checked %d, settled %d, failed %d, unreachable %d, mismatches %d",
+		report.Checked, report.Settled, report.Failed, report.Unreachable, len(report.Mismatches))
+	if len(report.Mismatches) > 0 {
+		if err := u.eventPublisher.Publish(ctx, event_publisher_inter.PaymentExchange, event_dto.RoutingKeyReconciliationReport, report); err != nil {
+			u.l.Error("payment_use_case - PaymentUseCase - Reconcile - publish: %v", err)
+		}
+	}
+	return report, nil
+}
+
+func (u *PaymentUseCase) reconcile(ctx context.Context, payment *payment_entity.PaymentEntity, report *event_dto.ReconciliationReportEvent) {
+	mismatch := func(reason string) {
+		report.Mismatches = append(report.Mismatches, event_dto.PaymentMismatch{
+			PaymentId:      payment.Id,
+			OrderId:        payment.OrderId,
+			TrackingNumber: payment.TrackingNumber,
+			Reason:         reason,
+		})
+	}
+	paymentId, _ := strconv.ParseInt(payment.Id, 10, 64)
+
+	var attempt *payment_entity.ParbadPaymentEntity
+	if payment.GatewayToken != "" {
+		found, err := u.paymentReadRepo.FindAttempt(payment.TrackingNumber)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			report.Unreachable++
+			return
+		}
+		attempt = found
+	}
+	if attempt == nil {
+		// The customer never went on to a gateway
+		err := u.paymentWriteRepo.Complete(payment_repo_inter.PaymentCompletion{
+			Id:             paymentId,
+			TrackingNumber: payment.TrackingNumber,
+			Status:         payment_entity.PaymentStatusFailed,
+			Message:        "payment was not started in time",
+		})
+		if err == nil {
+			report.Failed++
+			u.expired(ctx, payment, mismatch)
+		}
+		return
+	}
+	if attempt.IsCompleted {
+		mismatch("attempt is completed while the payment is still pending")
+		return
+	}
+
+	gateway, ok := u.gateways[attempt.GatewayName]
+	if !ok {
+		report.Unreachable++
+		mismatch("gateway " + attempt.GatewayName + " is not available anymore")
+		return
+	}
+	siteId, _ := strconv.ParseInt(payment.SiteId, 10, 64)
+	account, err := u.gatewayReadRepo.FindBySiteId(siteId)
+	if err != nil {
+		report.Unreachable++
+		return
+	}
+
+	result, err := gateway.Inquire(ctx, account, payment_gateway_inter.InquiryRequest{
+		TrackingNumber: attempt.TrackingNumber,
+		Token:          attempt.Token,
+		Amount:         attempt.Amount,
+	})
+	inquired := transaction(payment_entity.ParbadTransactionVerify, attempt.Amount, err, map[string]string{"source": "reconciler"})
+	attemptId, _ := strconv.ParseInt(attempt.Id, 10, 64)
+	completion := payment_repo_inter.PaymentCompletion{
+		Id:             paymentId,
+		TrackingNumber: attempt.TrackingNumber,
+		AttemptId:      attemptId,
+		Transaction:    &inquired,
+	}
+	switch {
+	case err == nil:
+		completion.Status = payment_entity.PaymentStatusSuccessful
+		completion.TransactionCode = result.ReferenceId
+		completion.GatewayResponseCode = result.ResponseCode
+		completion.Message = result.Message
+	case errors.Is(err, payment_gateway_inter.ErrNotVerified):
+		completion.Status = payment_entity.PaymentStatusFailed
+		completion.Message = err.Error()
+	default:
+		u.l.Error("payment_use_case - PaymentUseCase - reconcile - %s: %v", gateway.Name(), err)
+		inquired.PaymentId = attempt.Id
+		_ = u.paymentWriteRepo.AddTransaction(&inquired)
+		report.Unreachable++
+		return
+	}
+
+	// A conflict means a late callback settled the payment meanwhile
+	if err := u.paymentWriteRepo.Complete(completion); err != nil {
+		return
+	}
+	if completion.Status == payment_entity.PaymentStatusFailed {
+		report.Failed++
+		u.expired(ctx, payment, mismatch)
+		return
+	}
+	report.Settled++
+	if err := u.paid(ctx, payment); err != nil {
+		mismatch(u.unfulfilled(ctx, payment, result.ReferenceId, err))
+	}
+}
+
+// expired cancels the order of a failed payment, giving its reserved stock back. Payments of other
+// services go to their handler.
+func (u *PaymentUseCase) expired(ctx context.Context, payment *payment_entity.PaymentEntity, mismatch func(string)) {
+	if payment.ServiceName != order_use_case.PaymentServiceName {
+		if handler, ok := u.handlers[payment.ServiceName]; ok {
+			if err := handler.Expired(ctx, payment); err != nil {
+				mismatch(fmt.Sprintf("%s of the failed payment could not be closed: %v", payment.ServiceName, err))
+			}
+		}
+		return
+	}
+	order, err := u.orderReadRepo.FindById(payment.OrderId)
+	if err != nil {
+		mismatch(fmt.Sprintf("order of the failed payment could not be read: %v", err))
+		return
+	}
+	switch order.OrderStatus {
+	case order_entity.OrderStatusCancelled:
+	case order_entity.OrderStatusPendingPayment:
+		if _, err := u.orderUseCase.Transition(ctx, payment.OrderId, order_entity.OrderStatusCancelled, "payment was not completed"); err != nil {
+			mismatch(fmt.Sprintf("order of the failed payment could not be cancelled: %v", err))
+		}
+	default:
+		mismatch("payment failed while the order is " + order.OrderStatus)
+	}
+}
@@ -0,0 +1,393 @@
+package payment_use_case
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/infrastructures/impl/payment/fake_gateway"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/payment_repo_inter"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	testOrderId        = 41
+	testPaymentId      = 7
+	testTrackingNumber = 100000000042
+	testAmount         = 250000
+)
+
+// store keeps one order with its payment and attempt in memory, as the repositories would
+type store struct {
+	mu          sync.Mutex
+	order       order_entity.OrderEntity
+	payment     payment_entity.PaymentEntity
+	attempt     payment_entity.ParbadPaymentEntity
+	transitions []order_repo_inter.StatusTransition
+	events      []interface{}
+}
+
+// newStore opens an order waiting for its payment, which was sent to the fake gateway
+func newStore() *store {
+	return &store{
+		order: order_entity.OrderEntity{
+			Id:          strconv.Itoa(testOrderId),
+			SiteId:      "3",
+			OrderStatus: order_entity.OrderStatusPendingPayment,
+		},
+		payment: payment_entity.PaymentEntity{
+			Id:                strconv.Itoa(testPaymentId),
+			SiteId:            "3",
+			PaymentStatusEnum: payment_entity.PaymentStatusPending,
+			TrackingNumber:    testTrackingNumber,
+			Gateway:           payment_entity.GatewayZarinPal,
+			Amount:            testAmount,
+			ServiceName:       order_use_case.PaymentServiceName,
+			OrderId:           testOrderId,
+			GatewayToken:      "fake-" + strconv.Itoa(testTrackingNumber),
+		},
+		attempt: payment_entity.ParbadPaymentEntity{
+			Id:             "11",
+			TrackingNumber: testTrackingNumber,
+			Amount:         testAmount,
+			Token:          "fake-" + strconv.Itoa(testTrackingNumber),
+			GatewayName:    payment_entity.GatewayZarinPal,
+		},
+	}
+}
+
+type paymentReadRepo struct{ s *store }
+
+func (r paymentReadRepo) FindById(id int64) (*payment_entity.PaymentEntity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if id != testPaymentId {
+		return nil, repositories.ErrNotFound
+	}
+	payment := r.s.payment
+	return &payment, nil
+}
+
+func (r paymentReadRepo) FindByOrderId(serviceName string, orderId int64) (*payment_entity.PaymentEntity, error) {
+	if serviceName != order_use_case.PaymentServiceName || orderId != testOrderId {
+		return nil, repositories.ErrNotFound
+	}
+	return r.FindById(testPaymentId)
+}
+
+func (r paymentReadRepo) FindAttempt(trackingNumber int64) (*payment_entity.ParbadPaymentEntity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if trackingNumber != r.s.attempt.TrackingNumber {
+		return nil, repositories.ErrNotFound
+	}
+	attempt := r.s.attempt
+	attempt.Transactions = append([]payment_entity.ParbadTransactionEntity(nil), r.s.attempt.Transactions...)
+	return &attempt, nil
+}
+
+func (r paymentReadRepo) TrackingNumberExists(trackingNumber int64) (bool, error) {
+	_, err := r.FindAttempt(trackingNumber)
+	return err == nil, nil
+}
+
+func (r paymentReadRepo) FindStale(before time.Time, limit int) ([]payment_entity.PaymentEntity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.s.payment.PaymentStatusEnum != payment_entity.PaymentStatusPending {
+		return nil, nil
+	}
+	return []payment_entity.PaymentEntity{r.s.payment}, nil
+}
+
+type paymentWriteRepo struct{ s *store }
+
+func (r paymentWriteRepo) Start(start payment_repo_inter.PaymentStart) error {
+	return repositories.ErrConflict
+}
+
+func (r paymentWriteRepo) Complete(completion payment_repo_inter.PaymentCompletion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.s.payment.PaymentStatusEnum != payment_entity.PaymentStatusPending || r.s.payment.TrackingNumber != completion.TrackingNumber {
+		return repositories.ErrConflict
+	}
+	if completion.AttemptId != 0 && r.s.attempt.IsCompleted {
+		return repositories.ErrConflict
+	}
+	r.s.payment.PaymentStatusEnum = completion.Status
+	r.s.payment.TransactionCode = completion.TransactionCode
+	if completion.AttemptId != 0 {
+		r.s.attempt.IsCompleted = true
+		r.s.attempt.IsPaid = completion.Status == payment_entity.PaymentStatusSuccessful
+		r.s.attempt.TransactionCode = completion.TransactionCode
+		r.s.attempt.Transactions = append(r.s.attempt.Transactions, *completion.Transaction)
+	}
+	return nil
+}
+
+func (r paymentWriteRepo) CreateAttempt(attempt *payment_entity.ParbadPaymentEntity) error {
+	return nil
+}
+
+func (r paymentWriteRepo) AddTransaction(transaction *payment_entity.ParbadTransactionEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.attempt.Transactions = append(r.s.attempt.Transactions, *transaction)
+	return nil
+}
+
+func (r paymentWriteRepo) StartRefund(transaction *payment_entity.ParbadTransactionEntity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, t := range r.s.attempt.Transactions {
+		if t.IdempotencyKey == transaction.IdempotencyKey {
+			return repositories.ErrConflict
+		}
+	}
+	transaction.Id = strconv.Itoa(len(r.s.attempt.Transactions) + 1)
+	transaction.IsPending = true
+	r.s.attempt.Transactions = append(r.s.attempt.Transactions, *transaction)
+	return nil
+}
+
+func (r paymentWriteRepo) FinishRefund(id int64, succeeded bool, message string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, t := range r.s.attempt.Transactions {
+		if t.Id == strconv.FormatInt(id, 10) && t.IsPending {
+			r.s.attempt.Transactions[i].IsPending = false
+			r.s.attempt.Transactions[i].IsSucceed = succeeded
+			r.s.attempt.Transactions[i].Message = message
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+type gatewayReadRepo struct{}
+
+func (gatewayReadRepo) FindBySiteId(siteId int64) (*payment_entity.GatewayEntity, error) {
+	return &payment_entity.GatewayEntity{Id: "5", SiteId: strconv.FormatInt(siteId, 10)}, nil
+}
+
+type orderReadRepo struct{ s *store }
+
+func (r orderReadRepo) FindById(id int64) (*order_entity.OrderEntity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if id != testOrderId {
+		return nil, repositories.ErrNotFound
+	}
+	order := r.s.order
+	return &order, nil
+}
+
+func (r orderReadRepo) FindAll(filter order_repo_inter.OrderFilter) ([]order_entity.OrderEntity, int64, error) {
+	return nil, 0, nil
+}
+
+func (r orderReadRepo) FindHistory(orderId int64) ([]order_entity.OrderStatusHistoryEntity, error) {
+	return nil, nil
+}
+
+func (r orderReadRepo) FindItemById(id int64) (*order_entity.OrderItemEntity, error) {
+	return nil, repositories.ErrNotFound
+}
+
+func (r orderReadRepo) HasPurchased(customerId int64, productId int64) (bool, error) {
+	return false, nil
+}
+
+func (r orderReadRepo) FindByIdempotencyKey(siteId int64, customerId int64, key string) (*order_entity.OrderEntity, error) {
+	return nil, repositories.ErrNotFound
+}
+
+type orderWriteRepo struct{ s *store }
+
+func (r orderWriteRepo) Place(place order_repo_inter.PlaceOrder) error {
+	return nil
+}
+
+func (r orderWriteRepo) ChangeStatus(transition order_repo_inter.StatusTransition) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if r.s.order.OrderStatus != transition.From {
+		return repositories.ErrConflict
+	}
+	r.s.order.OrderStatus = transition.To
+	r.s.transitions = append(r.s.transitions, transition)
+	return nil
+}
+
+type siteReadRepo struct{}
+
+func (siteReadRepo) FindById(id int64) (*site_entity.SiteEntity, error) {
+	return nil, repositories.ErrNotFound
+}
+
+func (siteReadRepo) CheckOwner(siteId int64, userId int64) error {
+	return repositories.ErrNotFound
+}
+
+type eventPublisher struct{ s *store }
+
+func (p eventPublisher) Publish(ctx context.Context, exchange string, routingKey string, payload interface{}) error {
+	p.s.mu.Lock()
+	defer p.s.mu.Unlock()
+	p.s.events = append(p.s.events, payload)
+	return nil
+}
+
+// lateGateway lets the callback of the customer through while the reconciler asks the gateway
+type lateGateway struct {
+	*fake_gateway.Gateway
+	callback func()
+}
+
+func (g *lateGateway) Inquire(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.InquiryRequest) (*payment_gateway_inter.VerifyResult, error) {
+	g.callback()
+	return g.Gateway.Inquire(ctx, account, request)
+}
+
+func newPaymentUseCase(s *store, gateway payment_gateway_inter.PaymentGateway) *PaymentUseCase {
+	l := logger.NewLoggerFromConfig("error", "json", "stdout")
+	orderUseCase := order_use_case.NewOrderUseCase(siteReadRepo{}, orderReadRepo{s}, orderWriteRepo{s}, eventPublisher{s}, l)
+	return NewPaymentUseCase(paymentReadRepo{s}, paymentWriteRepo{s}, gatewayReadRepo{}, orderReadRepo{s}, orderUseCase, NewGatewayRegistry(gateway), eventPublisher{s}, "http://api.test", l)
+}
+
+func TestReconcilePaid(t *testing.T) {
+	s := newStore()
+	gateway := fake_gateway.NewGateway(payment_entity.GatewayZarinPal)
+	gateway.Pay(testTrackingNumber, "ref-1")
+
+	report, err := newPaymentUseCase(s, gateway).Reconcile(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Settled != 1 || len(report.Mismatches) != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if s.payment.PaymentStatusEnum != payment_entity.PaymentStatusSuccessful || s.payment.TransactionCode != "ref-1" {
+		t.Fatalf("payment = %s %q", s.payment.PaymentStatusEnum, s.payment.TransactionCode)
+	}
+	if s.order.OrderStatus != order_entity.OrderStatusPaid || len(s.transitions) != 1 || s.transitions[0].Reservations != order_repo_inter.ReservationCommit {
+		t.Fatalf("order %s after %+v", s.order.OrderStatus, s.transitions)
+	}
+}
+
+func TestReconcileNotPaid(t *testing.T) {
+	s := newStore()
+	gateway := fake_gateway.NewGateway(payment_entity.GatewayZarinPal)
+
+	report, err := newPaymentUseCase(s, gateway).Reconcile(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Failed != 1 || len(report.Mismatches) != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if s.payment.PaymentStatusEnum != payment_entity.PaymentStatusFailed || !s.attempt.IsCompleted {
+		t.Fatalf("payment = %s, attempt completed %t", s.payment.PaymentStatusEnum, s.attempt.IsCompleted)
+	}
+	if s.order.OrderStatus != order_entity.OrderStatusCancelled || len(s.transitions) != 1 || s.transitions[0].Reservations != order_repo_inter.ReservationRelease {
+		t.Fatalf("order %s after %+v, want the stock released", s.order.OrderStatus, s.transitions)
+	}
+}
+
+func TestReconcileUnreachable(t *testing.T) {
+	s := newStore()
+	gateway := fake_gateway.NewGateway(payment_entity.GatewayZarinPal)
+	gateway.Pay(testTrackingNumber, "ref-1")
+	gateway.SetUnreachable(true)
+
+	report, err := newPaymentUseCase(s, gateway).Reconcile(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Unreachable != 1 || report.Settled != 0 || report.Failed != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if s.payment.PaymentStatusEnum != payment_entity.PaymentStatusPending || s.attempt.IsCompleted || s.order.OrderStatus != order_entity.OrderStatusPendingPayment {
+		t.Fatalf("payment %s, order %s, want both pending", s.payment.PaymentStatusEnum, s.order.OrderStatus)
+	}
+	// The failed inquiry is kept for the record
+	if len(s.attempt.Transactions) != 1 || s.attempt.Transactions[0].IsSucceed {
+		t.Fatalf("transactions = %+v", s.attempt.Transactions)
+	}
+
+	// The next run settles it once the gateway answers again
+	gateway.SetUnreachable(false)
+	report, err = newPaymentUseCase(s, gateway).Reconcile(context.Background(), time.Now())
+	if err != nil || report.Settled != 1 || s.order.OrderStatus != order_entity.OrderStatusPaid {
+		t.Fatalf("report = %+v, %v, order %s", report, err, s.order.OrderStatus)
+	}
+}
+
+func TestReconcileLateCallback(t *testing.T) {
+	s := newStore()
+	gateway := &lateGateway{Gateway: fake_gateway.NewGateway(payment_entity.GatewayZarinPal)}
+	gateway.Pay(testTrackingNumber, "ref-1")
+	u := newPaymentUseCase(s, gateway)
+	gateway.callback = func() {
+		payment, err := u.Verify(context.Background(), testPaymentId, testTrackingNumber, map[string]string{"token": s.attempt.Token})
+		if err != nil || payment.PaymentStatusEnum != payment_entity.PaymentStatusSuccessful {
+			t.Errorf("Verify() = %+v, %v", payment, err)
+		}
+	}
+
+	report, err := u.Reconcile(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The callback completed the payment first, the reconciler's completion conflicts and changes nothing
+	if report.Settled != 0 || report.Failed != 0 || len(report.Mismatches) != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	if s.payment.PaymentStatusEnum != payment_entity.PaymentStatusSuccessful || s.order.OrderStatus != order_entity.OrderStatusPaid {
+		t.Fatalf("payment %s, order %s", s.payment.PaymentStatusEnum, s.order.OrderStatus)
+	}
+	if len(s.transitions) != 1 {
+		t.Fatalf("order moved %d times, want once", len(s.transitions))
+	}
+}
+
+func TestReconcilePaidAfterCancel(t *testing.T) {
+	s := newStore()
+	s.order.OrderStatus = order_entity.OrderStatusCancelled
+	gateway := fake_gateway.NewGateway(payment_entity.GatewayZarinPal)
+	gateway.Pay(testTrackingNumber, "ref-1")
+
+	report, err := newPaymentUseCase(s, gateway).Reconcile(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Settled != 1 || len(report.Mismatches) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if refunded := gateway.Refunded(testTrackingNumber); refunded != testAmount {
+		t.Fatalf("refunded %d, want %d", refunded, testAmount)
+	}
+	if s.order.OrderStatus != order_entity.OrderStatusCancelled {
+		t.Fatalf("order %s, want it to stay cancelled", s.order.OrderStatus)
+	}
+	published := false
+	for _, event := range s.events {
+		if _, ok := event.(*event_dto.ReconciliationReportEvent); ok {
+			published = true
+		}
+	}
+	if !published {
+		t.Fatal("the mismatch was not reported")
+	}
+}
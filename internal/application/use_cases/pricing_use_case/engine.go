@@ -0,0 +1,225 @@
+package pricing_use_case
+
+import (
+	"fmt"
+	"time"
+
+	"site_builder_backend/internal/domain/product_entity"
+)
+
+// Stacking steps. Each step prices the line as left by the previous one, so with
+// percentage offers the order changes the final price.
+const (
+	StepCoupon   = "coupon"
+	StepDiscount = "discount"
+)
+
+const (
+	DiscountTypePercentage = "percentage"
+	DiscountTypeFixed      = "fixed"
+)
+
+// PriceLine is a quantity of a product variant at its catalogue unit price
+type PriceLine struct {
+	ProductId        string `json:"product_id"`
+	ProductVariantId string `json:"product_variant_id"`
+	UnitPrice        int64  `json:"raw_price"`
+	Quantity         int    `json:"quantity"`
+}
+
+// PricedLine carries the breakdown stored on basket and order items. All amounts are line totals.
+type PricedLine struct {
+	PriceLine
+	FinalRawPrice                int64  `json:"final_raw_price"`
+	JustCouponPrice              int64  `json:"just_coupon_price"`
+	JustDiscountPrice            int64  `json:"just_discount_price"`
+	FinalPriceWithCouponDiscount int64  `json:"final_price_with_coupon_discount"`
+	CouponId                     string `json:"coupon_id,omitempty"`
+	CouponUnits                  int    `json:"coupon_units,omitempty"`
+}
+
+type PriceResult struct {
+	Lines              []PricedLine `json:"lines"`
+	TotalRawPrice      int64        `json:"total_raw_price"`
+	TotalCouponPrice   int64        `json:"total_coupon_price"`
+	TotalDiscountPrice int64        `json:"total_discount_price"`
+	// TotalCouponDiscount is everything taken off by coupons and the discount code together
+	TotalCouponDiscount          int64  `json:"total_coupon_discount"`
+	TotalPriceWithCouponDiscount int64  `json:"total_price_with_coupon_discount"`
+	DiscountId                   string `json:"discount_id,omitempty"`
+}
+
+// Offers are the coupons and the discount code that may apply to a set of lines
+type Offers struct {
+	// Coupons by product id
+	Coupons  map[string]product_entity.CouponEntity
+	Discount *product_entity.DiscountEntity
+	// DiscountProductIds restricts the discount to these products, nil means every product
+	DiscountProductIds map[string]bool
+}
+
+// PricingEngine computes line prices from the offers. It is pure and never touches storage.
+type PricingEngine struct {
+	order         []string
+	allowStacking bool
+}
+
+// NewPricingEngine validates the stacking order. Steps left out of order are appended in the default order.
+func NewPricingEngine(order []string, allowStacking bool) (*PricingEngine, error) {
+	seen := map[string]bool{}
+	steps := make([]string, 0, 2)
+	for _, step := range append(order, StepCoupon, StepDiscount) {
+		if step != StepCoupon && step != StepDiscount {
+			return nil, fmt.Errorf("pricing_use_case - NewPricingEngine: unknown stacking step %q", step)
+		}
+		if !seen[step] {
+			seen[step] = true
+			steps = append(steps, step)
+		}
+	}
+	return &PricingEngine{order: steps, allowStacking: allowStacking}, nil
+}
+
+// Price applies the offers to the lines. Without stacking every step is tried alone and
+// the one saving the most wins.
+func (e *PricingEngine) Price(lines []PriceLine, offers Offers, now time.Time) PriceResult {
+	if e.allowStacking {
+		return e.apply(lines, offers, e.order, now)
+	}
+
+	var best PriceResult
+	for i, step := range e.order {
+		result := e.apply(lines, offers, []string{step}, now)
+		if i == 0 || result.TotalCouponDiscount > best.TotalCouponDiscount {
+			best = result
+		}
+	}
+	return best
+}
+
+// DiscountApplies reports whether the discount code alone takes anything off the lines
+func (e *PricingEngine) DiscountApplies(lines []PriceLine, offers Offers, now time.Time) bool {
+	return e.apply(lines, offers, []string{StepDiscount}, now).DiscountId != ""
+}
+
+func (e *PricingEngine) apply(lines []PriceLine, offers Offers, steps []string, now time.Time) PriceResult {
+	priced := make([]PricedLine, len(lines))
+	current := make([]int64, len(lines))
+	for i, line := range lines {
+		priced[i] = PricedLine{PriceLine: line, FinalRawPrice: line.UnitPrice * int64(line.Quantity)}
+		current[i] = priced[i].FinalRawPrice
+	}
+
+	result := PriceResult{}
+	for _, step := range steps {
+		switch step {
+		case StepCoupon:
+			applyCoupons(priced, current, offers.Coupons, now)
+		case StepDiscount:
+			if applyDiscount(priced, current, offers) {
+				result.DiscountId = offers.Discount.Id
+			}
+		}
+	}
+
+	for i := range priced {
+		priced[i].FinalPriceWithCouponDiscount = current[i]
+		result.TotalRawPrice += priced[i].FinalRawPrice
+		result.TotalCouponPrice += priced[i].JustCouponPrice
+		result.TotalDiscountPrice += priced[i].JustDiscountPrice
+		result.TotalPriceWithCouponDiscount += current[i]
+	}
+	result.TotalCouponDiscount = result.TotalCouponPrice + result.TotalDiscountPrice
+	result.Lines = priced
+	return result
+}
+
+// applyCoupons takes the product coupon off as many units as the coupon has usages left
+func applyCoupons(priced []PricedLine, current []int64, coupons map[string]product_entity.CouponEntity, now time.Time) {
+	for i := range priced {
+		coupon, ok := coupons[priced[i].ProductId]
+		if !ok || coupon.IsDeleted || coupon.Quantity <= 0 || !coupon.ExpiryDate.After(now) || priced[i].Quantity <= 0 {
+			continue
+		}
+
+		units := min(priced[i].Quantity, coupon.Quantity)
+		unitsPrice := current[i] * int64(units) / int64(priced[i].Quantity)
+		value := coupon.Value
+		if coupon.Type == DiscountTypeFixed {
+			value *= int64(units)
+		}
+		amount := offerAmount(coupon.Type, value, unitsPrice)
+		if amount <= 0 {
+			continue
+		}
+
+		priced[i].JustCouponPrice += amount
+		priced[i].CouponId = coupon.Id
+		priced[i].CouponUnits = units
+		current[i] -= amount
+	}
+}
+
+// applyDiscount takes the discount code off the eligible lines. A fixed discount is an amount for the
+// whole basket, spread over the eligible lines in proportion to their price.
+func applyDiscount(priced []PricedLine, current []int64, offers Offers) bool {
+	discount := offers.Discount
+	if discount == nil {
+		return false
+	}
+
+	eligible := make([]int, 0, len(priced))
+	var eligibleTotal int64
+	for i := range priced {
+		if offers.DiscountProductIds != nil && !offers.DiscountProductIds[priced[i].ProductId] {
+			continue
+		}
+		if current[i] <= 0 {
+			continue
+		}
+		eligible = append(eligible, i)
+		eligibleTotal += current[i]
+	}
+	if len(eligible) == 0 {
+		return false
+	}
+
+	if discount.Type == DiscountTypePercentage {
+		applied := false
+		for _, i := range eligible {
+			amount := offerAmount(discount.Type, discount.Value, current[i])
+			priced[i].JustDiscountPrice += amount
+			current[i] -= amount
+			applied = applied || amount > 0
+		}
+		return applied
+	}
+
+	pool := min(discount.Value, eligibleTotal)
+	if pool <= 0 {
+		return false
+	}
+	remaining := pool
+	for n, i := range eligible {
+		amount := pool * current[i] / eligibleTotal
+		if n == len(eligible)-1 {
+			amount = remaining
+		}
+		remaining -= amount
+		priced[i].JustDiscountPrice += amount
+		current[i] -= amount
+	}
+	return true
+}
+
+// offerAmount is what an offer takes off price, never more than the price itself.
+// Percentage values above 100 are treated as 100.
+func offerAmount(offerType string, value int64, price int64) int64 {
+	if value <= 0 || price <= 0 {
+		return 0
+	}
+	if offerType == DiscountTypePercentage {
+		return price * min(value, 100) / 100
+	}
+	return min(value, price)
+}
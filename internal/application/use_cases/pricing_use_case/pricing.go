@@ -0,0 +1,216 @@
+package pricing_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/product/pricing_dto"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrProductNotFound       = errors.New("product not found")
+	ErrDiscountNotFound      = errors.New("discount code not found")
+	ErrDiscountExpired       = errors.New("discount code has expired")
+	ErrDiscountExhausted     = errors.New("discount code has no usages left")
+	ErrDiscountNotAllowed    = errors.New("discount code is not available to this customer")
+	ErrDiscountNotApplicable = errors.New("discount code does not apply to any item")
+	ErrOfferExhausted        = errors.New("a discount or coupon ran out while ordering, please review the prices")
+)
+
+type PricingUseCase struct {
+	engine            *PricingEngine
+	variantReadRepo   product_repo_inter.ProductVariantReadRepository
+	couponReadRepo    product_repo_inter.CouponReadRepository
+	discountReadRepo  product_repo_inter.DiscountReadRepository
+	discountWriteRepo product_repo_inter.DiscountWriteRepository
+	l                 *logger.ZapLogger
+}
+
+func NewPricingUseCase(engine *PricingEngine, variantReadRepo product_repo_inter.ProductVariantReadRepository, couponReadRepo product_repo_inter.CouponReadRepository, discountReadRepo product_repo_inter.DiscountReadRepository, discountWriteRepo product_repo_inter.DiscountWriteRepository, l *logger.ZapLogger) *PricingUseCase {
+	return &PricingUseCase{
+		engine:            engine,
+		variantReadRepo:   variantReadRepo,
+		couponReadRepo:    couponReadRepo,
+		discountReadRepo:  discountReadRepo,
+		discountWriteRepo: discountWriteRepo,
+		l:                 l,
+	}
+}
+
+// Quote prices variants of a site for a customer, zero for guests, without reserving anything
+func (u *PricingUseCase) Quote(ctx context.Context, customerId int64, dto pricing_dto.QuoteDto) (*PriceResult, error) {
+	ids := make([]int64, 0, len(dto.Items))
+	for _, item := range dto.Items {
+		ids = append(ids, item.ProductVariantId)
+	}
+	variants, err := u.variantReadRepo.FindByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	byId := make(map[string]product_entity.ProductVariantEntity, len(variants))
+	for _, variant := range variants {
+		byId[variant.Id] = variant
+	}
+
+	siteId := strconv.FormatInt(dto.SiteId, 10)
+	lines := make([]PriceLine, 0, len(dto.Items))
+	for _, item := range dto.Items {
+		variant, ok := byId[strconv.FormatInt(item.ProductVariantId, 10)]
+		if !ok || variant.Product.SiteId != siteId || variant.Product.IsDeleted {
+			return nil, ErrProductNotFound
+		}
+		lines = append(lines, PriceLine{
+			ProductId:        variant.ProductId,
+			ProductVariantId: variant.Id,
+			UnitPrice:        variant.Price,
+			Quantity:         item.Quantity,
+		})
+	}
+
+	return u.Price(ctx, dto.SiteId, customerId, lines, dto.DiscountCode)
+}
+
+// Price applies the coupons of the products and the discount code, if any, to the lines
+func (u *PricingUseCase) Price(ctx context.Context, siteId int64, customerId int64, lines []PriceLine, discountCode string) (*PriceResult, error) {
+	now := time.Now()
+	offers, err := u.offers(siteId, customerId, lines, discountCode, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := u.engine.Price(lines, offers, now)
+	// Without stacking coupons that save more leave the code unused, which is not an error. A code
+	// that fits no item is refused in both modes.
+	if offers.Discount != nil && result.DiscountId == "" && (u.engine.allowStacking || !u.engine.DiscountApplies(lines, offers, now)) {
+		return nil, ErrDiscountNotApplicable
+	}
+	return &result, nil
+}
+
+// PriceWithDiscount is Price for a discount already attached by id, such as the one stored on a basket
+func (u *PricingUseCase) PriceWithDiscount(ctx context.Context, siteId int64, customerId int64, lines []PriceLine, discountId int64) (*PriceResult, error) {
+	code := ""
+	if discountId > 0 {
+		discount, err := u.discountReadRepo.FindById(discountId)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrDiscountNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		code = discount.Code
+	}
+	return u.Price(ctx, siteId, customerId, lines, code)
+}
+
+// Redeem consumes the discount and coupon usages a priced result relies on, all or nothing
+func (u *PricingUseCase) Redeem(ctx context.Context, result *PriceResult) error {
+	discountId, couponUnits := Usages(result)
+	if discountId == 0 && len(couponUnits) == 0 {
+		return nil
+	}
+
+	err := u.discountWriteRepo.Redeem(discountId, couponUnits)
+	if errors.Is(err, repositories.ErrExhausted) {
+		return ErrOfferExhausted
+	}
+	return err
+}
+
+// Usages returns the discount, zero when none, and the coupon units by coupon id a priced result consumes
+func Usages(result *PriceResult) (int64, map[int64]int) {
+	discountId, _ := strconv.ParseInt(result.DiscountId, 10, 64)
+	couponUnits := map[int64]int{}
+	for _, line := range result.Lines {
+		if couponId, err := strconv.ParseInt(line.CouponId, 10, 64); err == nil && line.CouponUnits > 0 {
+			couponUnits[couponId] += line.CouponUnits
+		}
+	}
+	return discountId, couponUnits
+}
+
+// FindDiscount looks a discount code up and checks it is usable by the customer
+func (u *PricingUseCase) FindDiscount(ctx context.Context, siteId int64, customerId int64, code string) (*product_entity.DiscountEntity, error) {
+	discount, err := u.discountReadRepo.FindByCode(siteId, strings.TrimSpace(code))
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrDiscountNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDiscount(discount, customerId, time.Now()); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+func (u *PricingUseCase) offers(siteId int64, customerId int64, lines []PriceLine, discountCode string, now time.Time) (Offers, error) {
+	productIds := make([]int64, 0, len(lines))
+	for _, line := range lines {
+		id, err := strconv.ParseInt(line.ProductId, 10, 64)
+		if err == nil {
+			productIds = append(productIds, id)
+		}
+	}
+	coupons, err := u.couponReadRepo.FindByProductIds(productIds)
+	if err != nil {
+		return Offers{}, err
+	}
+
+	offers := Offers{Coupons: make(map[string]product_entity.CouponEntity, len(coupons))}
+	for _, coupon := range coupons {
+		offers.Coupons[coupon.ProductId] = coupon
+	}
+
+	discountCode = strings.TrimSpace(discountCode)
+	if discountCode == "" {
+		return offers, nil
+	}
+	discount, err := u.discountReadRepo.FindByCode(siteId, discountCode)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return Offers{}, ErrDiscountNotFound
+	}
+	if err != nil {
+		return Offers{}, err
+	}
+	if err := checkDiscount(discount, customerId, now); err != nil {
+		return Offers{}, err
+	}
+
+	offers.Discount = discount
+	if len(discount.Products) > 0 {
+		offers.DiscountProductIds = make(map[string]bool, len(discount.Products))
+		for _, product := range discount.Products {
+			offers.DiscountProductIds[product.Id] = true
+		}
+	}
+	return offers, nil
+}
+
+// checkDiscount validates expiry, remaining usages and the customer restriction of a discount.
+// Discounts restricted to customers are never available to guests.
+func checkDiscount(discount *product_entity.DiscountEntity, customerId int64, now time.Time) error {
+	if !discount.ExpiryDate.After(now) {
+		return ErrDiscountExpired
+	}
+	if discount.Quantity <= 0 {
+		return ErrDiscountExhausted
+	}
+	if len(discount.Customers) == 0 {
+		return nil
+	}
+	customer := strconv.FormatInt(customerId, 10)
+	for _, allowed := range discount.Customers {
+		if customerId > 0 && allowed.CustomerId == customer {
+			return nil
+		}
+	}
+	return ErrDiscountNotAllowed
+}
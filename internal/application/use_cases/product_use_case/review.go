@@ -0,0 +1,225 @@
+package product_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/product/review_dto"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrReviewNotFound   = errors.New("review not found")
+	ErrReviewExists     = errors.New("the customer has already reviewed this product")
+	ErrNotPurchased     = errors.New("only customers who bought the product can review it")
+	ErrSiteAccessDenied = site_repo_inter.ErrNotOwner
+	ErrOwnVote          = errors.New("customers cannot vote on their own review")
+)
+
+type ReviewUseCase struct {
+	siteReadRepo    site_repo_inter.SiteReadRepository
+	productReadRepo product_repo_inter.ProductReadRepository
+	orderReadRepo   order_repo_inter.OrderReadRepository
+	reviewReadRepo  product_repo_inter.ProductReviewReadRepository
+	reviewWriteRepo product_repo_inter.ProductReviewWriteRepository
+	l               *logger.ZapLogger
+}
+
+func NewReviewUseCase(siteReadRepo site_repo_inter.SiteReadRepository, productReadRepo product_repo_inter.ProductReadRepository, orderReadRepo order_repo_inter.OrderReadRepository, reviewReadRepo product_repo_inter.ProductReviewReadRepository, reviewWriteRepo product_repo_inter.ProductReviewWriteRepository, l *logger.ZapLogger) *ReviewUseCase {
+	return &ReviewUseCase{
+		siteReadRepo:    siteReadRepo,
+		productReadRepo: productReadRepo,
+		orderReadRepo:   orderReadRepo,
+		reviewReadRepo:  reviewReadRepo,
+		reviewWriteRepo: reviewWriteRepo,
+		l:               l,
+	}
+}
+
+// Create submits a review of a purchased product. Reviews wait in the moderation queue until approved.
+func (u *ReviewUseCase) Create(ctx context.Context, customerId int64, dto review_dto.CreateReviewDto) (*product_entity.ProductReviewEntity, error) {
+	product, err := u.productReadRepo.FindById(dto.ProductId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	purchased, err := u.orderReadRepo.HasPurchased(customerId, dto.ProductId)
+	if err != nil {
+		return nil, err
+	}
+	if !purchased {
+		return nil, ErrNotPurchased
+	}
+
+	_, err = u.reviewReadRepo.FindByCustomer(customerId, dto.ProductId)
+	if err == nil {
+		return nil, ErrReviewExists
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	entity := dto.ToProductReviewEntity(product, customerId)
+	if err := u.reviewWriteRepo.Create(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Update edits the review of the customer. An edited review goes back to the moderation queue.
+func (u *ReviewUseCase) Update(ctx context.Context, customerId int64, dto review_dto.UpdateReviewDto) (*product_entity.ProductReviewEntity, error) {
+	entity, err := u.findOwnReview(customerId, dto.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	entity.Rating = dto.Rating
+	entity.ReviewText = dto.ReviewText
+	entity.Approved = false
+	entity.UpdatedAt = time.Now()
+	if err := u.reviewWriteRepo.Update(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Delete removes the review of the customer
+func (u *ReviewUseCase) Delete(ctx context.Context, customerId int64, id int64) error {
+	if _, err := u.findOwnReview(customerId, id); err != nil {
+		return err
+	}
+	return u.reviewWriteRepo.Delete(id)
+}
+
+// Vote records a like or dislike. A customer has at most one vote per review, voting again replaces it.
+func (u *ReviewUseCase) Vote(ctx context.Context, customerId int64, dto review_dto.VoteReviewDto) error {
+	review, err := u.findApprovedReview(dto.Id)
+	if err != nil {
+		return err
+	}
+	if review.CustomerId == strconv.FormatInt(customerId, 10) {
+		return ErrOwnVote
+	}
+	return u.mapNotFound(u.reviewWriteRepo.Vote(dto.Id, customerId, dto.Vote == "like"))
+}
+
+// RemoveVote withdraws the vote of the customer
+func (u *ReviewUseCase) RemoveVote(ctx context.Context, customerId int64, id int64) error {
+	return u.mapNotFound(u.reviewWriteRepo.RemoveVote(id, customerId))
+}
+
+// GetAll lists the reviews of a site for moderation
+func (u *ReviewUseCase) GetAll(ctx context.Context, userId int64, dto review_dto.ReviewFilterDto) (common_dto.PaginatedDto[product_entity.ProductReviewEntity], error) {
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return common_dto.PaginatedDto[product_entity.ProductReviewEntity]{}, err
+	}
+
+	filter := product_repo_inter.ProductReviewFilter{
+		SiteId:    dto.SiteId,
+		ProductId: dto.ProductId,
+		Offset:    dto.Offset(),
+		Limit:     dto.Limit(),
+	}
+	if dto.Status != "" {
+		approved := dto.Status == review_dto.ReviewStatusApproved
+		filter.Approved = &approved
+	}
+
+	reviews, total, err := u.reviewReadRepo.FindAll(filter)
+	if err != nil {
+		return common_dto.PaginatedDto[product_entity.ProductReviewEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(reviews, total, dto.PaginationDto), nil
+}
+
+// Approve publishes a review and folds its rating into the product
+func (u *ReviewUseCase) Approve(ctx context.Context, userId int64, id int64) error {
+	if _, err := u.findSiteReview(userId, id); err != nil {
+		return err
+	}
+	return u.mapNotFound(u.reviewWriteRepo.Approve(id))
+}
+
+// Reject removes a review from the site, approved or not
+func (u *ReviewUseCase) Reject(ctx context.Context, userId int64, id int64) error {
+	if _, err := u.findSiteReview(userId, id); err != nil {
+		return err
+	}
+	return u.mapNotFound(u.reviewWriteRepo.Delete(id))
+}
+
+// PublicGetAll lists the approved reviews of a product
+func (u *ReviewUseCase) PublicGetAll(ctx context.Context, dto review_dto.PublicReviewFilterDto) (common_dto.PaginatedDto[product_entity.ProductReviewEntity], error) {
+	approved := true
+	reviews, total, err := u.reviewReadRepo.FindAll(product_repo_inter.ProductReviewFilter{
+		ProductId: dto.ProductId,
+		Approved:  &approved,
+		Offset:    dto.Offset(),
+		Limit:     dto.Limit(),
+	})
+	if err != nil {
+		return common_dto.PaginatedDto[product_entity.ProductReviewEntity]{}, err
+	}
+	return common_dto.NewPaginatedDto(reviews, total, dto.PaginationDto), nil
+}
+
+func (u *ReviewUseCase) findReview(id int64) (*product_entity.ProductReviewEntity, error) {
+	review, err := u.reviewReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrReviewNotFound
+	}
+	return review, err
+}
+
+func (u *ReviewUseCase) findOwnReview(customerId int64, id int64) (*product_entity.ProductReviewEntity, error) {
+	review, err := u.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.CustomerId != strconv.FormatInt(customerId, 10) {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+func (u *ReviewUseCase) findApprovedReview(id int64) (*product_entity.ProductReviewEntity, error) {
+	review, err := u.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if !review.Approved {
+		return nil, ErrReviewNotFound
+	}
+	return review, nil
+}
+
+func (u *ReviewUseCase) findSiteReview(userId int64, id int64) (*product_entity.ProductReviewEntity, error) {
+	review, err := u.findReview(id)
+	if err != nil {
+		return nil, err
+	}
+	siteId, _ := strconv.ParseInt(review.SiteId, 10, 64)
+	if err := u.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (u *ReviewUseCase) mapNotFound(err error) error {
+	if errors.Is(err, repositories.ErrNotFound) {
+		return ErrReviewNotFound
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package shipping_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"site_builder_backend/internal/application/dto/order/shipping_dto"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrShippingMethodNotFound = errors.New("shipping method not found")
+	ErrShippingCodeExists     = errors.New("a shipping method with this code already exists on the site")
+	ErrUnknownProvider        = errors.New("unknown courier provider")
+	ErrSiteAccessDenied       = site_repo_inter.ErrNotOwner
+	ErrCourierUnavailable     = errors.New("the selected courier does not deliver this order")
+)
+
+// Parcel is a basket on its way to an address. FreeSend is set when every product in it is sent for free.
+type Parcel struct {
+	Shipment courier_inter.Shipment
+	FreeSend bool
+}
+
+type ShippingUseCase struct {
+	siteReadRepo    site_repo_inter.SiteReadRepository
+	methodReadRepo  order_repo_inter.ShippingMethodReadRepository
+	methodWriteRepo order_repo_inter.ShippingMethodWriteRepository
+	providers       map[string]courier_inter.CourierProvider
+	l               *logger.ZapLogger
+}
+
+// NewShippingUseCase takes the courier providers by the name shipping methods refer to them with
+func NewShippingUseCase(siteReadRepo site_repo_inter.SiteReadRepository, methodReadRepo order_repo_inter.ShippingMethodReadRepository, methodWriteRepo order_repo_inter.ShippingMethodWriteRepository, providers map[string]courier_inter.CourierProvider, l *logger.ZapLogger) *ShippingUseCase {
+	return &ShippingUseCase{
+		siteReadRepo:    siteReadRepo,
+		methodReadRepo:  methodReadRepo,
+		methodWriteRepo: methodWriteRepo,
+		providers:       providers,
+		l:               l,
+	}
+}
+
+func (u *ShippingUseCase) Create(ctx context.Context, userId int64, dto shipping_dto.CreateShippingMethodDto) (*order_entity.ShippingMethodEntity, error) {
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return nil, err
+	}
+	entity := dto.ToShippingMethodEntity()
+	if err := u.validate(dto.SiteId, entity, 0); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	if err := u.methodWriteRepo.Create(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (u *ShippingUseCase) Update(ctx context.Context, userId int64, dto shipping_dto.UpdateShippingMethodDto) (*order_entity.ShippingMethodEntity, error) {
+	entity, err := u.findOwned(userId, dto.Id)
+	if err != nil {
+		return nil, err
+	}
+	dto.ApplyTo(entity)
+	siteId, _ := strconv.ParseInt(entity.SiteId, 10, 64)
+	if err := u.validate(siteId, entity, dto.Id); err != nil {
+		return nil, err
+	}
+
+	entity.UpdatedAt = time.Now()
+	if err := u.methodWriteRepo.Update(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (u *ShippingUseCase) Delete(ctx context.Context, userId int64, id int64) error {
+	if _, err := u.findOwned(userId, id); err != nil {
+		return err
+	}
+	return u.methodWriteRepo.Delete(id)
+}
+
+func (u *ShippingUseCase) GetAll(ctx context.Context, userId int64, dto shipping_dto.ShippingMethodFilterDto) ([]order_entity.ShippingMethodEntity, error) {
+	if err := u.siteReadRepo.CheckOwner(dto.SiteId, userId); err != nil {
+		return nil, err
+	}
+	return u.methodReadRepo.FindBySite(dto.SiteId, false)
+}
+
+// Options quotes every active shipping method of the site for the parcel. Couriers not delivering it are
+// left out, as are those whose provider fails, so one carrier being down does not block checkout.
+func (u *ShippingUseCase) Options(ctx context.Context, siteId int64, parcel Parcel) ([]shipping_dto.ShippingOptionDto, error) {
+	methods, err := u.methodReadRepo.FindBySite(siteId, true)
+	if err != nil {
+		return nil, err
+	}
+	options := make([]shipping_dto.ShippingOptionDto, 0, len(methods))
+	for i := range methods {
+		option, err := u.quote(ctx, &methods[i], parcel)
+		if errors.Is(err, ErrCourierUnavailable) {
+			continue
+		}
+		if err != nil {
+			u.l.Warn("shipping_use_case - ShippingUseCase - Options - %s: %v", methods[i].Code, err)
+			continue
+		}
+		options = append(options, *option)
+	}
+	return options, nil
+}
+
+// Quote prices the parcel with the active shipping method of the site having the code
+func (u *ShippingUseCase) Quote(ctx context.Context, siteId int64, code string, parcel Parcel) (*shipping_dto.ShippingOptionDto, error) {
+	methods, err := u.methodReadRepo.FindBySite(siteId, true)
+	if err != nil {
+		return nil, err
+	}
+	code = normalizeCode(code)
+	for i := range methods {
+		if methods[i].Code == code {
+			return u.quote(ctx, &methods[i], parcel)
+		}
+	}
+	return nil, ErrCourierUnavailable
+}
+
+// quote asks the provider even when shipping ends up free, it still decides whether the courier delivers
+func (u *ShippingUseCase) quote(ctx context.Context, method *order_entity.ShippingMethodEntity, parcel Parcel) (*shipping_dto.ShippingOptionDto, error) {
+	provider, ok := u.providers[method.Provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	quote, err := provider.Quote(ctx, method, parcel.Shipment)
+	if errors.Is(err, courier_inter.ErrUnavailable) {
+		return nil, ErrCourierUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	option := &shipping_dto.ShippingOptionDto{
+		Code:          method.Code,
+		Title:         method.Title,
+		Price:         quote.Price,
+		EstimatedDays: quote.EstimatedDays,
+	}
+	if parcel.FreeSend || (method.FreeOverAmount > 0 && parcel.Shipment.Value >= method.FreeOverAmount) {
+		option.Price = 0
+		option.FreeShipping = true
+	}
+	return option, nil
+}
+
+func (u *ShippingUseCase) validate(siteId int64, entity *order_entity.ShippingMethodEntity, excludeId int64) error {
+	entity.Code = normalizeCode(entity.Code)
+	if _, ok := u.providers[entity.Provider]; !ok {
+		return ErrUnknownProvider
+	}
+	exists, err := u.methodReadRepo.ExistsByCode(siteId, entity.Code, excludeId)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrShippingCodeExists
+	}
+	return nil
+}
+
+func (u *ShippingUseCase) findOwned(userId int64, id int64) (*order_entity.ShippingMethodEntity, error) {
+	entity, err := u.methodReadRepo.FindById(id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrShippingMethodNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	siteId, _ := strconv.ParseInt(entity.SiteId, 10, 64)
+	if err := u.siteReadRepo.CheckOwner(siteId, userId); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
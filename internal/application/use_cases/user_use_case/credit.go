@@ -0,0 +1,219 @@
+package user_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/user/credit_dto"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// CreditPaymentServiceName marks the payments of credit top-ups, their OrderId is the top-up id
+const CreditPaymentServiceName = "credit"
+
+var (
+	ErrUnitPriceNotFound = errors.New("unit price not found")
+	ErrTopUpDaysRequired = errors.New("this credit is priced per day, days are required")
+	ErrTopUpFree         = errors.New("credits priced at zero cannot be bought")
+	ErrTopUpDisabled     = errors.New("credit top-ups are not enabled")
+)
+
+type CreditUseCase struct {
+	creditReadRepo  user_repo_inter.CreditReadRepository
+	creditWriteRepo user_repo_inter.CreditWriteRepository
+	userReadRepo    user_repo_inter.UserReadRepository
+	paymentUseCase  *payment_use_case.PaymentUseCase
+	meteringUseCase *MeteringUseCase
+	paymentSiteId   int64
+	l               *logger.ZapLogger
+}
+
+// NewCreditUseCase takes the site whose gateways receive top-up payments. It registers itself with the
+// payment use case, which hands it the settled payments of top-ups.
+func NewCreditUseCase(creditReadRepo user_repo_inter.CreditReadRepository, creditWriteRepo user_repo_inter.CreditWriteRepository, userReadRepo user_repo_inter.UserReadRepository, paymentUseCase *payment_use_case.PaymentUseCase, meteringUseCase *MeteringUseCase, paymentSiteId int64, l *logger.ZapLogger) *CreditUseCase {
+	u := &CreditUseCase{
+		creditReadRepo:  creditReadRepo,
+		creditWriteRepo: creditWriteRepo,
+		userReadRepo:    userReadRepo,
+		paymentUseCase:  paymentUseCase,
+		meteringUseCase: meteringUseCase,
+		paymentSiteId:   paymentSiteId,
+		l:               l,
+	}
+	paymentUseCase.RegisterHandler(CreditPaymentServiceName, u)
+	return u
+}
+
+// UnitPrices lists what a unit of each credit costs
+func (u *CreditUseCase) UnitPrices(ctx context.Context) ([]user_entity.UnitPriceEntity, error) {
+	return u.creditReadRepo.FindUnitPrices()
+}
+
+// Balance returns what is left of the owner's credits
+func (u *CreditUseCase) Balance(ctx context.Context, userId int64) (*credit_dto.BalanceDto, error) {
+	user, err := u.userReadRepo.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	balance := &credit_dto.BalanceDto{
+		Sms:       user.SmsCredits,
+		Email:     user.EmailCredits,
+		StorageMb: user.StorageMbCredits,
+		Ai:        user.AiCredits,
+		AiImage:   user.AiImageCredits,
+	}
+	if !user.StorageMbCreditsExpireAt.IsZero() {
+		balance.StorageMbExpiresAt = &user.StorageMbCreditsExpireAt
+	}
+	return balance, nil
+}
+
+// Ledger lists the changes of the owner's credits, newest first
+func (u *CreditUseCase) Ledger(ctx context.Context, userId int64, dto credit_dto.LedgerFilterDto) (*common_dto.PaginatedDto[user_entity.CreditLedgerEntity], error) {
+	entries, total, err := u.creditReadRepo.FindLedger(user_repo_inter.CreditLedgerFilter{
+		UserId:     userId,
+		CreditType: dto.CreditType,
+		Offset:     dto.Offset(),
+		Limit:      dto.Limit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := common_dto.NewPaginatedDto(entries, total, dto.PaginationDto)
+	return &result, nil
+}
+
+// Quote prices a top-up. Credits priced per day cost their unit price for every unit and day.
+func (u *CreditUseCase) Quote(ctx context.Context, dto credit_dto.TopUpQuoteDto) (*credit_dto.TopUpQuoteResultDto, error) {
+	price, err := u.creditReadRepo.FindUnitPriceById(dto.UnitPriceId)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && !user_entity.IsCreditType(price.Name)) {
+		return nil, ErrUnitPriceNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &credit_dto.TopUpQuoteResultDto{
+		UnitPriceId: dto.UnitPriceId,
+		CreditType:  price.Name,
+		Quantity:    dto.Quantity,
+		UnitPrice:   discounted(price.Price, price.DiscountType, price.Discount),
+	}
+	quote.Amount = quote.UnitPrice * int64(dto.Quantity)
+	if price.HasDay {
+		if dto.Days < 1 {
+			return nil, ErrTopUpDaysRequired
+		}
+		quote.Days = dto.Days
+		quote.Amount *= int64(dto.Days)
+	}
+	if quote.Amount <= 0 {
+		return nil, ErrTopUpFree
+	}
+	return quote, nil
+}
+
+// TopUp stores a pending top-up with its payment and sends the payment to the gateway, the credits are
+// added once the payment is settled. An abandoned top-up stays pending until the payment reconciler
+// fails it.
+func (u *CreditUseCase) TopUp(ctx context.Context, userId int64, dto credit_dto.TopUpDto, clientIp string) (*credit_dto.TopUpResultDto, error) {
+	if u.paymentSiteId == 0 {
+		return nil, ErrTopUpDisabled
+	}
+	quote, err := u.Quote(ctx, dto.TopUpQuoteDto)
+	if err != nil {
+		return nil, err
+	}
+
+	topUp := &user_entity.CreditTopUpEntity{
+		UserId:      strconv.FormatInt(userId, 10),
+		UnitPriceId: strconv.FormatInt(quote.UnitPriceId, 10),
+		CreditType:  quote.CreditType,
+		Quantity:    quote.Quantity,
+		Days:        quote.Days,
+		Amount:      quote.Amount,
+		Status:      user_entity.CreditTopUpStatusPending,
+	}
+	payment := &payment_entity.PaymentEntity{
+		SiteId:            strconv.FormatInt(u.paymentSiteId, 10),
+		PaymentStatusEnum: payment_entity.PaymentStatusPending,
+		Amount:            quote.Amount,
+		ServiceName:       CreditPaymentServiceName,
+		ServiceAction:     quote.CreditType,
+		ReturnUrl:         dto.ReturnUrl,
+		ClientIp:          clientIp,
+		UserId:            topUp.UserId,
+		CustomerId:        "0",
+	}
+	if err := u.creditWriteRepo.CreateTopUp(topUp, payment); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("%d %s credits", quote.Quantity, quote.CreditType)
+	started, err := u.paymentUseCase.StartPayment(ctx, payment, dto.Gateway, description, clientIp)
+	if err != nil {
+		return nil, err
+	}
+	topUpId, _ := strconv.ParseInt(topUp.Id, 10, 64)
+	return &credit_dto.TopUpResultDto{
+		TopUpId:        topUpId,
+		Quote:          *quote,
+		PaymentId:      started.PaymentId,
+		TrackingNumber: started.TrackingNumber,
+		Redirect:       started.Redirect,
+	}, nil
+}
+
+// Paid credits the top-up of a settled payment. Storage bought for some days keeps the owner's storage
+// until the later of its current expiry and the end of those days.
+func (u *CreditUseCase) Paid(ctx context.Context, payment *payment_entity.PaymentEntity) error {
+	topUp, err := u.creditReadRepo.FindTopUpById(payment.OrderId)
+	if err != nil {
+		return err
+	}
+	if topUp.Status != user_entity.CreditTopUpStatusPending {
+		return nil
+	}
+
+	userId, _ := strconv.ParseInt(topUp.UserId, 10, 64)
+	var storageExpiresAt time.Time
+	if topUp.CreditType == user_entity.CreditStorageMb && topUp.Days > 0 {
+		user, err := u.userReadRepo.FindById(userId)
+		if err != nil {
+			return err
+		}
+		storageExpiresAt = time.Now().AddDate(0, 0, topUp.Days)
+		if user.StorageMbCreditsExpireAt.After(storageExpiresAt) {
+			storageExpiresAt = user.StorageMbCreditsExpireAt
+		}
+	}
+
+	// A conflict means a concurrent callback credited the top-up first
+	err = u.creditWriteRepo.ApplyTopUp(payment.OrderId, storageExpiresAt)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	u.meteringUseCase.Invalidate(ctx, userId)
+	return nil
+}
+
+// Expired fails the top-up of a failed payment
+func (u *CreditUseCase) Expired(ctx context.Context, payment *payment_entity.PaymentEntity) error {
+	err := u.creditWriteRepo.FailTopUp(payment.OrderId)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	return err
+}
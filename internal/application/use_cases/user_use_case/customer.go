@@ -0,0 +1,125 @@
+package user_use_case
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+
+	"site_builder_backend/internal/application/dto/user/customer_dto"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	_passwordIterations = 600_000
+	_passwordKeyLength  = 32
+	_passwordSaltLength = 16
+)
+
+var (
+	ErrCustomerSiteNotFound = errors.New("site not found")
+	ErrCustomerExists       = errors.New("a customer with this email is already registered")
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrCustomerInactive     = errors.New("the customer account is not active")
+)
+
+// _dummySalt is hashed against on unknown emails so a login takes as long whether the account exists or not
+var _dummySalt = make([]byte, _passwordSaltLength)
+
+// CustomerUseCase registers and signs in the storefront customers of a site
+type CustomerUseCase struct {
+	siteReadRepo      site_repo_inter.SiteReadRepository
+	customerReadRepo  user_repo_inter.CustomerReadRepository
+	customerWriteRepo user_repo_inter.CustomerWriteRepository
+	l                 *logger.ZapLogger
+}
+
+func NewCustomerUseCase(siteReadRepo site_repo_inter.SiteReadRepository, customerReadRepo user_repo_inter.CustomerReadRepository, customerWriteRepo user_repo_inter.CustomerWriteRepository, l *logger.ZapLogger) *CustomerUseCase {
+	return &CustomerUseCase{
+		siteReadRepo:      siteReadRepo,
+		customerReadRepo:  customerReadRepo,
+		customerWriteRepo: customerWriteRepo,
+		l:                 l,
+	}
+}
+
+// Register creates an active customer of the site with a salted PBKDF2 hash of the password
+func (u *CustomerUseCase) Register(ctx context.Context, dto customer_dto.RegisterCustomerDto) (*user_entity.CustomerEntity, error) {
+	_, err := u.siteReadRepo.FindById(dto.SiteId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrCustomerSiteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, _passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	hash, err := hashPassword(dto.Password, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	entity := dto.ToCustomerEntity()
+	entity.Salt = hex.EncodeToString(salt)
+	entity.Password = hex.EncodeToString(hash)
+	err = u.customerWriteRepo.Create(entity)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrCustomerExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Login returns the customer of the site the email and password belong to. Unknown emails and wrong
+// passwords answer the same ErrInvalidCredentials.
+func (u *CustomerUseCase) Login(ctx context.Context, dto customer_dto.LoginCustomerDto) (*user_entity.CustomerEntity, error) {
+	entity, err := u.customerReadRepo.FindByEmail(dto.SiteId, customer_dto.NormalizeEmail(dto.Email))
+	if errors.Is(err, repositories.ErrNotFound) {
+		_, _ = hashPassword(dto.Password, _dummySalt)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkPassword(entity, dto.Password) {
+		return nil, ErrInvalidCredentials
+	}
+	if entity.IsActive != customer_dto.CustomerStatusActive {
+		return nil, ErrCustomerInactive
+	}
+	return entity, nil
+}
+
+func hashPassword(password string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, password, salt, _passwordIterations, _passwordKeyLength)
+}
+
+// checkPassword compares in constant time, malformed stored credentials never match
+func checkPassword(entity *user_entity.CustomerEntity, password string) bool {
+	salt, err := hex.DecodeString(entity.Salt)
+	if err != nil {
+		return false
+	}
+	stored, err := hex.DecodeString(entity.Password)
+	if err != nil {
+		return false
+	}
+	hash, err := hashPassword(password, salt)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hash, stored) == 1
+}
@@ -0,0 +1,120 @@
+package user_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"site_builder_backend/internal/application/dto/user/customer_dto"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+const testSiteId = 3
+
+type siteReadRepo struct{}
+
+func (siteReadRepo) FindById(id int64) (*site_entity.SiteEntity, error) {
+	if id != testSiteId {
+		return nil, repositories.ErrNotFound
+	}
+	return &site_entity.SiteEntity{Id: strconv.Itoa(testSiteId)}, nil
+}
+
+func (siteReadRepo) CheckOwner(siteId int64, userId int64) error {
+	return nil
+}
+
+// customerRepo keeps the customers in memory with the unique email of the table
+type customerRepo struct {
+	customers []user_entity.CustomerEntity
+}
+
+func (r *customerRepo) FindByEmail(siteId int64, email string) (*user_entity.CustomerEntity, error) {
+	for _, customer := range r.customers {
+		if customer.SiteId == strconv.FormatInt(siteId, 10) && customer.Email == email {
+			found := customer
+			return &found, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (r *customerRepo) Create(entity *user_entity.CustomerEntity) error {
+	for _, customer := range r.customers {
+		if customer.Email == entity.Email {
+			return repositories.ErrConflict
+		}
+	}
+	entity.Id = strconv.Itoa(len(r.customers) + 1)
+	r.customers = append(r.customers, *entity)
+	return nil
+}
+
+func newCustomerUseCase() (*CustomerUseCase, *customerRepo) {
+	repo := &customerRepo{}
+	return NewCustomerUseCase(siteReadRepo{}, repo, repo, logger.NewLoggerFromConfig("error", "json", "stdout")), repo
+}
+
+func TestCustomerRegisterAndLogin(t *testing.T) {
+	useCase, repo := newCustomerUseCase()
+	ctx := context.Background()
+
+	registered, err := useCase.Register(ctx, customer_dto.RegisterCustomerDto{
+		SiteId:   testSiteId,
+		Email:    " Buyer@Example.com ",
+		Password: "correct horse",
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if registered.Email != "buyer@example.com" || registered.IsActive != customer_dto.CustomerStatusActive {
+		t.Fatalf("registered = %+v", registered)
+	}
+	if stored := repo.customers[0]; stored.Password == "correct horse" || stored.Salt == "" {
+		t.Fatalf("password stored in clear or without salt: %+v", stored)
+	}
+
+	customer, err := useCase.Login(ctx, customer_dto.LoginCustomerDto{SiteId: testSiteId, Email: "BUYER@example.com", Password: "correct horse"})
+	if err != nil || customer.Id != registered.Id {
+		t.Fatalf("Login = %+v, %v", customer, err)
+	}
+
+	logins := []customer_dto.LoginCustomerDto{
+		{SiteId: testSiteId, Email: "buyer@example.com", Password: "wrong horse"},
+		{SiteId: testSiteId, Email: "nobody@example.com", Password: "correct horse"},
+		{SiteId: testSiteId + 1, Email: "buyer@example.com", Password: "correct horse"},
+	}
+	for _, login := range logins {
+		if _, err := useCase.Login(ctx, login); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login %+v: err = %v, want ErrInvalidCredentials", login, err)
+		}
+	}
+}
+
+func TestCustomerRegisterRejectsDuplicatesAndUnknownSites(t *testing.T) {
+	useCase, repo := newCustomerUseCase()
+	ctx := context.Background()
+
+	dto := customer_dto.RegisterCustomerDto{SiteId: testSiteId, Email: "buyer@example.com", Password: "correct horse"}
+	if _, err := useCase.Register(ctx, dto); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := useCase.Register(ctx, dto); !errors.Is(err, ErrCustomerExists) {
+		t.Fatalf("second Register: err = %v, want ErrCustomerExists", err)
+	}
+	dto.SiteId = testSiteId + 1
+	dto.Email = "other@example.com"
+	if _, err := useCase.Register(ctx, dto); !errors.Is(err, ErrCustomerSiteNotFound) {
+		t.Fatalf("Register on a missing site: err = %v, want ErrCustomerSiteNotFound", err)
+	}
+
+	repo.customers[0].IsActive = "inactive"
+	_, err := useCase.Login(ctx, customer_dto.LoginCustomerDto{SiteId: testSiteId, Email: "buyer@example.com", Password: "correct horse"})
+	if !errors.Is(err, ErrCustomerInactive) {
+		t.Fatalf("Login of an inactive customer: err = %v, want ErrCustomerInactive", err)
+	}
+}
@@ -0,0 +1,238 @@
+package user_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/user/email_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/cache/credit_meter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/internal/interfaces/notification/email_template_inter"
+	"site_builder_backend/internal/interfaces/notification/mailer_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// ActionEmailSend is the action of usages paying for emails
+const ActionEmailSend = "email.send"
+
+var ErrEmailInvalid = errors.New("email needs a recipient and a template, an event or a subject with a body")
+
+type EmailUseCase struct {
+	emailReadRepo   user_repo_inter.EmailReadRepository
+	emailWriteRepo  user_repo_inter.EmailWriteRepository
+	userReadRepo    user_repo_inter.UserReadRepository
+	mailer          mailer_inter.Mailer
+	renderer        email_template_inter.EmailRenderer
+	meteringUseCase *MeteringUseCase
+	templateUseCase *notification_use_case.TemplateUseCase
+	platformSmtp    mailer_inter.SmtpAccount
+	maxAttempts     int
+	l               *logger.ZapLogger
+}
+
+// NewEmailUseCase sends through platformSmtp unless the owner of a message has an SMTP of their own. It
+// gives up on a message after maxAttempts failed attempts.
+func NewEmailUseCase(emailReadRepo user_repo_inter.EmailReadRepository, emailWriteRepo user_repo_inter.EmailWriteRepository, userReadRepo user_repo_inter.UserReadRepository, mailer mailer_inter.Mailer, renderer email_template_inter.EmailRenderer, meteringUseCase *MeteringUseCase, templateUseCase *notification_use_case.TemplateUseCase, platformSmtp mailer_inter.SmtpAccount, maxAttempts int, l *logger.ZapLogger) *EmailUseCase {
+	return &EmailUseCase{
+		emailReadRepo:   emailReadRepo,
+		emailWriteRepo:  emailWriteRepo,
+		userReadRepo:    userReadRepo,
+		mailer:          mailer,
+		renderer:        renderer,
+		meteringUseCase: meteringUseCase,
+		templateUseCase: templateUseCase,
+		platformSmtp:    platformSmtp,
+		maxAttempts:     maxAttempts,
+		l:               l,
+	}
+}
+
+// Send delivers a queued email and records how it went. A message of an owner goes through the owner's
+// SMTP when UseCustomEmailSmtp is set, otherwise through the platform's, which reserves one email credit
+// before sending and pays it once the server accepted the message. An error is returned only when the
+// message is worth another try, after maxAttempts it is failed instead.
+func (u *EmailUseCase) Send(ctx context.Context, dto email_dto.EmailDto) error {
+	if dto.To == "" || (dto.Template == "" && dto.Event == "" && (dto.Subject == "" || (dto.Html == "" && dto.Text == ""))) {
+		return ErrEmailInvalid
+	}
+	if dto.MessageId == "" {
+		id, err := reservationId()
+		if err != nil {
+			return err
+		}
+		dto.MessageId = id
+	}
+
+	mail := mailer_inter.Mail{MessageId: dto.MessageId, To: dto.To, Subject: dto.Subject, Html: dto.Html, Text: dto.Text}
+	var renderErr error
+	switch {
+	case dto.Event != "":
+		rendered, err := u.templateUseCase.Render(ctx, dto.SiteId, site_entity.NotificationChannelEmail, dto.Event, dto.Locale, dto.Data)
+		if errors.Is(err, notification_use_case.ErrUnknownEvent) {
+			renderErr = err
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(rendered.Missing) > 0 {
+			u.l.Warn("user_use_case - EmailUseCase - Send - message %s: %s sent without %v", dto.MessageId, dto.Event, rendered.Missing)
+		}
+		mail.Subject, mail.Html, mail.Text = rendered.Subject, rendered.Html, rendered.Body
+	case dto.Template != "":
+		rendered, err := u.renderer.Render(dto.Template, dto.Data)
+		if err != nil {
+			renderErr = err
+		} else {
+			mail.Subject, mail.Html, mail.Text = rendered.Subject, rendered.Html, rendered.Text
+		}
+	}
+
+	message, err := u.message(dto, mail.Subject)
+	if err != nil {
+		return err
+	}
+	if message.Status != user_entity.EmailStatusQueued {
+		// Redelivered after it was sent or given up on
+		return nil
+	}
+	id, _ := strconv.ParseInt(message.Id, 10, 64)
+	if renderErr != nil {
+		return u.fail(id, "", renderErr.Error(), message.Attempts)
+	}
+
+	account, smtp, err := u.account(dto.UserId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return u.fail(id, "", "owner not found", message.Attempts)
+	}
+	if err != nil {
+		return err
+	}
+
+	var reservation *credit_meter_inter.Reservation
+	if dto.UserId != 0 && smtp == user_entity.EmailSmtpPlatform {
+		reservation, err = u.meteringUseCase.Reserve(ctx, dto.UserId, user_entity.CreditEmail, 1, ActionEmailSend)
+		if errors.Is(err, ErrInsufficientCredits) {
+			return u.fail(id, smtp, err.Error(), message.Attempts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	attempts := message.Attempts + 1
+	if err := u.mailer.Send(ctx, account, mail); err != nil {
+		if reservation != nil {
+			if err := u.meteringUseCase.Release(ctx, reservation.Id); err != nil {
+				u.l.Error("user_use_case - EmailUseCase - Send - message %s: %v", dto.MessageId, err)
+			}
+		}
+		if errors.Is(err, mailer_inter.ErrRejected) || attempts >= u.maxAttempts {
+			u.l.Warn("user_use_case - EmailUseCase - Send - message %s: giving up after %d attempts: %v", dto.MessageId, attempts, err)
+			return u.fail(id, smtp, err.Error(), attempts)
+		}
+		if err := u.emailWriteRepo.MarkAttempt(id, smtp, err.Error(), attempts); err != nil && !errors.Is(err, repositories.ErrConflict) {
+			return err
+		}
+		return err
+	}
+
+	// The message is out, failures from here on are logged rather than retried so it is not sent twice
+	if reservation != nil {
+		if err := u.meteringUseCase.Commit(ctx, reservation.Id); err != nil {
+			u.l.Error("user_use_case - EmailUseCase - Send - message %s - Commit: %v", dto.MessageId, err)
+		}
+	}
+	err = u.emailWriteRepo.MarkSent(id, smtp, attempts, time.Now())
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		u.l.Error("user_use_case - EmailUseCase - Send - message %s - MarkSent: %v", dto.MessageId, err)
+	}
+	return nil
+}
+
+// Messages lists the owner's emails with their delivery status, newest first
+func (u *EmailUseCase) Messages(ctx context.Context, userId int64, dto email_dto.MessageFilterDto) (*common_dto.PaginatedDto[user_entity.EmailMessageEntity], error) {
+	messages, total, err := u.emailReadRepo.FindMessages(user_repo_inter.EmailMessageFilter{
+		UserId: userId,
+		Status: dto.Status,
+		Offset: dto.Offset(),
+		Limit:  dto.Limit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := common_dto.NewPaginatedDto(messages, total, dto.PaginationDto)
+	return &result, nil
+}
+
+// account picks the SMTP the owner's mail goes through, the platform's for platform mail
+func (u *EmailUseCase) account(userId int64) (mailer_inter.SmtpAccount, string, error) {
+	if userId == 0 {
+		return u.platformSmtp, user_entity.EmailSmtpPlatform, nil
+	}
+	user, err := u.userReadRepo.FindById(userId)
+	if err != nil {
+		return mailer_inter.SmtpAccount{}, "", err
+	}
+	if user.UseCustomEmailSmtp != "true" || user.SmtpHost == "" {
+		return u.platformSmtp, user_entity.EmailSmtpPlatform, nil
+	}
+	from := user.SmtpSenderEmail
+	if from == "" {
+		from = user.SmtpUsername
+	}
+	return mailer_inter.SmtpAccount{
+		Host:      user.SmtpHost,
+		Port:      user.SmtpPort,
+		Username:  user.SmtpUsername,
+		Password:  user.SmtpPassword,
+		EnableSsl: user.SmtpEnableSsl,
+		From:      from,
+		Public:    true,
+	}, user_entity.EmailSmtpCustom, nil
+}
+
+// message returns the stored message of the dto, storing it as queued the first time it is seen
+func (u *EmailUseCase) message(dto email_dto.EmailDto, subject string) (*user_entity.EmailMessageEntity, error) {
+	message, err := u.emailReadRepo.FindByMessageId(dto.MessageId)
+	if err == nil || !errors.Is(err, repositories.ErrNotFound) {
+		return message, err
+	}
+
+	template := dto.Template
+	if dto.Event != "" {
+		template = dto.Event
+	}
+	message = &user_entity.EmailMessageEntity{
+		MessageId: dto.MessageId,
+		UserId:    strconv.FormatInt(dto.UserId, 10),
+		Recipient: dto.To,
+		Subject:   subject,
+		Template:  template,
+		Status:    user_entity.EmailStatusQueued,
+	}
+	err = u.emailWriteRepo.Create(message)
+	if errors.Is(err, repositories.ErrConflict) {
+		// A concurrent delivery of the same message stored it first
+		return u.emailReadRepo.FindByMessageId(dto.MessageId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (u *EmailUseCase) fail(id int64, smtp string, reason string, attempts int) error {
+	err := u.emailWriteRepo.MarkFailed(id, smtp, reason, attempts)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,208 @@
+package user_use_case
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/user/credit_dto"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/cache/credit_meter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+var (
+	ErrInsufficientCredits = errors.New("insufficient credits")
+	ErrReservationNotFound = errors.New("credit reservation not found, it was committed, released or timed out")
+	ErrInvalidMeterAmount  = errors.New("metered amount must be positive")
+)
+
+// ActionStorageFree is the action of usages giving storage back
+const ActionStorageFree = "storage.free"
+
+// MeteringUseCase reserves credits before a billable action and commits them once the action is done, or
+// releases them when it failed. Reservations take from a counter cached per user and credit, so
+// concurrent actions cannot spend the same credits. Committing records the usage and takes it from the
+// credits in the database.
+type MeteringUseCase struct {
+	meter             credit_meter_inter.CreditMeter
+	meteringReadRepo  user_repo_inter.MeteringReadRepository
+	meteringWriteRepo user_repo_inter.MeteringWriteRepository
+	reservationTTL    time.Duration
+	counterTTL        time.Duration
+	l                 *logger.ZapLogger
+}
+
+func NewMeteringUseCase(meter credit_meter_inter.CreditMeter, meteringReadRepo user_repo_inter.MeteringReadRepository, meteringWriteRepo user_repo_inter.MeteringWriteRepository, reservationTTL time.Duration, counterTTL time.Duration, l *logger.ZapLogger) *MeteringUseCase {
+	return &MeteringUseCase{
+		meter:             meter,
+		meteringReadRepo:  meteringReadRepo,
+		meteringWriteRepo: meteringWriteRepo,
+		reservationTTL:    reservationTTL,
+		counterTTL:        counterTTL,
+		l:                 l,
+	}
+}
+
+// Reserve holds amount of the credit aside for the action, storage in KB. It fails with
+// ErrInsufficientCredits when the user has less left.
+func (u *MeteringUseCase) Reserve(ctx context.Context, userId int64, creditType string, amount int64, action string) (*credit_meter_inter.Reservation, error) {
+	if !user_entity.IsCreditType(creditType) {
+		return nil, fmt.Errorf("user_use_case - MeteringUseCase - Reserve: unknown credit type %s", creditType)
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidMeterAmount
+	}
+	id, err := reservationId()
+	if err != nil {
+		return nil, err
+	}
+	reservation := credit_meter_inter.Reservation{
+		Id:         id,
+		UserId:     userId,
+		CreditType: creditType,
+		Amount:     amount,
+		Action:     action,
+	}
+
+	reserved, err := u.meter.Reserve(ctx, reservation, u.reservationTTL)
+	if errors.Is(err, credit_meter_inter.ErrNotLoaded) {
+		var available int64
+		available, err = u.meteringReadRepo.Available(userId, creditType, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if err := u.meter.Load(ctx, userId, creditType, available, u.counterTTL); err != nil {
+			return nil, err
+		}
+		reserved, err = u.meter.Reserve(ctx, reservation, u.reservationTTL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		return nil, fmt.Errorf("%w for %s", ErrInsufficientCredits, creditType)
+	}
+	return &reservation, nil
+}
+
+// Commit records the reserved credits as used. When the database fails the reservation is kept, so the
+// commit can be retried.
+func (u *MeteringUseCase) Commit(ctx context.Context, reservationId string) error {
+	reservation, err := u.meter.Take(ctx, reservationId)
+	if errors.Is(err, credit_meter_inter.ErrReservationNotFound) {
+		return ErrReservationNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	err = u.meteringWriteRepo.Consume(&user_entity.CreditUsageEntity{
+		UserId:        strconv.FormatInt(reservation.UserId, 10),
+		CreditType:    reservation.CreditType,
+		Amount:        reservation.Amount,
+		Action:        reservation.Action,
+		ReservationId: reservation.Id,
+	})
+	if errors.Is(err, repositories.ErrExhausted) {
+		// The counter held more than the database, it is loaded again on the next reservation
+		u.settle(ctx, reservation)
+		if err := u.meter.Invalidate(ctx, reservation.UserId, []string{reservation.CreditType}); err != nil {
+			u.l.Error("user_use_case - MeteringUseCase - Commit - reservation %s: %v", reservationId, err)
+		}
+		return fmt.Errorf("%w for %s", ErrInsufficientCredits, reservation.CreditType)
+	}
+	if err != nil {
+		if err := u.meter.Restore(ctx, *reservation, u.reservationTTL); err != nil {
+			u.l.Error("user_use_case - MeteringUseCase - Commit - reservation %s: %v", reservationId, err)
+		}
+		return err
+	}
+	u.settle(ctx, reservation)
+	return nil
+}
+
+// settle drops the committed reservation from the pending ones. A failure is logged only, the reservation
+// then keeps its credits out of reloaded counters until it times out.
+func (u *MeteringUseCase) settle(ctx context.Context, reservation *credit_meter_inter.Reservation) {
+	if err := u.meter.Settle(ctx, *reservation); err != nil {
+		u.l.Error("user_use_case - MeteringUseCase - settle - reservation %s: %v", reservation.Id, err)
+	}
+}
+
+// Release gives the reserved credits back, for actions that failed. Releasing a reservation that is gone
+// already is not an error.
+func (u *MeteringUseCase) Release(ctx context.Context, reservationId string) error {
+	_, err := u.meter.Release(ctx, reservationId)
+	if errors.Is(err, credit_meter_inter.ErrReservationNotFound) {
+		return nil
+	}
+	return err
+}
+
+// FreeStorage gives kb of used storage back to the user, once the stored files are deleted
+func (u *MeteringUseCase) FreeStorage(ctx context.Context, userId int64, kb int64) error {
+	if kb <= 0 {
+		return ErrInvalidMeterAmount
+	}
+	id, err := reservationId()
+	if err != nil {
+		return err
+	}
+	err = u.meteringWriteRepo.Consume(&user_entity.CreditUsageEntity{
+		UserId:        strconv.FormatInt(userId, 10),
+		CreditType:    user_entity.CreditStorageMb,
+		Amount:        -kb,
+		Action:        ActionStorageFree,
+		ReservationId: id,
+	})
+	if err != nil {
+		return err
+	}
+	return u.meter.Credit(ctx, userId, user_entity.CreditStorageMb, kb)
+}
+
+// Invalidate drops the cached counters of the user, after its credits changed in the database. They are
+// loaded again less what the open reservations hold.
+func (u *MeteringUseCase) Invalidate(ctx context.Context, userId int64) {
+	creditTypes := []string{
+		user_entity.CreditSms,
+		user_entity.CreditEmail,
+		user_entity.CreditStorageMb,
+		user_entity.CreditAi,
+		user_entity.CreditAiImage,
+	}
+	if err := u.meter.Invalidate(ctx, userId, creditTypes); err != nil {
+		u.l.Error("user_use_case - MeteringUseCase - Invalidate - user %d: %v", userId, err)
+	}
+}
+
+// Usage lists the committed usages of the owner, newest first
+func (u *MeteringUseCase) Usage(ctx context.Context, userId int64, dto credit_dto.UsageFilterDto) (*common_dto.PaginatedDto[user_entity.CreditUsageEntity], error) {
+	usages, total, err := u.meteringReadRepo.FindUsages(user_repo_inter.CreditUsageFilter{
+		UserId:     userId,
+		CreditType: dto.CreditType,
+		Offset:     dto.Offset(),
+		Limit:      dto.Limit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := common_dto.NewPaginatedDto(usages, total, dto.PaginationDto)
+	return &result, nil
+}
+
+func reservationId() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,441 @@
+package user_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/dto/user/plan_dto"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// PlanPaymentServiceName marks the payments of plan purchases, their OrderId is the purchase id
+const PlanPaymentServiceName = "plan"
+
+// PlanBatchSize is how many users one run of the expiry or the reminder job looks at
+const PlanBatchSize = 100
+
+var (
+	ErrPlanNotFound        = errors.New("plan not found")
+	ErrPlanDowngrade       = errors.New("plan costs less than what is left of the current one, it can be bought once the current plan expires")
+	ErrPlanFree            = errors.New("plan is free and cannot be purchased")
+	ErrPlanPaymentDisabled = errors.New("plan purchases are not enabled")
+	ErrPlanPurchasePending = errors.New("a plan purchase is waiting for its payment, finish it or try again once it expires")
+)
+
+type PlanUseCase struct {
+	planReadRepo    user_repo_inter.PlanReadRepository
+	planWriteRepo   user_repo_inter.PlanWriteRepository
+	userReadRepo    user_repo_inter.UserReadRepository
+	paymentUseCase  *payment_use_case.PaymentUseCase
+	meteringUseCase *MeteringUseCase
+	eventPublisher  event_publisher_inter.EventPublisher
+	paymentSiteId   int64
+	l               *logger.ZapLogger
+}
+
+// NewPlanUseCase takes the site whose gateways receive plan payments. It registers itself with the payment
+// use case, which hands it the settled payments of plan purchases.
+func NewPlanUseCase(planReadRepo user_repo_inter.PlanReadRepository, planWriteRepo user_repo_inter.PlanWriteRepository, userReadRepo user_repo_inter.UserReadRepository, paymentUseCase *payment_use_case.PaymentUseCase, meteringUseCase *MeteringUseCase, eventPublisher event_publisher_inter.EventPublisher, paymentSiteId int64, l *logger.ZapLogger) *PlanUseCase {
+	u := &PlanUseCase{
+		planReadRepo:    planReadRepo,
+		planWriteRepo:   planWriteRepo,
+		userReadRepo:    userReadRepo,
+		paymentUseCase:  paymentUseCase,
+		meteringUseCase: meteringUseCase,
+		eventPublisher:  eventPublisher,
+		paymentSiteId:   paymentSiteId,
+		l:               l,
+	}
+	paymentUseCase.RegisterHandler(PlanPaymentServiceName, u)
+	return u
+}
+
+// Plans lists the plans offered to site owners, cheapest first
+func (u *PlanUseCase) Plans(ctx context.Context) ([]user_entity.PlanEntity, error) {
+	return u.planReadRepo.FindVisible()
+}
+
+// Subscription returns the owner's current plan
+func (u *PlanUseCase) Subscription(ctx context.Context, userId int64) (*plan_dto.SubscriptionDto, error) {
+	user, err := u.userReadRepo.FindById(userId)
+	if err != nil {
+		return nil, err
+	}
+	result := &plan_dto.SubscriptionDto{}
+	if user.PlanId == "" {
+		return result, nil
+	}
+	planId, _ := strconv.ParseInt(user.PlanId, 10, 64)
+	plan, err := u.planReadRepo.FindById(planId)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	result.Plan = plan
+	result.StartedAt = &user.PlanStartedAt
+	result.ExpiresAt = &user.PlanExpiredAt
+	return result, nil
+}
+
+// Quote tells the owner what buying the plan costs now and whether it is a purchase, renewal or upgrade
+func (u *PlanUseCase) Quote(ctx context.Context, userId int64, planId int64) (*plan_dto.PlanQuoteDto, error) {
+	user, plan, err := u.userAndPlan(userId, planId)
+	if err != nil {
+		return nil, err
+	}
+	return u.quote(user, plan, time.Now())
+}
+
+// Purchase buys, renews or upgrades to the plan. It stores a pending purchase with its payment and sends
+// the payment to the gateway, the plan is activated once the payment is settled. An owner has one pending
+// purchase at a time, so the credit of an upgrade is never given to two purchases. An abandoned purchase
+// stays pending until the payment reconciler fails it.
+func (u *PlanUseCase) Purchase(ctx context.Context, userId int64, dto plan_dto.PurchasePlanDto, clientIp string) (*plan_dto.PurchasePlanResultDto, error) {
+	if u.paymentSiteId == 0 {
+		return nil, ErrPlanPaymentDisabled
+	}
+	user, plan, err := u.userAndPlan(userId, dto.PlanId)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := u.quote(user, plan, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	purchase := &user_entity.PlanPurchaseEntity{
+		UserId: user.Id,
+		PlanId: plan.Id,
+		Action: quote.Action,
+		Amount: quote.Amount,
+		Credit: quote.Credit,
+		Status: user_entity.PlanPurchaseStatusPending,
+	}
+	payment := &payment_entity.PaymentEntity{
+		SiteId:            strconv.FormatInt(u.paymentSiteId, 10),
+		PaymentStatusEnum: payment_entity.PaymentStatusPending,
+		Amount:            quote.Amount,
+		ServiceName:       PlanPaymentServiceName,
+		ServiceAction:     quote.Action,
+		ReturnUrl:         dto.ReturnUrl,
+		ClientIp:          clientIp,
+		UserId:            user.Id,
+		CustomerId:        "0",
+	}
+	err = u.planWriteRepo.CreatePurchase(purchase, payment)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil, ErrPlanPurchasePending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	started, err := u.paymentUseCase.StartPayment(ctx, payment, dto.Gateway, "Plan "+plan.Name, clientIp)
+	if err != nil {
+		return nil, err
+	}
+	purchaseId, _ := strconv.ParseInt(purchase.Id, 10, 64)
+	return &plan_dto.PurchasePlanResultDto{
+		PurchaseId:     purchaseId,
+		Quote:          *quote,
+		PaymentId:      started.PaymentId,
+		TrackingNumber: started.TrackingNumber,
+		Redirect:       started.Redirect,
+	}, nil
+}
+
+// Paid activates the purchase of a settled payment. A renewal of a running plan extends it, purchases and
+// upgrades start now. The plan's credits are added to the owner's and the roles of the previous plan are
+// swapped for the roles of the new one.
+func (u *PlanUseCase) Paid(ctx context.Context, payment *payment_entity.PaymentEntity) error {
+	purchase, err := u.planReadRepo.FindPurchaseById(payment.OrderId)
+	if err != nil {
+		return err
+	}
+	if purchase.Status != user_entity.PlanPurchaseStatusPending {
+		return nil
+	}
+	userId, _ := strconv.ParseInt(purchase.UserId, 10, 64)
+	planId, _ := strconv.ParseInt(purchase.PlanId, 10, 64)
+	user, err := u.userReadRepo.FindById(userId)
+	if err != nil {
+		return err
+	}
+	plan, err := u.planReadRepo.FindById(planId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	startsAt, from := now, now
+	running := user.PlanId == plan.Id && user.PlanExpiredAt.After(now)
+	if purchase.Action == user_entity.PlanActionRenew && running {
+		startsAt, from = user.PlanStartedAt, user.PlanExpiredAt
+	}
+	activation := user_repo_inter.PlanActivation{
+		UserId:         userId,
+		PlanId:         planId,
+		StartsAt:       startsAt,
+		PeriodStartsAt: from,
+		ExpiresAt:      from.AddDate(0, plan.Duration, 0),
+		SmsCredits:     plan.SmsCredits,
+		EmailCredits:   plan.EmailCredits,
+		AiCredits:      plan.AiCredits,
+		AiImageCredits: plan.AiImageCredits,
+		StorageMb:      plan.StorageMbCredits,
+		GrantRoleIds:   roleIds(plan.Roles),
+	}
+	activation.PurchaseId, _ = strconv.ParseInt(purchase.Id, 10, 64)
+	if user.PlanId != "" && user.PlanId != plan.Id {
+		previousId, _ := strconv.ParseInt(user.PlanId, 10, 64)
+		previous, err := u.planReadRepo.FindById(previousId)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		if previous != nil {
+			activation.RevokeRoleIds = without(roleIds(previous.Roles), activation.GrantRoleIds)
+		}
+	}
+
+	// A conflict means a concurrent callback activated the purchase first
+	err = u.planWriteRepo.Activate(activation)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	u.meteringUseCase.Invalidate(ctx, activation.UserId)
+	u.publish(ctx, event_dto.RoutingKeyPlanActivated, user, plan, purchase.Action, activation.ExpiresAt)
+	return nil
+}
+
+// Expired fails the purchase of a failed payment
+func (u *PlanUseCase) Expired(ctx context.Context, payment *payment_entity.PaymentEntity) error {
+	err := u.planWriteRepo.FailPurchase(payment.OrderId)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	return err
+}
+
+// ExpirePlans downgrades owners whose plan ran out before now, taking the plan and its roles from them.
+// Credits they were granted are kept. It returns how many plans expired.
+func (u *PlanUseCase) ExpirePlans(ctx context.Context, now time.Time) (int, error) {
+	users, err := u.planReadRepo.FindExpiredUsers(now, PlanBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	plans := make(map[string]*user_entity.PlanEntity)
+	expired := 0
+	for i := range users {
+		if ctx.Err() != nil {
+			break
+		}
+		user := &users[i]
+		plan, err := u.cachedPlan(plans, user.PlanId)
+		if err != nil {
+			u.l.Error("user_use_case - PlanUseCase - ExpirePlans - plan %s: %v", user.PlanId, err)
+			continue
+		}
+		userId, _ := strconv.ParseInt(user.Id, 10, 64)
+		planId, _ := strconv.ParseInt(user.PlanId, 10, 64)
+		// A conflict means the owner renewed meanwhile, other errors are logged by the repository
+		if err := u.planWriteRepo.ExpirePlan(userId, planId, roleIds(plan.Roles), now); err != nil {
+			continue
+		}
+		expired++
+		u.meteringUseCase.Invalidate(ctx, userId)
+		u.publish(ctx, event_dto.RoutingKeyPlanExpired, user, plan, "", user.PlanExpiredAt)
+	}
+	return expired, nil
+}
+
+// RemindExpiring lets owners know their plan runs out within the next period, once per subscription
+// period. It returns how many owners were reminded.
+func (u *PlanUseCase) RemindExpiring(ctx context.Context, now time.Time, within time.Duration) (int, error) {
+	users, err := u.planReadRepo.FindExpiringUsers(now, now.Add(within), PlanBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	plans := make(map[string]*user_entity.PlanEntity)
+	reminded := 0
+	for i := range users {
+		if ctx.Err() != nil {
+			break
+		}
+		user := &users[i]
+		plan, err := u.cachedPlan(plans, user.PlanId)
+		if err != nil {
+			u.l.Error("user_use_case - PlanUseCase - RemindExpiring - plan %s: %v", user.PlanId, err)
+			continue
+		}
+		userId, _ := strconv.ParseInt(user.Id, 10, 64)
+		if err := u.planWriteRepo.MarkReminded(userId, now); err != nil {
+			continue
+		}
+		reminded++
+		u.publish(ctx, event_dto.RoutingKeyPlanExpiring, user, plan, "", user.PlanExpiredAt)
+	}
+	return reminded, nil
+}
+
+func (u *PlanUseCase) userAndPlan(userId int64, planId int64) (*user_entity.UserEntity, *user_entity.PlanEntity, error) {
+	user, err := u.userReadRepo.FindById(userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan, err := u.planReadRepo.FindById(planId)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && plan.ShowStatus != user_entity.PlanShowStatusVisible) {
+		return nil, nil, ErrPlanNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, plan, nil
+}
+
+// quote prices the plan for the owner. Switching from a running plan is an upgrade, credited with the
+// part of the current plan's price that was not used yet. Switching to a plan costing less than that
+// credit is refused.
+func (u *PlanUseCase) quote(user *user_entity.UserEntity, plan *user_entity.PlanEntity, now time.Time) (*plan_dto.PlanQuoteDto, error) {
+	planId, _ := strconv.ParseInt(plan.Id, 10, 64)
+	quote := &plan_dto.PlanQuoteDto{PlanId: planId, Action: user_entity.PlanActionPurchase, Price: NetPrice(plan)}
+	if quote.Price <= 0 {
+		return nil, ErrPlanFree
+	}
+
+	running := user.PlanId != "" && user.PlanExpiredAt.After(now)
+	switch {
+	case running && user.PlanId == plan.Id:
+		quote.Action = user_entity.PlanActionRenew
+	case running:
+		credit, err := u.unusedCredit(user, now)
+		if err != nil {
+			return nil, err
+		}
+		quote.Action = user_entity.PlanActionUpgrade
+		quote.Credit = credit
+		if quote.Price <= quote.Credit {
+			return nil, ErrPlanDowngrade
+		}
+	}
+	quote.Amount = quote.Price - quote.Credit
+	return quote, nil
+}
+
+// unusedCredit is what is left of the purchases paying for the user's running plan, each prorated over
+// the period it paid for. A purchase is worth its amount plus the credit it was given, a renewal counts
+// in full until its period starts. A plan given without a purchase is worth nothing.
+func (u *PlanUseCase) unusedCredit(user *user_entity.UserEntity, now time.Time) (int64, error) {
+	userId, _ := strconv.ParseInt(user.Id, 10, 64)
+	planId, _ := strconv.ParseInt(user.PlanId, 10, 64)
+	purchases, err := u.planReadRepo.FindRunningPurchases(userId, planId, user.PlanStartedAt, now)
+	if err != nil {
+		return 0, err
+	}
+	var credit int64
+	for _, purchase := range purchases {
+		credit += Prorate(purchase.Amount+purchase.Credit, purchase.StartsAt, purchase.ExpiresAt, now)
+	}
+	return credit, nil
+}
+
+func (u *PlanUseCase) cachedPlan(plans map[string]*user_entity.PlanEntity, id string) (*user_entity.PlanEntity, error) {
+	if plan, ok := plans[id]; ok {
+		return plan, nil
+	}
+	planId, _ := strconv.ParseInt(id, 10, 64)
+	plan, err := u.planReadRepo.FindById(planId)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// The plan was deleted, the owner still loses it
+		plan, err = &user_entity.PlanEntity{Id: id}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plans[id] = plan
+	return plan, nil
+}
+
+// publish notifies consumers, a failed publish is logged only
+func (u *PlanUseCase) publish(ctx context.Context, routingKey string, user *user_entity.UserEntity, plan *user_entity.PlanEntity, action string, expiresAt time.Time) {
+	event := event_dto.PlanSubscriptionEvent{
+		UserId:     user.Id,
+		PlanId:     plan.Id,
+		PlanName:   plan.Name,
+		Action:     action,
+		Email:      user.Email,
+		Phone:      user.Phone,
+		ExpiresAt:  expiresAt,
+		OccurredAt: time.Now(),
+	}
+	if err := u.eventPublisher.Publish(ctx, event_publisher_inter.PlanExchange, routingKey, event); err != nil {
+		u.l.Warn("user_use_case - PlanUseCase - publish - %s: %v", routingKey, err)
+	}
+}
+
+// NetPrice is the plan's price after its discount
+func NetPrice(plan *user_entity.PlanEntity) int64 {
+	return discounted(plan.Price, plan.DiscountType, plan.Discount)
+}
+
+// discounted applies a percent or fixed discount to the price, never going below zero
+func discounted(price int64, discountType string, discount int64) int64 {
+	net := price
+	switch discountType {
+	case user_entity.DiscountPercent:
+		net -= price * discount / 100
+	case user_entity.DiscountFixed:
+		net -= discount
+	}
+	if net < 0 {
+		return 0
+	}
+	return net
+}
+
+// Prorate is the part of price matching the time left of the period from startedAt to expiresAt
+func Prorate(price int64, startedAt time.Time, expiresAt time.Time, now time.Time) int64 {
+	total := expiresAt.Sub(startedAt)
+	left := expiresAt.Sub(now)
+	if total <= 0 || left <= 0 {
+		return 0
+	}
+	if left > total {
+		left = total
+	}
+	return int64(float64(price) * left.Seconds() / total.Seconds())
+}
+
+func roleIds(roles []user_entity.RoleEntity) []int64 {
+	ids := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		id, _ := strconv.ParseInt(role.Id, 10, 64)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// without returns the ids not in exclude
+func without(ids []int64, exclude []int64) []int64 {
+	excluded := make(map[int64]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !excluded[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
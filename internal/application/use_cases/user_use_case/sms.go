@@ -0,0 +1,196 @@
+package user_use_case
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"site_builder_backend/internal/application/dto/common_dto"
+	"site_builder_backend/internal/application/dto/user/sms_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/cache/credit_meter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/internal/interfaces/notification/sms_provider_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// ActionSmsSend is the action of usages paying for text messages
+const ActionSmsSend = "sms.send"
+
+var ErrSmsInvalid = errors.New("sms needs a phone number and a message or an event")
+
+type SmsUseCase struct {
+	smsReadRepo     user_repo_inter.SmsReadRepository
+	smsWriteRepo    user_repo_inter.SmsWriteRepository
+	provider        sms_provider_inter.SmsProvider
+	meteringUseCase *MeteringUseCase
+	templateUseCase *notification_use_case.TemplateUseCase
+	maxAttempts     int
+	l               *logger.ZapLogger
+}
+
+// NewSmsUseCase gives up on a message after maxAttempts failed attempts
+func NewSmsUseCase(smsReadRepo user_repo_inter.SmsReadRepository, smsWriteRepo user_repo_inter.SmsWriteRepository, provider sms_provider_inter.SmsProvider, meteringUseCase *MeteringUseCase, templateUseCase *notification_use_case.TemplateUseCase, maxAttempts int, l *logger.ZapLogger) *SmsUseCase {
+	return &SmsUseCase{
+		smsReadRepo:     smsReadRepo,
+		smsWriteRepo:    smsWriteRepo,
+		provider:        provider,
+		meteringUseCase: meteringUseCase,
+		templateUseCase: templateUseCase,
+		maxAttempts:     maxAttempts,
+		l:               l,
+	}
+}
+
+// Send delivers a queued message and records how it went. A message with an event is rendered from the
+// site's notification template first. A message of an owner reserves one SMS credit
+// per part before it is sent and pays them once the provider accepted it. An error is returned only when
+// the message is worth another try, after maxAttempts it is failed instead.
+func (u *SmsUseCase) Send(ctx context.Context, dto sms_dto.SmsDto) error {
+	if dto.Phone == "" || (dto.Message == "" && dto.Event == "") {
+		return ErrSmsInvalid
+	}
+	if dto.MessageId == "" {
+		id, err := reservationId()
+		if err != nil {
+			return err
+		}
+		dto.MessageId = id
+	}
+	if dto.Event != "" {
+		rendered, err := u.templateUseCase.Render(ctx, dto.SiteId, site_entity.NotificationChannelSms, dto.Event, dto.Locale, dto.Data)
+		if errors.Is(err, notification_use_case.ErrUnknownEvent) {
+			return fmt.Errorf("%w: %v", ErrSmsInvalid, err)
+		}
+		if err != nil {
+			return err
+		}
+		if len(rendered.Missing) > 0 {
+			u.l.Warn("user_use_case - SmsUseCase - Send - message %s: %s sent without %v", dto.MessageId, dto.Event, rendered.Missing)
+		}
+		dto.Message = rendered.Body
+	}
+	message, err := u.message(dto)
+	if err != nil {
+		return err
+	}
+	if message.Status != user_entity.SmsStatusQueued {
+		// Redelivered after it was sent or given up on
+		return nil
+	}
+	id, _ := strconv.ParseInt(message.Id, 10, 64)
+
+	var reservation *credit_meter_inter.Reservation
+	if dto.UserId != 0 {
+		reservation, err = u.meteringUseCase.Reserve(ctx, dto.UserId, user_entity.CreditSms, int64(message.Parts), ActionSmsSend)
+		if errors.Is(err, ErrInsufficientCredits) {
+			return u.fail(id, err.Error(), message.Attempts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	attempts := message.Attempts + 1
+	result, err := u.provider.Send(ctx, dto.Phone, dto.Message)
+	if err != nil {
+		if reservation != nil {
+			if err := u.meteringUseCase.Release(ctx, reservation.Id); err != nil {
+				u.l.Error("user_use_case - SmsUseCase - Send - message %s: %v", dto.MessageId, err)
+			}
+		}
+		if errors.Is(err, sms_provider_inter.ErrRejected) || attempts >= u.maxAttempts {
+			u.l.Warn("user_use_case - SmsUseCase - Send - message %s: giving up after %d attempts: %v", dto.MessageId, attempts, err)
+			return u.fail(id, err.Error(), attempts)
+		}
+		if err := u.smsWriteRepo.MarkAttempt(id, u.provider.Name(), err.Error(), attempts); err != nil && !errors.Is(err, repositories.ErrConflict) {
+			return err
+		}
+		return err
+	}
+
+	// The message is out, failures from here on are logged rather than retried so it is not sent twice
+	if reservation != nil {
+		if err := u.meteringUseCase.Commit(ctx, reservation.Id); err != nil {
+			u.l.Error("user_use_case - SmsUseCase - Send - message %s - Commit: %v", dto.MessageId, err)
+		}
+	}
+	err = u.smsWriteRepo.MarkSent(id, u.provider.Name(), result.MessageId, attempts, time.Now())
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		u.l.Error("user_use_case - SmsUseCase - Send - message %s - MarkSent: %v", dto.MessageId, err)
+	}
+	return nil
+}
+
+// Messages lists the owner's messages with their delivery status, newest first
+func (u *SmsUseCase) Messages(ctx context.Context, userId int64, dto sms_dto.MessageFilterDto) (*common_dto.PaginatedDto[user_entity.SmsMessageEntity], error) {
+	messages, total, err := u.smsReadRepo.FindMessages(user_repo_inter.SmsMessageFilter{
+		UserId: userId,
+		Status: dto.Status,
+		Offset: dto.Offset(),
+		Limit:  dto.Limit(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := common_dto.NewPaginatedDto(messages, total, dto.PaginationDto)
+	return &result, nil
+}
+
+// message returns the stored message of the dto, storing it as queued the first time it is seen
+func (u *SmsUseCase) message(dto sms_dto.SmsDto) (*user_entity.SmsMessageEntity, error) {
+	message, err := u.smsReadRepo.FindByMessageId(dto.MessageId)
+	if err == nil || !errors.Is(err, repositories.ErrNotFound) {
+		return message, err
+	}
+
+	message = &user_entity.SmsMessageEntity{
+		MessageId: dto.MessageId,
+		UserId:    strconv.FormatInt(dto.UserId, 10),
+		Phone:     dto.Phone,
+		Message:   dto.Message,
+		Parts:     SmsParts(dto.Message),
+		Status:    user_entity.SmsStatusQueued,
+	}
+	err = u.smsWriteRepo.Create(message)
+	if errors.Is(err, repositories.ErrConflict) {
+		// A concurrent delivery of the same message stored it first
+		return u.smsReadRepo.FindByMessageId(dto.MessageId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (u *SmsUseCase) fail(id int64, reason string, attempts int) error {
+	err := u.smsWriteRepo.MarkFailed(id, u.provider.Name(), reason, attempts)
+	if errors.Is(err, repositories.ErrConflict) {
+		return nil
+	}
+	return err
+}
+
+// SmsParts counts the parts a message is sent in. Plain ASCII text fits 160 characters in a single part
+// and 153 in each part of a longer message, any other text such as Persian is sent as UCS-2 with 70 and
+// 67 characters.
+func SmsParts(message string) int {
+	single, multi := 160, 153
+	for _, r := range message {
+		if r >= utf8.RuneSelf {
+			single, multi = 70, 67
+			break
+		}
+	}
+	length := utf8.RuneCountInString(message)
+	if length <= single {
+		return 1
+	}
+	return (length + multi - 1) / multi
+}
@@ -1,7 +1,6 @@
 package user_use_case
 
 import (
-	"context"
 	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
 	"site_builder_backend/pkg/logger"
 )
@@ -31,11 +30,3 @@ func (u *UserUseCase) RegisterUserCommand() {
 func (u *UserUseCase) RefreshTokenCommand() {
 
 }
-
-// SendSms sends an SMS to the specified phone number with the given message
-func (u *UserUseCase) SendSms(ctx context.Context, phone, message string) error {
-	// Here you would implement the actual SMS sending logic
-	// This could involve calling an external SMS service or updating a database
-	// For now, this is just a placeholder
-	return nil
-}
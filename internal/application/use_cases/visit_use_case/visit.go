@@ -0,0 +1,203 @@
+package visit_use_case
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"site_builder_backend/internal/application/dto/visit/visit_dto"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/interfaces/cache/visit_counter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	_mostViewedDays = 7
+	// Pages found to exist are counted without a database lookup for _knownTTL
+	_knownTTL = 10 * time.Minute
+)
+
+var (
+	ErrInvalidVisitor = errors.New("visitor could not be identified")
+	ErrPageNotFound   = errors.New("page not found on the site")
+)
+
+type VisitUseCase struct {
+	visitCounter     visit_counter_inter.VisitCounter
+	productReadRepo  product_repo_inter.ProductReadRepository
+	productWriteRepo product_repo_inter.ProductWriteRepository
+	articleReadRepo  blog_repo_inter.ArticleReadRepository
+	articleWriteRepo blog_repo_inter.ArticleWriteRepository
+	imageUseCase     *drive_use_case.ImageUseCase
+	l                *logger.ZapLogger
+}
+
+func NewVisitUseCase(visitCounter visit_counter_inter.VisitCounter, productReadRepo product_repo_inter.ProductReadRepository, productWriteRepo product_repo_inter.ProductWriteRepository, articleReadRepo blog_repo_inter.ArticleReadRepository, articleWriteRepo blog_repo_inter.ArticleWriteRepository, imageUseCase *drive_use_case.ImageUseCase, l *logger.ZapLogger) *VisitUseCase {
+	return &VisitUseCase{
+		visitCounter:     visitCounter,
+		productReadRepo:  productReadRepo,
+		productWriteRepo: productWriteRepo,
+		articleReadRepo:  articleReadRepo,
+		articleWriteRepo: articleWriteRepo,
+		imageUseCase:     imageUseCase,
+		l:                l,
+	}
+}
+
+// TrackProduct counts a view of a listed product of the site. VisitedCount is updated by Flush.
+func (u *VisitUseCase) TrackProduct(ctx context.Context, siteId int64, productId int64, visitorId string) error {
+	return u.track(ctx, visit_counter_inter.TargetProduct, siteId, productId, visitorId, func() (bool, error) {
+		products, err := u.productReadRepo.FindListedByIds(siteId, []int64{productId})
+		return len(products) > 0, err
+	})
+}
+
+// TrackArticle counts a view of a visible article of the site. VisitedCount is updated by Flush.
+func (u *VisitUseCase) TrackArticle(ctx context.Context, siteId int64, articleId int64, visitorId string) error {
+	return u.track(ctx, visit_counter_inter.TargetArticle, siteId, articleId, visitorId, func() (bool, error) {
+		articles, err := u.articleReadRepo.FindVisibleByIds(siteId, []int64{articleId}, time.Now())
+		return len(articles) > 0, err
+	})
+}
+
+// Flush writes the counters accumulated in Redis back to VisitedCount
+func (u *VisitUseCase) Flush(ctx context.Context) error {
+	productErr := u.flush(ctx, visit_counter_inter.TargetProduct, u.productWriteRepo.IncrementVisitedCounts)
+	articleErr := u.flush(ctx, visit_counter_inter.TargetArticle, u.articleWriteRepo.IncrementVisitedCounts)
+	return errors.Join(productErr, articleErr)
+}
+
+// MostViewedProducts returns the products of a site with the most unique visits over the last week
+func (u *VisitUseCase) MostViewedProducts(ctx context.Context, siteId int64, dto visit_dto.MostViewedFilterDto) ([]visit_dto.MostViewedDto[product_entity.ProductEntity], error) {
+	scores, err := u.visitCounter.TopVisited(ctx, visit_counter_inter.TargetProduct, siteId, _mostViewedDays, dto.Normalize().Limit, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	products, err := u.productReadRepo.FindListedByIds(siteId, scoreIds(scores))
+	if err != nil {
+		return nil, err
+	}
+
+	u.attachImages(ctx, products)
+
+	byId := make(map[string]product_entity.ProductEntity, len(products))
+	for _, product := range products {
+		byId[product.Id] = product
+	}
+	return rank(scores, byId), nil
+}
+
+// MostViewedArticles returns the visible articles of a site with the most unique visits over the last week
+func (u *VisitUseCase) MostViewedArticles(ctx context.Context, siteId int64, dto visit_dto.MostViewedFilterDto) ([]visit_dto.MostViewedDto[blog_entity.ArticleEntity], error) {
+	now := time.Now()
+	scores, err := u.visitCounter.TopVisited(ctx, visit_counter_inter.TargetArticle, siteId, _mostViewedDays, dto.Normalize().Limit, now)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := u.articleReadRepo.FindVisibleByIds(siteId, scoreIds(scores), now)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[string]blog_entity.ArticleEntity, len(articles))
+	for _, article := range articles {
+		byId[article.Id] = article
+	}
+	return rank(scores, byId), nil
+}
+
+// attachImages fills in the processed images of the products' media, products are shown with plain
+// media ids when they cannot be read
+func (u *VisitUseCase) attachImages(ctx context.Context, products []product_entity.ProductEntity) {
+	var ids []string
+	for _, product := range products {
+		for _, media := range product.Media {
+			ids = append(ids, media.MediaId)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	images, err := u.imageUseCase.Images(ctx, ids)
+	if err != nil {
+		u.l.Error("visit_use_case - VisitUseCase - attachImages: %v", err)
+		return
+	}
+	for i := range products {
+		for j := range products[i].Media {
+			products[i].Media[j].Image = images[products[i].Media[j].MediaId]
+		}
+	}
+}
+
+// track counts the visit once the page is known to exist, so ids of no page create no counters
+func (u *VisitUseCase) track(ctx context.Context, target visit_counter_inter.Target, siteId int64, id int64, visitorId string, exists func() (bool, error)) error {
+	if visitorId == "" {
+		return ErrInvalidVisitor
+	}
+	key := visit_counter_inter.VisitKey{SiteId: siteId, Id: id}
+	known, err := u.visitCounter.IsKnown(ctx, target, key)
+	if err != nil {
+		return err
+	}
+	if !known {
+		found, err := exists()
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrPageNotFound
+		}
+		if err := u.visitCounter.Remember(ctx, target, key, _knownTTL); err != nil {
+			return err
+		}
+	}
+	_, err = u.visitCounter.Track(ctx, target, key, visitorId, time.Now())
+	return err
+}
+
+func (u *VisitUseCase) flush(ctx context.Context, target visit_counter_inter.Target, write func([]repositories.CounterDelta) error) error {
+	counts, err := u.visitCounter.TakePending(ctx, target)
+	if err != nil {
+		return err
+	}
+	if len(counts) > 0 {
+		deltas := make([]repositories.CounterDelta, 0, len(counts))
+		for key, count := range counts {
+			deltas = append(deltas, repositories.CounterDelta{SiteId: key.SiteId, Id: key.Id, Delta: count})
+		}
+		// When the write fails the batch is not acknowledged and the next run retries it
+		if err := write(deltas); err != nil {
+			return err
+		}
+		u.l.Info("visit_use_case - VisitUseCase - flush: %d %s counters written", len(deltas), target)
+	}
+	return u.visitCounter.AckPending(ctx, target)
+}
+
+func scoreIds(scores []visit_counter_inter.VisitScore) []int64 {
+	ids := make([]int64, 0, len(scores))
+	for _, score := range scores {
+		ids = append(ids, score.Id)
+	}
+	return ids
+}
+
+// rank keeps the order of the scores and drops ids that no longer resolve to a listed item
+func rank[T any](scores []visit_counter_inter.VisitScore, byId map[string]T) []visit_dto.MostViewedDto[T] {
+	ranked := make([]visit_dto.MostViewedDto[T], 0, len(scores))
+	for _, score := range scores {
+		item, ok := byId[strconv.FormatInt(score.Id, 10)]
+		if !ok {
+			continue
+		}
+		ranked = append(ranked, visit_dto.MostViewedDto[T]{Item: item, Visits: score.Visits})
+	}
+	return ranked
+}
@@ -2,6 +2,7 @@ package blog_entity
 
 import (
 	"site_builder_backend/internal/domain/site_entity"
+	"strings"
 	"time"
 )
 
@@ -17,6 +18,8 @@ type ArticleEntity struct {
 	Rate         int       `json:"rate" gorm:"column:Rate" faker:"boundary_start=0, boundary_end=5"`
 	Badges       string    `json:"badges,omitempty" gorm:"column:Badges" faker:"sentence"`
 	SeoTags      string    `json:"seo_tags,omitempty" gorm:"column:SeoTags" faker:"sentence"`
+	Status       string    `json:"status" gorm:"column:Status" faker:"oneof: draft, published, scheduled"`
+	PublishedAt  time.Time `json:"published_at,omitempty" gorm:"column:PublishedAt" faker:"time"`
 	UserId       string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
 	CreatedAt    time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
 	UpdatedAt    time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
@@ -33,3 +36,32 @@ type ArticleEntity struct {
 func (ArticleEntity) TableName() string {
 	return "Blog.Articles"
 }
+
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusPublished = "published"
+	ArticleStatusScheduled = "scheduled"
+)
+
+// IsVisibleAt reports whether the article can be shown on the storefront at the given time.
+// Scheduled articles become visible once their PublishedAt moment has passed.
+func (a ArticleEntity) IsVisibleAt(now time.Time) bool {
+	if a.IsDeleted {
+		return false
+	}
+	if a.Status != ArticleStatusPublished && a.Status != ArticleStatusScheduled {
+		return false
+	}
+	return !a.PublishedAt.After(now)
+}
+
+// BadgeList splits the comma separated Badges column
+func (a ArticleEntity) BadgeList() []string {
+	badges := make([]string, 0)
+	for _, badge := range strings.Split(a.Badges, ",") {
+		if badge = strings.TrimSpace(badge); badge != "" {
+			badges = append(badges, badge)
+		}
+	}
+	return badges
+}
@@ -0,0 +1,37 @@
+package blog_entity
+
+import "time"
+
+type ArticleCommentEntity struct {
+	Id         string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	ArticleId  string    `json:"article_id" gorm:"column:ArticleId" faker:"uuid_digit"`
+	SiteId     string    `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	ParentId   string    `json:"parent_id,omitempty" gorm:"column:ParentId" faker:"uuid_digit"`
+	RootId     string    `json:"root_id,omitempty" gorm:"column:RootId" faker:"uuid_digit"`
+	CustomerId string    `json:"customer_id,omitempty" gorm:"column:CustomerId" faker:"uuid_digit"`
+	GuestName  string    `json:"guest_name,omitempty" gorm:"column:GuestName" faker:"name"`
+	GuestEmail string    `json:"-" gorm:"column:GuestEmail" faker:"email"`
+	Body       string    `json:"body" gorm:"column:Body" faker:"paragraph"`
+	Rating     int       `json:"rating,omitempty" gorm:"column:Rating" faker:"boundary_start=0, boundary_end=5"`
+	Status     string    `json:"status" gorm:"column:Status" faker:"oneof: pending, approved, spam"`
+	IpAddress  string    `json:"-" gorm:"column:IpAddress" faker:"ipv4"`
+	UserAgent  string    `json:"-" gorm:"column:UserAgent" faker:"-"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+	Version    time.Time `json:"version" gorm:"column:Version" faker:"time"`
+	IsDeleted  bool      `json:"is_deleted" gorm:"column:IsDeleted" faker:"oneof: true, false"`
+	DeletedAt  time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
+
+	// Relationships
+	Article ArticleEntity `json:"article" gorm:"foreignKey:ArticleId"`
+}
+
+func (ArticleCommentEntity) TableName() string {
+	return "Blog.ArticleComments"
+}
+
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusSpam     = "spam"
+)
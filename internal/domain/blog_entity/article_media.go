@@ -1,14 +1,18 @@
 package blog_entity
 
+import "site_builder_backend/internal/domain/drive_entity"
+
 type ArticleMediaEntity struct {
 	Id        string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
 	ArticleId string `json:"article_id" gorm:"column:ArticleId" faker:"uuid_digit"`
 	MediaId   string `json:"media_id" gorm:"column:MediaId" faker:"uuid_digit"`
-	
+	// Image is filled in for API answers once the image is processed
+	Image *drive_entity.MediaImage `json:"image,omitempty" gorm:"-"`
+
 	// Relationships
-	Article   ArticleEntity `json:"article" gorm:"foreignKey:ArticleId"`
+	Article ArticleEntity `json:"article" gorm:"foreignKey:ArticleId"`
 }
 
 func (ArticleMediaEntity) TableName() string {
 	return "Blog.ArticleMedia"
-} 
\ No newline at end of file
+}
@@ -1,14 +1,18 @@
 package blog_entity
 
+import "site_builder_backend/internal/domain/drive_entity"
+
 type CategoryMediaEntity struct {
 	Id         string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
 	CategoryId string `json:"category_id" gorm:"column:CategoryId" faker:"uuid_digit"`
 	MediaId    string `json:"media_id" gorm:"column:MediaId" faker:"uuid_digit"`
-	
+	// Image is filled in for API answers once the image is processed
+	Image *drive_entity.MediaImage `json:"image,omitempty" gorm:"-"`
+
 	// Relationships
-	Category   CategoryEntity `json:"category" gorm:"foreignKey:CategoryId"`
+	Category CategoryEntity `json:"category" gorm:"foreignKey:CategoryId"`
 }
 
 func (CategoryMediaEntity) TableName() string {
 	return "Blog.CategoryMedia"
-} 
\ No newline at end of file
+}
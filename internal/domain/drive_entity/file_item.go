@@ -19,13 +19,21 @@ type FileItemEntity struct {
 	Version     time.Time `json:"version" gorm:"column:Version" faker:"time"`
 	IsDeleted   bool      `json:"is_deleted" gorm:"column:IsDeleted" faker:"oneof: true, false"`
 	DeletedAt   time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
+	ImageStatus string    `json:"image_status,omitempty" gorm:"column:ImageStatus" faker:"oneof: pending, private, ready, failed"`
 
 	// Relationships
-	Parent   *FileItemEntity  `json:"parent,omitempty" gorm:"foreignKey:ParentId"`
-	Children []FileItemEntity `json:"children,omitempty" gorm:"foreignKey:ParentId"`
+	Parent   *FileItemEntity     `json:"parent,omitempty" gorm:"foreignKey:ParentId"`
+	Children []FileItemEntity    `json:"children,omitempty" gorm:"foreignKey:ParentId"`
+	Variants []FileVariantEntity `json:"variants,omitempty" gorm:"foreignKey:FileItemId"`
 }
 
 func (FileItemEntity) TableName() string {
 	return "Drive.FileItems"
 }
- 
\ No newline at end of file
+ 
+// Who may read a file
+const (
+	FilePermissionPrivate = "private"
+	FilePermissionPublic  = "public"
+	FilePermissionShared  = "shared"
+)
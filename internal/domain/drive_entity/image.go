@@ -0,0 +1,44 @@
+package drive_entity
+
+import "time"
+
+// FileVariantEntity is a derivative of an image file: a thumbnail or one of the responsive sizes, upright
+// and without the metadata of the original. Variants are kept under public keys, unlike their originals.
+type FileVariantEntity struct {
+	Id         string    `json:"-" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	FileItemId string    `json:"-" gorm:"column:FileItemId" faker:"uuid_digit"`
+	Name       string    `json:"name" gorm:"column:Name" faker:"oneof: thumb, w320, w640, w1024"`
+	Width      int       `json:"width" gorm:"column:Width" faker:"boundary_start=100, boundary_end=2000"`
+	Height     int       `json:"height" gorm:"column:Height" faker:"boundary_start=100, boundary_end=2000"`
+	Size       int64     `json:"size" gorm:"column:Size" faker:"boundary_start=1000, boundary_end=1000000"`
+	MimeType   string    `json:"mime_type" gorm:"column:MimeType" faker:"mime_type"`
+	BucketName string    `json:"-" gorm:"column:BucketName" faker:"word"`
+	ServerKey  string    `json:"server_key" gorm:"column:ServerKey" faker:"uuid_digit"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+}
+
+func (FileVariantEntity) TableName() string {
+	return "Drive.FileVariants"
+}
+
+// ImageStatus of image files, other files have none. Private images get no variants until they are
+// public or used by a site.
+const (
+	ImageStatusPending = "pending"
+	ImageStatusPrivate = "private"
+	ImageStatusReady   = "ready"
+	ImageStatusFailed  = "failed"
+)
+
+// VariantThumbnail is the name of the square thumbnail, responsive sizes are named "w<width>"
+const VariantThumbnail = "thumb"
+
+// MediaImage is how an image of the drive is shown on sites. Url is its largest responsive size, SrcSet
+// lists all of them for the srcset attribute of img tags.
+type MediaImage struct {
+	Url          string `json:"url"`
+	SrcSet       string `json:"srcset"`
+	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+}
@@ -0,0 +1,54 @@
+package drive_entity
+
+import "time"
+
+// UploadEntity is a file being uploaded in parts of PartSize bytes, the last one may be smaller. Parts
+// can be sent again and in any order until the upload is completed, then the file item is made and
+// FileItemId points at it. StorageUploadId is the object store's id of the multipart upload and
+// ReservationId holds the storage quota of the file aside until then.
+type UploadEntity struct {
+	Id              string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UserId          string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	Name            string    `json:"name" gorm:"column:Name" faker:"file_name"`
+	ParentId        string    `json:"parent_id,omitempty" gorm:"column:ParentId" faker:"uuid_digit"`
+	MimeType        string    `json:"mime_type" gorm:"column:MimeType" faker:"mime_type"`
+	Size            int64     `json:"size" gorm:"column:Size" faker:"boundary_start=1000, boundary_end=1000000"`
+	PartSize        int64     `json:"part_size" gorm:"column:PartSize" faker:"boundary_start=5242880, boundary_end=16777216"`
+	BucketName      string    `json:"-" gorm:"column:BucketName" faker:"word"`
+	ServerKey       string    `json:"-" gorm:"column:ServerKey" faker:"uuid_digit"`
+	StorageUploadId string    `json:"-" gorm:"column:StorageUploadId" faker:"uuid_digit"`
+	ReservationId   string    `json:"-" gorm:"column:ReservationId" faker:"uuid_digit"`
+	Status          string    `json:"status" gorm:"column:Status" faker:"oneof: uploading, completed, aborted, expired"`
+	FileItemId      string    `json:"file_item_id,omitempty" gorm:"column:FileItemId" faker:"uuid_digit"`
+	ExpiresAt       time.Time `json:"expires_at" gorm:"column:ExpiresAt" faker:"time"`
+	CreatedAt       time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt       time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+
+	// Relationships
+	Parts []UploadPartEntity `json:"parts,omitempty" gorm:"foreignKey:UploadId"`
+}
+
+func (UploadEntity) TableName() string {
+	return "Drive.Uploads"
+}
+
+// UploadPartEntity is a part of an upload the object store has received
+type UploadPartEntity struct {
+	Id         string    `json:"-" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UploadId   string    `json:"-" gorm:"column:UploadId" faker:"uuid_digit"`
+	PartNumber int       `json:"part_number" gorm:"column:PartNumber" faker:"boundary_start=1, boundary_end=100"`
+	Size       int64     `json:"size" gorm:"column:Size" faker:"boundary_start=1000, boundary_end=1000000"`
+	ETag       string    `json:"etag" gorm:"column:ETag" faker:"uuid_digit"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+}
+
+func (UploadPartEntity) TableName() string {
+	return "Drive.UploadParts"
+}
+
+const (
+	UploadStatusUploading = "uploading"
+	UploadStatusCompleted = "completed"
+	UploadStatusAborted   = "aborted"
+	UploadStatusExpired   = "expired"
+)
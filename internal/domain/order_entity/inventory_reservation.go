@@ -0,0 +1,31 @@
+package order_entity
+
+import "time"
+
+// InventoryReservationEntity records stock taken from a variant for an order, so it can be given back
+// when the order does not go through
+type InventoryReservationEntity struct {
+	Id               string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	SiteId           string    `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	OrderId          string    `json:"order_id" gorm:"column:OrderId" faker:"uuid_digit"`
+	ProductVariantId string    `json:"product_variant_id" gorm:"column:ProductVariantId" faker:"uuid_digit"`
+	Quantity         int       `json:"quantity" gorm:"column:Quantity" faker:"boundary_start=1, boundary_end=10"`
+	Status           string    `json:"status" gorm:"column:Status" faker:"oneof: reserved, released, committed, returned"`
+	CreatedAt        time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+
+	// Relationships
+	Order OrderEntity `json:"-" gorm:"foreignKey:OrderId"`
+}
+
+func (InventoryReservationEntity) TableName() string {
+	return "Order.InventoryReservations"
+}
+
+const (
+	InventoryReservationStatusReserved  = "reserved"
+	InventoryReservationStatusReleased  = "released"
+	InventoryReservationStatusCommitted = "committed"
+	// InventoryReservationStatusReturned is committed stock that came back with an approved return
+	InventoryReservationStatusReturned = "returned"
+)
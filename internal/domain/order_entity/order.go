@@ -10,7 +10,7 @@ type OrderEntity struct {
 	TotalPriceWithCouponDiscount int64     `json:"total_price_with_coupon_discount" gorm:"column:TotalPriceWithCouponDiscount" faker:"boundary_start=1000, boundary_end=1000000"`
 	CourierPrice                 int64     `json:"courier_price" gorm:"column:CourierPrice" faker:"boundary_start=0, boundary_end=50000"`
 	Courier                      string    `json:"courier" gorm:"column:Courier" faker:"oneof: post, express, pickup"`
-	OrderStatus                  string    `json:"order_status" gorm:"column:OrderStatus" faker:"oneof: pending, processing, shipped, delivered, cancelled"`
+	OrderStatus                  string    `json:"order_status" gorm:"column:OrderStatus" faker:"oneof: pending_payment, paid, processing, shipped, delivered, cancelled, refunded"`
 	TotalFinalPrice              int64     `json:"total_final_price" gorm:"column:TotalFinalPrice" faker:"boundary_start=1000, boundary_end=1000000"`
 	Description                  string    `json:"description,omitempty" gorm:"column:Description" faker:"paragraph"`
 	TotalWeight                  int       `json:"total_weight" gorm:"column:TotalWeight" faker:"boundary_start=100, boundary_end=10000"`
@@ -19,6 +19,7 @@ type OrderEntity struct {
 	DiscountId                   string    `json:"discount_id,omitempty" gorm:"column:DiscountId" faker:"uuid_digit"`
 	AddressId                    string    `json:"address_id" gorm:"column:AddressId" faker:"uuid_digit"`
 	CustomerId                   string    `json:"customer_id" gorm:"column:CustomerId" faker:"uuid_digit"`
+	IdempotencyKey               string    `json:"-" gorm:"column:IdempotencyKey" faker:"uuid_hyphenated"`
 	CreatedAt                    time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
 	UpdatedAt                    time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
 	Version                      time.Time `json:"version" gorm:"column:Version" faker:"time"`
@@ -26,9 +27,25 @@ type OrderEntity struct {
 	DeletedAt                    time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
 
 	// Relationships
-	OrderItems []OrderItemEntity `json:"order_items,omitempty" gorm:"foreignKey:OrderId"`
+	OrderItems    []OrderItemEntity          `json:"order_items,omitempty" gorm:"foreignKey:OrderId"`
+	StatusHistory []OrderStatusHistoryEntity `json:"status_history,omitempty" gorm:"foreignKey:OrderId"`
 }
 
 func (OrderEntity) TableName() string {
 	return "Order.Orders"
 }
+
+// Order statuses. The allowed transitions between them are enforced by order_use_case.
+const (
+	// OrderStatusPendingPayment is an order placed at checkout that waits for its payment
+	OrderStatusPendingPayment = "pending_payment"
+	OrderStatusPaid           = "paid"
+	OrderStatusProcessing     = "processing"
+	OrderStatusShipped        = "shipped"
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCancelled      = "cancelled"
+	OrderStatusRefunded       = "refunded"
+)
+
+// PurchasedOrderStatuses are the statuses of orders whose items count as bought by the customer
+var PurchasedOrderStatuses = []string{OrderStatusPaid, OrderStatusProcessing, OrderStatusShipped, OrderStatusDelivered}
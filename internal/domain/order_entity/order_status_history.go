@@ -0,0 +1,29 @@
+package order_entity
+
+import "time"
+
+// OrderStatusHistoryEntity records one status change of an order and who made it
+type OrderStatusHistoryEntity struct {
+	Id         string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	OrderId    string    `json:"order_id" gorm:"column:OrderId" faker:"uuid_digit"`
+	SiteId     string    `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	FromStatus string    `json:"from_status,omitempty" gorm:"column:FromStatus" faker:"oneof: pending_payment, paid, processing, shipped"`
+	ToStatus   string    `json:"to_status" gorm:"column:ToStatus" faker:"oneof: paid, processing, shipped, delivered, cancelled, refunded"`
+	ActorType  string    `json:"actor_type" gorm:"column:ActorType" faker:"oneof: owner, customer, system"`
+	ActorId    string    `json:"actor_id,omitempty" gorm:"column:ActorId" faker:"uuid_digit"`
+	Note       string    `json:"note,omitempty" gorm:"column:Note" faker:"sentence"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+
+	// Relationships
+	Order OrderEntity `json:"-" gorm:"foreignKey:OrderId"`
+}
+
+func (OrderStatusHistoryEntity) TableName() string {
+	return "Order.OrderStatusHistories"
+}
+
+const (
+	ActorTypeOwner    = "owner"
+	ActorTypeCustomer = "customer"
+	ActorTypeSystem   = "system"
+)
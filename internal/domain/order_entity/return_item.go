@@ -6,6 +6,10 @@ type ReturnItemEntity struct {
 	Id           string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
 	ReturnReason string    `json:"return_reason" gorm:"column:ReturnReason" faker:"sentence"`
 	OrderStatus  int       `json:"order_status" gorm:"column:OrderStatus" faker:"oneof: 1, 2, 3, 4, 5"`
+	OwnerNote    string    `json:"owner_note,omitempty" gorm:"column:OwnerNote" faker:"sentence"`
+	RefundAmount int64     `json:"refund_amount" gorm:"column:RefundAmount" faker:"boundary_start=1000, boundary_end=100000"`
+	SiteId       string    `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	OrderId      string    `json:"order_id" gorm:"column:OrderId" faker:"uuid_digit"`
 	OrderItemId  string    `json:"order_item_id" gorm:"column:OrderItemId;unique" faker:"uuid_digit"`
 	ProductId    string    `json:"product_id" gorm:"column:ProductId" faker:"uuid_digit"`
 	UserId       string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
@@ -15,11 +19,43 @@ type ReturnItemEntity struct {
 	Version      time.Time `json:"version" gorm:"column:Version" faker:"time"`
 	IsDeleted    bool      `json:"is_deleted" gorm:"column:IsDeleted" faker:"oneof: true, false"`
 	DeletedAt    time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
-	
+
 	// Relationships
-	OrderItem    OrderItemEntity `json:"order_item" gorm:"foreignKey:OrderItemId"`
+	OrderItem *OrderItemEntity `json:"order_item,omitempty" gorm:"foreignKey:OrderItemId"`
 }
 
 func (ReturnItemEntity) TableName() string {
 	return "Order.ReturnItem"
-} 
\ No newline at end of file
+}
+
+// Return states stored in ReturnItemEntity.OrderStatus
+const (
+	ReturnStatusRequested = 1
+	ReturnStatusApproved  = 2
+	ReturnStatusRejected  = 3
+	ReturnStatusRefunded  = 4
+	ReturnStatusCancelled = 5
+)
+
+var returnStatusNames = map[int]string{
+	ReturnStatusRequested: "requested",
+	ReturnStatusApproved:  "approved",
+	ReturnStatusRejected:  "rejected",
+	ReturnStatusRefunded:  "refunded",
+	ReturnStatusCancelled: "cancelled",
+}
+
+// ReturnStatusName returns the name of a return state, empty for an unknown code
+func ReturnStatusName(status int) string {
+	return returnStatusNames[status]
+}
+
+// ReturnStatusByName is the inverse of ReturnStatusName, zero for an unknown name
+func ReturnStatusByName(name string) int {
+	for status, n := range returnStatusNames {
+		if n == name {
+			return status
+		}
+	}
+	return 0
+}
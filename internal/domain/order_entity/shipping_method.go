@@ -0,0 +1,54 @@
+package order_entity
+
+import "time"
+
+// ShippingMethodEntity is a courier a site offers at checkout. Code is what orders store as their courier,
+// Provider names the courier provider that prices it.
+type ShippingMethodEntity struct {
+	Id            string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	SiteId        string `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	Code          string `json:"code" gorm:"column:Code" faker:"oneof: post, express, pickup"`
+	Title         string `json:"title" gorm:"column:Title" faker:"word"`
+	Provider      string `json:"provider" gorm:"column:Provider" faker:"oneof: flat, weight, province"`
+	BasePrice     int64  `json:"base_price" gorm:"column:BasePrice" faker:"boundary_start=0, boundary_end=100000"`
+	EstimatedDays int    `json:"estimated_days" gorm:"column:EstimatedDays" faker:"boundary_start=1, boundary_end=10"`
+	// FreeOverAmount makes shipping free for orders worth at least this much, zero disables it
+	FreeOverAmount int64     `json:"free_over_amount" gorm:"column:FreeOverAmount" faker:"boundary_start=0, boundary_end=5000000"`
+	IsActive       bool      `json:"is_active" gorm:"column:IsActive" faker:"oneof: true, false"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+	IsDeleted      bool      `json:"is_deleted" gorm:"column:IsDeleted" faker:"oneof: true, false"`
+	DeletedAt      time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
+
+	// Relationships
+	Rates []ShippingRateEntity `json:"rates,omitempty" gorm:"foreignKey:ShippingMethodId"`
+}
+
+func (ShippingMethodEntity) TableName() string {
+	return "Order.ShippingMethods"
+}
+
+// ShippingRateEntity is one price of a rule based shipping method. An empty ProvinceId matches every
+// province and a zero MaxWeight matches any weight.
+type ShippingRateEntity struct {
+	Id               string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	ShippingMethodId string `json:"shipping_method_id" gorm:"column:ShippingMethodId" faker:"uuid_digit"`
+	ProvinceId       string `json:"province_id,omitempty" gorm:"column:ProvinceId" faker:"uuid_digit"`
+	// MaxWeight is the heaviest shipment in grams this rate covers
+	MaxWeight int   `json:"max_weight" gorm:"column:MaxWeight" faker:"boundary_start=0, boundary_end=30000"`
+	Price     int64 `json:"price" gorm:"column:Price" faker:"boundary_start=0, boundary_end=500000"`
+}
+
+func (ShippingRateEntity) TableName() string {
+	return "Order.ShippingRates"
+}
+
+// Built-in rule based courier providers
+const (
+	ShippingProviderFlat     = "flat"
+	ShippingProviderWeight   = "weight"
+	ShippingProviderProvince = "province"
+)
+
+// ShippingProviderPost is priced by the rate service of the post
+const ShippingProviderPost = "post"
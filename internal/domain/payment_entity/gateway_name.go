@@ -0,0 +1,58 @@
+package payment_entity
+
+// Gateway names as stored on payments
+const (
+	GatewaySaman         = "saman"
+	GatewayMellat        = "mellat"
+	GatewayParsian       = "parsian"
+	GatewayPasargad      = "pasargad"
+	GatewayIranKish      = "irankish"
+	GatewayMelli         = "melli"
+	GatewayAsanPardakht  = "asanpardakht"
+	GatewaySepehr        = "sepehr"
+	GatewayZarinPal      = "zarinpal"
+	GatewayPayIr         = "payir"
+	GatewayIdPay         = "idpay"
+	GatewayYekPay        = "yekpay"
+	GatewayPayPing       = "payping"
+	GatewayParbadVirtual = "parbad_virtual"
+)
+
+// GatewayStatusActive is the value of the IsActive* columns of a switched on gateway
+const GatewayStatusActive = "active"
+
+// IsActive reports whether the site switched on the gateway with the given name
+func (e *GatewayEntity) IsActive(name string) bool {
+	var status string
+	switch name {
+	case GatewaySaman:
+		status = e.IsActiveSaman
+	case GatewayMellat:
+		status = e.IsActiveMellat
+	case GatewayParsian:
+		status = e.IsActiveParsian
+	case GatewayPasargad:
+		status = e.IsActivePasargad
+	case GatewayIranKish:
+		status = e.IsActiveIranKish
+	case GatewayMelli:
+		status = e.IsActiveMelli
+	case GatewayAsanPardakht:
+		status = e.IsActiveAsanPardakht
+	case GatewaySepehr:
+		status = e.IsActiveSepehr
+	case GatewayZarinPal:
+		status = e.IsActiveZarinPal
+	case GatewayPayIr:
+		status = e.IsActivePayIr
+	case GatewayIdPay:
+		status = e.IsActiveIdPay
+	case GatewayYekPay:
+		status = e.IsActiveYekPay
+	case GatewayPayPing:
+		status = e.IsActivePayPing
+	case GatewayParbadVirtual:
+		status = e.IsActiveParbadVirtual
+	}
+	return status == GatewayStatusActive
+}
@@ -0,0 +1,32 @@
+package payment_entity
+
+import "encoding/json"
+
+// SecretMask replaces a credential that is set in API responses
+const SecretMask = "********"
+
+// MarshalJSON masks the credentials of the gateway accounts. They are encrypted at rest and only ever
+// leave the api towards the gateways themselves.
+func (e GatewayEntity) MarshalJSON() ([]byte, error) {
+	type gateway GatewayEntity
+	masked := gateway(e)
+	for _, field := range []*string{
+		&masked.Saman_Password,
+		&masked.Mellat_UserPassword,
+		&masked.Pasargad_PrivateKey,
+		&masked.IranKish_PassPhrase,
+		&masked.Melli_TerminalKey,
+		&masked.AsanPardakht_Password,
+		&masked.AsanPardakht_Key,
+		&masked.AsanPardakht_IV,
+		&masked.ZarinPal_AuthorizationToken,
+		&masked.PayIr_Api,
+		&masked.IdPay_Api,
+		&masked.PayPing_AccessToken,
+	} {
+		if *field != "" {
+			*field = SecretMask
+		}
+	}
+	return json.Marshal(masked)
+}
@@ -0,0 +1,51 @@
+package payment_entity
+
+import "time"
+
+// ParbadPaymentEntity is one attempt to pay a payment at a gateway, stored the way Parbad stores its
+// payments. The payment points at its latest attempt through TrackingNumber.
+type ParbadPaymentEntity struct {
+	Id                 string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	TrackingNumber     int64  `json:"tracking_number" gorm:"column:TrackingNumber" faker:"boundary_start=100000000000, boundary_end=999999999999"`
+	Amount             int64  `json:"amount" gorm:"column:Amount" faker:"boundary_start=1000, boundary_end=1000000"`
+	Token              string `json:"-" gorm:"column:Token" faker:"uuid_digit"`
+	TransactionCode    string `json:"transaction_code,omitempty" gorm:"column:TransactionCode" faker:"uuid_digit"`
+	GatewayName        string `json:"gateway_name" gorm:"column:GatewayName" faker:"oneof: zarinpal, idpay, parbad_virtual"`
+	GatewayAccountName string `json:"gateway_account_name" gorm:"column:GatewayAccountName" faker:"word"`
+	IsCompleted        bool   `json:"is_completed" gorm:"column:IsCompleted" faker:"oneof: true, false"`
+	IsPaid             bool   `json:"is_paid" gorm:"column:IsPaid" faker:"oneof: true, false"`
+
+	// Relationships
+	Transactions []ParbadTransactionEntity `json:"transactions,omitempty" gorm:"foreignKey:PaymentId"`
+}
+
+func (ParbadPaymentEntity) TableName() string {
+	return "Payment.ParbadPayments"
+}
+
+// ParbadTransactionEntity records one request, verify or refund call made to the gateway for an attempt.
+// A refund is stored pending before the gateway is called, so a refund whose outcome was never recorded
+// is not sent twice.
+type ParbadTransactionEntity struct {
+	Id             string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	Amount         int64     `json:"amount" gorm:"column:Amount" faker:"boundary_start=1000, boundary_end=1000000"`
+	Type           uint8     `json:"type" gorm:"column:Type" faker:"oneof: 0, 1, 2"`
+	IsSucceed      bool      `json:"is_succeed" gorm:"column:IsSucceed" faker:"oneof: true, false"`
+	IsPending      bool      `json:"is_pending" gorm:"column:IsPending" faker:"oneof: true, false"`
+	Message        string    `json:"message,omitempty" gorm:"column:Message" faker:"sentence"`
+	AdditionalData string    `json:"additional_data,omitempty" gorm:"column:AdditionalData" faker:"paragraph"`
+	PaymentId      string    `json:"payment_id" gorm:"column:PaymentId" faker:"uuid_digit"`
+	IdempotencyKey string    `json:"-" gorm:"column:IdempotencyKey" faker:"uuid_hyphenated"`
+	CreatedAt      time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+}
+
+func (ParbadTransactionEntity) TableName() string {
+	return "Payment.ParbadTransactions"
+}
+
+// Transaction types, numbered as in Parbad
+const (
+	ParbadTransactionRequest uint8 = 0
+	ParbadTransactionVerify  uint8 = 1
+	ParbadTransactionRefund  uint8 = 2
+)
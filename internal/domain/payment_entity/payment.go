@@ -20,6 +20,7 @@ type PaymentEntity struct {
 	Message             string    `json:"message,omitempty" gorm:"column:Message" faker:"sentence"`
 	GatewayResponseCode string    `json:"gateway_response_code,omitempty" gorm:"column:GatewayResponseCode" faker:"oneof: 0, 1, -1"`
 	TransactionCode     string    `json:"transaction_code,omitempty" gorm:"column:TransactionCode" faker:"uuid_digit"`
+	GatewayToken        string    `json:"-" gorm:"column:GatewayToken" faker:"uuid_digit"`
 	AdditionalData      string    `json:"additional_data,omitempty" gorm:"column:AdditionalData" faker:"paragraph"`
 	OrderData           string    `json:"order_data,omitempty" gorm:"column:OrderData" faker:"paragraph"`
 	UserId              string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
@@ -33,4 +34,10 @@ type PaymentEntity struct {
 
 func (PaymentEntity) TableName() string {
 	return "Payment.Payments"
-} 
\ No newline at end of file
+}
+
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusSuccessful = "successful"
+	PaymentStatusFailed     = "failed"
+)
@@ -1,14 +1,18 @@
 package product_entity
 
+import "site_builder_backend/internal/domain/drive_entity"
+
 type ProductMediaEntity struct {
 	Id        string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
 	ProductId string `json:"product_id" gorm:"column:ProductId" faker:"uuid_digit"`
 	MediaId   string `json:"media_id" gorm:"column:MediaId" faker:"uuid_digit"`
-	
+	// Image is filled in for API answers once the image is processed
+	Image *drive_entity.MediaImage `json:"image,omitempty" gorm:"-"`
+
 	// Relationships
-	Product   ProductEntity `json:"product" gorm:"foreignKey:ProductId"`
+	Product ProductEntity `json:"product" gorm:"foreignKey:ProductId"`
 }
 
 func (ProductMediaEntity) TableName() string {
 	return "Product.ProductMedia"
-} 
\ No newline at end of file
+}
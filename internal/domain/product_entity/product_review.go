@@ -20,7 +20,8 @@ type ProductReviewEntity struct {
 	DeletedAt  time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
 
 	// Relationships
-	Product ProductEntity `json:"product" gorm:"foreignKey:ProductId"`
+	Product ProductEntity             `json:"product" gorm:"foreignKey:ProductId"`
+	Votes   []ProductReviewVoteEntity `json:"votes,omitempty" gorm:"foreignKey:ReviewId"`
 }
 
 func (ProductReviewEntity) TableName() string {
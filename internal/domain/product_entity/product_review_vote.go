@@ -0,0 +1,19 @@
+package product_entity
+
+import "time"
+
+type ProductReviewVoteEntity struct {
+	Id         string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	ReviewId   string    `json:"review_id" gorm:"column:ReviewId" faker:"uuid_digit"`
+	CustomerId string    `json:"customer_id" gorm:"column:CustomerId" faker:"uuid_digit"`
+	IsLike     bool      `json:"is_like" gorm:"column:IsLike" faker:"oneof: true, false"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+
+	// Relationships
+	Review ProductReviewEntity `json:"review" gorm:"foreignKey:ReviewId"`
+}
+
+func (ProductReviewVoteEntity) TableName() string {
+	return "Product.ProductReviewVotes"
+}
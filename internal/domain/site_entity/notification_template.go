@@ -0,0 +1,36 @@
+package site_entity
+
+import "time"
+
+// NotificationTemplateEntity is the owner's own text of a notification of the site, replacing the default
+// text of its event, channel and locale. Variables are written as {{Name}}. Subject is used by email
+// only, Html is the HTML body of an email and derived from Body when empty.
+type NotificationTemplateEntity struct {
+	Id        string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	SiteId    string    `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	UserId    string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	Channel   string    `json:"channel" gorm:"column:Channel" faker:"oneof: sms, email"`
+	Event     string    `json:"event" gorm:"column:Event" faker:"oneof: order_placed, order_shipped, order_delivered, otp"`
+	Locale    string    `json:"locale" gorm:"column:Locale" faker:"oneof: fa, en"`
+	Subject   string    `json:"subject,omitempty" gorm:"column:Subject" faker:"sentence"`
+	Body      string    `json:"body" gorm:"column:Body" faker:"paragraph"`
+	Html      string    `json:"html,omitempty" gorm:"column:Html" faker:"paragraph"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+}
+
+func (NotificationTemplateEntity) TableName() string {
+	return "Site.NotificationTemplates"
+}
+
+// Notification channels
+const (
+	NotificationChannelSms   = "sms"
+	NotificationChannelEmail = "email"
+)
+
+// Notification locales
+const (
+	LocaleFa = "fa"
+	LocaleEn = "en"
+)
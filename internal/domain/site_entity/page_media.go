@@ -1,14 +1,18 @@
 package site_entity
 
+import "site_builder_backend/internal/domain/drive_entity"
+
 type PageMediaEntity struct {
 	Id      string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
 	PageId  string `json:"page_id" gorm:"column:PageId" faker:"uuid_digit"`
 	MediaId string `json:"media_id" gorm:"column:MediaId" faker:"uuid_digit"`
-	
+	// Image is filled in for API answers once the image is processed
+	Image *drive_entity.MediaImage `json:"image,omitempty" gorm:"-"`
+
 	// Relationships
-	Page    PageEntity `json:"page" gorm:"foreignKey:PageId"`
+	Page PageEntity `json:"page" gorm:"foreignKey:PageId"`
 }
 
 func (PageMediaEntity) TableName() string {
 	return "Site.PageMedia"
-} 
\ No newline at end of file
+}
@@ -3,20 +3,25 @@ package site_entity
 import "time"
 
 type SettingsEntity struct {
-	Id         string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
-	SiteId     string    `json:"site_id" gorm:"column:SiteId;unique" faker:"uuid_digit"`
-	UserId     string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
-	CustomerId string    `json:"customer_id" gorm:"column:CustomerId" faker:"uuid_digit"`
-	CreatedAt  time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
-	UpdatedAt  time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
-	Version    time.Time `json:"version" gorm:"column:Version" faker:"time"`
-	IsDeleted  bool      `json:"is_deleted" gorm:"column:IsDeleted" faker:"oneof: true, false"`
-	DeletedAt  time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
-	
+	Id         string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	SiteId     string `json:"site_id" gorm:"column:SiteId;unique" faker:"uuid_digit"`
+	UserId     string `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	CustomerId string `json:"customer_id" gorm:"column:CustomerId" faker:"uuid_digit"`
+	// ReturnWindowDays is how long after delivery customers may ask for a return, zero disables returns
+	ReturnWindowDays int       `json:"return_window_days" gorm:"column:ReturnWindowDays" faker:"boundary_start=0, boundary_end=30"`
+	CreatedAt        time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt        time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+	Version          time.Time `json:"version" gorm:"column:Version" faker:"time"`
+	IsDeleted        bool      `json:"is_deleted" gorm:"column:IsDeleted" faker:"oneof: true, false"`
+	DeletedAt        time.Time `json:"deleted_at,omitempty" gorm:"column:DeletedAt" faker:"time"`
+
 	// Relationships
-	Site       SiteEntity `json:"site" gorm:"foreignKey:SiteId"`
+	Site SiteEntity `json:"site" gorm:"foreignKey:SiteId"`
 }
 
 func (SettingsEntity) TableName() string {
 	return "Site.Settings"
-} 
\ No newline at end of file
+}
+
+// DefaultReturnWindowDays applies to sites that never saved their settings
+const DefaultReturnWindowDays = 14
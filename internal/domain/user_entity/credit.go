@@ -0,0 +1,95 @@
+package user_entity
+
+import "time"
+
+// Credit types, the counters of UserEntity they stand for
+const (
+	CreditSms       = "sms"
+	CreditEmail     = "email"
+	CreditStorageMb = "storage_mb"
+	CreditAi        = "ai"
+	CreditAiImage   = "ai_image"
+)
+
+// IsCreditType tells whether name is one of the credit types
+func IsCreditType(name string) bool {
+	switch name {
+	case CreditSms, CreditEmail, CreditStorageMb, CreditAi, CreditAiImage:
+		return true
+	}
+	return false
+}
+
+// CreditLedgerEntity records one change of a credit counter of a user with the balance it left. Entries
+// are only ever added, the counters of the user can be rebuilt from them.
+type CreditLedgerEntity struct {
+	Id            string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UserId        string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	CreditType    string    `json:"credit_type" gorm:"column:CreditType" faker:"oneof: sms, email, storage_mb, ai, ai_image"`
+	Amount        int       `json:"amount" gorm:"column:Amount" faker:"boundary_start=-100, boundary_end=1000"`
+	Balance       int       `json:"balance" gorm:"column:Balance" faker:"boundary_start=0, boundary_end=10000"`
+	Reason        string    `json:"reason" gorm:"column:Reason" faker:"oneof: plan, top_up, usage"`
+	ReferenceType string    `json:"reference_type,omitempty" gorm:"column:ReferenceType" faker:"oneof: plan_purchase, credit_top_up, credit_usage"`
+	ReferenceId   string    `json:"reference_id,omitempty" gorm:"column:ReferenceId" faker:"uuid_digit"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+}
+
+func (CreditLedgerEntity) TableName() string {
+	return "User.CreditLedger"
+}
+
+// Ledger reasons
+const (
+	CreditReasonPlan  = "plan"
+	CreditReasonTopUp = "top_up"
+	CreditReasonUsage = "usage"
+)
+
+// Ledger references
+const (
+	CreditReferencePlanPurchase = "plan_purchase"
+	CreditReferenceTopUp        = "credit_top_up"
+	CreditReferenceUsage        = "credit_usage"
+)
+
+// CreditTopUpEntity is an owner buying credits priced by a unit price. It is paid through a payment of
+// the "credit" service whose OrderId is the top-up id and credited once that payment is settled.
+type CreditTopUpEntity struct {
+	Id          string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UserId      string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	UnitPriceId string    `json:"unit_price_id" gorm:"column:UnitPriceId" faker:"uuid_digit"`
+	CreditType  string    `json:"credit_type" gorm:"column:CreditType" faker:"oneof: sms, email, storage_mb, ai, ai_image"`
+	Quantity    int       `json:"quantity" gorm:"column:Quantity" faker:"boundary_start=1, boundary_end=1000"`
+	Days        int       `json:"days,omitempty" gorm:"column:Days" faker:"boundary_start=0, boundary_end=365"`
+	Amount      int64     `json:"amount" gorm:"column:Amount" faker:"boundary_start=1000, boundary_end=1000000"`
+	Status      string    `json:"status" gorm:"column:Status" faker:"oneof: pending, paid, failed"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+}
+
+func (CreditTopUpEntity) TableName() string {
+	return "User.CreditTopUps"
+}
+
+const (
+	CreditTopUpStatusPending = "pending"
+	CreditTopUpStatusPaid    = "paid"
+	CreditTopUpStatusFailed  = "failed"
+)
+
+// CreditUsageEntity records credits spent by a billable action of a user, committed from a reservation
+// of the credit meter. Storage is metered in KB against the MB quota, freeing it records a negative
+// amount.
+type CreditUsageEntity struct {
+	Id            string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UserId        string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	CreditType    string    `json:"credit_type" gorm:"column:CreditType" faker:"oneof: sms, email, storage_mb, ai, ai_image"`
+	Amount        int64     `json:"amount" gorm:"column:Amount" faker:"boundary_start=1, boundary_end=100"`
+	Action        string    `json:"action" gorm:"column:Action" faker:"oneof: sms.send, email.send, ai.generate"`
+	ReservationId string    `json:"-" gorm:"column:ReservationId" faker:"uuid_hyphenated"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+}
+
+func (CreditUsageEntity) TableName() string {
+	return "User.CreditUsages"
+}
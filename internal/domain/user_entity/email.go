@@ -0,0 +1,39 @@
+package user_entity
+
+import "time"
+
+// EmailMessageEntity is one email handed to the email consumer, with how its delivery went. MessageId
+// comes with the queued message, so a redelivered message is not sent twice. Smtp tells whether it went
+// through the owner's own server or the platform's, only the latter is paid with EmailCredits. Messages
+// of the platform itself have UserId "0".
+type EmailMessageEntity struct {
+	Id        string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	MessageId string    `json:"message_id" gorm:"column:MessageId" faker:"uuid_hyphenated"`
+	UserId    string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	Recipient string    `json:"recipient" gorm:"column:Recipient" faker:"email"`
+	Subject   string    `json:"subject" gorm:"column:Subject" faker:"sentence"`
+	Template  string    `json:"template,omitempty" gorm:"column:Template" faker:"oneof: plan_activated, plan_expiring, plan_expired"`
+	Smtp      string    `json:"smtp,omitempty" gorm:"column:Smtp" faker:"oneof: platform, custom"`
+	Status    string    `json:"status" gorm:"column:Status" faker:"oneof: queued, sent, failed"`
+	Error     string    `json:"error,omitempty" gorm:"column:Error" faker:"sentence"`
+	Attempts  int       `json:"attempts" gorm:"column:Attempts" faker:"boundary_start=0, boundary_end=5"`
+	SentAt    time.Time `json:"sent_at,omitempty" gorm:"column:SentAt" faker:"time"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+}
+
+func (EmailMessageEntity) TableName() string {
+	return "User.EmailMessages"
+}
+
+const (
+	EmailStatusQueued = "queued"
+	EmailStatusSent   = "sent"
+	EmailStatusFailed = "failed"
+)
+
+// SMTP servers an email is sent through
+const (
+	EmailSmtpPlatform = "platform"
+	EmailSmtpCustom   = "custom"
+)
@@ -0,0 +1,44 @@
+package user_entity
+
+import "time"
+
+// NotificationEntity is an entry of the owner's notification inbox in the dashboard. ReferenceType and
+// ReferenceId point at what it is about, such as an order, for the dashboard to link to. EventKey comes
+// from the event the notification was made for, so a redelivered event is not notified twice. SiteId
+// is "0" for notifications about the account such as its plan.
+type NotificationEntity struct {
+	Id            string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UserId        string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	SiteId        string    `json:"site_id" gorm:"column:SiteId" faker:"uuid_digit"`
+	Type          string    `json:"type" gorm:"column:Type" faker:"oneof: order_placed, return_requested, low_stock, ticket, plan_expiring, plan_expired"`
+	Title         string    `json:"title" gorm:"column:Title" faker:"sentence"`
+	Body          string    `json:"body" gorm:"column:Body" faker:"paragraph"`
+	ReferenceType string    `json:"reference_type,omitempty" gorm:"column:ReferenceType" faker:"oneof: order, return_item, product_variant, ticket, plan"`
+	ReferenceId   string    `json:"reference_id,omitempty" gorm:"column:ReferenceId" faker:"uuid_digit"`
+	EventKey      string    `json:"-" gorm:"column:EventKey" faker:"uuid_hyphenated"`
+	IsRead        bool      `json:"is_read" gorm:"column:IsRead" faker:"oneof: true, false"`
+	ReadAt        time.Time `json:"read_at,omitempty" gorm:"column:ReadAt" faker:"time"`
+	CreatedAt     time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+}
+
+func (NotificationEntity) TableName() string {
+	return "User.Notifications"
+}
+
+const (
+	NotificationTypeOrderPlaced     = "order_placed"
+	NotificationTypeReturnRequested = "return_requested"
+	NotificationTypeLowStock        = "low_stock"
+	NotificationTypeTicket          = "ticket"
+	NotificationTypePlanExpiring    = "plan_expiring"
+	NotificationTypePlanExpired     = "plan_expired"
+)
+
+// What a notification refers to
+const (
+	NotificationReferenceOrder          = "order"
+	NotificationReferenceReturnItem     = "return_item"
+	NotificationReferenceProductVariant = "product_variant"
+	NotificationReferenceTicket         = "ticket"
+	NotificationReferencePlan           = "plan"
+)
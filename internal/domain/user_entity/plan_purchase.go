@@ -0,0 +1,48 @@
+package user_entity
+
+import "time"
+
+// PlanPurchaseEntity is a site owner buying, renewing or upgrading a plan. It is paid through a payment of
+// the "plan" service whose OrderId is the purchase id, and activated once that payment is settled.
+type PlanPurchaseEntity struct {
+	Id        string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	UserId    string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	PlanId    string    `json:"plan_id" gorm:"column:PlanId" faker:"uuid_digit"`
+	Action    string    `json:"action" gorm:"column:Action" faker:"oneof: purchase, renew, upgrade"`
+	Amount    int64     `json:"amount" gorm:"column:Amount" faker:"boundary_start=1000, boundary_end=1000000"`
+	Credit    int64     `json:"credit" gorm:"column:Credit" faker:"boundary_start=0, boundary_end=100000"`
+	Status    string    `json:"status" gorm:"column:Status" faker:"oneof: pending, paid, failed"`
+	StartsAt  time.Time `json:"starts_at,omitempty" gorm:"column:StartsAt" faker:"time"`
+	ExpiresAt time.Time `json:"expires_at,omitempty" gorm:"column:ExpiresAt" faker:"time"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+
+	// Relationships
+	Plan *PlanEntity `json:"plan,omitempty" gorm:"foreignKey:PlanId"`
+}
+
+func (PlanPurchaseEntity) TableName() string {
+	return "User.PlanPurchases"
+}
+
+const (
+	PlanActionPurchase = "purchase"
+	PlanActionRenew    = "renew"
+	// PlanActionUpgrade switches to another plan, what is left of the current one is credited
+	PlanActionUpgrade = "upgrade"
+)
+
+const (
+	PlanPurchaseStatusPending = "pending"
+	PlanPurchaseStatusPaid    = "paid"
+	PlanPurchaseStatusFailed  = "failed"
+)
+
+// Discount types of plans and unit prices
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// PlanShowStatusVisible marks plans offered to site owners
+const PlanShowStatusVisible = "visible"
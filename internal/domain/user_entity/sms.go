@@ -0,0 +1,33 @@
+package user_entity
+
+import "time"
+
+// SmsMessageEntity is one text message handed to the SMS consumer, with how its delivery went. MessageId
+// comes with the queued message, so a redelivered message is not sent twice. Messages of the platform
+// itself have UserId "0", the others are paid with the SMS credits of their user.
+type SmsMessageEntity struct {
+	Id                string    `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	MessageId         string    `json:"message_id" gorm:"column:MessageId" faker:"uuid_hyphenated"`
+	UserId            string    `json:"user_id" gorm:"column:UserId" faker:"uuid_digit"`
+	Phone             string    `json:"phone" gorm:"column:Phone" faker:"phone_number"`
+	Message           string    `json:"message" gorm:"column:Message" faker:"sentence"`
+	Parts             int       `json:"parts" gorm:"column:Parts" faker:"boundary_start=1, boundary_end=5"`
+	Provider          string    `json:"provider,omitempty" gorm:"column:Provider" faker:"oneof: kavenegar, http, log"`
+	ProviderMessageId string    `json:"provider_message_id,omitempty" gorm:"column:ProviderMessageId" faker:"uuid_digit"`
+	Status            string    `json:"status" gorm:"column:Status" faker:"oneof: queued, sent, failed"`
+	Error             string    `json:"error,omitempty" gorm:"column:Error" faker:"sentence"`
+	Attempts          int       `json:"attempts" gorm:"column:Attempts" faker:"boundary_start=0, boundary_end=5"`
+	SentAt            time.Time `json:"sent_at,omitempty" gorm:"column:SentAt" faker:"time"`
+	CreatedAt         time.Time `json:"created_at" gorm:"column:CreatedAt" faker:"time"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"column:UpdatedAt" faker:"time"`
+}
+
+func (SmsMessageEntity) TableName() string {
+	return "User.SmsMessages"
+}
+
+const (
+	SmsStatusQueued = "queued"
+	SmsStatusSent   = "sent"
+	SmsStatusFailed = "failed"
+)
@@ -0,0 +1,16 @@
+package user_entity
+
+// UnitPriceEntity prices one unit of a credit bought on top of the plan. Name is the credit type the price
+// is for, prices with HasDay are per unit and day.
+type UnitPriceEntity struct {
+	Id           string `json:"id" gorm:"column:Id;primaryKey;autoIncrement" faker:"uuid_digit"`
+	Name         string `json:"name" gorm:"column:Name" faker:"oneof: sms, email, storage_mb, ai, ai_image"`
+	HasDay       bool   `json:"has_day" gorm:"column:HasDay" faker:"oneof: true, false"`
+	Price        int64  `json:"price" gorm:"column:Price" faker:"boundary_start=10, boundary_end=10000"`
+	DiscountType string `json:"discount_type,omitempty" gorm:"column:DiscountType" faker:"oneof: percent, fixed"`
+	Discount     int64  `json:"discount,omitempty" gorm:"column:Discount" faker:"boundary_start=0, boundary_end=100"`
+}
+
+func (UnitPriceEntity) TableName() string {
+	return "User.UnitPrices"
+}
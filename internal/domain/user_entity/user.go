@@ -22,6 +22,7 @@ type UserEntity struct {
 	PlanId                   string    `json:"plan_id,omitempty" gorm:"column:PlanId"`
 	PlanStartedAt            time.Time `json:"plan_started_at,omitempty" gorm:"column:PlanStartedAt" faker:"time"`
 	PlanExpiredAt            time.Time `json:"plan_expired_at,omitempty" gorm:"column:PlanExpiredAt" faker:"time"`
+	PlanRemindedAt           time.Time `json:"-" gorm:"column:PlanRemindedAt" faker:"time"`
 	VerifyCode               int       `json:"verify_code,omitempty" gorm:"column:VerifyCode" faker:"boundary_start=1000, boundary_end=9999"`
 	ExpireVerifyCodeAt       time.Time `json:"expire_verify_code_at,omitempty" gorm:"column:ExpireVerifyCodeAt" faker:"time"`
 	AiCredits                int       `json:"ai_credits" gorm:"column:AiCredits" faker:"boundary_start=0, boundary_end=1000"`
@@ -34,7 +35,7 @@ type UserEntity struct {
 	SmtpHost                 string    `json:"smtp_host,omitempty" gorm:"column:Smtp_Host" faker:"url"`
 	SmtpPort                 int       `json:"smtp_port,omitempty" gorm:"column:Smtp_Port" faker:"boundary_start=0, boundary_end=65535"`
 	SmtpUsername             string    `json:"smtp_username,omitempty" gorm:"column:Smtp_Username" faker:"username"`
-	SmtpPassword             string    `json:"smtp_password,omitempty" gorm:"column:Smtp_Password" faker:"-"`
+	SmtpPassword             string    `json:"smtp_password,omitempty" gorm:"column:Smtp_Password;serializer:secret" faker:"-"`
 	SmtpEnableSsl            bool      `json:"smtp_enable_ssl,omitempty" gorm:"column:Smtp_EnableSsl" faker:"oneof: true, false"`
 	SmtpSenderEmail          string    `json:"smtp_sender_email,omitempty" gorm:"column:Smtp_SenderEmail" faker:"email"`
 	IsAdmin                  bool      `json:"is_admin" gorm:"column:IsAdmin" faker:"oneof: true, false"`
@@ -0,0 +1,16 @@
+package user_entity
+
+import "encoding/json"
+
+// SecretMask replaces a credential that is set in API responses
+const SecretMask = "********"
+
+// MarshalJSON masks the SMTP password, it is encrypted at rest and only used to send the user's mail
+func (e UserEntity) MarshalJSON() ([]byte, error) {
+	type user UserEntity
+	masked := user(e)
+	if masked.SmtpPassword != "" {
+		masked.SmtpPassword = SecretMask
+	}
+	return json.Marshal(masked)
+}
@@ -56,15 +56,18 @@ func (s *jwtService) Generate(ctx context.Context, claimsBuilder authInterface.C
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	// Generate refresh token with minimal claims
-	refreshClaims := NewClaimsBuilder().
+	// Generate refresh token with minimal claims, the role included so refreshing keeps it
+	refreshBuilder := NewClaimsBuilder().
 		WithSubject(accessClaims["sub"].(string)).
 		WithIssuer(s.config.Issuer).
 		WithIssuedAt(time.Now()).
 		WithExpiresAt(time.Now().Add(s.config.RefreshTokenExpiration)).
 		WithID(uuid.New().String()).
-		WithCustomClaim("type", string(authInterface.RefreshToken)).
-		Build()
+		WithCustomClaim("type", string(authInterface.RefreshToken))
+	if role, ok := accessClaims["role"]; ok {
+		refreshBuilder = refreshBuilder.WithCustomClaim("role", role)
+	}
+	refreshClaims := refreshBuilder.Build()
 
 	refreshToken, err := s.generateToken(refreshClaims, s.config.RefreshTokenSecret)
 	if err != nil {
@@ -132,6 +135,10 @@ func (s *jwtService) RefreshToken(ctx context.Context, refreshToken string) (*au
 	// Create a new claims builder with the subject from the refresh token
 	subject, _ := claims["sub"].(string)
 	claimsBuilder := NewClaimsBuilder().WithSubject(subject)
+	// The role is carried over, a refreshed customer token must not turn into an owner token
+	if role, ok := claims["role"]; ok {
+		claimsBuilder = claimsBuilder.WithCustomClaim("role", role)
+	}
 
 	// Generate new tokens
 	return s.Generate(ctx, claimsBuilder)
@@ -0,0 +1,256 @@
+package credit_meter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"site_builder_backend/internal/interfaces/cache/credit_meter_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/redis"
+)
+
+// loadScript sets the counter to what the database has less what open reservations hold, unless the
+// counter is cached. Pending reservations that timed out are dropped first.
+// KEYS: counter, pending set
+// ARGV: available, counter ttl, now in milliseconds
+var loadScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[3])
+local pending = 0
+for _, member in ipairs(redis.call('ZRANGE', KEYS[2], 0, -1)) do
+	pending = pending + tonumber(string.match(member, ':(%d+)$'))
+end
+redis.call('SET', KEYS[1], tonumber(ARGV[1]) - pending, 'PX', ARGV[2])
+return 1
+`)
+
+// reserveScript takes the amount from the counter, stores the reservation and adds it to the pending set,
+// scored by when it times out.
+// KEYS: counter, reservation hash, pending set
+// ARGV: amount, user id, credit type, action, reservation ttl, timeout in milliseconds, pending member
+// Returns -1 when the counter is not loaded, 0 when it holds less than the amount
+var reserveScript = goredis.NewScript(`
+local available = redis.call('GET', KEYS[1])
+if not available then
+	return -1
+end
+if tonumber(available) < tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('DECRBY', KEYS[1], ARGV[1])
+redis.call('HSET', KEYS[2], 'user_id', ARGV[2], 'credit_type', ARGV[3], 'amount', ARGV[1], 'action', ARGV[4])
+redis.call('PEXPIRE', KEYS[2], ARGV[5])
+redis.call('ZADD', KEYS[3], ARGV[6], ARGV[7])
+redis.call('PEXPIRE', KEYS[3], ARGV[5])
+return 1
+`)
+
+// takeScript removes the reservation and returns it.
+// KEYS: reservation hash
+var takeScript = goredis.NewScript(`
+local fields = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return fields
+`)
+
+// releaseScript removes the reservation from its hash and the pending set and gives its amount back to the
+// counter, when the counter is still cached. A dropped counter is loaded again without the reservation.
+// KEYS: reservation hash
+// ARGV: counter key prefix, pending key prefix, reservation id
+var releaseScript = goredis.NewScript(`
+local fields = redis.call('HGETALL', KEYS[1])
+if #fields == 0 then
+	return fields
+end
+redis.call('DEL', KEYS[1])
+local reservation = {}
+for i = 1, #fields, 2 do
+	reservation[fields[i]] = fields[i + 1]
+end
+local suffix = reservation['user_id'] .. ':' .. reservation['credit_type']
+redis.call('ZREM', ARGV[2] .. suffix, ARGV[3] .. ':' .. reservation['amount'])
+local counter = ARGV[1] .. suffix
+if redis.call('EXISTS', counter) == 1 then
+	redis.call('INCRBY', counter, reservation['amount'])
+end
+return fields
+`)
+
+// creditScript adds to the counter when it is cached.
+// KEYS: counter
+// ARGV: amount
+var creditScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCRBY', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+const (
+	_counterPrefix = "credit:"
+	_pendingPrefix = "credit:pending:"
+)
+
+type CreditMeter struct {
+	client *goredis.Client
+	l      *logger.ZapLogger
+}
+
+func NewCreditMeter(r *redis.Redis, l *logger.ZapLogger) *CreditMeter {
+	return &CreditMeter{
+		client: r.DefaultClient(),
+		l:      l,
+	}
+}
+
+func (m *CreditMeter) Load(ctx context.Context, userId int64, creditType string, available int64, ttl time.Duration) error {
+	keys := []string{counterKey(userId, creditType), pendingKey(userId, creditType)}
+	err := loadScript.Run(ctx, m.client, keys, available, ttl.Milliseconds(), time.Now().UnixMilli()).Err()
+	if err != nil {
+		m.l.Error("credit_meter - CreditMeter - Load: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (m *CreditMeter) Reserve(ctx context.Context, reservation credit_meter_inter.Reservation, ttl time.Duration) (bool, error) {
+	keys := []string{
+		counterKey(reservation.UserId, reservation.CreditType),
+		reservationKey(reservation.Id),
+		pendingKey(reservation.UserId, reservation.CreditType),
+	}
+	result, err := reserveScript.Run(ctx, m.client, keys,
+		reservation.Amount,
+		reservation.UserId,
+		reservation.CreditType,
+		reservation.Action,
+		ttl.Milliseconds(),
+		time.Now().Add(ttl).UnixMilli(),
+		pendingMember(reservation),
+	).Int()
+	if err != nil {
+		m.l.Error("credit_meter - CreditMeter - Reserve: %v", err)
+		return false, err
+	}
+	if result < 0 {
+		return false, credit_meter_inter.ErrNotLoaded
+	}
+	return result == 1, nil
+}
+
+func (m *CreditMeter) Take(ctx context.Context, id string) (*credit_meter_inter.Reservation, error) {
+	fields, err := takeScript.Run(ctx, m.client, []string{reservationKey(id)}).StringSlice()
+	if err != nil {
+		m.l.Error("credit_meter - CreditMeter - Take: %v", err)
+		return nil, err
+	}
+	return decodeReservation(id, fields)
+}
+
+func (m *CreditMeter) Restore(ctx context.Context, reservation credit_meter_inter.Reservation, ttl time.Duration) error {
+	key := reservationKey(reservation.Id)
+	_, err := m.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"user_id", reservation.UserId,
+			"credit_type", reservation.CreditType,
+			"amount", reservation.Amount,
+			"action", reservation.Action,
+		)
+		pipe.PExpire(ctx, key, ttl)
+		pending := pendingKey(reservation.UserId, reservation.CreditType)
+		pipe.ZAdd(ctx, pending, goredis.Z{Score: float64(time.Now().Add(ttl).UnixMilli()), Member: pendingMember(reservation)})
+		pipe.PExpire(ctx, pending, ttl)
+		return nil
+	})
+	if err != nil {
+		m.l.Error("credit_meter - CreditMeter - Restore: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (m *CreditMeter) Settle(ctx context.Context, reservation credit_meter_inter.Reservation) error {
+	err := m.client.ZRem(ctx, pendingKey(reservation.UserId, reservation.CreditType), pendingMember(reservation)).Err()
+	if err != nil {
+		m.l.Error("credit_meter - CreditMeter - Settle: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (m *CreditMeter) Release(ctx context.Context, id string) (*credit_meter_inter.Reservation, error) {
+	fields, err := releaseScript.Run(ctx, m.client, []string{reservationKey(id)}, _counterPrefix, _pendingPrefix, id).StringSlice()
+	if err != nil {
+		m.l.Error("credit_meter - CreditMeter - Release: %v", err)
+		return nil, err
+	}
+	return decodeReservation(id, fields)
+}
+
+func (m *CreditMeter) Credit(ctx context.Context, userId int64, creditType string, amount int64) error {
+	if err := creditScript.Run(ctx, m.client, []string{counterKey(userId, creditType)}, amount).Err(); err != nil {
+		m.l.Error("credit_meter - CreditMeter - Credit: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (m *CreditMeter) Invalidate(ctx context.Context, userId int64, creditTypes []string) error {
+	keys := make([]string, 0, len(creditTypes))
+	for _, creditType := range creditTypes {
+		keys = append(keys, counterKey(userId, creditType))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := m.client.Del(ctx, keys...).Err(); err != nil {
+		m.l.Error("credit_meter - CreditMeter - Invalidate: %v", err)
+		return err
+	}
+	return nil
+}
+
+func counterKey(userId int64, creditType string) string {
+	return fmt.Sprintf("%s%d:%s", _counterPrefix, userId, creditType)
+}
+
+func pendingKey(userId int64, creditType string) string {
+	return fmt.Sprintf("%s%d:%s", _pendingPrefix, userId, creditType)
+}
+
+// pendingMember carries the amount in the member, so the pending set is summed without reading the
+// reservations
+func pendingMember(reservation credit_meter_inter.Reservation) string {
+	return reservation.Id + ":" + strconv.FormatInt(reservation.Amount, 10)
+}
+
+func reservationKey(id string) string {
+	return "credit:reservation:" + id
+}
+
+func decodeReservation(id string, fields []string) (*credit_meter_inter.Reservation, error) {
+	if len(fields) == 0 {
+		return nil, credit_meter_inter.ErrReservationNotFound
+	}
+	reservation := &credit_meter_inter.Reservation{Id: id}
+	for i := 0; i+1 < len(fields); i += 2 {
+		switch fields[i] {
+		case "user_id":
+			reservation.UserId, _ = strconv.ParseInt(fields[i+1], 10, 64)
+		case "credit_type":
+			reservation.CreditType = fields[i+1]
+		case "amount":
+			reservation.Amount, _ = strconv.ParseInt(fields[i+1], 10, 64)
+		case "action":
+			reservation.Action = fields[i+1]
+		}
+	}
+	return reservation, nil
+}
+
+var _ credit_meter_inter.CreditMeter = (*CreditMeter)(nil)
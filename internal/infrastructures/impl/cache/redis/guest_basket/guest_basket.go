@@ -0,0 +1,72 @@
+package guest_basket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"site_builder_backend/internal/interfaces/cache/basket_cache_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/redis"
+)
+
+type GuestBasketStore struct {
+	client *goredis.Client
+	ttl    time.Duration
+	l      *logger.ZapLogger
+}
+
+func NewGuestBasketStore(r *redis.Redis, ttl time.Duration, l *logger.ZapLogger) *GuestBasketStore {
+	return &GuestBasketStore{
+		client: r.SessionClient(),
+		ttl:    ttl,
+		l:      l,
+	}
+}
+
+func (s *GuestBasketStore) Get(ctx context.Context, token string) (*basket_cache_inter.GuestBasket, error) {
+	data, err := s.client.Get(ctx, key(token)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		s.l.Error("guest_basket - GuestBasketStore - Get: %v", err)
+		return nil, err
+	}
+
+	var basket basket_cache_inter.GuestBasket
+	if err := json.Unmarshal(data, &basket); err != nil {
+		// A basket we cannot read is as good as an expired one
+		s.l.Warn("guest_basket - GuestBasketStore - Get - json.Unmarshal: %v", err)
+		return nil, nil
+	}
+	return &basket, nil
+}
+
+func (s *GuestBasketStore) Save(ctx context.Context, token string, basket *basket_cache_inter.GuestBasket) error {
+	data, err := json.Marshal(basket)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, key(token), data, s.ttl).Err(); err != nil {
+		s.l.Error("guest_basket - GuestBasketStore - Save: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *GuestBasketStore) Delete(ctx context.Context, token string) error {
+	if err := s.client.Del(ctx, key(token)).Err(); err != nil {
+		s.l.Error("guest_basket - GuestBasketStore - Delete: %v", err)
+		return err
+	}
+	return nil
+}
+
+func key(token string) string {
+	return "basket:guest:" + token
+}
+
+var _ basket_cache_inter.GuestBasketStore = (*GuestBasketStore)(nil)
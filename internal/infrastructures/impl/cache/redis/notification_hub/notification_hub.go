@@ -0,0 +1,141 @@
+package notification_hub
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+	"site_builder_backend/internal/interfaces/cache/notification_hub_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/redis"
+)
+
+const (
+	_channelPrefix = "notification:user:"
+	// _subscriberBuffer messages wait for a slow connection before newer ones are dropped
+	_subscriberBuffer = 16
+)
+
+// NotificationHub publishes to a Redis channel per user. Every replica listens to all of them with a
+// single pattern subscription and hands the messages to the connections it holds, so a message reaches
+// the user wherever the load balancer put their dashboard.
+type NotificationHub struct {
+	client      *goredis.Client
+	pubsub      *goredis.PubSub
+	mu          sync.Mutex
+	subscribers map[int64]map[chan notification_hub_inter.Message]struct{}
+	closed      bool
+	l           *logger.ZapLogger
+}
+
+func NewNotificationHub(r *redis.Redis, l *logger.ZapLogger) *NotificationHub {
+	client := r.DefaultClient()
+	h := &NotificationHub{
+		client:      client,
+		pubsub:      client.PSubscribe(context.Background(), _channelPrefix+"*"),
+		subscribers: make(map[int64]map[chan notification_hub_inter.Message]struct{}),
+		l:           l,
+	}
+	go h.run()
+	return h
+}
+
+func (h *NotificationHub) Publish(ctx context.Context, userId int64, message notification_hub_inter.Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if err := h.client.Publish(ctx, _channelPrefix+strconv.FormatInt(userId, 10), payload).Err(); err != nil {
+		h.l.Error("notification_hub - NotificationHub - Publish: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (h *NotificationHub) Subscribe(userId int64) (<-chan notification_hub_inter.Message, func()) {
+	messages := make(chan notification_hub_inter.Message, _subscriberBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(messages)
+		return messages, func() {}
+	}
+	if h.subscribers[userId] == nil {
+		h.subscribers[userId] = make(map[chan notification_hub_inter.Message]struct{})
+	}
+	h.subscribers[userId][messages] = struct{}{}
+
+	var once sync.Once
+	return messages, func() {
+		once.Do(func() { h.unsubscribe(userId, messages) })
+	}
+}
+
+func (h *NotificationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for userId, subscribers := range h.subscribers {
+		for messages := range subscribers {
+			close(messages)
+		}
+		delete(h.subscribers, userId)
+	}
+	if err := h.pubsub.Close(); err != nil {
+		h.l.Error("notification_hub - NotificationHub - Close: %v", err)
+	}
+}
+
+func (h *NotificationHub) unsubscribe(userId int64, messages chan notification_hub_inter.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subscribers, ok := h.subscribers[userId]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[messages]; !ok {
+		// Closed with the hub
+		return
+	}
+	delete(subscribers, messages)
+	close(messages)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, userId)
+	}
+}
+
+// run hands the messages received from Redis to the local subscribers until the hub is closed. The
+// go-redis channel reconnects and resubscribes on its own after a connection loss.
+func (h *NotificationHub) run() {
+	for received := range h.pubsub.Channel() {
+		userId, err := strconv.ParseInt(strings.TrimPrefix(received.Channel, _channelPrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		var message notification_hub_inter.Message
+		if err := json.Unmarshal([]byte(received.Payload), &message); err != nil {
+			h.l.Error("notification_hub - NotificationHub - run: %v", err)
+			continue
+		}
+		h.dispatch(userId, message)
+	}
+}
+
+func (h *NotificationHub) dispatch(userId int64, message notification_hub_inter.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for messages := range h.subscribers[userId] {
+		select {
+		case messages <- message:
+		default:
+			// The connection is not keeping up, it catches up from the unread count of the next message
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package rate_limiter
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"site_builder_backend/internal/interfaces/cache/rate_limiter_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/redis"
+)
+
+// allowScript increments the window counter and starts its expiry on the first hit
+var allowScript = goredis.NewScript(`
+local hits = redis.call('INCR', KEYS[1])
+if hits == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return hits
+`)
+
+type RateLimiter struct {
+	client *goredis.Client
+	l      *logger.ZapLogger
+}
+
+func NewRateLimiter(r *redis.Redis, l *logger.ZapLogger) *RateLimiter {
+	return &RateLimiter{
+		client: r.RateLimiterClient(),
+		l:      l,
+	}
+}
+
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	hits, err := allowScript.Run(ctx, r.client, []string{"rate:" + key}, window.Milliseconds()).Int()
+	if err != nil {
+		r.l.Error("rate_limiter - RateLimiter - Allow: %v", err)
+		return false, err
+	}
+	return hits <= limit, nil
+}
+
+var _ rate_limiter_inter.RateLimiter = (*RateLimiter)(nil)
@@ -0,0 +1,216 @@
+package visit_counter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"site_builder_backend/internal/interfaces/cache/visit_counter_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/redis"
+)
+
+const (
+	_dayLayout       = "20060102"
+	_visitorTTL      = 48 * time.Hour
+	_dailyRetention  = 31
+	_topVisitedCache = time.Minute
+)
+
+// trackScript counts the visit only when the visitor was not seen on this page today.
+// KEYS: unique visitors hll, pending hash, daily ranking zset
+// ARGV: visitor id, hll ttl, pending field, ranking member, ranking ttl
+var trackScript = goredis.NewScript(`
+local added = redis.call('PFADD', KEYS[1], ARGV[1])
+if added == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[2])
+	redis.call('HINCRBY', KEYS[2], ARGV[3], 1)
+	redis.call('ZINCRBY', KEYS[3], 1, ARGV[4])
+	redis.call('EXPIRE', KEYS[3], ARGV[5])
+end
+return added
+`)
+
+// takeScript moves the pending counters aside unless a previous batch is still waiting for its ack.
+// KEYS: pending hash, flushing hash
+var takeScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	if redis.call('EXISTS', KEYS[1]) == 0 then
+		return {}
+	end
+	redis.call('RENAME', KEYS[1], KEYS[2])
+end
+return redis.call('HGETALL', KEYS[2])
+`)
+
+type VisitCounter struct {
+	client *goredis.Client
+	l      *logger.ZapLogger
+}
+
+func NewVisitCounter(r *redis.Redis, l *logger.ZapLogger) *VisitCounter {
+	return &VisitCounter{
+		client: r.DefaultClient(),
+		l:      l,
+	}
+}
+
+func (v *VisitCounter) Track(ctx context.Context, target visit_counter_inter.Target, key visit_counter_inter.VisitKey, visitorId string, now time.Time) (bool, error) {
+	day := now.UTC().Format(_dayLayout)
+	keys := []string{
+		fmt.Sprintf("visit:%s:uv:%d:%d:%s", target, key.SiteId, key.Id, day),
+		pendingKey(target),
+		dailyKey(target, key.SiteId, day),
+	}
+	added, err := trackScript.Run(ctx, v.client, keys,
+		visitorId,
+		int64(_visitorTTL.Seconds()),
+		encodeKey(key),
+		key.Id,
+		int64((_dailyRetention+1)*24*time.Hour/time.Second),
+	).Int()
+	if err != nil {
+		v.l.Error("visit_counter - VisitCounter - Track: %v", err)
+		return false, err
+	}
+	return added == 1, nil
+}
+
+func (v *VisitCounter) IsKnown(ctx context.Context, target visit_counter_inter.Target, key visit_counter_inter.VisitKey) (bool, error) {
+	exists, err := v.client.Exists(ctx, knownKey(target, key)).Result()
+	if err != nil {
+		v.l.Error("visit_counter - VisitCounter - IsKnown: %v", err)
+		return false, err
+	}
+	return exists > 0, nil
+}
+
+func (v *VisitCounter) Remember(ctx context.Context, target visit_counter_inter.Target, key visit_counter_inter.VisitKey, ttl time.Duration) error {
+	if err := v.client.Set(ctx, knownKey(target, key), 1, ttl).Err(); err != nil {
+		v.l.Error("visit_counter - VisitCounter - Remember: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (v *VisitCounter) TakePending(ctx context.Context, target visit_counter_inter.Target) (map[visit_counter_inter.VisitKey]int64, error) {
+	values, err := takeScript.Run(ctx, v.client, []string{pendingKey(target), flushingKey(target)}).StringSlice()
+	if err != nil {
+		v.l.Error("visit_counter - VisitCounter - TakePending: %v", err)
+		return nil, err
+	}
+
+	counts := make(map[visit_counter_inter.VisitKey]int64, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		key, ok := decodeKey(values[i])
+		if !ok {
+			v.l.Warn("visit_counter - VisitCounter - TakePending: malformed field %q", values[i])
+			continue
+		}
+		count, err := strconv.ParseInt(values[i+1], 10, 64)
+		if err != nil {
+			v.l.Warn("visit_counter - VisitCounter - TakePending: malformed count %q", values[i+1])
+			continue
+		}
+		counts[key] += count
+	}
+	return counts, nil
+}
+
+func (v *VisitCounter) AckPending(ctx context.Context, target visit_counter_inter.Target) error {
+	if err := v.client.Del(ctx, flushingKey(target)).Err(); err != nil {
+		v.l.Error("visit_counter - VisitCounter - AckPending: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (v *VisitCounter) TopVisited(ctx context.Context, target visit_counter_inter.Target, siteId int64, days int, limit int, now time.Time) ([]visit_counter_inter.VisitScore, error) {
+	if days < 1 {
+		days = 1
+	}
+	if days > _dailyRetention {
+		days = _dailyRetention
+	}
+
+	today := now.UTC()
+	unionKey := fmt.Sprintf("visit:%s:top:%d:%s:%d", target, siteId, today.Format(_dayLayout), days)
+
+	exists, err := v.client.Exists(ctx, unionKey).Result()
+	if err != nil {
+		v.l.Error("visit_counter - VisitCounter - TopVisited: %v", err)
+		return nil, err
+	}
+	if exists == 0 {
+		dailyKeys := make([]string, 0, days)
+		for i := 0; i < days; i++ {
+			dailyKeys = append(dailyKeys, dailyKey(target, siteId, today.AddDate(0, 0, -i).Format(_dayLayout)))
+		}
+		_, err := v.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.ZUnionStore(ctx, unionKey, &goredis.ZStore{Keys: dailyKeys, Aggregate: "SUM"})
+			pipe.Expire(ctx, unionKey, _topVisitedCache)
+			return nil
+		})
+		if err != nil {
+			v.l.Error("visit_counter - VisitCounter - TopVisited: %v", err)
+			return nil, err
+		}
+	}
+
+	members, err := v.client.ZRevRangeWithScores(ctx, unionKey, 0, int64(limit)-1).Result()
+	if err != nil {
+		v.l.Error("visit_counter - VisitCounter - TopVisited: %v", err)
+		return nil, err
+	}
+
+	scores := make([]visit_counter_inter.VisitScore, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(fmt.Sprint(member.Member), 10, 64)
+		if err != nil {
+			continue
+		}
+		scores = append(scores, visit_counter_inter.VisitScore{Id: id, Visits: int64(member.Score)})
+	}
+	return scores, nil
+}
+
+func pendingKey(target visit_counter_inter.Target) string {
+	return fmt.Sprintf("visit:%s:pending", target)
+}
+
+func flushingKey(target visit_counter_inter.Target) string {
+	return fmt.Sprintf("visit:%s:flushing", target)
+}
+
+func knownKey(target visit_counter_inter.Target, key visit_counter_inter.VisitKey) string {
+	return fmt.Sprintf("visit:%s:known:%s", target, encodeKey(key))
+}
+
+func dailyKey(target visit_counter_inter.Target, siteId int64, day string) string {
+	return fmt.Sprintf("visit:%s:day:%d:%s", target, siteId, day)
+}
+
+func encodeKey(key visit_counter_inter.VisitKey) string {
+	return fmt.Sprintf("%d:%d", key.SiteId, key.Id)
+}
+
+func decodeKey(field string) (visit_counter_inter.VisitKey, bool) {
+	siteId, id, found := strings.Cut(field, ":")
+	if !found {
+		return visit_counter_inter.VisitKey{}, false
+	}
+	s, err := strconv.ParseInt(siteId, 10, 64)
+	if err != nil {
+		return visit_counter_inter.VisitKey{}, false
+	}
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return visit_counter_inter.VisitKey{}, false
+	}
+	return visit_counter_inter.VisitKey{SiteId: s, Id: i}, true
+}
+
+var _ visit_counter_inter.VisitCounter = (*VisitCounter)(nil)
@@ -0,0 +1,277 @@
+package blog_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/db_helper"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// articleEditableColumns are the columns an article edit may write, VisitedCount, ReviewCount and
+// Rate are left to their counters
+var articleEditableColumns = []string{
+	"Title", "Description", "Body", "Slug", "Badges", "SeoTags", "Status", "PublishedAt", "UpdatedAt", "Version",
+}
+
+type ArticleReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type ArticleWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewArticleReadRepository(db *gorm.DB, l *logger.ZapLogger) *ArticleReadRepository {
+	return &ArticleReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewArticleWriteRepository(db *gorm.DB, l *logger.ZapLogger) *ArticleWriteRepository {
+	return &ArticleWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ArticleReadRepository) FindById(id int64) (*blog_entity.ArticleEntity, error) {
+	var entity blog_entity.ArticleEntity
+	err := r.db.Preload("Categories").
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("blog_repo - ArticleReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ArticleReadRepository) FindBySlug(siteId int64, slug string) (*blog_entity.ArticleEntity, error) {
+	var entity blog_entity.ArticleEntity
+	err := r.db.Preload("Categories").Preload("Media").
+		Where(map[string]interface{}{"SiteId": siteId, "Slug": slug, "IsDeleted": false}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("blog_repo - ArticleReadRepository - FindBySlug: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ArticleReadRepository) ExistsBySlug(siteId int64, slug string, excludeId int64) (bool, error) {
+	var count int64
+	query := r.db.Model(&blog_entity.ArticleEntity{}).
+		Where(map[string]interface{}{"SiteId": siteId, "Slug": slug, "IsDeleted": false})
+	if excludeId > 0 {
+		query = query.Where(`"Id" <> ?`, excludeId)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		r.l.Error("blog_repo - ArticleReadRepository - ExistsBySlug: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *ArticleReadRepository) FindAll(filter blog_repo_inter.ArticleFilter) ([]blog_entity.ArticleEntity, int64, error) {
+	return r.paginate(r.filtered(filter), filter, "blog_repo - ArticleReadRepository - FindAll")
+}
+
+func (r *ArticleReadRepository) FindVisible(filter blog_repo_inter.ArticleFilter, now time.Time) ([]blog_entity.ArticleEntity, int64, error) {
+	filter.Status = ""
+	return r.paginate(r.visible(r.filtered(filter), now), filter, "blog_repo - ArticleReadRepository - FindVisible")
+}
+
+func (r *ArticleReadRepository) FindRelated(article *blog_entity.ArticleEntity, limit int, now time.Time) ([]blog_entity.ArticleEntity, error) {
+	var entities []blog_entity.ArticleEntity
+	categoryIds := r.db.Table("Blog.ArticleCategory").Select(`"CategoryId"`).Where(`"ArticleId" = ?`, article.Id)
+	query := r.db.Model(&blog_entity.ArticleEntity{}).
+		Select(`"Blog"."Articles".*`).
+		Joins(`JOIN "Blog"."ArticleCategory" ac ON ac."ArticleId" = "Blog"."Articles"."Id"`).
+		Where(`ac."CategoryId" IN (?)`, categoryIds).
+		Where(`"Blog"."Articles"."Id" <> ?`, article.Id).
+		Where(`"Blog"."Articles"."SiteId" = ?`, article.SiteId)
+	err := r.visible(query, now).
+		Group(`"Blog"."Articles"."Id"`).
+		Order(`COUNT(ac."CategoryId") DESC, "Blog"."Articles"."PublishedAt" DESC`).
+		Limit(limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("blog_repo - ArticleReadRepository - FindRelated: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *ArticleReadRepository) FindVisibleByIds(siteId int64, ids []int64, now time.Time) ([]blog_entity.ArticleEntity, error) {
+	var entities []blog_entity.ArticleEntity
+	if len(ids) == 0 {
+		return entities, nil
+	}
+	query := r.filtered(blog_repo_inter.ArticleFilter{SiteId: siteId}).Where(`"Blog"."Articles"."Id" IN ?`, ids)
+	if err := r.visible(query, now).Find(&entities).Error; err != nil {
+		r.l.Error("blog_repo - ArticleReadRepository - FindVisibleByIds: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *ArticleReadRepository) filtered(filter blog_repo_inter.ArticleFilter) *gorm.DB {
+	query := r.db.Model(&blog_entity.ArticleEntity{}).Where(`"Blog"."Articles"."IsDeleted" = ?`, false)
+	if filter.SiteId > 0 {
+		query = query.Where(`"Blog"."Articles"."SiteId" = ?`, filter.SiteId)
+	}
+	if filter.UserId > 0 {
+		query = query.Where(`"Blog"."Articles"."UserId" = ?`, filter.UserId)
+	}
+	if filter.Status != "" {
+		query = query.Where(`"Blog"."Articles"."Status" = ?`, filter.Status)
+	}
+	if filter.CategoryId > 0 {
+		query = query.Where(`EXISTS (SELECT 1 FROM "Blog"."ArticleCategory" ac WHERE ac."ArticleId" = "Blog"."Articles"."Id" AND ac."CategoryId" = ?)`, filter.CategoryId)
+	}
+	return query
+}
+
+func (r *ArticleReadRepository) visible(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.
+		Where(`"Blog"."Articles"."Status" IN ?`, []string{blog_entity.ArticleStatusPublished, blog_entity.ArticleStatusScheduled}).
+		Where(`"Blog"."Articles"."PublishedAt" <= ?`, now)
+}
+
+func (r *ArticleReadRepository) paginate(query *gorm.DB, filter blog_repo_inter.ArticleFilter, op string) ([]blog_entity.ArticleEntity, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("%s: %v", op, err)
+		return nil, 0, err
+	}
+
+	var entities []blog_entity.ArticleEntity
+	err := query.Preload("Categories").
+		Order(`"Blog"."Articles"."PublishedAt" DESC, "Blog"."Articles"."Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("%s: %v", op, err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *ArticleWriteRepository) Create(entity *blog_entity.ArticleEntity, categoryIds []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("Categories", "Media", "PageUsages").Create(entity).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repositories.ErrConflict
+		}
+		if err != nil {
+			r.l.Error("blog_repo - ArticleWriteRepository - Create: %v", err)
+			return err
+		}
+		return r.replaceCategories(tx, entity, categoryIds)
+	})
+}
+
+// Update writes the editable columns of the article if its Version is still the one it was loaded
+// with. The counters maintained by visits and reviews are never touched.
+func (r *ArticleWriteRepository) Update(entity *blog_entity.ArticleEntity, categoryIds []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		version := time.Now().Truncate(time.Microsecond)
+		result := tx.Model(&blog_entity.ArticleEntity{}).
+			Where(map[string]interface{}{"Id": entity.Id, "Version": entity.Version, "IsDeleted": false}).
+			Select(articleEditableColumns).
+			Updates(map[string]interface{}{
+				"Title":       entity.Title,
+				"Description": entity.Description,
+				"Body":        entity.Body,
+				"Slug":        entity.Slug,
+				"Badges":      entity.Badges,
+				"SeoTags":     entity.SeoTags,
+				"Status":      entity.Status,
+				"PublishedAt": entity.PublishedAt,
+				"UpdatedAt":   entity.UpdatedAt,
+				"Version":     version,
+			})
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return repositories.ErrConflict
+		}
+		if result.Error != nil {
+			r.l.Error("blog_repo - ArticleWriteRepository - Update: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrStale
+		}
+		entity.Version = version
+		return r.replaceCategories(tx, entity, categoryIds)
+	})
+}
+
+func (r *ArticleWriteRepository) Delete(id int64) error {
+	err := r.db.Model(&blog_entity.ArticleEntity{}).
+		Where(map[string]interface{}{"Id": id}).
+		Updates(map[string]interface{}{"IsDeleted": true, "DeletedAt": time.Now()}).Error
+	if err != nil {
+		r.l.Error("blog_repo - ArticleWriteRepository - Delete: %v", err)
+	}
+	return err
+}
+
+func (r *ArticleWriteRepository) IncrementVisitedCounts(deltas []repositories.CounterDelta) error {
+	if err := db_helper.IncrementCounters(r.db, `"Blog"."Articles"`, "VisitedCount", deltas); err != nil {
+		r.l.Error("blog_repo - ArticleWriteRepository - IncrementVisitedCounts: %v", err)
+		return err
+	}
+	return nil
+}
+
+// replaceCategories rewrites Blog.ArticleCategory for the article, silently dropping categories of other sites.
+func (r *ArticleWriteRepository) replaceCategories(tx *gorm.DB, entity *blog_entity.ArticleEntity, categoryIds []int64) error {
+	if err := tx.Where(map[string]interface{}{"ArticleId": entity.Id}).Delete(&blog_entity.ArticleCategoryEntity{}).Error; err != nil {
+		r.l.Error("blog_repo - ArticleWriteRepository - replaceCategories: %v", err)
+		return err
+	}
+	if len(categoryIds) == 0 {
+		return nil
+	}
+
+	var validIds []int64
+	err := tx.Model(&blog_entity.CategoryEntity{}).
+		Where(map[string]interface{}{"Id": categoryIds, "SiteId": entity.SiteId, "IsDeleted": false}).
+		Pluck("Id", &validIds).Error
+	if err != nil {
+		r.l.Error("blog_repo - ArticleWriteRepository - replaceCategories: %v", err)
+		return err
+	}
+
+	links := make([]blog_entity.ArticleCategoryEntity, 0, len(validIds))
+	for _, categoryId := range validIds {
+		links = append(links, blog_entity.ArticleCategoryEntity{
+			ArticleId:  entity.Id,
+			CategoryId: strconv.FormatInt(categoryId, 10),
+		})
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	if err := tx.Omit("Article", "Category").Create(&links).Error; err != nil {
+		r.l.Error("blog_repo - ArticleWriteRepository - replaceCategories: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package blog_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type ArticleCommentReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type ArticleCommentWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewArticleCommentReadRepository(db *gorm.DB, l *logger.ZapLogger) *ArticleCommentReadRepository {
+	return &ArticleCommentReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewArticleCommentWriteRepository(db *gorm.DB, l *logger.ZapLogger) *ArticleCommentWriteRepository {
+	return &ArticleCommentWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ArticleCommentReadRepository) FindById(id int64) (*blog_entity.ArticleCommentEntity, error) {
+	var entity blog_entity.ArticleCommentEntity
+	err := r.db.Where(map[string]interface{}{"Id": id, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("blog_repo - ArticleCommentReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ArticleCommentReadRepository) FindAll(filter blog_repo_inter.ArticleCommentFilter) ([]blog_entity.ArticleCommentEntity, int64, error) {
+	query := r.db.Model(&blog_entity.ArticleCommentEntity{}).Where(`"IsDeleted" = ?`, false)
+	if filter.SiteId > 0 {
+		query = query.Where(`"SiteId" = ?`, filter.SiteId)
+	}
+	if filter.ArticleId > 0 {
+		query = query.Where(`"ArticleId" = ?`, filter.ArticleId)
+	}
+	if filter.Status != "" {
+		query = query.Where(`"Status" = ?`, filter.Status)
+	}
+	if filter.RootsOnly {
+		query = query.Where(`"ParentId" IS NULL`)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("blog_repo - ArticleCommentReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []blog_entity.ArticleCommentEntity
+	err := query.Order(`"CreatedAt" DESC, "Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("blog_repo - ArticleCommentReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *ArticleCommentReadRepository) FindReplies(rootIds []int64, status string) ([]blog_entity.ArticleCommentEntity, error) {
+	var entities []blog_entity.ArticleCommentEntity
+	if len(rootIds) == 0 {
+		return entities, nil
+	}
+	err := r.db.Where(`"RootId" IN ? AND "Status" = ? AND "IsDeleted" = ?`, rootIds, status, false).
+		Order(`"CreatedAt" ASC, "Id" ASC`).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("blog_repo - ArticleCommentReadRepository - FindReplies: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *ArticleCommentWriteRepository) Create(entity *blog_entity.ArticleCommentEntity) error {
+	// Optional references are stored as NULL rather than an empty id
+	omit := []string{"Article"}
+	if entity.ParentId == "" {
+		omit = append(omit, "ParentId", "RootId")
+	}
+	if entity.CustomerId == "" {
+		omit = append(omit, "CustomerId")
+	}
+
+	if err := r.db.Omit(omit...).Create(entity).Error; err != nil {
+		r.l.Error("blog_repo - ArticleCommentWriteRepository - Create: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ArticleCommentWriteRepository) ChangeStatus(id int64, status string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		comment, err := r.lock(tx, id)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&blog_entity.ArticleCommentEntity{}).
+			Where(map[string]interface{}{"Id": id}).
+			Updates(map[string]interface{}{"Status": status, "UpdatedAt": time.Now()}).Error
+		if err != nil {
+			r.l.Error("blog_repo - ArticleCommentWriteRepository - ChangeStatus: %v", err)
+			return err
+		}
+		return r.recomputeRating(tx, comment.ArticleId)
+	})
+}
+
+// Delete soft deletes the comment together with every reply below it
+func (r *ArticleCommentWriteRepository) Delete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		comment, err := r.lock(tx, id)
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`WITH RECURSIVE thread AS (
+				SELECT "Id" FROM "Blog"."ArticleComments" WHERE "Id" = ?
+				UNION ALL
+				SELECT c."Id" FROM "Blog"."ArticleComments" c JOIN thread t ON c."ParentId" = t."Id"
+			)
+			UPDATE "Blog"."ArticleComments" SET "IsDeleted" = ?, "DeletedAt" = ?
+			WHERE "Id" IN (SELECT "Id" FROM thread)`, id, true, time.Now()).Error
+		if err != nil {
+			r.l.Error("blog_repo - ArticleCommentWriteRepository - Delete: %v", err)
+			return err
+		}
+		return r.recomputeRating(tx, comment.ArticleId)
+	})
+}
+
+func (r *ArticleCommentWriteRepository) lock(tx *gorm.DB, id int64) (*blog_entity.ArticleCommentEntity, error) {
+	var comment blog_entity.ArticleCommentEntity
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&comment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("blog_repo - ArticleCommentWriteRepository - lock: %v", err)
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// recomputeRating derives ReviewCount and Rate of the article from its approved comments that carry a rating
+func (r *ArticleCommentWriteRepository) recomputeRating(tx *gorm.DB, articleId string) error {
+	err := tx.Exec(`UPDATE "Blog"."Articles" a
+		SET "ReviewCount" = s.review_count, "Rate" = s.rate
+		FROM (
+			SELECT COUNT(*) AS review_count, COALESCE(ROUND(AVG("Rating")), 0) AS rate
+			FROM "Blog"."ArticleComments"
+			WHERE "ArticleId" = ? AND "Status" = ? AND "Rating" > 0 AND "IsDeleted" = ?
+		) s
+		WHERE a."Id" = ?`, articleId, blog_entity.CommentStatusApproved, false, articleId).Error
+	if err != nil {
+		r.l.Error("blog_repo - ArticleCommentWriteRepository - recomputeRating: %v", err)
+	}
+	return err
+}
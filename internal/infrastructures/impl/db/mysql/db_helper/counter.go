@@ -0,0 +1,57 @@
+package db_helper
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/interfaces/db/repositories"
+)
+
+const _counterBatchSize = 500
+
+// IncrementCounters adds each delta to the column of the matching row with one UPDATE per batch.
+// Rows are matched on both Id and SiteId so a delta can never leak into another site.
+func IncrementCounters(db *gorm.DB, table string, column string, deltas []repositories.CounterDelta) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(deltas); start += _counterBatchSize {
+			end := min(start+_counterBatchSize, len(deltas))
+			batch := deltas[start:end]
+
+			rows := make([]string, 0, len(batch))
+			args := make([]interface{}, 0, len(batch)*3)
+			for _, delta := range batch {
+				rows = append(rows, "(?::bigint, ?::bigint, ?::bigint)")
+				args = append(args, delta.SiteId, delta.Id, delta.Delta)
+			}
+
+			sql := fmt.Sprintf(
+				`UPDATE %s t SET "%s" = t."%s" + v.delta FROM (VALUES %s) AS v(site_id, id, delta) WHERE t."Id" = v.id AND t."SiteId" = v.site_id`,
+				table, column, column, strings.Join(rows, ", "))
+			if err := tx.Exec(sql, args...).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DecrementQuantity takes amount from the Quantity column of the row only when enough is left.
+// It returns repositories.ErrExhausted when the guard fails, which callers use to roll back their transaction.
+func DecrementQuantity(db *gorm.DB, table string, id interface{}, amount int) error {
+	return DecrementColumn(db, table, "Quantity", id, amount)
+}
+
+// DecrementColumn is DecrementQuantity for any integer column
+func DecrementColumn(db *gorm.DB, table string, column string, id interface{}, amount int) error {
+	result := db.Exec(
+		fmt.Sprintf(`UPDATE %s SET "%s" = "%s" - ? WHERE "Id" = ? AND "%s" >= ? AND "IsDeleted" = ?`, table, column, column, column),
+		amount, id, amount, false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrExhausted
+	}
+	return nil
+}
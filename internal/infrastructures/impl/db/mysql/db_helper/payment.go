@@ -0,0 +1,19 @@
+package db_helper
+
+import (
+	"strconv"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/payment_entity"
+)
+
+// CreatePayment inserts the payment another service opens in its own transaction. Until a gateway attempt
+// is started the payment is tracked by its own id, which no other payment shares whatever service it
+// belongs to and which stays far below the twelve digit numbers drawn for attempts.
+func CreatePayment(tx *gorm.DB, payment *payment_entity.PaymentEntity) error {
+	if err := tx.Create(payment).Error; err != nil {
+		return err
+	}
+	payment.TrackingNumber, _ = strconv.ParseInt(payment.Id, 10, 64)
+	return tx.Model(payment).Update("TrackingNumber", payment.TrackingNumber).Error
+}
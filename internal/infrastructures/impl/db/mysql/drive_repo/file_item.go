@@ -0,0 +1,554 @@
+package drive_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// _tree selects the ids of an item, given as first argument, and of the items below it as tree, with
+// their depth below the item. Walks of the tree stop 1000 levels down, so an item that became its own
+// ancestor cannot loop them.
+const _tree = `WITH RECURSIVE tree AS (
+		SELECT "Id", 0 AS "Depth" FROM "Drive"."FileItems" WHERE "Id" = ?
+		UNION ALL
+		SELECT i."Id", t."Depth" + 1 FROM "Drive"."FileItems" i JOIN tree t ON i."ParentId" = t."Id"
+		WHERE t."Depth" < 1000
+	)
+	`
+
+// _published holds for the items that sites may show: public items, and items their user put in the
+// media of one of their sites. Its argument is the public permission.
+const _published = `("Drive"."FileItems"."Permission" = ? OR EXISTS (
+		SELECT 1 FROM "Blog"."ArticleMedia" m JOIN "Blog"."Articles" o ON o."Id" = m."ArticleId"
+		JOIN "Site"."Sites" s ON s."Id" = o."SiteId"
+		WHERE m."MediaId" = "Drive"."FileItems"."Id" AND s."UserId" = "Drive"."FileItems"."UserId"
+		UNION ALL
+		SELECT 1 FROM "Blog"."CategoryMedia" m JOIN "Blog"."Categories" o ON o."Id" = m."CategoryId"
+		JOIN "Site"."Sites" s ON s."Id" = o."SiteId"
+		WHERE m."MediaId" = "Drive"."FileItems"."Id" AND s."UserId" = "Drive"."FileItems"."UserId"
+		UNION ALL
+		SELECT 1 FROM "Product"."ProductMedia" m JOIN "Product"."Products" o ON o."Id" = m."ProductId"
+		JOIN "Site"."Sites" s ON s."Id" = o."SiteId"
+		WHERE m."MediaId" = "Drive"."FileItems"."Id" AND s."UserId" = "Drive"."FileItems"."UserId"
+		UNION ALL
+		SELECT 1 FROM "Product"."CategoryMedia" m JOIN "Product"."Categories" o ON o."Id" = m."CategoryId"
+		JOIN "Site"."Sites" s ON s."Id" = o."SiteId"
+		WHERE m."MediaId" = "Drive"."FileItems"."Id" AND s."UserId" = "Drive"."FileItems"."UserId"
+		UNION ALL
+		SELECT 1 FROM "Site"."PageMedia" m JOIN "Site"."Pages" o ON o."Id" = m."PageId"
+		JOIN "Site"."Sites" s ON s."Id" = o."SiteId"
+		WHERE m."MediaId" = "Drive"."FileItems"."Id" AND s."UserId" = "Drive"."FileItems"."UserId"
+	))`
+
+type FileItemReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type FileItemWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewFileItemReadRepository(db *gorm.DB, l *logger.ZapLogger) *FileItemReadRepository {
+	return &FileItemReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewFileItemWriteRepository(db *gorm.DB, l *logger.ZapLogger) *FileItemWriteRepository {
+	return &FileItemWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *FileItemReadRepository) FindById(id int64) (*drive_entity.FileItemEntity, error) {
+	var item drive_entity.FileItemEntity
+	err := r.db.Preload("Variants").First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *FileItemReadRepository) NameTaken(userId int64, parentId int64, name string) (bool, error) {
+	taken, err := nameTaken(r.db, userId, parentId, name)
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - NameTaken: %v", err)
+	}
+	return taken, err
+}
+
+func (r *FileItemReadRepository) FindChildren(filter drive_repo_inter.FileItemFilter) ([]drive_entity.FileItemEntity, int64, error) {
+	query := r.db.Model(&drive_entity.FileItemEntity{}).
+		Where(`"UserId" = ? AND "IsDeleted" = ?`, filter.UserId, false)
+	if filter.ParentId == 0 {
+		query = query.Where(`"ParentId" IS NULL`)
+	} else {
+		query = query.Where(`"ParentId" = ?`, filter.ParentId)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindChildren: %v", err)
+		return nil, 0, err
+	}
+
+	var items []drive_entity.FileItemEntity
+	err := query.Order(`"IsDirectory" DESC, "Name", "Id"`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&items).Error
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindChildren: %v", err)
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *FileItemReadRepository) FindTrash(userId int64, offset int, limit int) ([]drive_entity.FileItemEntity, int64, error) {
+	// Items deleted with their folder share its DeletedAt, they are restored and purged with it
+	query := r.db.Model(&drive_entity.FileItemEntity{}).
+		Where(`"UserId" = ? AND "IsDeleted" = ?`, userId, true).
+		Where(`NOT EXISTS (SELECT 1 FROM "Drive"."FileItems" p WHERE p."Id" = "Drive"."FileItems"."ParentId"
+			AND p."IsDeleted" = ? AND p."DeletedAt" = "Drive"."FileItems"."DeletedAt")`, true)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindTrash: %v", err)
+		return nil, 0, err
+	}
+
+	var items []drive_entity.FileItemEntity
+	err := query.Order(`"DeletedAt" DESC, "Id" DESC`).
+		Offset(offset).
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindTrash: %v", err)
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *FileItemReadRepository) FindTree(id int64) ([]drive_entity.FileItemEntity, error) {
+	items, err := findTree(r.db, id)
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindTree: %v", err)
+	}
+	return items, err
+}
+
+func (r *FileItemReadRepository) IsWithin(id int64, folderId int64) (bool, error) {
+	within, err := isWithin(r.db, id, folderId)
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - IsWithin: %v", err)
+	}
+	return within, err
+}
+
+func (r *FileItemReadRepository) FindImages(ids []int64) ([]drive_entity.FileItemEntity, error) {
+	var items []drive_entity.FileItemEntity
+	if len(ids) == 0 {
+		return items, nil
+	}
+	err := r.db.Preload("Variants").
+		Where(`"Id" IN ? AND "IsDeleted" = ? AND "ImageStatus" = ?`, ids, false, drive_entity.ImageStatusReady).
+		Find(&items).Error
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindImages: %v", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FileItemReadRepository) IsPublished(id int64) (bool, error) {
+	var published bool
+	err := r.db.Model(&drive_entity.FileItemEntity{}).
+		Select("COUNT(*) > 0").
+		Where(`"Id" = ? AND `+_published, id, drive_entity.FilePermissionPublic).
+		Scan(&published).Error
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - IsPublished: %v", err)
+	}
+	return published, err
+}
+
+func (r *FileItemReadRepository) FindPendingImages(before time.Time, limit int) ([]drive_entity.FileItemEntity, error) {
+	var items []drive_entity.FileItemEntity
+	err := r.db.Where(`"IsDeleted" = ?`, false).
+		Where(r.db.Where(`"ImageStatus" = ? AND "UpdatedAt" < ?`, drive_entity.ImageStatusPending, before).
+			Or(`"ImageStatus" = ? AND `+_published, drive_entity.ImageStatusPrivate, drive_entity.FilePermissionPublic)).
+		Order(`"UpdatedAt"`).
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		r.l.Error("drive_repo - FileItemReadRepository - FindPendingImages: %v", err)
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FileItemWriteRepository) Create(item *drive_entity.FileItemEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return createItem(tx, item)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("drive_repo - FileItemWriteRepository - Create: %v", err)
+	}
+	return err
+}
+
+func (r *FileItemWriteRepository) CreateTree(items []*drive_entity.FileItemEntity, parents []int) error {
+	if len(items) == 0 {
+		return nil
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		userId, err := strconv.ParseInt(items[0].UserId, 10, 64)
+		if err != nil {
+			return err
+		}
+		if err := lockDrive(tx, userId); err != nil {
+			return err
+		}
+		var parentId int64
+		if items[0].ParentId != "" {
+			if parentId, err = strconv.ParseInt(items[0].ParentId, 10, 64); err != nil {
+				return err
+			}
+		}
+		if err := checkFolder(tx, userId, parentId); err != nil {
+			return err
+		}
+		for i, item := range items {
+			if i > 0 {
+				item.ParentId = items[parents[i]].Id
+			}
+			if err := createItem(tx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("drive_repo - FileItemWriteRepository - CreateTree: %v", err)
+	}
+	return err
+}
+
+func (r *FileItemWriteRepository) Move(id int64, parentId int64, name string, path string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		item, err := lockItem(tx, id, false)
+		if err != nil {
+			return err
+		}
+		userId, _ := strconv.ParseInt(item.UserId, 10, 64)
+		if err := checkFolder(tx, userId, parentId); err != nil {
+			return err
+		}
+		if parentId != 0 {
+			within, err := isWithin(tx, parentId, id)
+			if err != nil {
+				return err
+			}
+			if within {
+				return drive_repo_inter.ErrCycle
+			}
+		}
+		sameFolder := item.ParentId == strconv.FormatInt(parentId, 10) || item.ParentId == "" && parentId == 0
+		if !sameFolder || item.Name != name {
+			taken, err := nameTaken(tx, userId, parentId, name)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return repositories.ErrConflict
+			}
+		}
+		return place(tx, item, parentId, name, path, map[string]interface{}{})
+	})
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) && !errors.Is(err, repositories.ErrConflict) &&
+		!errors.Is(err, drive_repo_inter.ErrCycle) {
+		r.l.Error("drive_repo - FileItemWriteRepository - Move: %v", err)
+	}
+	return err
+}
+
+func (r *FileItemWriteRepository) Trash(id int64, at time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockItem(tx, id, false); err != nil {
+			return err
+		}
+		// Items deleted before keep their DeletedAt, restoring the folder leaves them in the trash
+		return tx.Exec(_tree+`UPDATE "Drive"."FileItems" SET "IsDeleted" = ?, "DeletedAt" = ?, "UpdatedAt" = ?
+			WHERE "Id" IN (SELECT "Id" FROM tree) AND "IsDeleted" = ?`, id, true, at, time.Now(), false).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		r.l.Error("drive_repo - FileItemWriteRepository - Trash: %v", err)
+	}
+	return err
+}
+
+func (r *FileItemWriteRepository) Restore(id int64, parentId int64, name string, path string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		item, err := lockItem(tx, id, true)
+		if err != nil {
+			return err
+		}
+		userId, _ := strconv.ParseInt(item.UserId, 10, 64)
+		if err := checkFolder(tx, userId, parentId); err != nil {
+			return err
+		}
+		taken, err := nameTaken(tx, userId, parentId, name)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return repositories.ErrConflict
+		}
+
+		err = tx.Exec(_tree+`UPDATE "Drive"."FileItems" SET "IsDeleted" = ?, "DeletedAt" = NULL, "UpdatedAt" = ?
+			WHERE "Id" IN (SELECT "Id" FROM tree WHERE "Depth" > 0) AND "IsDeleted" = ? AND "DeletedAt" = ?`,
+			id, false, time.Now(), true, item.DeletedAt).Error
+		if err != nil {
+			return err
+		}
+		return place(tx, item, parentId, name, path, map[string]interface{}{"IsDeleted": false, "DeletedAt": nil})
+	})
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("drive_repo - FileItemWriteRepository - Restore: %v", err)
+	}
+	return err
+}
+
+func (r *FileItemWriteRepository) Purge(id int64) ([]drive_entity.FileItemEntity, error) {
+	var items []drive_entity.FileItemEntity
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockItem(tx, id, true); err != nil {
+			return err
+		}
+		var err error
+		if items, err = findTree(tx, id); err != nil {
+			return err
+		}
+		ids := make([]int64, 0, len(items))
+		for _, item := range items {
+			itemId, _ := strconv.ParseInt(item.Id, 10, 64)
+			ids = append(ids, itemId)
+		}
+		var variants []drive_entity.FileVariantEntity
+		if err := tx.Where(`"FileItemId" IN ?`, ids).Find(&variants).Error; err != nil {
+			return err
+		}
+		for _, variant := range variants {
+			for i := range items {
+				if items[i].Id == variant.FileItemId {
+					items[i].Variants = append(items[i].Variants, variant)
+				}
+			}
+		}
+		if err := tx.Where(`"FileItemId" IN ?`, ids).Delete(&drive_entity.FileVariantEntity{}).Error; err != nil {
+			return err
+		}
+		return tx.Where(`"Id" IN ?`, ids).Delete(&drive_entity.FileItemEntity{}).Error
+	})
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			r.l.Error("drive_repo - FileItemWriteRepository - Purge: %v", err)
+		}
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *FileItemWriteRepository) SaveImage(id int64, status string, variants []drive_entity.FileVariantEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var item drive_entity.FileItemEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(`"Id" = ?`, id).First(&item).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if item.ImageStatus != drive_entity.ImageStatusPending && item.ImageStatus != drive_entity.ImageStatusPrivate {
+			return repositories.ErrConflict
+		}
+
+		if err := tx.Where(`"FileItemId" = ?`, id).Delete(&drive_entity.FileVariantEntity{}).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for i := range variants {
+			variants[i].FileItemId = item.Id
+			variants[i].CreatedAt = now
+		}
+		if len(variants) > 0 {
+			if err := tx.Create(&variants).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&drive_entity.FileItemEntity{}).Where(`"Id" = ?`, id).
+			Updates(map[string]interface{}{"ImageStatus": status, "UpdatedAt": now}).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("drive_repo - FileItemWriteRepository - SaveImage: %v", err)
+	}
+	return err
+}
+
+// lockDrive serializes the changes to the tree of the user's drive, so concurrent moves cannot make a
+// cycle nor put items in a folder being deleted
+func lockDrive(tx *gorm.DB, userId int64) error {
+	return tx.Exec(`SELECT pg_advisory_xact_lock(?)`, userId).Error
+}
+
+// lockItem locks the drive of the item's user, then the item, live or deleted as asked
+func lockItem(tx *gorm.DB, id int64, deleted bool) (*drive_entity.FileItemEntity, error) {
+	var userIds []int64
+	err := tx.Model(&drive_entity.FileItemEntity{}).Where(`"Id" = ?`, id).Pluck("UserId", &userIds).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(userIds) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+	if err := lockDrive(tx, userIds[0]); err != nil {
+		return nil, err
+	}
+
+	var item drive_entity.FileItemEntity
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(map[string]interface{}{"Id": id, "IsDeleted": deleted}).
+		First(&item).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// checkFolder returns ErrNotFound unless parentId is a live folder of the user or zero, the root
+func checkFolder(tx *gorm.DB, userId int64, parentId int64) error {
+	if parentId == 0 {
+		return nil
+	}
+	var count int64
+	err := tx.Model(&drive_entity.FileItemEntity{}).
+		Where(`"Id" = ? AND "UserId" = ? AND "IsDirectory" = ? AND "IsDeleted" = ?`, parentId, userId, true, false).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// place names the item and puts it in the folder parentId with the other updates, then rewrites the
+// paths of the items below it
+func place(tx *gorm.DB, item *drive_entity.FileItemEntity, parentId int64, name string, path string, updates map[string]interface{}) error {
+	id, _ := strconv.ParseInt(item.Id, 10, 64)
+	if item.FilePath != path {
+		err := tx.Exec(_tree+`UPDATE "Drive"."FileItems" SET "FilePath" = CAST(? AS text) || substr("FilePath", ?)
+			WHERE "Id" IN (SELECT "Id" FROM tree WHERE "Depth" > 0)`, id, path, utf8.RuneCountInString(item.FilePath)+1).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	updates["Name"] = name
+	updates["ParentId"] = nil
+	if parentId != 0 {
+		updates["ParentId"] = parentId
+	}
+	updates["FilePath"] = path
+	updates["UpdatedAt"] = time.Now()
+	return tx.Model(&drive_entity.FileItemEntity{}).Where(`"Id" = ?`, id).Updates(updates).Error
+}
+
+// findTree returns the item and the items below it, ordered by depth
+func findTree(db *gorm.DB, id int64) ([]drive_entity.FileItemEntity, error) {
+	var items []drive_entity.FileItemEntity
+	err := db.Raw(_tree+`SELECT f.* FROM "Drive"."FileItems" f JOIN tree t ON f."Id" = t."Id"
+		ORDER BY t."Depth", f."Id"`, id).Scan(&items).Error
+	return items, err
+}
+
+// isWithin walks up from the item to the root looking for the folder
+func isWithin(db *gorm.DB, id int64, folderId int64) (bool, error) {
+	var count int64
+	err := db.Raw(`WITH RECURSIVE up AS (
+			SELECT "Id", "ParentId", 0 AS "Depth" FROM "Drive"."FileItems" WHERE "Id" = ?
+			UNION ALL
+			SELECT p."Id", p."ParentId", u."Depth" + 1 FROM "Drive"."FileItems" p JOIN up u ON p."Id" = u."ParentId"
+			WHERE u."Depth" < 1000
+		)
+		SELECT count(*) FROM up WHERE "Id" = ?`, id, folderId).Scan(&count).Error
+	return count > 0, err
+}
+
+// nameTaken reports whether a live item of the user's folder has the name, parentId zero is the root
+func nameTaken(db *gorm.DB, userId int64, parentId int64, name string) (bool, error) {
+	query := db.Model(&drive_entity.FileItemEntity{}).
+		Where(`"UserId" = ? AND "Name" = ? AND "IsDeleted" = ?`, userId, name, false)
+	if parentId == 0 {
+		query = query.Where(`"ParentId" IS NULL`)
+	} else {
+		query = query.Where(`"ParentId" = ?`, parentId)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// createItem inserts the item unless its folder has a live item of the same name
+func createItem(tx *gorm.DB, item *drive_entity.FileItemEntity) error {
+	userId, err := strconv.ParseInt(item.UserId, 10, 64)
+	if err != nil {
+		return err
+	}
+	var parentId int64
+	if item.ParentId != "" {
+		if parentId, err = strconv.ParseInt(item.ParentId, 10, 64); err != nil {
+			return err
+		}
+	}
+	taken, err := nameTaken(tx, userId, parentId, item.Name)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return repositories.ErrConflict
+	}
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+	item.IsDeleted = false
+	omit := []string{"Version", "DeletedAt", "Parent", "Children", "Variants"}
+	if item.ParentId == "" {
+		omit = append(omit, "ParentId")
+	}
+	return tx.Omit(omit...).Create(item).Error
+}
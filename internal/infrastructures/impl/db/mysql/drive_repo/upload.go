@@ -0,0 +1,133 @@
+package drive_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type UploadReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type UploadWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewUploadReadRepository(db *gorm.DB, l *logger.ZapLogger) *UploadReadRepository {
+	return &UploadReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewUploadWriteRepository(db *gorm.DB, l *logger.ZapLogger) *UploadWriteRepository {
+	return &UploadWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *UploadReadRepository) FindById(id int64) (*drive_entity.UploadEntity, error) {
+	var upload drive_entity.UploadEntity
+	err := r.db.Preload("Parts", func(db *gorm.DB) *gorm.DB {
+		return db.Order(`"PartNumber"`)
+	}).First(&upload, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("drive_repo - UploadReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &upload, nil
+}
+
+func (r *UploadReadRepository) FindExpired(now time.Time, limit int) ([]drive_entity.UploadEntity, error) {
+	var uploads []drive_entity.UploadEntity
+	err := r.db.Where(`"Status" = ? AND "ExpiresAt" < ?`, drive_entity.UploadStatusUploading, now).
+		Order(`"ExpiresAt"`).
+		Limit(limit).
+		Find(&uploads).Error
+	if err != nil {
+		r.l.Error("drive_repo - UploadReadRepository - FindExpired: %v", err)
+		return nil, err
+	}
+	return uploads, nil
+}
+
+func (r *UploadWriteRepository) Create(upload *drive_entity.UploadEntity) error {
+	now := time.Now()
+	upload.CreatedAt = now
+	upload.UpdatedAt = now
+	omit := []string{"FileItemId", "Parts"}
+	if upload.ParentId == "" {
+		omit = append(omit, "ParentId")
+	}
+	if err := r.db.Omit(omit...).Create(upload).Error; err != nil {
+		r.l.Error("drive_repo - UploadWriteRepository - Create: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UploadWriteRepository) SavePart(part *drive_entity.UploadPartEntity) error {
+	part.CreatedAt = time.Now()
+	err := r.db.Exec(`INSERT INTO "Drive"."UploadParts" ("UploadId", "PartNumber", "Size", "ETag", "CreatedAt")
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT ("UploadId", "PartNumber") DO UPDATE SET "Size" = EXCLUDED."Size", "ETag" = EXCLUDED."ETag", "CreatedAt" = EXCLUDED."CreatedAt"`,
+		part.UploadId, part.PartNumber, part.Size, part.ETag, part.CreatedAt).Error
+	if err != nil {
+		r.l.Error("drive_repo - UploadWriteRepository - SavePart: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *UploadWriteRepository) Complete(id int64, item *drive_entity.FileItemEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var upload drive_entity.UploadEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(`"Status" = ?`, drive_entity.UploadStatusUploading).
+			First(&upload, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := createItem(tx, item); err != nil {
+			return err
+		}
+		return tx.Model(&upload).Updates(map[string]interface{}{
+			"Status":     drive_entity.UploadStatusCompleted,
+			"FileItemId": item.Id,
+			"UpdatedAt":  time.Now(),
+		}).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("drive_repo - UploadWriteRepository - Complete: %v", err)
+	}
+	return err
+}
+
+func (r *UploadWriteRepository) End(id int64, status string) error {
+	result := r.db.Model(&drive_entity.UploadEntity{}).
+		Where(`"Id" = ? AND "Status" = ?`, id, drive_entity.UploadStatusUploading).
+		Updates(map[string]interface{}{"Status": status, "UpdatedAt": time.Now()})
+	if result.Error != nil {
+		r.l.Error("drive_repo - UploadWriteRepository - End: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package order_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type BasketReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type BasketWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewBasketReadRepository(db *gorm.DB, l *logger.ZapLogger) *BasketReadRepository {
+	return &BasketReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewBasketWriteRepository(db *gorm.DB, l *logger.ZapLogger) *BasketWriteRepository {
+	return &BasketWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *BasketReadRepository) FindByCustomer(siteId int64, customerId int64) (*order_entity.BasketEntity, error) {
+	var entity order_entity.BasketEntity
+	err := r.db.
+		Preload("BasketItems", func(db *gorm.DB) *gorm.DB {
+			return db.Where(`"IsDeleted" = ?`, false).Order(`"Id"`)
+		}).
+		Where(map[string]interface{}{"SiteId": siteId, "CustomerId": customerId, "IsDeleted": false}).
+		Order(`"Id" DESC`).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("order_repo - BasketReadRepository - FindByCustomer: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *BasketWriteRepository) Save(basket *order_entity.BasketEntity) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if basket.Id == "" {
+			basket.CreatedAt = now
+			basket.UpdatedAt = now
+			omit := []string{"BasketItems"}
+			if basket.DiscountId == "" {
+				omit = append(omit, "DiscountId")
+			}
+			if err := tx.Omit(omit...).Create(basket).Error; err != nil {
+				return err
+			}
+		} else {
+			basket.UpdatedAt = now
+			var discountId interface{}
+			if basket.DiscountId != "" {
+				discountId = basket.DiscountId
+			}
+			err := tx.Model(&order_entity.BasketEntity{}).
+				Where(map[string]interface{}{"Id": basket.Id}).
+				Updates(map[string]interface{}{
+					"TotalRawPrice":                basket.TotalRawPrice,
+					"TotalCouponDiscount":          basket.TotalCouponDiscount,
+					"TotalPriceWithCouponDiscount": basket.TotalPriceWithCouponDiscount,
+					"DiscountId":                   discountId,
+					"UpdatedAt":                    now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		keep := make([]string, 0, len(basket.BasketItems))
+		for i := range basket.BasketItems {
+			item := &basket.BasketItems[i]
+			item.BasketId = basket.Id
+			item.UpdatedAt = now
+			if item.Id == "" {
+				item.CreatedAt = now
+				if err := tx.Omit("Basket").Create(item).Error; err != nil {
+					return err
+				}
+			} else {
+				err := tx.Model(&order_entity.BasketItemEntity{}).
+					Where(map[string]interface{}{"Id": item.Id, "BasketId": basket.Id}).
+					Updates(map[string]interface{}{
+						"Quantity":                     item.Quantity,
+						"RawPrice":                     item.RawPrice,
+						"FinalRawPrice":                item.FinalRawPrice,
+						"FinalPriceWithCouponDiscount": item.FinalPriceWithCouponDiscount,
+						"JustCouponPrice":              item.JustCouponPrice,
+						"JustDiscountPrice":            item.JustDiscountPrice,
+						"UpdatedAt":                    now,
+					}).Error
+				if err != nil {
+					return err
+				}
+			}
+			keep = append(keep, item.Id)
+		}
+
+		removed := tx.Model(&order_entity.BasketItemEntity{}).
+			Where(`"BasketId" = ? AND "IsDeleted" = ?`, basket.Id, false)
+		if len(keep) > 0 {
+			removed = removed.Where(`"Id" NOT IN ?`, keep)
+		}
+		return removed.Updates(map[string]interface{}{"IsDeleted": true, "DeletedAt": now}).Error
+	})
+	if err != nil {
+		r.l.Error("order_repo - BasketWriteRepository - Save: %v", err)
+	}
+	return err
+}
@@ -0,0 +1,309 @@
+package order_repo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/db_helper"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type OrderReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type OrderWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewOrderReadRepository(db *gorm.DB, l *logger.ZapLogger) *OrderReadRepository {
+	return &OrderReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewOrderWriteRepository(db *gorm.DB, l *logger.ZapLogger) *OrderWriteRepository {
+	return &OrderWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *OrderReadRepository) HasPurchased(customerId int64, productId int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&order_entity.OrderItemEntity{}).
+		Joins(`JOIN "Order"."Orders" o ON o."Id" = "Order"."OrderItems"."OrderId"`).
+		Where(`o."CustomerId" = ? AND o."IsDeleted" = ? AND o."OrderStatus" IN ?`, customerId, false, order_entity.PurchasedOrderStatuses).
+		Where(`"Order"."OrderItems"."ProductId" = ? AND "Order"."OrderItems"."IsDeleted" = ?`, productId, false).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		r.l.Error("order_repo - OrderReadRepository - HasPurchased: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *OrderReadRepository) FindById(id int64) (*order_entity.OrderEntity, error) {
+	var entity order_entity.OrderEntity
+	err := r.db.Preload("OrderItems", func(db *gorm.DB) *gorm.DB {
+		return db.Where(`"IsDeleted" = ?`, false).Order(`"Id"`)
+	}).
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("order_repo - OrderReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *OrderReadRepository) FindAll(filter order_repo_inter.OrderFilter) ([]order_entity.OrderEntity, int64, error) {
+	query := r.db.Model(&order_entity.OrderEntity{}).Where(`"IsDeleted" = ?`, false)
+	if filter.SiteId > 0 {
+		query = query.Where(`"SiteId" = ?`, filter.SiteId)
+	}
+	if filter.CustomerId > 0 {
+		query = query.Where(`"CustomerId" = ?`, filter.CustomerId)
+	}
+	if filter.Status != "" {
+		query = query.Where(`"OrderStatus" = ?`, filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("order_repo - OrderReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []order_entity.OrderEntity
+	err := query.Order(`"CreatedAt" DESC, "Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("order_repo - OrderReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *OrderReadRepository) FindHistory(orderId int64) ([]order_entity.OrderStatusHistoryEntity, error) {
+	var entities []order_entity.OrderStatusHistoryEntity
+	err := r.db.Where(map[string]interface{}{"OrderId": orderId}).
+		Order(`"CreatedAt", "Id"`).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("order_repo - OrderReadRepository - FindHistory: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *OrderReadRepository) FindItemById(id int64) (*order_entity.OrderItemEntity, error) {
+	var entity order_entity.OrderItemEntity
+	err := r.db.Preload("Order").
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && entity.Order.IsDeleted) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("order_repo - OrderReadRepository - FindItemById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *OrderReadRepository) FindByIdempotencyKey(siteId int64, customerId int64, key string) (*order_entity.OrderEntity, error) {
+	var entity order_entity.OrderEntity
+	err := r.db.Preload("OrderItems").
+		Where(map[string]interface{}{"SiteId": siteId, "CustomerId": customerId, "IdempotencyKey": key}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("order_repo - OrderReadRepository - FindByIdempotencyKey: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *OrderWriteRepository) Place(place order_repo_inter.PlaceOrder) error {
+	order := place.Order
+	now := time.Now()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		omit := []string{"OrderItems"}
+		if order.DiscountId == "" {
+			omit = append(omit, "DiscountId")
+		}
+		// The unique idempotency key turns a concurrent second checkout into a no-op insert
+		result := tx.Omit(omit...).Clauses(clause.OnConflict{DoNothing: true}).Create(order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+
+		for i := range order.OrderItems {
+			item := &order.OrderItems[i]
+			item.OrderId = order.Id
+			item.CreatedAt = now
+			item.UpdatedAt = now
+			if err := tx.Omit("Order", "ReturnItem").Create(item).Error; err != nil {
+				return err
+			}
+
+			err := db_helper.DecrementColumn(tx, `"Product"."ProductVariants"`, "Stock", item.ProductVariantId, item.Quantity)
+			if errors.Is(err, repositories.ErrExhausted) {
+				return fmt.Errorf("%w: variant %s", repositories.ErrOutOfStock, item.ProductVariantId)
+			}
+			if err != nil {
+				return err
+			}
+			reservation := order_entity.InventoryReservationEntity{
+				SiteId:           order.SiteId,
+				OrderId:          order.Id,
+				ProductVariantId: item.ProductVariantId,
+				Quantity:         item.Quantity,
+				Status:           order_entity.InventoryReservationStatusReserved,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}
+			if err := tx.Omit("Order").Create(&reservation).Error; err != nil {
+				return err
+			}
+		}
+
+		if place.DiscountId > 0 {
+			if err := db_helper.DecrementQuantity(tx, `"Product"."Discounts"`, place.DiscountId, 1); err != nil {
+				return err
+			}
+		}
+		for couponId, units := range place.CouponUnits {
+			if err := db_helper.DecrementQuantity(tx, `"Product"."Coupons"`, couponId, units); err != nil {
+				return err
+			}
+		}
+
+		payment := place.Payment
+		payment.OrderId, _ = strconv.ParseInt(order.Id, 10, 64)
+		payment.CreatedAt = now
+		payment.UpdatedAt = now
+		if err := db_helper.CreatePayment(tx, payment); err != nil {
+			return err
+		}
+
+		if place.History != nil {
+			place.History.OrderId = order.Id
+			place.History.CreatedAt = now
+			if err := r.createHistory(tx, place.History); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&order_entity.BasketEntity{}).
+			Where(map[string]interface{}{"Id": place.BasketId}).
+			Updates(map[string]interface{}{"IsDeleted": true, "DeletedAt": now}).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) && !errors.Is(err, repositories.ErrOutOfStock) &&
+		!errors.Is(err, repositories.ErrExhausted) {
+		r.l.Error("order_repo - OrderWriteRepository - Place: %v", err)
+	}
+	return err
+}
+
+func (r *OrderWriteRepository) ChangeStatus(transition order_repo_inter.StatusTransition) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"OrderStatus": transition.To, "UpdatedAt": now}
+		if transition.TrackingCode != "" {
+			updates["TrackingCode"] = transition.TrackingCode
+		}
+		// Guarding on the current status makes concurrent transitions of the same order fail instead of racing
+		result := tx.Model(&order_entity.OrderEntity{}).
+			Where(`"Id" = ? AND "OrderStatus" = ? AND "IsDeleted" = ?`, transition.OrderId, transition.From, false).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+
+		switch transition.Reservations {
+		case order_repo_inter.ReservationCommit:
+			err := tx.Model(&order_entity.InventoryReservationEntity{}).
+				Where(`"OrderId" = ? AND "Status" = ?`, transition.OrderId, order_entity.InventoryReservationStatusReserved).
+				Updates(map[string]interface{}{"Status": order_entity.InventoryReservationStatusCommitted, "UpdatedAt": now}).Error
+			if err != nil {
+				return err
+			}
+		case order_repo_inter.ReservationRelease:
+			if err := r.release(tx, transition.OrderId, now); err != nil {
+				return err
+			}
+		}
+
+		if transition.History == nil {
+			return nil
+		}
+		transition.History.CreatedAt = now
+		return r.createHistory(tx, transition.History)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("order_repo - OrderWriteRepository - ChangeStatus: %v", err)
+	}
+	return err
+}
+
+// release gives the stock of the order's active reservations back to their variants
+func (r *OrderWriteRepository) release(tx *gorm.DB, orderId int64, now time.Time) error {
+	var reservations []order_entity.InventoryReservationEntity
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(`"OrderId" = ? AND "Status" <> ?`, orderId, order_entity.InventoryReservationStatusReleased).
+		Find(&reservations).Error
+	if err != nil {
+		return err
+	}
+	for _, reservation := range reservations {
+		err := tx.Exec(`UPDATE "Product"."ProductVariants" SET "Stock" = "Stock" + ? WHERE "Id" = ?`,
+			reservation.Quantity, reservation.ProductVariantId).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Model(&order_entity.InventoryReservationEntity{}).
+		Where(`"OrderId" = ? AND "Status" <> ?`, orderId, order_entity.InventoryReservationStatusReleased).
+		Updates(map[string]interface{}{"Status": order_entity.InventoryReservationStatusReleased, "UpdatedAt": now}).Error
+}
+
+func (r *OrderWriteRepository) createHistory(tx *gorm.DB, history *order_entity.OrderStatusHistoryEntity) error {
+	// Optional columns are stored as NULL rather than an empty value
+	omit := []string{"Order"}
+	if history.FromStatus == "" {
+		omit = append(omit, "FromStatus")
+	}
+	if history.ActorId == "" {
+		omit = append(omit, "ActorId")
+	}
+	return tx.Omit(omit...).Create(history).Error
+}
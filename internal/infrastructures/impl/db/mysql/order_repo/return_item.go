@@ -0,0 +1,172 @@
+package order_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type ReturnItemReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type ReturnItemWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewReturnItemReadRepository(db *gorm.DB, l *logger.ZapLogger) *ReturnItemReadRepository {
+	return &ReturnItemReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewReturnItemWriteRepository(db *gorm.DB, l *logger.ZapLogger) *ReturnItemWriteRepository {
+	return &ReturnItemWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ReturnItemReadRepository) FindById(id int64) (*order_entity.ReturnItemEntity, error) {
+	var entity order_entity.ReturnItemEntity
+	err := r.db.Preload("OrderItem").
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("order_repo - ReturnItemReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ReturnItemReadRepository) FindAll(filter order_repo_inter.ReturnItemFilter) ([]order_entity.ReturnItemEntity, int64, error) {
+	query := r.db.Model(&order_entity.ReturnItemEntity{}).Where(`"IsDeleted" = ?`, false)
+	if filter.SiteId > 0 {
+		query = query.Where(`"SiteId" = ?`, filter.SiteId)
+	}
+	if filter.CustomerId > 0 {
+		query = query.Where(`"CustomerId" = ?`, filter.CustomerId)
+	}
+	if filter.Status > 0 {
+		query = query.Where(`"OrderStatus" = ?`, filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("order_repo - ReturnItemReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []order_entity.ReturnItemEntity
+	err := query.Preload("OrderItem").
+		Order(`"CreatedAt" DESC, "Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("order_repo - ReturnItemReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *ReturnItemReadRepository) FindByOrderId(orderId int64) ([]order_entity.ReturnItemEntity, error) {
+	var entities []order_entity.ReturnItemEntity
+	err := r.db.Where(map[string]interface{}{"OrderId": orderId, "IsDeleted": false}).Find(&entities).Error
+	if err != nil {
+		r.l.Error("order_repo - ReturnItemReadRepository - FindByOrderId: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *ReturnItemWriteRepository) Create(entity *order_entity.ReturnItemEntity) error {
+	now := time.Now()
+	entity.CreatedAt = now
+	entity.UpdatedAt = now
+	// An order item has at most one return, a second request is a no-op insert
+	result := r.db.Omit("OrderItem", "OwnerNote").Clauses(clause.OnConflict{DoNothing: true}).Create(entity)
+	if result.Error != nil {
+		r.l.Error("order_repo - ReturnItemWriteRepository - Create: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
+
+func (r *ReturnItemWriteRepository) ChangeStatus(change order_repo_inter.ReturnStatusChange) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"OrderStatus": change.To, "UpdatedAt": now}
+		if change.OwnerNote != "" {
+			updates["OwnerNote"] = change.OwnerNote
+		}
+		result := tx.Model(&order_entity.ReturnItemEntity{}).
+			Where(`"Id" = ? AND "OrderStatus" = ? AND "IsDeleted" = ?`, change.Id, change.From, false).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+		if !change.Restock {
+			return nil
+		}
+		return r.restock(tx, change.Id, now)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("order_repo - ReturnItemWriteRepository - ChangeStatus: %v", err)
+	}
+	return err
+}
+
+// restock marks the committed reservation of the returned item as returned and gives its stock back
+func (r *ReturnItemWriteRepository) restock(tx *gorm.DB, returnId int64, now time.Time) error {
+	var item order_entity.OrderItemEntity
+	err := tx.Model(&order_entity.OrderItemEntity{}).
+		Where(`"Id" = (SELECT "OrderItemId" FROM "Order"."ReturnItem" WHERE "Id" = ?)`, returnId).
+		First(&item).Error
+	if err != nil {
+		return err
+	}
+
+	var reservation order_entity.InventoryReservationEntity
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(map[string]interface{}{
+			"OrderId":          item.OrderId,
+			"ProductVariantId": item.ProductVariantId,
+			"Status":           order_entity.InventoryReservationStatusCommitted,
+		}).
+		First(&reservation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Nothing was taken from stock for this item, so there is nothing to give back
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&order_entity.InventoryReservationEntity{}).
+		Where(map[string]interface{}{"Id": reservation.Id}).
+		Updates(map[string]interface{}{"Status": order_entity.InventoryReservationStatusReturned, "UpdatedAt": now}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec(`UPDATE "Product"."ProductVariants" SET "Stock" = "Stock" + ? WHERE "Id" = ?`,
+		reservation.Quantity, reservation.ProductVariantId).Error
+}
@@ -0,0 +1,131 @@
+package order_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type ShippingMethodReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type ShippingMethodWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewShippingMethodReadRepository(db *gorm.DB, l *logger.ZapLogger) *ShippingMethodReadRepository {
+	return &ShippingMethodReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewShippingMethodWriteRepository(db *gorm.DB, l *logger.ZapLogger) *ShippingMethodWriteRepository {
+	return &ShippingMethodWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ShippingMethodReadRepository) FindById(id int64) (*order_entity.ShippingMethodEntity, error) {
+	var entity order_entity.ShippingMethodEntity
+	err := r.db.Preload("Rates").
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("order_repo - ShippingMethodReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ShippingMethodReadRepository) FindBySite(siteId int64, activeOnly bool) ([]order_entity.ShippingMethodEntity, error) {
+	var entities []order_entity.ShippingMethodEntity
+	query := r.db.Preload("Rates").Where(map[string]interface{}{"SiteId": siteId, "IsDeleted": false})
+	if activeOnly {
+		query = query.Where(`"IsActive" = ?`, true)
+	}
+	if err := query.Order(`"Id"`).Find(&entities).Error; err != nil {
+		r.l.Error("order_repo - ShippingMethodReadRepository - FindBySite: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *ShippingMethodReadRepository) ExistsByCode(siteId int64, code string, excludeId int64) (bool, error) {
+	var count int64
+	query := r.db.Model(&order_entity.ShippingMethodEntity{}).
+		Where(map[string]interface{}{"SiteId": siteId, "Code": code, "IsDeleted": false})
+	if excludeId > 0 {
+		query = query.Where(`"Id" <> ?`, excludeId)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		r.l.Error("order_repo - ShippingMethodReadRepository - ExistsByCode: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *ShippingMethodWriteRepository) Create(entity *order_entity.ShippingMethodEntity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rates").Create(entity).Error; err != nil {
+			r.l.Error("order_repo - ShippingMethodWriteRepository - Create: %v", err)
+			return err
+		}
+		return r.insertRates(tx, entity)
+	})
+}
+
+func (r *ShippingMethodWriteRepository) Update(entity *order_entity.ShippingMethodEntity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Rates").Save(entity).Error; err != nil {
+			r.l.Error("order_repo - ShippingMethodWriteRepository - Update: %v", err)
+			return err
+		}
+		err := tx.Where(map[string]interface{}{"ShippingMethodId": entity.Id}).
+			Delete(&order_entity.ShippingRateEntity{}).Error
+		if err != nil {
+			r.l.Error("order_repo - ShippingMethodWriteRepository - Update - rates: %v", err)
+			return err
+		}
+		return r.insertRates(tx, entity)
+	})
+}
+
+func (r *ShippingMethodWriteRepository) Delete(id int64) error {
+	err := r.db.Model(&order_entity.ShippingMethodEntity{}).
+		Where(map[string]interface{}{"Id": id}).
+		Updates(map[string]interface{}{"IsDeleted": true, "DeletedAt": time.Now()}).Error
+	if err != nil {
+		r.l.Error("order_repo - ShippingMethodWriteRepository - Delete: %v", err)
+	}
+	return err
+}
+
+func (r *ShippingMethodWriteRepository) insertRates(tx *gorm.DB, entity *order_entity.ShippingMethodEntity) error {
+	for i := range entity.Rates {
+		rate := &entity.Rates[i]
+		rate.Id = ""
+		rate.ShippingMethodId = entity.Id
+		// A rate for every province is stored with a NULL province
+		omit := []string{}
+		if rate.ProvinceId == "" {
+			omit = append(omit, "ProvinceId")
+		}
+		if err := tx.Omit(omit...).Create(rate).Error; err != nil {
+			r.l.Error("order_repo - ShippingMethodWriteRepository - insertRates: %v", err)
+			return err
+		}
+	}
+	return nil
+}
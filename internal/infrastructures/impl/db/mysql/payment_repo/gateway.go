@@ -0,0 +1,35 @@
+package payment_repo
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type GatewayReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewGatewayReadRepository(db *gorm.DB, l *logger.ZapLogger) *GatewayReadRepository {
+	return &GatewayReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *GatewayReadRepository) FindBySiteId(siteId int64) (*payment_entity.GatewayEntity, error) {
+	var entity payment_entity.GatewayEntity
+	err := r.db.Where(map[string]interface{}{"SiteId": siteId, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("payment_repo - GatewayReadRepository - FindBySiteId: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
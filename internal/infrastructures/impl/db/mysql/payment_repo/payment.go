@@ -0,0 +1,279 @@
+package payment_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/payment_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type PaymentReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type PaymentWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewPaymentReadRepository(db *gorm.DB, l *logger.ZapLogger) *PaymentReadRepository {
+	return &PaymentReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewPaymentWriteRepository(db *gorm.DB, l *logger.ZapLogger) *PaymentWriteRepository {
+	return &PaymentWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *PaymentReadRepository) FindById(id int64) (*payment_entity.PaymentEntity, error) {
+	var entity payment_entity.PaymentEntity
+	err := r.db.Where(map[string]interface{}{"Id": id, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("payment_repo - PaymentReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *PaymentReadRepository) FindByOrderId(serviceName string, orderId int64) (*payment_entity.PaymentEntity, error) {
+	var entity payment_entity.PaymentEntity
+	err := r.db.Where(map[string]interface{}{"ServiceName": serviceName, "OrderId": orderId, "IsDeleted": false}).
+		Order(`"Id" DESC`).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("payment_repo - PaymentReadRepository - FindByOrderId: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *PaymentReadRepository) FindAttempt(trackingNumber int64) (*payment_entity.ParbadPaymentEntity, error) {
+	var entity payment_entity.ParbadPaymentEntity
+	err := r.db.Preload("Transactions", func(db *gorm.DB) *gorm.DB {
+		return db.Order(`"Id"`)
+	}).Where(map[string]interface{}{"TrackingNumber": trackingNumber}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("payment_repo - PaymentReadRepository - FindAttempt: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *PaymentReadRepository) TrackingNumberExists(trackingNumber int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&payment_entity.ParbadPaymentEntity{}).
+		Where(map[string]interface{}{"TrackingNumber": trackingNumber}).
+		Count(&count).Error
+	if err != nil {
+		r.l.Error("payment_repo - PaymentReadRepository - TrackingNumberExists: %v", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *PaymentReadRepository) FindStale(before time.Time, limit int) ([]payment_entity.PaymentEntity, error) {
+	var entities []payment_entity.PaymentEntity
+	err := r.db.Where(`"PaymentStatusEnum" = ? AND "UpdatedAt" < ? AND "IsDeleted" = ?`, payment_entity.PaymentStatusPending, before, false).
+		Order(`"UpdatedAt"`).
+		Limit(limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("payment_repo - PaymentReadRepository - FindStale: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *PaymentWriteRepository) Start(start payment_repo_inter.PaymentStart) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&payment_entity.PaymentEntity{}).
+			Where(`"Id" = ? AND "PaymentStatusEnum" IN ?`, start.Id, []string{payment_entity.PaymentStatusPending, payment_entity.PaymentStatusFailed}).
+			Updates(map[string]interface{}{
+				"PaymentStatusEnum":   payment_entity.PaymentStatusPending,
+				"TrackingNumber":      start.TrackingNumber,
+				"Gateway":             start.Gateway,
+				"GatewayAccountName":  start.GatewayAccountName,
+				"GatewayToken":        start.GatewayToken,
+				"CallVerifyUrl":       start.CallVerifyUrl,
+				"ClientIp":            start.ClientIp,
+				"GatewayResponseCode": gorm.Expr("NULL"),
+				"Message":             gorm.Expr("NULL"),
+				"UpdatedAt":           time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+		return createAttempt(tx, start.Attempt)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("payment_repo - PaymentWriteRepository - Start: %v", err)
+	}
+	return err
+}
+
+func (r *PaymentWriteRepository) Complete(completion payment_repo_inter.PaymentCompletion) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&payment_entity.PaymentEntity{}).
+			Where(`"Id" = ? AND "TrackingNumber" = ? AND "PaymentStatusEnum" = ?`, completion.Id, completion.TrackingNumber, payment_entity.PaymentStatusPending).
+			Updates(map[string]interface{}{
+				"PaymentStatusEnum":   completion.Status,
+				"TransactionCode":     completion.TransactionCode,
+				"GatewayResponseCode": completion.GatewayResponseCode,
+				"Message":             completion.Message,
+				"UpdatedAt":           time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+		if completion.AttemptId == 0 {
+			return nil
+		}
+
+		result = tx.Model(&payment_entity.ParbadPaymentEntity{}).
+			Where(`"Id" = ? AND "IsCompleted" = ?`, completion.AttemptId, false).
+			Updates(map[string]interface{}{
+				"IsCompleted":     true,
+				"IsPaid":          completion.Status == payment_entity.PaymentStatusSuccessful,
+				"TransactionCode": completion.TransactionCode,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+		completion.Transaction.PaymentId = strconv.FormatInt(completion.AttemptId, 10)
+		return createTransaction(tx, completion.Transaction)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("payment_repo - PaymentWriteRepository - Complete: %v", err)
+	}
+	return err
+}
+
+func (r *PaymentWriteRepository) CreateAttempt(attempt *payment_entity.ParbadPaymentEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return createAttempt(tx, attempt)
+	})
+	if err != nil {
+		r.l.Error("payment_repo - PaymentWriteRepository - CreateAttempt: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *PaymentWriteRepository) AddTransaction(transaction *payment_entity.ParbadTransactionEntity) error {
+	if err := createTransaction(r.db, transaction); err != nil {
+		r.l.Error("payment_repo - PaymentWriteRepository - AddTransaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *PaymentWriteRepository) StartRefund(transaction *payment_entity.ParbadTransactionEntity) error {
+	transaction.Type = payment_entity.ParbadTransactionRefund
+	transaction.IsSucceed = false
+	transaction.IsPending = true
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		where := map[string]interface{}{
+			"PaymentId":      transaction.PaymentId,
+			"IdempotencyKey": transaction.IdempotencyKey,
+			"Type":           payment_entity.ParbadTransactionRefund,
+			"IsSucceed":      false,
+			"IsPending":      false,
+		}
+		result := tx.Model(&payment_entity.ParbadTransactionEntity{}).
+			Where(where).
+			Updates(map[string]interface{}{
+				"Amount":    transaction.Amount,
+				"IsPending": true,
+				"Message":   gorm.Expr("NULL"),
+				"CreatedAt": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			where["IsPending"] = true
+			return tx.Where(where).First(transaction).Error
+		}
+		err := createTransaction(tx, transaction)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repositories.ErrConflict
+		}
+		return err
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("payment_repo - PaymentWriteRepository - StartRefund: %v", err)
+	}
+	return err
+}
+
+func (r *PaymentWriteRepository) FinishRefund(id int64, succeeded bool, message string) error {
+	result := r.db.Model(&payment_entity.ParbadTransactionEntity{}).
+		Where(map[string]interface{}{"Id": id, "IsPending": true}).
+		Updates(map[string]interface{}{
+			"IsPending": false,
+			"IsSucceed": succeeded,
+			"Message":   message,
+		})
+	if result.Error != nil {
+		r.l.Error("payment_repo - PaymentWriteRepository - FinishRefund: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func createAttempt(tx *gorm.DB, attempt *payment_entity.ParbadPaymentEntity) error {
+	if err := tx.Omit("Transactions").Create(attempt).Error; err != nil {
+		return err
+	}
+	for i := range attempt.Transactions {
+		attempt.Transactions[i].PaymentId = attempt.Id
+		if err := createTransaction(tx, &attempt.Transactions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createTransaction stores a missing idempotency key as NULL
+func createTransaction(tx *gorm.DB, transaction *payment_entity.ParbadTransactionEntity) error {
+	var omit []string
+	if transaction.IdempotencyKey == "" {
+		omit = append(omit, "IdempotencyKey")
+	}
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = time.Now()
+	}
+	return tx.Omit(omit...).Create(transaction).Error
+}
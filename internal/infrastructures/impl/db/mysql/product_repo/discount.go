@@ -0,0 +1,105 @@
+package product_repo
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/db_helper"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type DiscountReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type DiscountWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type CouponReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewDiscountReadRepository(db *gorm.DB, l *logger.ZapLogger) *DiscountReadRepository {
+	return &DiscountReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewDiscountWriteRepository(db *gorm.DB, l *logger.ZapLogger) *DiscountWriteRepository {
+	return &DiscountWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewCouponReadRepository(db *gorm.DB, l *logger.ZapLogger) *CouponReadRepository {
+	return &CouponReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *DiscountReadRepository) FindByCode(siteId int64, code string) (*product_entity.DiscountEntity, error) {
+	return r.find("FindByCode", map[string]interface{}{"SiteId": siteId, "Code": code, "IsDeleted": false})
+}
+
+func (r *DiscountReadRepository) FindById(id int64) (*product_entity.DiscountEntity, error) {
+	return r.find("FindById", map[string]interface{}{"Id": id, "IsDeleted": false})
+}
+
+func (r *DiscountReadRepository) find(op string, where map[string]interface{}) (*product_entity.DiscountEntity, error) {
+	var entity product_entity.DiscountEntity
+	err := r.db.
+		Preload("Products", func(db *gorm.DB) *gorm.DB { return db.Select(`"Product"."Products"."Id"`) }).
+		Preload("Customers").
+		Where(where).
+		First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("product_repo - DiscountReadRepository - %s: %v", op, err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *DiscountWriteRepository) Redeem(discountId int64, couponUnits map[int64]int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if discountId > 0 {
+			if err := db_helper.DecrementQuantity(tx, `"Product"."Discounts"`, discountId, 1); err != nil {
+				return err
+			}
+		}
+		for couponId, units := range couponUnits {
+			if err := db_helper.DecrementQuantity(tx, `"Product"."Coupons"`, couponId, units); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, repositories.ErrExhausted) {
+		r.l.Error("product_repo - DiscountWriteRepository - Redeem: %v", err)
+	}
+	return err
+}
+
+func (r *CouponReadRepository) FindByProductIds(productIds []int64) ([]product_entity.CouponEntity, error) {
+	var entities []product_entity.CouponEntity
+	if len(productIds) == 0 {
+		return entities, nil
+	}
+	err := r.db.Where(map[string]interface{}{"ProductId": productIds, "IsDeleted": false}).Find(&entities).Error
+	if err != nil {
+		r.l.Error("product_repo - CouponReadRepository - FindByProductIds: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
@@ -0,0 +1,74 @@
+package product_repo
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/db_helper"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+const _productStatusInactive = "inactive"
+
+type ProductReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type ProductWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewProductReadRepository(db *gorm.DB, l *logger.ZapLogger) *ProductReadRepository {
+	return &ProductReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewProductWriteRepository(db *gorm.DB, l *logger.ZapLogger) *ProductWriteRepository {
+	return &ProductWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ProductReadRepository) FindById(id int64) (*product_entity.ProductEntity, error) {
+	var entity product_entity.ProductEntity
+	err := r.db.Where(map[string]interface{}{"Id": id, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("product_repo - ProductReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ProductReadRepository) FindListedByIds(siteId int64, ids []int64) ([]product_entity.ProductEntity, error) {
+	var entities []product_entity.ProductEntity
+	if len(ids) == 0 {
+		return entities, nil
+	}
+	err := r.db.Preload("Media").
+		Where(map[string]interface{}{"Id": ids, "SiteId": siteId, "IsDeleted": false}).
+		Where(`"Status" <> ?`, _productStatusInactive).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("product_repo - ProductReadRepository - FindListedByIds: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *ProductWriteRepository) IncrementVisitedCounts(deltas []repositories.CounterDelta) error {
+	if err := db_helper.IncrementCounters(r.db, `"Product"."Products"`, "VisitedCount", deltas); err != nil {
+		r.l.Error("product_repo - ProductWriteRepository - IncrementVisitedCounts: %v", err)
+		return err
+	}
+	return nil
+}
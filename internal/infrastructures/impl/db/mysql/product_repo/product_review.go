@@ -0,0 +1,279 @@
+package product_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type ProductReviewReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type ProductReviewWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewProductReviewReadRepository(db *gorm.DB, l *logger.ZapLogger) *ProductReviewReadRepository {
+	return &ProductReviewReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewProductReviewWriteRepository(db *gorm.DB, l *logger.ZapLogger) *ProductReviewWriteRepository {
+	return &ProductReviewWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ProductReviewReadRepository) FindById(id int64) (*product_entity.ProductReviewEntity, error) {
+	var entity product_entity.ProductReviewEntity
+	err := r.db.Where(map[string]interface{}{"Id": id, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ProductReviewReadRepository) FindByCustomer(customerId int64, productId int64) (*product_entity.ProductReviewEntity, error) {
+	var entity product_entity.ProductReviewEntity
+	err := r.db.Where(map[string]interface{}{"CustomerId": customerId, "ProductId": productId, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewReadRepository - FindByCustomer: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ProductReviewReadRepository) FindAll(filter product_repo_inter.ProductReviewFilter) ([]product_entity.ProductReviewEntity, int64, error) {
+	query := r.db.Model(&product_entity.ProductReviewEntity{}).Where(`"IsDeleted" = ?`, false)
+	if filter.SiteId > 0 {
+		query = query.Where(`"SiteId" = ?`, filter.SiteId)
+	}
+	if filter.ProductId > 0 {
+		query = query.Where(`"ProductId" = ?`, filter.ProductId)
+	}
+	if filter.Approved != nil {
+		query = query.Where(`"Approved" = ?`, *filter.Approved)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("product_repo - ProductReviewReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []product_entity.ProductReviewEntity
+	err := query.Order(`"CreatedAt" DESC, "Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewReadRepository - FindAll: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *ProductReviewReadRepository) FindVote(reviewId int64, customerId int64) (*product_entity.ProductReviewVoteEntity, error) {
+	var entity product_entity.ProductReviewVoteEntity
+	err := r.db.Where(map[string]interface{}{"ReviewId": reviewId, "CustomerId": customerId}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewReadRepository - FindVote: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *ProductReviewWriteRepository) Create(entity *product_entity.ProductReviewEntity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Product", "Votes").Create(entity).Error; err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - Create: %v", err)
+			return err
+		}
+		return r.recomputeRating(tx, entity.ProductId)
+	})
+}
+
+func (r *ProductReviewWriteRepository) Update(entity *product_entity.ProductReviewEntity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Product", "Votes").Save(entity).Error; err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - Update: %v", err)
+			return err
+		}
+		return r.recomputeRating(tx, entity.ProductId)
+	})
+}
+
+func (r *ProductReviewWriteRepository) Approve(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		review, err := r.lock(tx, id)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&product_entity.ProductReviewEntity{}).
+			Where(map[string]interface{}{"Id": id}).
+			Updates(map[string]interface{}{"Approved": true, "UpdatedAt": time.Now()}).Error
+		if err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - Approve: %v", err)
+			return err
+		}
+		return r.recomputeRating(tx, review.ProductId)
+	})
+}
+
+func (r *ProductReviewWriteRepository) Delete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		review, err := r.lock(tx, id)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&product_entity.ProductReviewEntity{}).
+			Where(map[string]interface{}{"Id": id}).
+			Updates(map[string]interface{}{"IsDeleted": true, "DeletedAt": time.Now()}).Error
+		if err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - Delete: %v", err)
+			return err
+		}
+		return r.recomputeRating(tx, review.ProductId)
+	})
+}
+
+func (r *ProductReviewWriteRepository) Vote(reviewId int64, customerId int64, isLike bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.lock(tx, reviewId); err != nil {
+			return err
+		}
+
+		var vote product_entity.ProductReviewVoteEntity
+		err := tx.Where(map[string]interface{}{"ReviewId": reviewId, "CustomerId": customerId}).First(&vote).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			now := time.Now()
+			vote = product_entity.ProductReviewVoteEntity{
+				ReviewId:   strconv.FormatInt(reviewId, 10),
+				CustomerId: strconv.FormatInt(customerId, 10),
+				IsLike:     isLike,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if err := tx.Omit("Review").Create(&vote).Error; err != nil {
+				r.l.Error("product_repo - ProductReviewWriteRepository - Vote: %v", err)
+				return err
+			}
+			like, dislike := voteDelta(isLike, 1)
+			return r.adjustVotes(tx, reviewId, like, dislike)
+		case err != nil:
+			r.l.Error("product_repo - ProductReviewWriteRepository - Vote: %v", err)
+			return err
+		case vote.IsLike == isLike:
+			return nil
+		}
+
+		err = tx.Model(&vote).Updates(map[string]interface{}{"IsLike": isLike, "UpdatedAt": time.Now()}).Error
+		if err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - Vote: %v", err)
+			return err
+		}
+		like, dislike := voteDelta(isLike, 1)
+		previousLike, previousDislike := voteDelta(!isLike, -1)
+		return r.adjustVotes(tx, reviewId, like+previousLike, dislike+previousDislike)
+	})
+}
+
+func (r *ProductReviewWriteRepository) RemoveVote(reviewId int64, customerId int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.lock(tx, reviewId); err != nil {
+			return err
+		}
+
+		var vote product_entity.ProductReviewVoteEntity
+		err := tx.Where(map[string]interface{}{"ReviewId": reviewId, "CustomerId": customerId}).First(&vote).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - RemoveVote: %v", err)
+			return err
+		}
+		if err := tx.Delete(&vote).Error; err != nil {
+			r.l.Error("product_repo - ProductReviewWriteRepository - RemoveVote: %v", err)
+			return err
+		}
+		like, dislike := voteDelta(vote.IsLike, -1)
+		return r.adjustVotes(tx, reviewId, like, dislike)
+	})
+}
+
+// lock loads the review with a row lock so concurrent votes and moderation of the same review serialize
+func (r *ProductReviewWriteRepository) lock(tx *gorm.DB, id int64) (*product_entity.ProductReviewEntity, error) {
+	var review product_entity.ProductReviewEntity
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(map[string]interface{}{"Id": id, "IsDeleted": false}).
+		First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewWriteRepository - lock: %v", err)
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ProductReviewWriteRepository) adjustVotes(tx *gorm.DB, reviewId int64, like int, dislike int) error {
+	err := tx.Model(&product_entity.ProductReviewEntity{}).
+		Where(map[string]interface{}{"Id": reviewId}).
+		Updates(map[string]interface{}{
+			"Like":    gorm.Expr(`"Like" + ?`, like),
+			"Dislike": gorm.Expr(`"Dislike" + ?`, dislike),
+		}).Error
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewWriteRepository - adjustVotes: %v", err)
+	}
+	return err
+}
+
+// recomputeRating derives ReviewCount and Rate of the product from its approved reviews
+func (r *ProductReviewWriteRepository) recomputeRating(tx *gorm.DB, productId string) error {
+	err := tx.Exec(`UPDATE "Product"."Products" p
+		SET "ReviewCount" = s.review_count, "Rate" = s.rate
+		FROM (
+			SELECT COUNT(*) AS review_count, COALESCE(ROUND(AVG("Rating")), 0) AS rate
+			FROM "Product"."ProductReviews"
+			WHERE "ProductId" = ? AND "Approved" = ? AND "IsDeleted" = ?
+		) s
+		WHERE p."Id" = ?`, productId, true, false, productId).Error
+	if err != nil {
+		r.l.Error("product_repo - ProductReviewWriteRepository - recomputeRating: %v", err)
+	}
+	return err
+}
+
+func voteDelta(isLike bool, delta int) (int, int) {
+	if isLike {
+		return delta, 0
+	}
+	return 0, delta
+}
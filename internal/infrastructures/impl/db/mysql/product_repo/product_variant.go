@@ -0,0 +1,34 @@
+package product_repo
+
+import (
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/pkg/logger"
+)
+
+type ProductVariantReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewProductVariantReadRepository(db *gorm.DB, l *logger.ZapLogger) *ProductVariantReadRepository {
+	return &ProductVariantReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *ProductVariantReadRepository) FindByIds(ids []int64) ([]product_entity.ProductVariantEntity, error) {
+	var entities []product_entity.ProductVariantEntity
+	if len(ids) == 0 {
+		return entities, nil
+	}
+	err := r.db.Preload("Product").
+		Where(map[string]interface{}{"Id": ids, "IsDeleted": false}).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("product_repo - ProductVariantReadRepository - FindByIds: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
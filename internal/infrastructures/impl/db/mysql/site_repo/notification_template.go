@@ -0,0 +1,88 @@
+package site_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type NotificationTemplateReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type NotificationTemplateWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewNotificationTemplateReadRepository(db *gorm.DB, l *logger.ZapLogger) *NotificationTemplateReadRepository {
+	return &NotificationTemplateReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewNotificationTemplateWriteRepository(db *gorm.DB, l *logger.ZapLogger) *NotificationTemplateWriteRepository {
+	return &NotificationTemplateWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *NotificationTemplateReadRepository) FindBySiteId(siteId int64) ([]site_entity.NotificationTemplateEntity, error) {
+	var templates []site_entity.NotificationTemplateEntity
+	err := r.db.Where(map[string]interface{}{"SiteId": siteId}).
+		Order(`"Channel", "Event", "Locale"`).Find(&templates).Error
+	if err != nil {
+		r.l.Error("site_repo - NotificationTemplateReadRepository - FindBySiteId: %v", err)
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *NotificationTemplateReadRepository) Find(siteId int64, channel string, event string, locale string) (*site_entity.NotificationTemplateEntity, error) {
+	var template site_entity.NotificationTemplateEntity
+	err := r.db.Where(map[string]interface{}{"SiteId": siteId, "Channel": channel, "Event": event, "Locale": locale}).
+		First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("site_repo - NotificationTemplateReadRepository - Find: %v", err)
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *NotificationTemplateWriteRepository) Save(template *site_entity.NotificationTemplateEntity) error {
+	now := time.Now()
+	err := r.db.Exec(`INSERT INTO "Site"."NotificationTemplates" ("SiteId", "UserId", "Channel", "Event", "Locale", "Subject", "Body", "Html", "CreatedAt", "UpdatedAt")
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT ("SiteId", "Channel", "Event", "Locale") DO UPDATE SET "Subject" = EXCLUDED."Subject", "Body" = EXCLUDED."Body", "Html" = EXCLUDED."Html", "UpdatedAt" = EXCLUDED."UpdatedAt"`,
+		template.SiteId, template.UserId, template.Channel, template.Event, template.Locale,
+		template.Subject, template.Body, template.Html, now, now).Error
+	if err != nil {
+		r.l.Error("site_repo - NotificationTemplateWriteRepository - Save: %v", err)
+		return err
+	}
+	template.UpdatedAt = now
+	return nil
+}
+
+func (r *NotificationTemplateWriteRepository) Delete(siteId int64, channel string, event string, locale string) error {
+	result := r.db.Where(map[string]interface{}{"SiteId": siteId, "Channel": channel, "Event": event, "Locale": locale}).
+		Delete(&site_entity.NotificationTemplateEntity{})
+	if result.Error != nil {
+		r.l.Error("site_repo - NotificationTemplateWriteRepository - Delete: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
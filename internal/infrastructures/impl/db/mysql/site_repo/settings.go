@@ -0,0 +1,61 @@
+package site_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type SettingsReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type SettingsWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewSettingsReadRepository(db *gorm.DB, l *logger.ZapLogger) *SettingsReadRepository {
+	return &SettingsReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewSettingsWriteRepository(db *gorm.DB, l *logger.ZapLogger) *SettingsWriteRepository {
+	return &SettingsWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *SettingsReadRepository) FindBySiteId(siteId int64) (*site_entity.SettingsEntity, error) {
+	var entity site_entity.SettingsEntity
+	err := r.db.Where(map[string]interface{}{"SiteId": siteId, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("site_repo - SettingsReadRepository - FindBySiteId: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *SettingsWriteRepository) SaveReturnWindow(siteId int64, userId int64, days int) error {
+	now := time.Now()
+	err := r.db.Exec(`INSERT INTO "Site"."Settings" ("SiteId", "UserId", "CustomerId", "ReturnWindowDays", "CreatedAt", "UpdatedAt", "IsDeleted")
+		VALUES (?, ?, 0, ?, ?, ?, ?)
+		ON CONFLICT ("SiteId") DO UPDATE SET "ReturnWindowDays" = EXCLUDED."ReturnWindowDays", "UpdatedAt" = EXCLUDED."UpdatedAt"`,
+		siteId, userId, days, now, now, false).Error
+	if err != nil {
+		r.l.Error("site_repo - SettingsWriteRepository - SaveReturnWindow: %v", err)
+		return err
+	}
+	return nil
+}
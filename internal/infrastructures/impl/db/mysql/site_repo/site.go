@@ -0,0 +1,52 @@
+package site_repo
+
+import (
+	"errors"
+	"strconv"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/site_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type SiteReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewSiteReadRepository(db *gorm.DB, l *logger.ZapLogger) *SiteReadRepository {
+	return &SiteReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *SiteReadRepository) FindById(id int64) (*site_entity.SiteEntity, error) {
+	var entity site_entity.SiteEntity
+	err := r.db.Where(map[string]interface{}{"Id": id, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("site_repo - SiteReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *SiteReadRepository) CheckOwner(siteId int64, userId int64) error {
+	var count int64
+	err := r.db.Model(&site_entity.SiteEntity{}).
+		Where(map[string]interface{}{"Id": siteId, "UserId": strconv.FormatInt(userId, 10), "IsDeleted": false}).
+		Count(&count).Error
+	if err != nil {
+		r.l.Error("site_repo - SiteReadRepository - CheckOwner: %v", err)
+		return err
+	}
+	if count == 0 {
+		return site_repo_inter.ErrNotOwner
+	}
+	return nil
+}
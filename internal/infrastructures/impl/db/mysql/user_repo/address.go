@@ -1,8 +1,11 @@
 package user_repo
 
 import (
+	"errors"
+
 	"gorm.io/gorm"
 	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
 	"site_builder_backend/pkg/logger"
 )
 
@@ -35,7 +38,11 @@ func (r *AddressWriteRepository) Create(entity user_entity.AddressEntity) error
 
 func (r *AddressReadRepository) FindById(id int64) (*user_entity.AddressEntity, error) {
 	var entity user_entity.AddressEntity
-	if err := r.db.First(&entity, id).Error; err != nil {
+	err := r.db.First(&entity, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
 		r.l.Error("user_repo - AddressReadRepository - FindById: %v", err)
 		return nil, err
 	}
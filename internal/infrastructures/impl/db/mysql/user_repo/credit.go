@@ -0,0 +1,256 @@
+package user_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/db_helper"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// creditColumns maps the credit types to the counters of UserEntity
+var creditColumns = map[string]string{
+	user_entity.CreditSms:       "SmsCredits",
+	user_entity.CreditEmail:     "EmailCredits",
+	user_entity.CreditStorageMb: "StorageMbCredits",
+	user_entity.CreditAi:        "AiCredits",
+	user_entity.CreditAiImage:   "AiImageCredits",
+}
+
+type CreditReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type CreditWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewCreditReadRepository(db *gorm.DB, l *logger.ZapLogger) *CreditReadRepository {
+	return &CreditReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewCreditWriteRepository(db *gorm.DB, l *logger.ZapLogger) *CreditWriteRepository {
+	return &CreditWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *CreditReadRepository) FindUnitPrices() ([]user_entity.UnitPriceEntity, error) {
+	var entities []user_entity.UnitPriceEntity
+	if err := r.db.Order(`"Name"`).Find(&entities).Error; err != nil {
+		r.l.Error("user_repo - CreditReadRepository - FindUnitPrices: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *CreditReadRepository) FindUnitPriceById(id int64) (*user_entity.UnitPriceEntity, error) {
+	var entity user_entity.UnitPriceEntity
+	err := r.db.First(&entity, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - CreditReadRepository - FindUnitPriceById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *CreditReadRepository) FindTopUpById(id int64) (*user_entity.CreditTopUpEntity, error) {
+	var entity user_entity.CreditTopUpEntity
+	err := r.db.First(&entity, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - CreditReadRepository - FindTopUpById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *CreditReadRepository) FindLedger(filter user_repo_inter.CreditLedgerFilter) ([]user_entity.CreditLedgerEntity, int64, error) {
+	query := r.db.Model(&user_entity.CreditLedgerEntity{}).Where(`"UserId" = ?`, filter.UserId)
+	if filter.CreditType != "" {
+		query = query.Where(`"CreditType" = ?`, filter.CreditType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("user_repo - CreditReadRepository - FindLedger: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []user_entity.CreditLedgerEntity
+	err := query.Order(`"Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - CreditReadRepository - FindLedger: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *CreditWriteRepository) CreateTopUp(topUp *user_entity.CreditTopUpEntity, payment *payment_entity.PaymentEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		topUp.CreatedAt = now
+		topUp.UpdatedAt = now
+		if err := tx.Create(topUp).Error; err != nil {
+			return err
+		}
+
+		payment.OrderId, _ = strconv.ParseInt(topUp.Id, 10, 64)
+		payment.CreatedAt = now
+		payment.UpdatedAt = now
+		return db_helper.CreatePayment(tx, payment)
+	})
+	if err != nil {
+		r.l.Error("user_repo - CreditWriteRepository - CreateTopUp: %v", err)
+	}
+	return err
+}
+
+func (r *CreditWriteRepository) ApplyTopUp(id int64, storageExpiresAt time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var topUp user_entity.CreditTopUpEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&topUp, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if topUp.Status != user_entity.CreditTopUpStatusPending {
+			return repositories.ErrConflict
+		}
+		err = tx.Model(&topUp).Updates(map[string]interface{}{
+			"Status":    user_entity.CreditTopUpStatusPaid,
+			"UpdatedAt": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		userId, _ := strconv.ParseInt(topUp.UserId, 10, 64)
+		if !storageExpiresAt.IsZero() {
+			err := tx.Model(&user_entity.UserEntity{}).
+				Where(`"Id" = ?`, userId).
+				Update("StorageMbCreditsExpireAt", storageExpiresAt).Error
+			if err != nil {
+				return err
+			}
+		}
+		changes := []creditChange{{creditType: topUp.CreditType, amount: topUp.Quantity}}
+		return applyCredits(tx, userId, changes, user_entity.CreditReasonTopUp, user_entity.CreditReferenceTopUp, id)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - CreditWriteRepository - ApplyTopUp: %v", err)
+	}
+	return err
+}
+
+func (r *CreditWriteRepository) FailTopUp(id int64) error {
+	result := r.db.Model(&user_entity.CreditTopUpEntity{}).
+		Where(`"Id" = ? AND "Status" = ?`, id, user_entity.CreditTopUpStatusPending).
+		Updates(map[string]interface{}{
+			"Status":    user_entity.CreditTopUpStatusFailed,
+			"UpdatedAt": time.Now(),
+		})
+	if result.Error != nil {
+		r.l.Error("user_repo - CreditWriteRepository - FailTopUp: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
+
+// creditChange moves one counter of a user by amount, or replaces it with amount when set
+type creditChange struct {
+	creditType string
+	amount     int
+	set        bool
+}
+
+// applyCredits moves the counters of the user with the row locked and records every counter that changed
+// in the ledger, with the balance it left. It is the only way counters are changed, so the ledger adds up
+// to them. A change taking a counter below zero returns ErrExhausted.
+func applyCredits(tx *gorm.DB, userId int64, changes []creditChange, reason string, referenceType string, referenceId int64) error {
+	var user user_entity.UserEntity
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("Id", "SmsCredits", "EmailCredits", "StorageMbCredits", "AiCredits", "AiImageCredits").
+		First(&user, userId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repositories.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	balances := map[string]int{
+		user_entity.CreditSms:       user.SmsCredits,
+		user_entity.CreditEmail:     user.EmailCredits,
+		user_entity.CreditStorageMb: user.StorageMbCredits,
+		user_entity.CreditAi:        user.AiCredits,
+		user_entity.CreditAiImage:   user.AiImageCredits,
+	}
+
+	now := time.Now()
+	updates := make(map[string]interface{})
+	var entries []user_entity.CreditLedgerEntity
+	for _, change := range changes {
+		column, ok := creditColumns[change.creditType]
+		if !ok {
+			return errors.New("user_repo - applyCredits: unknown credit type " + change.creditType)
+		}
+		before := balances[change.creditType]
+		after := before + change.amount
+		if change.set {
+			after = change.amount
+		}
+		if after == before {
+			continue
+		}
+		if after < 0 {
+			return repositories.ErrExhausted
+		}
+		balances[change.creditType] = after
+		updates[column] = after
+		entries = append(entries, user_entity.CreditLedgerEntity{
+			UserId:        user.Id,
+			CreditType:    change.creditType,
+			Amount:        after - before,
+			Balance:       after,
+			Reason:        reason,
+			ReferenceType: referenceType,
+			ReferenceId:   strconv.FormatInt(referenceId, 10),
+			CreatedAt:     now,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	updates["UpdatedAt"] = now
+	if err := tx.Model(&user_entity.UserEntity{}).Where(`"Id" = ?`, userId).Updates(updates).Error; err != nil {
+		return err
+	}
+	return tx.Create(&entries).Error
+}
@@ -0,0 +1,58 @@
+package user_repo
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/pkg/logger"
+)
+
+type CustomerReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type CustomerWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewCustomerReadRepository(db *gorm.DB, l *logger.ZapLogger) *CustomerReadRepository {
+	return &CustomerReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewCustomerWriteRepository(db *gorm.DB, l *logger.ZapLogger) *CustomerWriteRepository {
+	return &CustomerWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *CustomerReadRepository) FindByEmail(siteId int64, email string) (*user_entity.CustomerEntity, error) {
+	var entity user_entity.CustomerEntity
+	err := r.db.Where(map[string]interface{}{"SiteId": siteId, "Email": email, "IsDeleted": false}).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - CustomerReadRepository - FindByEmail: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *CustomerWriteRepository) Create(entity *user_entity.CustomerEntity) error {
+	err := r.db.Omit("Roles", "Addresses", "AvatarId", "ExpireVerifyCodeAt", "Version", "DeletedAt").Create(entity).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repositories.ErrConflict
+	}
+	if err != nil {
+		r.l.Error("user_repo - CustomerWriteRepository - Create: %v", err)
+	}
+	return err
+}
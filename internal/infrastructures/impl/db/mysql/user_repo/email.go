@@ -0,0 +1,147 @@
+package user_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// Sizes of the Subject and Error columns, longer values are cut
+const (
+	_emailSubjectLength = 500
+	_emailErrorLength   = 500
+)
+
+type EmailReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type EmailWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewEmailReadRepository(db *gorm.DB, l *logger.ZapLogger) *EmailReadRepository {
+	return &EmailReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewEmailWriteRepository(db *gorm.DB, l *logger.ZapLogger) *EmailWriteRepository {
+	return &EmailWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *EmailReadRepository) FindByMessageId(messageId string) (*user_entity.EmailMessageEntity, error) {
+	var entity user_entity.EmailMessageEntity
+	err := r.db.Where(`"MessageId" = ?`, messageId).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - EmailReadRepository - FindByMessageId: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *EmailReadRepository) FindMessages(filter user_repo_inter.EmailMessageFilter) ([]user_entity.EmailMessageEntity, int64, error) {
+	query := r.db.Model(&user_entity.EmailMessageEntity{}).Where(`"UserId" = ?`, filter.UserId)
+	if filter.Status != "" {
+		query = query.Where(`"Status" = ?`, filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("user_repo - EmailReadRepository - FindMessages: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []user_entity.EmailMessageEntity
+	err := query.Order(`"Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - EmailReadRepository - FindMessages: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *EmailWriteRepository) Create(message *user_entity.EmailMessageEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&user_entity.EmailMessageEntity{}).
+			Where(`"MessageId" = ?`, message.MessageId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return repositories.ErrConflict
+		}
+
+		now := time.Now()
+		message.Subject = truncate(message.Subject, _emailSubjectLength)
+		message.CreatedAt = now
+		message.UpdatedAt = now
+		return tx.Omit("SentAt").Create(message).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - EmailWriteRepository - Create: %v", err)
+	}
+	return err
+}
+
+func (r *EmailWriteRepository) MarkSent(id int64, smtp string, attempts int, at time.Time) error {
+	return r.update(id, "MarkSent", map[string]interface{}{
+		"Status":   user_entity.EmailStatusSent,
+		"Smtp":     smtp,
+		"Error":    "",
+		"Attempts": attempts,
+		"SentAt":   at,
+	})
+}
+
+func (r *EmailWriteRepository) MarkAttempt(id int64, smtp string, reason string, attempts int) error {
+	return r.update(id, "MarkAttempt", map[string]interface{}{
+		"Smtp":     smtp,
+		"Error":    truncate(reason, _emailErrorLength),
+		"Attempts": attempts,
+	})
+}
+
+func (r *EmailWriteRepository) MarkFailed(id int64, smtp string, reason string, attempts int) error {
+	return r.update(id, "MarkFailed", map[string]interface{}{
+		"Status":   user_entity.EmailStatusFailed,
+		"Smtp":     smtp,
+		"Error":    truncate(reason, _emailErrorLength),
+		"Attempts": attempts,
+	})
+}
+
+// update changes the message while it is queued
+func (r *EmailWriteRepository) update(id int64, method string, values map[string]interface{}) error {
+	values["UpdatedAt"] = time.Now()
+	result := r.db.Model(&user_entity.EmailMessageEntity{}).
+		Where(`"Id" = ? AND "Status" = ?`, id, user_entity.EmailStatusQueued).
+		Updates(values)
+	if result.Error != nil {
+		r.l.Error("user_repo - EmailWriteRepository - %s: %v", method, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
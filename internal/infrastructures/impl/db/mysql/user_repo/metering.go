@@ -0,0 +1,178 @@
+package user_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/drive_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type MeteringReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type MeteringWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewMeteringReadRepository(db *gorm.DB, l *logger.ZapLogger) *MeteringReadRepository {
+	return &MeteringReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewMeteringWriteRepository(db *gorm.DB, l *logger.ZapLogger) *MeteringWriteRepository {
+	return &MeteringWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *MeteringReadRepository) Available(userId int64, creditType string, now time.Time) (int64, error) {
+	column, ok := creditColumns[creditType]
+	if !ok {
+		return 0, errors.New("user_repo - MeteringReadRepository - Available: unknown credit type " + creditType)
+	}
+
+	var user user_entity.UserEntity
+	err := r.db.Select("Id", column, "StorageMbCreditsExpireAt").First(&user, userId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - MeteringReadRepository - Available: %v", err)
+		return 0, err
+	}
+
+	switch creditType {
+	case user_entity.CreditSms:
+		return int64(user.SmsCredits), nil
+	case user_entity.CreditEmail:
+		return int64(user.EmailCredits), nil
+	case user_entity.CreditAi:
+		return int64(user.AiCredits), nil
+	case user_entity.CreditAiImage:
+		return int64(user.AiImageCredits), nil
+	}
+
+	if !user.StorageMbCreditsExpireAt.IsZero() && user.StorageMbCreditsExpireAt.Before(now) {
+		return 0, nil
+	}
+	var used int64
+	err = r.db.Model(&drive_entity.StorageEntity{}).
+		Where(`"UserId" = ? AND "IsDeleted" = ?`, userId, false).
+		Select(`COALESCE(SUM("UsedSpaceKb"), 0)`).
+		Scan(&used).Error
+	if err != nil {
+		r.l.Error("user_repo - MeteringReadRepository - Available: %v", err)
+		return 0, err
+	}
+	if left := int64(user.StorageMbCredits)*1024 - used; left > 0 {
+		return left, nil
+	}
+	return 0, nil
+}
+
+func (r *MeteringReadRepository) FindUsages(filter user_repo_inter.CreditUsageFilter) ([]user_entity.CreditUsageEntity, int64, error) {
+	query := r.db.Model(&user_entity.CreditUsageEntity{}).Where(`"UserId" = ?`, filter.UserId)
+	if filter.CreditType != "" {
+		query = query.Where(`"CreditType" = ?`, filter.CreditType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("user_repo - MeteringReadRepository - FindUsages: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []user_entity.CreditUsageEntity
+	err := query.Order(`"Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - MeteringReadRepository - FindUsages: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *MeteringWriteRepository) Consume(usage *user_entity.CreditUsageEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&user_entity.CreditUsageEntity{}).
+			Where(`"ReservationId" = ?`, usage.ReservationId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		usage.CreatedAt = time.Now()
+		if err := tx.Create(usage).Error; err != nil {
+			return err
+		}
+		userId, _ := strconv.ParseInt(usage.UserId, 10, 64)
+		if usage.CreditType == user_entity.CreditStorageMb {
+			return useStorage(tx, userId, usage.Amount, usage.CreatedAt)
+		}
+		usageId, _ := strconv.ParseInt(usage.Id, 10, 64)
+		changes := []creditChange{{creditType: usage.CreditType, amount: -int(usage.Amount)}}
+		return applyCredits(tx, userId, changes, user_entity.CreditReasonUsage, user_entity.CreditReferenceUsage, usageId)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrExhausted) {
+		r.l.Error("user_repo - MeteringWriteRepository - Consume: %v", err)
+	}
+	return err
+}
+
+// useStorage moves the used space of the user by kb, creating the storage of users who never stored
+// anything. The quota and expiry of the storage follow the storage credits of the user.
+func useStorage(tx *gorm.DB, userId int64, kb int64, now time.Time) error {
+	var user user_entity.UserEntity
+	err := tx.Select("Id", "StorageMbCredits", "StorageMbCreditsExpireAt").First(&user, userId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repositories.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var storage drive_entity.StorageEntity
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(`"UserId" = ? AND "IsDeleted" = ?`, userId, false).
+		First(&storage).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		storage = drive_entity.StorageEntity{
+			UsedSpaceKb: max(kb, 0),
+			QuotaKb:     int64(user.StorageMbCredits) * 1024,
+			ChargedAt:   now,
+			ExpireAt:    user.StorageMbCreditsExpireAt,
+			UserId:      user.Id,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		return tx.Omit("Version", "DeletedAt").Create(&storage).Error
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Model(&storage).Updates(map[string]interface{}{
+		"UsedSpaceKb": max(storage.UsedSpaceKb+kb, 0),
+		"QuotaKb":     int64(user.StorageMbCredits) * 1024,
+		"ExpireAt":    user.StorageMbCreditsExpireAt,
+		"UpdatedAt":   now,
+	}).Error
+}
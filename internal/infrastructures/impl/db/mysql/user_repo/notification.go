@@ -0,0 +1,124 @@
+package user_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// Sizes of the Title and Body columns, longer values are cut
+const (
+	_notificationTitleLength = 200
+	_notificationBodyLength  = 1000
+)
+
+type NotificationReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type NotificationWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewNotificationReadRepository(db *gorm.DB, l *logger.ZapLogger) *NotificationReadRepository {
+	return &NotificationReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewNotificationWriteRepository(db *gorm.DB, l *logger.ZapLogger) *NotificationWriteRepository {
+	return &NotificationWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *NotificationReadRepository) FindNotifications(filter user_repo_inter.NotificationFilter) ([]user_entity.NotificationEntity, int64, error) {
+	query := r.db.Model(&user_entity.NotificationEntity{}).Where(`"UserId" = ?`, filter.UserId)
+	if filter.Unread {
+		query = query.Where(`"IsRead" = ?`, false)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("user_repo - NotificationReadRepository - FindNotifications: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []user_entity.NotificationEntity
+	err := query.Order(`"Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - NotificationReadRepository - FindNotifications: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *NotificationReadRepository) CountUnread(userId int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&user_entity.NotificationEntity{}).
+		Where(`"UserId" = ? AND "IsRead" = ?`, userId, false).
+		Count(&count).Error
+	if err != nil {
+		r.l.Error("user_repo - NotificationReadRepository - CountUnread: %v", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *NotificationWriteRepository) Create(notification *user_entity.NotificationEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&user_entity.NotificationEntity{}).
+			Where(`"EventKey" = ?`, notification.EventKey).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return repositories.ErrConflict
+		}
+
+		notification.Title = truncate(notification.Title, _notificationTitleLength)
+		notification.Body = truncate(notification.Body, _notificationBodyLength)
+		notification.IsRead = false
+		notification.CreatedAt = time.Now()
+		omit := []string{"ReadAt"}
+		if notification.ReferenceId == "" {
+			omit = append(omit, "ReferenceId")
+		}
+		return tx.Omit(omit...).Create(notification).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - NotificationWriteRepository - Create: %v", err)
+	}
+	return err
+}
+
+func (r *NotificationWriteRepository) MarkRead(userId int64, ids []int64, at time.Time) (int64, error) {
+	query := r.db.Model(&user_entity.NotificationEntity{}).
+		Where(`"UserId" = ? AND "IsRead" = ?`, userId, false)
+	if len(ids) > 0 {
+		query = query.Where(`"Id" IN ?`, ids)
+	}
+	result := query.Updates(map[string]interface{}{
+		"IsRead": true,
+		"ReadAt": at,
+	})
+	if result.Error != nil {
+		r.l.Error("user_repo - NotificationWriteRepository - MarkRead: %v", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
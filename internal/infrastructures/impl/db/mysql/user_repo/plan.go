@@ -0,0 +1,299 @@
+package user_repo
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/db_helper"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// roleUserTable is the join table of UserEntity.Roles
+const roleUserTable = "User.RoleUser"
+
+type PlanReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type PlanWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewPlanReadRepository(db *gorm.DB, l *logger.ZapLogger) *PlanReadRepository {
+	return &PlanReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewPlanWriteRepository(db *gorm.DB, l *logger.ZapLogger) *PlanWriteRepository {
+	return &PlanWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *PlanReadRepository) FindById(id int64) (*user_entity.PlanEntity, error) {
+	var entity user_entity.PlanEntity
+	err := r.db.Preload("Roles").First(&entity, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - PlanReadRepository - FindById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *PlanReadRepository) FindVisible() ([]user_entity.PlanEntity, error) {
+	var entities []user_entity.PlanEntity
+	err := r.db.Where(map[string]interface{}{"ShowStatus": user_entity.PlanShowStatusVisible}).
+		Order(`"Price"`).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - PlanReadRepository - FindVisible: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *PlanReadRepository) FindPurchaseById(id int64) (*user_entity.PlanPurchaseEntity, error) {
+	var entity user_entity.PlanPurchaseEntity
+	err := r.db.First(&entity, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - PlanReadRepository - FindPurchaseById: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *PlanReadRepository) FindRunningPurchases(userId int64, planId int64, since time.Time, now time.Time) ([]user_entity.PlanPurchaseEntity, error) {
+	var entities []user_entity.PlanPurchaseEntity
+	err := r.db.Where(`"UserId" = ? AND "PlanId" = ? AND "Status" = ? AND "StartsAt" >= ? AND "ExpiresAt" > ?`,
+		userId, planId, user_entity.PlanPurchaseStatusPaid, since, now).
+		Order(`"StartsAt"`).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - PlanReadRepository - FindRunningPurchases: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *PlanReadRepository) FindExpiredUsers(now time.Time, limit int) ([]user_entity.UserEntity, error) {
+	var entities []user_entity.UserEntity
+	err := r.db.Where(`"PlanId" IS NOT NULL AND "PlanExpiredAt" < ? AND "IsDeleted" = ?`, now, false).
+		Order(`"PlanExpiredAt"`).
+		Limit(limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - PlanReadRepository - FindExpiredUsers: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *PlanReadRepository) FindExpiringUsers(now time.Time, until time.Time, limit int) ([]user_entity.UserEntity, error) {
+	var entities []user_entity.UserEntity
+	err := r.db.Where(`"PlanId" IS NOT NULL AND "PlanExpiredAt" >= ? AND "PlanExpiredAt" < ? AND "PlanRemindedAt" IS NULL AND "IsDeleted" = ?`, now, until, false).
+		Order(`"PlanExpiredAt"`).
+		Limit(limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - PlanReadRepository - FindExpiringUsers: %v", err)
+		return nil, err
+	}
+	return entities, nil
+}
+
+// CreatePurchase stores the purchase without dates, they are set on activation. The user's row is locked
+// so concurrent purchases of the same user cannot both pass the pending check.
+func (r *PlanWriteRepository) CreatePurchase(purchase *user_entity.PlanPurchaseEntity, payment *payment_entity.PaymentEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var user user_entity.UserEntity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select(`"Id"`).
+			Where(`"Id" = ?`, purchase.UserId).
+			First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var pending int64
+		err = tx.Model(&user_entity.PlanPurchaseEntity{}).
+			Where(`"UserId" = ? AND "Status" = ?`, purchase.UserId, user_entity.PlanPurchaseStatusPending).
+			Count(&pending).Error
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return repositories.ErrConflict
+		}
+
+		now := time.Now()
+		purchase.CreatedAt = now
+		purchase.UpdatedAt = now
+		if err := tx.Omit("StartsAt", "ExpiresAt", "Plan").Create(purchase).Error; err != nil {
+			return err
+		}
+
+		payment.OrderId, _ = strconv.ParseInt(purchase.Id, 10, 64)
+		payment.CreatedAt = now
+		payment.UpdatedAt = now
+		return db_helper.CreatePayment(tx, payment)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - PlanWriteRepository - CreatePurchase: %v", err)
+	}
+	return err
+}
+
+func (r *PlanWriteRepository) Activate(activation user_repo_inter.PlanActivation) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&user_entity.PlanPurchaseEntity{}).
+			Where(`"Id" = ? AND "Status" = ?`, activation.PurchaseId, user_entity.PlanPurchaseStatusPending).
+			Updates(map[string]interface{}{
+				"Status":    user_entity.PlanPurchaseStatusPaid,
+				"StartsAt":  activation.PeriodStartsAt,
+				"ExpiresAt": activation.ExpiresAt,
+				"UpdatedAt": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+
+		result = tx.Model(&user_entity.UserEntity{}).
+			Where(`"Id" = ?`, activation.UserId).
+			Updates(map[string]interface{}{
+				"PlanId":                   activation.PlanId,
+				"PlanStartedAt":            activation.StartsAt,
+				"PlanExpiredAt":            activation.ExpiresAt,
+				"PlanRemindedAt":           gorm.Expr("NULL"),
+				"StorageMbCreditsExpireAt": activation.ExpiresAt,
+				"UpdatedAt":                now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrNotFound
+		}
+
+		changes := []creditChange{
+			{creditType: user_entity.CreditSms, amount: activation.SmsCredits},
+			{creditType: user_entity.CreditEmail, amount: activation.EmailCredits},
+			{creditType: user_entity.CreditAi, amount: activation.AiCredits},
+			{creditType: user_entity.CreditAiImage, amount: activation.AiImageCredits},
+			{creditType: user_entity.CreditStorageMb, amount: activation.StorageMb, set: true},
+		}
+		err := applyCredits(tx, activation.UserId, changes, user_entity.CreditReasonPlan, user_entity.CreditReferencePlanPurchase, activation.PurchaseId)
+		if err != nil {
+			return err
+		}
+		if err := revokeRoles(tx, activation.UserId, activation.RevokeRoleIds); err != nil {
+			return err
+		}
+		return grantRoles(tx, activation.UserId, activation.GrantRoleIds)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - PlanWriteRepository - Activate: %v", err)
+	}
+	return err
+}
+
+func (r *PlanWriteRepository) FailPurchase(id int64) error {
+	result := r.db.Model(&user_entity.PlanPurchaseEntity{}).
+		Where(`"Id" = ? AND "Status" = ?`, id, user_entity.PlanPurchaseStatusPending).
+		Updates(map[string]interface{}{
+			"Status":    user_entity.PlanPurchaseStatusFailed,
+			"UpdatedAt": time.Now(),
+		})
+	if result.Error != nil {
+		r.l.Error("user_repo - PlanWriteRepository - FailPurchase: %v", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
+
+// ExpirePlan keeps the dates of the plan, so the owner can still see when it ran out
+func (r *PlanWriteRepository) ExpirePlan(userId int64, planId int64, roleIds []int64, now time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&user_entity.UserEntity{}).
+			Where(`"Id" = ? AND "PlanId" = ? AND "PlanExpiredAt" < ?`, userId, planId, now).
+			Updates(map[string]interface{}{
+				"PlanId":    gorm.Expr("NULL"),
+				"UpdatedAt": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrConflict
+		}
+		return revokeRoles(tx, userId, roleIds)
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - PlanWriteRepository - ExpirePlan: %v", err)
+	}
+	return err
+}
+
+func (r *PlanWriteRepository) MarkReminded(userId int64, at time.Time) error {
+	err := r.db.Model(&user_entity.UserEntity{}).
+		Where(`"Id" = ?`, userId).
+		Update("PlanRemindedAt", at).Error
+	if err != nil {
+		r.l.Error("user_repo - PlanWriteRepository - MarkReminded: %v", err)
+	}
+	return err
+}
+
+func revokeRoles(tx *gorm.DB, userId int64, roleIds []int64) error {
+	if len(roleIds) == 0 {
+		return nil
+	}
+	return tx.Exec(`DELETE FROM "User"."RoleUser" WHERE "UserId" = ? AND "RoleId" IN ?`, userId, roleIds).Error
+}
+
+// grantRoles skips the roles the user already has, given by hand or by another plan
+func grantRoles(tx *gorm.DB, userId int64, roleIds []int64) error {
+	for _, roleId := range roleIds {
+		var count int64
+		err := tx.Table(roleUserTable).
+			Where(`"UserId" = ? AND "RoleId" = ?`, userId, roleId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		err = tx.Table(roleUserTable).Create(map[string]interface{}{"UserId": userId, "RoleId": roleId}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
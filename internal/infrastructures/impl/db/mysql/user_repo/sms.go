@@ -0,0 +1,152 @@
+package user_repo
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"site_builder_backend/internal/domain/user_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// _smsErrorLength is the size of the Error column, longer provider answers are cut
+const _smsErrorLength = 500
+
+type SmsReadRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+type SmsWriteRepository struct {
+	db *gorm.DB
+	l  *logger.ZapLogger
+}
+
+func NewSmsReadRepository(db *gorm.DB, l *logger.ZapLogger) *SmsReadRepository {
+	return &SmsReadRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func NewSmsWriteRepository(db *gorm.DB, l *logger.ZapLogger) *SmsWriteRepository {
+	return &SmsWriteRepository{
+		db: db,
+		l:  l,
+	}
+}
+
+func (r *SmsReadRepository) FindByMessageId(messageId string) (*user_entity.SmsMessageEntity, error) {
+	var entity user_entity.SmsMessageEntity
+	err := r.db.Where(`"MessageId" = ?`, messageId).First(&entity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repositories.ErrNotFound
+	}
+	if err != nil {
+		r.l.Error("user_repo - SmsReadRepository - FindByMessageId: %v", err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *SmsReadRepository) FindMessages(filter user_repo_inter.SmsMessageFilter) ([]user_entity.SmsMessageEntity, int64, error) {
+	query := r.db.Model(&user_entity.SmsMessageEntity{}).Where(`"UserId" = ?`, filter.UserId)
+	if filter.Status != "" {
+		query = query.Where(`"Status" = ?`, filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.l.Error("user_repo - SmsReadRepository - FindMessages: %v", err)
+		return nil, 0, err
+	}
+
+	var entities []user_entity.SmsMessageEntity
+	err := query.Order(`"Id" DESC`).
+		Offset(filter.Offset).
+		Limit(filter.Limit).
+		Find(&entities).Error
+	if err != nil {
+		r.l.Error("user_repo - SmsReadRepository - FindMessages: %v", err)
+		return nil, 0, err
+	}
+	return entities, total, nil
+}
+
+func (r *SmsWriteRepository) Create(message *user_entity.SmsMessageEntity) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&user_entity.SmsMessageEntity{}).
+			Where(`"MessageId" = ?`, message.MessageId).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return repositories.ErrConflict
+		}
+
+		now := time.Now()
+		message.CreatedAt = now
+		message.UpdatedAt = now
+		return tx.Omit("SentAt").Create(message).Error
+	})
+	if err != nil && !errors.Is(err, repositories.ErrConflict) {
+		r.l.Error("user_repo - SmsWriteRepository - Create: %v", err)
+	}
+	return err
+}
+
+func (r *SmsWriteRepository) MarkSent(id int64, provider string, providerMessageId string, attempts int, at time.Time) error {
+	return r.update(id, "MarkSent", map[string]interface{}{
+		"Status":            user_entity.SmsStatusSent,
+		"Provider":          provider,
+		"ProviderMessageId": providerMessageId,
+		"Error":             "",
+		"Attempts":          attempts,
+		"SentAt":            at,
+	})
+}
+
+func (r *SmsWriteRepository) MarkAttempt(id int64, provider string, reason string, attempts int) error {
+	return r.update(id, "MarkAttempt", map[string]interface{}{
+		"Provider": provider,
+		"Error":    truncate(reason, _smsErrorLength),
+		"Attempts": attempts,
+	})
+}
+
+func (r *SmsWriteRepository) MarkFailed(id int64, provider string, reason string, attempts int) error {
+	return r.update(id, "MarkFailed", map[string]interface{}{
+		"Status":   user_entity.SmsStatusFailed,
+		"Provider": provider,
+		"Error":    truncate(reason, _smsErrorLength),
+		"Attempts": attempts,
+	})
+}
+
+// update changes the message while it is queued
+func (r *SmsWriteRepository) update(id int64, method string, values map[string]interface{}) error {
+	values["UpdatedAt"] = time.Now()
+	result := r.db.Model(&user_entity.SmsMessageEntity{}).
+		Where(`"Id" = ? AND "Status" = ?`, id, user_entity.SmsStatusQueued).
+		Updates(values)
+	if result.Error != nil {
+		r.l.Error("user_repo - SmsWriteRepository - %s: %v", method, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrConflict
+	}
+	return nil
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
@@ -0,0 +1,79 @@
+package image_processor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"io"
+
+	"site_builder_backend/internal/interfaces/media/image_processor_inter"
+	"site_builder_backend/pkg/imaging"
+	"site_builder_backend/pkg/logger"
+)
+
+// Processor renders derivatives in pure Go and encodes them with encoder, WebP with cwebp in production
+type Processor struct {
+	encoder   imaging.Encoder
+	maxPixels int
+	l         *logger.ZapLogger
+}
+
+// NewProcessor refuses images of more than maxPixels pixels, which would take too much memory to decode
+func NewProcessor(encoder imaging.Encoder, maxPixels int, l *logger.ZapLogger) *Processor {
+	return &Processor{
+		encoder:   encoder,
+		maxPixels: maxPixels,
+		l:         l,
+	}
+}
+
+func (p *Processor) Render(ctx context.Context, src io.Reader, sizes []image_processor_inter.Size) ([]image_processor_inter.Rendition, error) {
+	img, err := imaging.Decode(src, p.maxPixels)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupported):
+		return nil, image_processor_inter.ErrUnsupportedImage
+	case errors.Is(err, imaging.ErrTooLarge):
+		return nil, image_processor_inter.ErrImageTooLarge
+	case err != nil:
+		// Truncated and corrupt files of a known format
+		p.l.Warn("image_processor - Processor - Render: %v", err)
+		return nil, image_processor_inter.ErrUnsupportedImage
+	}
+
+	renditions := make([]image_processor_inter.Rendition, 0, len(sizes))
+	rendered := make(map[image.Point]bool, len(sizes))
+	for _, size := range sizes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var out *image.RGBA
+		if size.Height > 0 {
+			out = imaging.Fill(img, size.Width, size.Height)
+		} else {
+			out = imaging.Fit(img, size.Width)
+		}
+		dims := out.Bounds().Size()
+		if rendered[dims] {
+			continue
+		}
+		rendered[dims] = true
+
+		var buf bytes.Buffer
+		if err := p.encoder.Encode(ctx, &buf, out); err != nil {
+			p.l.Error("image_processor - Processor - Render - %s: %v", size.Name, err)
+			return nil, err
+		}
+		renditions = append(renditions, image_processor_inter.Rendition{
+			Name:     size.Name,
+			Width:    dims.X,
+			Height:   dims.Y,
+			Data:     buf.Bytes(),
+			MimeType: p.encoder.MimeType(),
+			Ext:      p.encoder.Ext(),
+		})
+	}
+	return renditions, nil
+}
+
+var _ image_processor_inter.ImageProcessor = (*Processor)(nil)
@@ -0,0 +1,42 @@
+package event_publisher
+
+import (
+	"context"
+	"sync"
+
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/rabbitmq"
+)
+
+type EventPublisher struct {
+	client *rabbitmq.Client
+	l      *logger.ZapLogger
+	// mu serializes publishes because they share the channel of the client
+	mu sync.Mutex
+}
+
+func NewEventPublisher(client *rabbitmq.Client, l *logger.ZapLogger) *EventPublisher {
+	return &EventPublisher{
+		client: client,
+		l:      l,
+	}
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, exchange string, routingKey string, payload interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	err := p.client.Publisher(exchange).
+		RoutingKey(routingKey).
+		Type("topic").
+		Config(true, false, false, false).
+		PublishJSON(ctx, payload)
+	if err != nil {
+		p.l.Error("event_publisher - EventPublisher - Publish %s: %v", routingKey, err)
+		return err
+	}
+	return nil
+}
+
+var _ event_publisher_inter.EventPublisher = (*EventPublisher)(nil)
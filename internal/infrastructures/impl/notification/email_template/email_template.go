@@ -0,0 +1,77 @@
+package email_template
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+
+	"site_builder_backend/internal/interfaces/notification/email_template_inter"
+)
+
+// templates holds three files per template: <name>.subject.txt, <name>.txt and <name>.html
+//
+//go:embed templates
+var templates embed.FS
+
+type template struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+// Renderer renders the templates shipped with the backend
+type Renderer struct {
+	templates map[string]template
+}
+
+// NewRenderer parses every embedded template, a broken template fails at startup rather than on send
+func NewRenderer() (*Renderer, error) {
+	subjects, err := fs.Glob(templates, "templates/*.subject.txt")
+	if err != nil {
+		return nil, err
+	}
+	r := &Renderer{templates: make(map[string]template, len(subjects))}
+	for _, subject := range subjects {
+		name := strings.TrimSuffix(strings.TrimPrefix(subject, "templates/"), ".subject.txt")
+		var t template
+		if t.subject, err = texttemplate.ParseFS(templates, subject); err != nil {
+			return nil, err
+		}
+		if t.text, err = texttemplate.ParseFS(templates, "templates/"+name+".txt"); err != nil {
+			return nil, err
+		}
+		if t.html, err = htmltemplate.ParseFS(templates, "templates/"+name+".html"); err != nil {
+			return nil, err
+		}
+		r.templates[name] = t
+	}
+	return r, nil
+}
+
+func (r *Renderer) Render(name string, data map[string]interface{}) (*email_template_inter.RenderedEmail, error) {
+	t, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", email_template_inter.ErrTemplateNotFound, name)
+	}
+	var subject, text, html bytes.Buffer
+	if err := t.subject.Execute(&subject, data); err != nil {
+		return nil, err
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return nil, err
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return nil, err
+	}
+	return &email_template_inter.RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Html:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+var _ email_template_inter.EmailRenderer = (*Renderer)(nil)
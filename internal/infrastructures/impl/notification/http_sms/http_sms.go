@@ -0,0 +1,94 @@
+package http_sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"site_builder_backend/internal/interfaces/notification/sms_provider_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// Provider posts every message as JSON to a configured url, for providers without an adapter of their own
+// or a relay in front of them. A 2xx answer accepts the message, other 4xx answers except timeouts and
+// throttling reject it.
+type Provider struct {
+	client *http.Client
+	url    string
+	token  string
+	sender string
+	l      *logger.ZapLogger
+}
+
+// NewProvider sends the token as a bearer token when it is set
+func NewProvider(client *http.Client, url string, token string, sender string, l *logger.ZapLogger) *Provider {
+	return &Provider{
+		client: client,
+		url:    url,
+		token:  token,
+		sender: sender,
+		l:      l,
+	}
+}
+
+type requestBody struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+	Sender  string `json:"sender,omitempty"`
+}
+
+type responseBody struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func (p *Provider) Name() string {
+	return "http"
+}
+
+func (p *Provider) Send(ctx context.Context, phone string, message string) (*sms_provider_inter.SmsResult, error) {
+	payload, err := json.Marshal(requestBody{To: phone, Message: message, Sender: p.sender})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		p.l.Error("http_sms - Provider - Send: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+	case res.StatusCode == http.StatusRequestTimeout || res.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("http_sms - Send: status %d: %s", res.StatusCode, body)
+	case res.StatusCode >= 400 && res.StatusCode < 500:
+		return nil, fmt.Errorf("%w: status %d: %s", sms_provider_inter.ErrRejected, res.StatusCode, body)
+	default:
+		return nil, fmt.Errorf("http_sms - Send: status %d: %s", res.StatusCode, body)
+	}
+
+	// The answer is optional, providers that send none still accepted the message
+	var answer responseBody
+	_ = json.Unmarshal(body, &answer)
+	return &sms_provider_inter.SmsResult{MessageId: answer.Id, Status: answer.Status}, nil
+}
+
+var _ sms_provider_inter.SmsProvider = (*Provider)(nil)
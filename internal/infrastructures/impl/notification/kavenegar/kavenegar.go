@@ -0,0 +1,108 @@
+package kavenegar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"site_builder_backend/internal/interfaces/notification/sms_provider_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const statusOk = 200
+
+// rejectedStatuses are the answers about the message itself, the others concern the account or the
+// service and may pass once fixed
+var rejectedStatuses = map[int]bool{
+	400: true, // missing or invalid parameters
+	411: true, // invalid receptor
+	412: true, // invalid sender line
+	413: true, // empty or too long message
+	414: true, // too many receptors
+	422: true, // invalid characters in the message
+}
+
+const DefaultApi = "https://api.kavenegar.com/v1"
+
+// Provider implements the Kavenegar sms/send api
+type Provider struct {
+	client *http.Client
+	api    string
+	apiKey string
+	sender string
+	l      *logger.ZapLogger
+}
+
+// NewProvider sends from the sender line, or the account's default line when sender is empty
+func NewProvider(client *http.Client, api string, apiKey string, sender string, l *logger.ZapLogger) *Provider {
+	return &Provider{
+		client: client,
+		api:    api,
+		apiKey: apiKey,
+		sender: sender,
+		l:      l,
+	}
+}
+
+type response struct {
+	Return struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	} `json:"return"`
+	Entries []struct {
+		MessageId  int64  `json:"messageid"`
+		Status     int    `json:"status"`
+		StatusText string `json:"statustext"`
+	} `json:"entries"`
+}
+
+func (p *Provider) Name() string {
+	return "kavenegar"
+}
+
+func (p *Provider) Send(ctx context.Context, phone string, message string) (*sms_provider_inter.SmsResult, error) {
+	form := url.Values{}
+	form.Set("receptor", phone)
+	form.Set("message", message)
+	if p.sender != "" {
+		form.Set("sender", p.sender)
+	}
+	endpoint := fmt.Sprintf("%s/%s/sms/send.json", p.api, p.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		p.l.Error("kavenegar - Provider - Send: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var answer response
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return nil, fmt.Errorf("kavenegar - Send: status %d: %w", res.StatusCode, err)
+	}
+	if answer.Return.Status != statusOk {
+		if rejectedStatuses[answer.Return.Status] {
+			return nil, fmt.Errorf("%w: status %d: %s", sms_provider_inter.ErrRejected, answer.Return.Status, answer.Return.Message)
+		}
+		return nil, fmt.Errorf("kavenegar - Send: status %d: %s", answer.Return.Status, answer.Return.Message)
+	}
+
+	result := &sms_provider_inter.SmsResult{}
+	if len(answer.Entries) > 0 {
+		result.MessageId = strconv.FormatInt(answer.Entries[0].MessageId, 10)
+		result.Status = answer.Entries[0].StatusText
+	}
+	return result, nil
+}
+
+var _ sms_provider_inter.SmsProvider = (*Provider)(nil)
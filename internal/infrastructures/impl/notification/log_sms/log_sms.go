@@ -0,0 +1,69 @@
+package log_sms
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"site_builder_backend/internal/interfaces/notification/sms_provider_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// Provider sends nothing, it logs every message and appends it to a file when one is set, for development
+// and tests
+type Provider struct {
+	path string
+	mu   sync.Mutex
+	l    *logger.ZapLogger
+}
+
+// NewProvider appends the messages to path as JSON lines, an empty path only logs them
+func NewProvider(path string, l *logger.ZapLogger) *Provider {
+	return &Provider{
+		path: path,
+		l:    l,
+	}
+}
+
+type entry struct {
+	Id      string    `json:"id"`
+	To      string    `json:"to"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+func (p *Provider) Name() string {
+	return "log"
+}
+
+func (p *Provider) Send(ctx context.Context, phone string, message string) (*sms_provider_inter.SmsResult, error) {
+	now := time.Now()
+	id := strconv.FormatInt(now.UnixNano(), 10)
+	p.l.Info("log_sms - Provider - Send: to %s: %s", phone, message)
+	if p.path == "" {
+		return &sms_provider_inter.SmsResult{MessageId: id, Status: "logged"}, nil
+	}
+
+	line, err := json.Marshal(entry{Id: id, To: phone, Message: message, SentAt: now})
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	file, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		p.l.Error("log_sms - Provider - Send: %v", err)
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		p.l.Error("log_sms - Provider - Send: %v", err)
+		return nil, err
+	}
+	return &sms_provider_inter.SmsResult{MessageId: id, Status: "logged"}, nil
+}
+
+var _ sms_provider_inter.SmsProvider = (*Provider)(nil)
@@ -0,0 +1,235 @@
+package smtp_mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"site_builder_backend/internal/interfaces/notification/mailer_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// publicPorts are the ports public accounts may use: relay, submission over TLS, submission and the
+// common alternative to submission
+var publicPorts = map[int]bool{25: true, 465: true, 587: true, 2525: true}
+
+// errBlockedAddress is returned when a public account points at an address of the platform's network
+var errBlockedAddress = errors.New("smtp server address is not public")
+
+// Mailer sends mail with net/smtp, one connection per message
+type Mailer struct {
+	timeout time.Duration
+	l       *logger.ZapLogger
+}
+
+// NewMailer gives up on a connection after timeout, or earlier when the context ends
+func NewMailer(timeout time.Duration, l *logger.ZapLogger) *Mailer {
+	return &Mailer{
+		timeout: timeout,
+		l:       l,
+	}
+}
+
+func (m *Mailer) Send(ctx context.Context, account mailer_inter.SmtpAccount, message mailer_inter.Mail) error {
+	from, err := mail.ParseAddress(account.From)
+	if err != nil {
+		return fmt.Errorf("%w: sender %q: %v", mailer_inter.ErrRejected, account.From, err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("%w: recipient %q: %v", mailer_inter.ErrRejected, message.To, err)
+	}
+	body, err := compose(from, to, message)
+	if err != nil {
+		return err
+	}
+
+	if account.Public && !publicPorts[account.Port] {
+		return fmt.Errorf("%w: smtp port %d is not allowed", mailer_inter.ErrRejected, account.Port)
+	}
+
+	if err := m.send(ctx, account, from.Address, to.Address, body); err != nil {
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return fmt.Errorf("%w: %v", mailer_inter.ErrRejected, err)
+		}
+		if errors.Is(err, errBlockedAddress) {
+			return fmt.Errorf("%w: %v", mailer_inter.ErrRejected, err)
+		}
+		m.l.Error("smtp_mailer - Mailer - Send: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (m *Mailer) send(ctx context.Context, account mailer_inter.SmtpAccount, from string, to string, body []byte) error {
+	deadline := time.Now().Add(m.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	addr := net.JoinHostPort(account.Host, strconv.Itoa(account.Port))
+	dialer := &net.Dialer{Deadline: deadline}
+	if account.Public {
+		// Checked on the resolved address of every connection, so a host resolving differently later
+		// cannot reach the platform's network either
+		dialer.Control = publicOnly
+	}
+	tlsConfig := &tls.Config{ServerName: account.Host}
+
+	var conn net.Conn
+	var err error
+	if account.EnableSsl {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, account.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !account.EnableSsl {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if account.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", account.Username, account.Password, account.Host)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// publicOnly refuses connections to loopback, private, link local and other addresses that are not
+// routed on the internet
+func publicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier grade NAT range, private but not covered by IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// compose writes the message with its headers, as multipart/alternative when it has both bodies
+func compose(from *mail.Address, to *mail.Address, message mailer_inter.Mail) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(name string, value string) {
+		buf.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	if message.MessageId != "" {
+		domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+		header("Message-ID", "<"+message.MessageId+"@"+domain+">")
+	}
+	header("MIME-Version", "1.0")
+
+	if message.Html == "" || message.Text == "" {
+		contentType, content := "text/plain; charset=utf-8", message.Text
+		if message.Html != "" {
+			contentType, content = "text/html; charset=utf-8", message.Html
+		}
+		header("Content-Type", contentType)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuoted(&buf, content); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+	header("Content-Type", "multipart/alternative; boundary="+writer.Boundary())
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.Html},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuoted(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write(parts.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuoted(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+var _ mailer_inter.Mailer = (*Mailer)(nil)
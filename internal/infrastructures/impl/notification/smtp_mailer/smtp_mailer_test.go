@@ -0,0 +1,251 @@
+package smtp_mailer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"site_builder_backend/internal/interfaces/notification/mailer_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// smtpServer is an in-process SMTP server without STARTTLS or AUTH. rcptReplies are the replies to
+// RCPT TO of the connections in order, connections past them are accepted.
+type smtpServer struct {
+	listener    net.Listener
+	mu          sync.Mutex
+	rcptReplies []string
+	messages    []string
+}
+
+func newSmtpServer(t *testing.T, rcptReplies ...string) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{listener: listener, rcptReplies: rcptReplies}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = io.WriteString(conn, line+"\r\n")
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO"):
+			reply(s.rcptReply())
+		case command == "DATA":
+			reply("354 end with .")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpServer) rcptReply() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.rcptReplies) == 0 {
+		return "250 OK"
+	}
+	reply := s.rcptReplies[0]
+	s.rcptReplies = s.rcptReplies[1:]
+	return reply
+}
+
+func (s *smtpServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func (s *smtpServer) account() mailer_inter.SmtpAccount {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return mailer_inter.SmtpAccount{Host: "127.0.0.1", Port: addr.Port, From: "Shop <shop@example.com>"}
+}
+
+func newTestMailer() *Mailer {
+	return NewMailer(5*time.Second, logger.NewLoggerFromConfig("error", "json", "stdout"))
+}
+
+func TestSendComposesMultipartAlternative(t *testing.T) {
+	server := newSmtpServer(t)
+	err := newTestMailer().Send(context.Background(), server.account(), mailer_inter.Mail{
+		MessageId: "m1",
+		To:        "owner@example.com",
+		Subject:   "سفارش جدید",
+		Html:      "<p>Order paid</p>",
+		Text:      "Order paid",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	received := server.received()
+	if len(received) != 1 {
+		t.Fatalf("received %d messages, want 1", len(received))
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(received[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "سفارش جدید" {
+		t.Fatalf("subject = %q, %v", subject, err)
+	}
+	if got := msg.Header.Get("Message-ID"); got != "<m1@example.com>" {
+		t.Fatalf("Message-ID = %q", got)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, %v", mediaType, err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	want := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", "Order paid"},
+		{"text/html; charset=utf-8", "<p>Order paid</p>"},
+	}
+	for _, w := range want {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("part %s: %v", w.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Fatalf("part content type = %q, want %q", got, w.contentType)
+		}
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil || string(content) != w.content {
+			t.Fatalf("part content = %q, %v, want %q", content, err, w.content)
+		}
+	}
+	if _, err := reader.NextRawPart(); err != io.EOF {
+		t.Fatalf("want two parts, next part: %v", err)
+	}
+}
+
+func TestSendPermanentReplyIsRejected(t *testing.T) {
+	server := newSmtpServer(t, "550 no such user")
+	err := newTestMailer().Send(context.Background(), server.account(), mailer_inter.Mail{
+		To:   "missing@example.com",
+		Text: "hello",
+	})
+	if !errors.Is(err, mailer_inter.ErrRejected) {
+		t.Fatalf("err = %v, want ErrRejected", err)
+	}
+}
+
+func TestSendTemporaryReplyIsRetried(t *testing.T) {
+	server := newSmtpServer(t, "451 try again later")
+	mailer := newTestMailer()
+	message := mailer_inter.Mail{To: "owner@example.com", Text: "hello"}
+
+	err := mailer.Send(context.Background(), server.account(), message)
+	if err == nil || errors.Is(err, mailer_inter.ErrRejected) {
+		t.Fatalf("first attempt err = %v, want a temporary error", err)
+	}
+	if err := mailer.Send(context.Background(), server.account(), message); err != nil {
+		t.Fatalf("second attempt: %v", err)
+	}
+	if received := server.received(); len(received) != 1 {
+		t.Fatalf("received %d messages, want 1", len(received))
+	}
+}
+
+func TestSendPublicAccountRefusesPlatformAddresses(t *testing.T) {
+	server := newSmtpServer(t)
+	mailer := newTestMailer()
+	message := mailer_inter.Mail{To: "owner@example.com", Text: "hello"}
+
+	account := server.account()
+	account.Public = true
+	account.Port = 25
+	listening := server.account()
+	for _, host := range []string{"127.0.0.1", "10.0.0.1", "192.168.1.10", "169.254.169.254", "::1"} {
+		account.Host = host
+		if err := mailer.Send(context.Background(), account, message); !errors.Is(err, mailer_inter.ErrRejected) {
+			t.Fatalf("host %s: err = %v, want ErrRejected", host, err)
+		}
+	}
+
+	// The listener's own port is not an SMTP port, it is refused before dialing
+	listening.Public = true
+	if err := mailer.Send(context.Background(), listening, message); !errors.Is(err, mailer_inter.ErrRejected) {
+		t.Fatalf("port %d: err = %v, want ErrRejected", listening.Port, err)
+	}
+	if received := server.received(); len(received) != 0 {
+		t.Fatalf("received %d messages, want none", len(received))
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	cases := map[string]bool{
+		"8.8.8.8":          true,
+		"2001:4860::8888":  true,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false,
+	}
+	for address, want := range cases {
+		if got := isPublic(net.ParseIP(address)); got != want {
+			t.Errorf("isPublic(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
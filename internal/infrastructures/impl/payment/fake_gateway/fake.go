@@ -0,0 +1,116 @@
+package fake_gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+)
+
+// ErrUnreachable stands for a gateway that times out or answers with a server error
+var ErrUnreachable = errors.New("fake gateway is unreachable")
+
+// Gateway is an in-memory gateway for tests of the payment flow and the reconciler. It goes by the name
+// of the gateway it replaces, attempts are unpaid until Pay is called for their tracking number.
+type Gateway struct {
+	name string
+
+	mu          sync.Mutex
+	paid        map[int64]string
+	refunded    map[int64]int64
+	unreachable bool
+}
+
+func NewGateway(name string) *Gateway {
+	return &Gateway{
+		name:     name,
+		paid:     make(map[int64]string),
+		refunded: make(map[int64]int64),
+	}
+}
+
+// Pay settles the attempt at the gateway under the reference id, as if the customer paid and closed the
+// browser before the callback
+func (g *Gateway) Pay(trackingNumber int64, referenceId string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paid[trackingNumber] = referenceId
+}
+
+// SetUnreachable makes every call fail with ErrUnreachable until it is switched back
+func (g *Gateway) SetUnreachable(unreachable bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.unreachable = unreachable
+}
+
+// Refunded returns the amount refunded for the attempt
+func (g *Gateway) Refunded(trackingNumber int64) int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.refunded[trackingNumber]
+}
+
+func (g *Gateway) Name() string {
+	return g.name
+}
+
+func (g *Gateway) Request(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.PaymentRequest) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.unreachable {
+		return "", ErrUnreachable
+	}
+	return token(request.TrackingNumber), nil
+}
+
+func (g *Gateway) Redirect(account *payment_entity.GatewayEntity, request payment_gateway_inter.RedirectRequest) (*payment_gateway_inter.Redirect, error) {
+	return &payment_gateway_inter.Redirect{Url: request.CallbackUrl + "?token=" + request.Token, Method: http.MethodGet}, nil
+}
+
+func (g *Gateway) Verify(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.VerifyRequest) (*payment_gateway_inter.VerifyResult, error) {
+	if request.Callback["token"] != request.Token {
+		return nil, payment_gateway_inter.ErrNotVerified
+	}
+	return g.Inquire(ctx, account, payment_gateway_inter.InquiryRequest{
+		TrackingNumber: request.TrackingNumber,
+		Token:          request.Token,
+		Amount:         request.Amount,
+	})
+}
+
+func (g *Gateway) Inquire(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.InquiryRequest) (*payment_gateway_inter.VerifyResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.unreachable {
+		return nil, ErrUnreachable
+	}
+	reference, ok := g.paid[request.TrackingNumber]
+	if !ok || request.Token != token(request.TrackingNumber) {
+		return nil, payment_gateway_inter.ErrNotVerified
+	}
+	return &payment_gateway_inter.VerifyResult{ReferenceId: reference, ResponseCode: "100"}, nil
+}
+
+func (g *Gateway) Refund(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.RefundRequest) (*payment_gateway_inter.RefundResult, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.unreachable {
+		return nil, ErrUnreachable
+	}
+	if _, ok := g.paid[request.TrackingNumber]; !ok {
+		return nil, payment_gateway_inter.ErrNotVerified
+	}
+	g.refunded[request.TrackingNumber] += request.Amount
+	return &payment_gateway_inter.RefundResult{ReferenceId: "refund-" + strconv.FormatInt(request.TrackingNumber, 10)}, nil
+}
+
+func token(trackingNumber int64) string {
+	return "fake-" + strconv.FormatInt(trackingNumber, 10)
+}
+
+var _ payment_gateway_inter.PaymentGateway = (*Gateway)(nil)
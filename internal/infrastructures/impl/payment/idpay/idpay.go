@@ -0,0 +1,194 @@
+package idpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	// callbackStatusPaid is sent back with a payment that waits for verification
+	callbackStatusPaid    = "10"
+	statusVerified        = 100
+	statusAlreadyVerified = 101
+)
+
+// Endpoints are the IdPay hosts, replaced with a stub server in tests
+type Endpoints struct {
+	Api            string
+	PaymentPage    string
+	SandboxPayment string
+}
+
+var DefaultEndpoints = Endpoints{
+	Api:            "https://api.idpay.ir/v1.1",
+	PaymentPage:    "https://idpay.ir/p/ws/",
+	SandboxPayment: "https://idpay.ir/p/ws-sandbox/",
+}
+
+// Gateway implements the IdPay v1.1 api. Test accounts are sent with the sandbox header. Amounts are in rials.
+type Gateway struct {
+	client    *http.Client
+	endpoints Endpoints
+	l         *logger.ZapLogger
+}
+
+func NewGateway(client *http.Client, endpoints Endpoints, l *logger.ZapLogger) *Gateway {
+	return &Gateway{
+		client:    client,
+		endpoints: endpoints,
+		l:         l,
+	}
+}
+
+type requestBody struct {
+	OrderId  string `json:"order_id"`
+	Amount   int64  `json:"amount"`
+	Desc     string `json:"desc"`
+	Callback string `json:"callback"`
+}
+
+type requestAnswer struct {
+	Id   string `json:"id"`
+	Link string `json:"link"`
+}
+
+type verifyBody struct {
+	Id      string `json:"id"`
+	OrderId string `json:"order_id"`
+}
+
+type verifyAnswer struct {
+	Status  int    `json:"status"`
+	TrackId string `json:"track_id"`
+	Amount  int64  `json:"amount"`
+	Payment struct {
+		TrackId string `json:"track_id"`
+		CardNo  string `json:"card_no"`
+	} `json:"payment"`
+}
+
+type errorAnswer struct {
+	ErrorCode    int    `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+func (g *Gateway) Name() string {
+	return payment_entity.GatewayIdPay
+}
+
+func (g *Gateway) Request(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.PaymentRequest) (string, error) {
+	if account.IdPay_Api == "" {
+		return "", payment_gateway_inter.ErrMisconfigured
+	}
+	var answer requestAnswer
+	err := g.call(ctx, account, "/payment", requestBody{
+		OrderId:  strconv.FormatInt(request.TrackingNumber, 10),
+		Amount:   request.Amount,
+		Desc:     request.Description,
+		Callback: request.CallbackUrl,
+	}, &answer)
+	if err != nil {
+		return "", err
+	}
+	if answer.Id == "" {
+		return "", fmt.Errorf("idpay - Request: answer without id")
+	}
+	return answer.Id, nil
+}
+
+func (g *Gateway) Redirect(account *payment_entity.GatewayEntity, request payment_gateway_inter.RedirectRequest) (*payment_gateway_inter.Redirect, error) {
+	page := g.endpoints.PaymentPage
+	if account.IdPay_IsTestAccount {
+		page = g.endpoints.SandboxPayment
+	}
+	return &payment_gateway_inter.Redirect{Url: page + request.Token, Method: http.MethodGet}, nil
+}
+
+func (g *Gateway) Verify(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.VerifyRequest) (*payment_gateway_inter.VerifyResult, error) {
+	orderId := strconv.FormatInt(request.TrackingNumber, 10)
+	if request.Callback["id"] != request.Token || request.Callback["order_id"] != orderId {
+		return nil, payment_gateway_inter.ErrNotVerified
+	}
+	if request.Callback["status"] != callbackStatusPaid {
+		return nil, fmt.Errorf("%w: status %s", payment_gateway_inter.ErrNotVerified, request.Callback["status"])
+	}
+	return g.verify(ctx, account, request.Token, orderId, request.Amount)
+}
+
+// Inquire verifies the payment, IdPay answers with an error for payments that were not paid
+func (g *Gateway) Inquire(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.InquiryRequest) (*payment_gateway_inter.VerifyResult, error) {
+	return g.verify(ctx, account, request.Token, strconv.FormatInt(request.TrackingNumber, 10), request.Amount)
+}
+
+func (g *Gateway) verify(ctx context.Context, account *payment_entity.GatewayEntity, id string, orderId string, amount int64) (*payment_gateway_inter.VerifyResult, error) {
+	var answer verifyAnswer
+	if err := g.call(ctx, account, "/payment/verify", verifyBody{Id: id, OrderId: orderId}, &answer); err != nil {
+		return nil, err
+	}
+	if answer.Status != statusVerified && answer.Status != statusAlreadyVerified {
+		return nil, fmt.Errorf("%w: status %d", payment_gateway_inter.ErrNotVerified, answer.Status)
+	}
+	if answer.Amount != amount {
+		return nil, fmt.Errorf("%w: paid %d instead of %d", payment_gateway_inter.ErrNotVerified, answer.Amount, amount)
+	}
+	return &payment_gateway_inter.VerifyResult{
+		ReferenceId:  answer.Payment.TrackId,
+		CardNumber:   answer.Payment.CardNo,
+		ResponseCode: strconv.Itoa(answer.Status),
+	}, nil
+}
+
+// Refund is not offered by the IdPay api
+func (g *Gateway) Refund(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.RefundRequest) (*payment_gateway_inter.RefundResult, error) {
+	return nil, payment_gateway_inter.ErrRefundNotSupported
+}
+
+// call posts the body and decodes a successful answer into out. Errors reported by IdPay come back as
+// ErrNotVerified wrapped with their code, anything else is a transport failure.
+func (g *Gateway) call(ctx context.Context, account *payment_entity.GatewayEntity, path string, body interface{}, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoints.Api+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-KEY", account.IdPay_Api)
+	if account.IdPay_IsTestAccount {
+		req.Header.Set("X-SANDBOX", "1")
+	}
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		g.l.Error("idpay - Gateway - call: %v", err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest && res.StatusCode < http.StatusInternalServerError {
+		var failure errorAnswer
+		if err := json.NewDecoder(res.Body).Decode(&failure); err != nil {
+			return fmt.Errorf("idpay - call: status %d: %w", res.StatusCode, err)
+		}
+		return fmt.Errorf("%w: code %d: %s", payment_gateway_inter.ErrNotVerified, failure.ErrorCode, failure.ErrorMessage)
+	}
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("idpay - call: status %d", res.StatusCode)
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("idpay - call: status %d: %w", res.StatusCode, err)
+	}
+	return nil
+}
+
+var _ payment_gateway_inter.PaymentGateway = (*Gateway)(nil)
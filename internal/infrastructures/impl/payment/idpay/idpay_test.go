@@ -0,0 +1,120 @@
+package idpay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+type answer struct {
+	status int
+	body   string
+}
+
+// stub answers every path with its answer and records the calls
+func stub(t *testing.T, answers map[string]answer) (*Gateway, *[]string) {
+	t.Helper()
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		if r.Header.Get("X-API-KEY") != "key" {
+			t.Errorf("%s sent key %q", r.URL.Path, r.Header.Get("X-API-KEY"))
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode %s: %v", r.URL.Path, err)
+		}
+		if body["order_id"] != "7" {
+			t.Errorf("%s sent order %v", r.URL.Path, body["order_id"])
+		}
+		a := answers[r.URL.Path]
+		w.WriteHeader(a.status)
+		_, _ = w.Write([]byte(a.body))
+	}))
+	t.Cleanup(server.Close)
+
+	gateway := NewGateway(server.Client(), Endpoints{Api: server.URL, PaymentPage: "https://pay.test/", SandboxPayment: "https://sandbox.pay.test/"}, logger.NewLoggerFromConfig("error", "json", "stdout"))
+	return gateway, &calls
+}
+
+var account = &payment_entity.GatewayEntity{IdPay_Api: "key"}
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		answer      answer
+		id          string
+		notVerified bool
+	}{
+		{name: "created", answer: answer{http.StatusCreated, `{"id":"d2e353189823079e1e4181772cff5292","link":"https://idpay.ir/p/ws/d2e353189823079e1e4181772cff5292"}`}, id: "d2e353189823079e1e4181772cff5292"},
+		{name: "rejected", answer: answer{http.StatusForbidden, `{"error_code":11,"error_message":"user is blocked"}`}, notVerified: true},
+		{name: "server failure", answer: answer{http.StatusInternalServerError, ``}},
+		{name: "answer without id", answer: answer{http.StatusCreated, `{}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, _ := stub(t, map[string]answer{"/payment": tt.answer})
+
+			id, err := gateway.Request(context.Background(), account, payment_gateway_inter.PaymentRequest{TrackingNumber: 7, Amount: 10000})
+			if tt.id != "" {
+				if err != nil || id != tt.id {
+					t.Fatalf("Request() = %q, %v, want %q", id, err, tt.id)
+				}
+				return
+			}
+			if err == nil || errors.Is(err, payment_gateway_inter.ErrNotVerified) != tt.notVerified {
+				t.Fatalf("Request() error = %v, want ErrNotVerified %v", err, tt.notVerified)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	callback := map[string]string{"id": "abc", "order_id": "7", "status": "10"}
+	tests := []struct {
+		name     string
+		callback map[string]string
+		answer   answer
+		err      error
+		calls    int
+	}{
+		{name: "paid", callback: callback, answer: answer{http.StatusOK, `{"status":100,"track_id":"1","amount":10000,"payment":{"track_id":"888","card_no":"123456******1234"}}`}, calls: 1},
+		{name: "verified before", callback: callback, answer: answer{http.StatusOK, `{"status":101,"amount":10000,"payment":{"track_id":"888"}}`}, calls: 1},
+		{name: "cancelled by the customer", callback: map[string]string{"id": "abc", "order_id": "7", "status": "7"}, err: payment_gateway_inter.ErrNotVerified},
+		{name: "callback of another order", callback: map[string]string{"id": "abc", "order_id": "8", "status": "10"}, err: payment_gateway_inter.ErrNotVerified},
+		{name: "another amount paid", callback: callback, answer: answer{http.StatusOK, `{"status":100,"amount":500,"payment":{"track_id":"888"}}`}, err: payment_gateway_inter.ErrNotVerified, calls: 1},
+		{name: "not paid", callback: callback, answer: answer{http.StatusMethodNotAllowed, `{"error_code":53,"error_message":"payment was not confirmed"}`}, err: payment_gateway_inter.ErrNotVerified, calls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, calls := stub(t, map[string]answer{"/payment/verify": tt.answer})
+
+			result, err := gateway.Verify(context.Background(), account, payment_gateway_inter.VerifyRequest{TrackingNumber: 7, Token: "abc", Amount: 10000, Callback: tt.callback})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && result.ReferenceId != "888" {
+				t.Fatalf("Verify() reference = %s", result.ReferenceId)
+			}
+			if len(*calls) != tt.calls {
+				t.Fatalf("calls = %v, want %d", *calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestVerifyUnreachable(t *testing.T) {
+	gateway, _ := stub(t, map[string]answer{"/payment/verify": {http.StatusServiceUnavailable, ``}})
+
+	_, err := gateway.Inquire(context.Background(), account, payment_gateway_inter.InquiryRequest{TrackingNumber: 7, Token: "abc", Amount: 10000})
+	if err == nil || errors.Is(err, payment_gateway_inter.ErrNotVerified) {
+		t.Fatalf("Inquire() error = %v, want a transport failure", err)
+	}
+}
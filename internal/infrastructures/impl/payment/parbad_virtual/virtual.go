@@ -0,0 +1,82 @@
+package parbad_virtual
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+)
+
+// Values the virtual gateway page posts back to the callback
+const (
+	CallbackToken   = "token"
+	CallbackStatus  = "status"
+	StatusSucceeded = "succeeded"
+	StatusCancelled = "cancelled"
+)
+
+// Gateway mimics a bank for local development. The customer is sent to a page of this api where the
+// payment is accepted or cancelled with a click, nothing is ever charged.
+type Gateway struct {
+	pageUrl string
+}
+
+func NewGateway(pageUrl string) *Gateway {
+	return &Gateway{pageUrl: pageUrl}
+}
+
+func (g *Gateway) Name() string {
+	return payment_entity.GatewayParbadVirtual
+}
+
+func (g *Gateway) Request(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.PaymentRequest) (string, error) {
+	return randomCode()
+}
+
+func (g *Gateway) Redirect(account *payment_entity.GatewayEntity, request payment_gateway_inter.RedirectRequest) (*payment_gateway_inter.Redirect, error) {
+	query := url.Values{}
+	query.Set("tracking_number", strconv.FormatInt(request.TrackingNumber, 10))
+	query.Set("token", request.Token)
+	query.Set("amount", strconv.FormatInt(request.Amount, 10))
+	query.Set("callback", request.CallbackUrl)
+	return &payment_gateway_inter.Redirect{Url: g.pageUrl + "?" + query.Encode(), Method: http.MethodGet}, nil
+}
+
+func (g *Gateway) Verify(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.VerifyRequest) (*payment_gateway_inter.VerifyResult, error) {
+	if request.Callback[CallbackToken] != request.Token || request.Callback[CallbackStatus] != StatusSucceeded {
+		return nil, payment_gateway_inter.ErrNotVerified
+	}
+	reference, err := randomCode()
+	if err != nil {
+		return nil, err
+	}
+	return &payment_gateway_inter.VerifyResult{ReferenceId: reference, ResponseCode: StatusSucceeded, Message: "virtual payment"}, nil
+}
+
+// Inquire fails every attempt, the virtual gateway only learns of a payment through its callback
+func (g *Gateway) Inquire(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.InquiryRequest) (*payment_gateway_inter.VerifyResult, error) {
+	return nil, payment_gateway_inter.ErrNotVerified
+}
+
+func (g *Gateway) Refund(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.RefundRequest) (*payment_gateway_inter.RefundResult, error) {
+	reference, err := randomCode()
+	if err != nil {
+		return nil, err
+	}
+	return &payment_gateway_inter.RefundResult{ReferenceId: reference}, nil
+}
+
+func randomCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+var _ payment_gateway_inter.PaymentGateway = (*Gateway)(nil)
@@ -0,0 +1,209 @@
+package zarinpal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	codeSuccess         = 100
+	codeAlreadyVerified = 101
+	callbackStatusOk    = "OK"
+)
+
+// Endpoints are the ZarinPal hosts, replaced with a stub server in tests
+type Endpoints struct {
+	Api             string
+	SandboxApi      string
+	StartPay        string
+	SandboxStartPay string
+}
+
+var DefaultEndpoints = Endpoints{
+	Api:             "https://api.zarinpal.com/pg/v4/payment",
+	SandboxApi:      "https://sandbox.zarinpal.com/pg/v4/payment",
+	StartPay:        "https://www.zarinpal.com/pg/StartPay/",
+	SandboxStartPay: "https://sandbox.zarinpal.com/pg/StartPay/",
+}
+
+// Gateway implements the ZarinPal v4 REST api. Amounts are sent in rials.
+type Gateway struct {
+	client    *http.Client
+	endpoints Endpoints
+	l         *logger.ZapLogger
+}
+
+func NewGateway(client *http.Client, endpoints Endpoints, l *logger.ZapLogger) *Gateway {
+	return &Gateway{
+		client:    client,
+		endpoints: endpoints,
+		l:         l,
+	}
+}
+
+type requestBody struct {
+	MerchantId  string `json:"merchant_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	CallbackUrl string `json:"callback_url"`
+	Description string `json:"description"`
+	Metadata    struct {
+		OrderId string `json:"order_id"`
+	} `json:"metadata"`
+}
+
+type verifyBody struct {
+	MerchantId string `json:"merchant_id"`
+	Amount     int64  `json:"amount"`
+	Authority  string `json:"authority"`
+}
+
+// response wraps every answer. Data and Errors are an empty array rather than an object when unset.
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors json.RawMessage `json:"errors"`
+}
+
+type responseData struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	Authority string `json:"authority"`
+	RefId     int64  `json:"ref_id"`
+	CardPan   string `json:"card_pan"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (g *Gateway) Name() string {
+	return payment_entity.GatewayZarinPal
+}
+
+func (g *Gateway) Request(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.PaymentRequest) (string, error) {
+	if account.ZarinPal_MerchantId == "" {
+		return "", payment_gateway_inter.ErrMisconfigured
+	}
+	body := requestBody{
+		MerchantId:  account.ZarinPal_MerchantId,
+		Amount:      request.Amount,
+		Currency:    "IRR",
+		CallbackUrl: request.CallbackUrl,
+		Description: request.Description,
+	}
+	body.Metadata.OrderId = strconv.FormatInt(request.TrackingNumber, 10)
+
+	data, err := g.call(ctx, g.api(account)+"/request.json", body)
+	if err != nil {
+		return "", err
+	}
+	if data.Code != codeSuccess || data.Authority == "" {
+		return "", fmt.Errorf("zarinpal - Request: code %d: %s", data.Code, data.Message)
+	}
+	return data.Authority, nil
+}
+
+func (g *Gateway) Redirect(account *payment_entity.GatewayEntity, request payment_gateway_inter.RedirectRequest) (*payment_gateway_inter.Redirect, error) {
+	startPay := g.endpoints.StartPay
+	if account.ZarinPal_IsSandbox {
+		startPay = g.endpoints.SandboxStartPay
+	}
+	return &payment_gateway_inter.Redirect{Url: startPay + request.Token, Method: http.MethodGet}, nil
+}
+
+func (g *Gateway) Verify(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.VerifyRequest) (*payment_gateway_inter.VerifyResult, error) {
+	if request.Callback["Authority"] != request.Token || request.Callback["Status"] != callbackStatusOk {
+		return nil, payment_gateway_inter.ErrNotVerified
+	}
+	return g.verify(ctx, account, request.Token, request.Amount)
+}
+
+// Inquire verifies the authority, ZarinPal rejects authorities that were not paid
+func (g *Gateway) Inquire(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.InquiryRequest) (*payment_gateway_inter.VerifyResult, error) {
+	return g.verify(ctx, account, request.Token, request.Amount)
+}
+
+func (g *Gateway) verify(ctx context.Context, account *payment_entity.GatewayEntity, authority string, amount int64) (*payment_gateway_inter.VerifyResult, error) {
+	data, err := g.call(ctx, g.api(account)+"/verify.json", verifyBody{
+		MerchantId: account.ZarinPal_MerchantId,
+		Amount:     amount,
+		Authority:  authority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data.Code != codeSuccess && data.Code != codeAlreadyVerified {
+		return nil, fmt.Errorf("%w: code %d: %s", payment_gateway_inter.ErrNotVerified, data.Code, data.Message)
+	}
+	return &payment_gateway_inter.VerifyResult{
+		ReferenceId:  strconv.FormatInt(data.RefId, 10),
+		CardNumber:   data.CardPan,
+		ResponseCode: strconv.Itoa(data.Code),
+		Message:      data.Message,
+	}, nil
+}
+
+// Refund is not offered by the payment api, ZarinPal refunds are issued from the merchant panel
+func (g *Gateway) Refund(ctx context.Context, account *payment_entity.GatewayEntity, request payment_gateway_inter.RefundRequest) (*payment_gateway_inter.RefundResult, error) {
+	return nil, payment_gateway_inter.ErrRefundNotSupported
+}
+
+func (g *Gateway) api(account *payment_entity.GatewayEntity) string {
+	if account.ZarinPal_IsSandbox {
+		return g.endpoints.SandboxApi
+	}
+	return g.endpoints.Api
+}
+
+// call posts the body and returns the data of the answer. Rejections by ZarinPal come back as
+// ErrNotVerified wrapped with their code, anything else is a transport failure.
+func (g *Gateway) call(ctx context.Context, url string, body interface{}) (*responseData, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		g.l.Error("zarinpal - Gateway - call: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var answer response
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return nil, fmt.Errorf("zarinpal - call: status %d: %w", res.StatusCode, err)
+	}
+	var failure responseError
+	if len(answer.Errors) > 0 && answer.Errors[0] == '{' {
+		if err := json.Unmarshal(answer.Errors, &failure); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: code %d: %s", payment_gateway_inter.ErrNotVerified, failure.Code, failure.Message)
+	}
+	var data responseData
+	if len(answer.Data) == 0 || answer.Data[0] != '{' {
+		return nil, fmt.Errorf("zarinpal - call: status %d without data", res.StatusCode)
+	}
+	if err := json.Unmarshal(answer.Data, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+var _ payment_gateway_inter.PaymentGateway = (*Gateway)(nil)
@@ -0,0 +1,147 @@
+package zarinpal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// stub serves the api under /live and /sandbox, answering every call with the body of its path
+func stub(t *testing.T, answers map[string]string, status int) (*Gateway, *[]string) {
+	t.Helper()
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s %s with content type %q", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode %s: %v", r.URL.Path, err)
+		}
+		if body["merchant_id"] != "merchant" {
+			t.Errorf("%s sent merchant %v", r.URL.Path, body["merchant_id"])
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(answers[r.URL.Path]))
+	}))
+	t.Cleanup(server.Close)
+
+	gateway := NewGateway(server.Client(), Endpoints{
+		Api:             server.URL + "/live",
+		SandboxApi:      server.URL + "/sandbox",
+		StartPay:        "https://pay.test/",
+		SandboxStartPay: "https://sandbox.pay.test/",
+	}, logger.NewLoggerFromConfig("error", "json", "stdout"))
+	return gateway, &calls
+}
+
+var account = &payment_entity.GatewayEntity{ZarinPal_MerchantId: "merchant"}
+
+func TestRequest(t *testing.T) {
+	gateway, calls := stub(t, map[string]string{
+		"/live/request.json": `{"data":{"code":100,"message":"Success","authority":"A0000000001"},"errors":[]}`,
+	}, http.StatusOK)
+
+	authority, err := gateway.Request(context.Background(), account, payment_gateway_inter.PaymentRequest{TrackingNumber: 7, Amount: 10000, CallbackUrl: "https://shop.test/callback"})
+	if err != nil || authority != "A0000000001" {
+		t.Fatalf("Request() = %q, %v", authority, err)
+	}
+	if len(*calls) != 1 || (*calls)[0] != "/live/request.json" {
+		t.Fatalf("calls = %v", *calls)
+	}
+}
+
+func TestRequestSandbox(t *testing.T) {
+	gateway, calls := stub(t, map[string]string{
+		"/sandbox/request.json": `{"data":{"code":100,"authority":"S0000000001"},"errors":[]}`,
+	}, http.StatusOK)
+
+	sandbox := &payment_entity.GatewayEntity{ZarinPal_MerchantId: "merchant", ZarinPal_IsSandbox: true}
+	authority, err := gateway.Request(context.Background(), sandbox, payment_gateway_inter.PaymentRequest{TrackingNumber: 7, Amount: 10000})
+	if err != nil || authority != "S0000000001" || (*calls)[0] != "/sandbox/request.json" {
+		t.Fatalf("Request() = %q, %v after %v", authority, err, *calls)
+	}
+	redirect, _ := gateway.Redirect(sandbox, payment_gateway_inter.RedirectRequest{Token: authority})
+	if redirect.Url != "https://sandbox.pay.test/S0000000001" {
+		t.Fatalf("Redirect() = %s", redirect.Url)
+	}
+}
+
+func TestRequestMisconfigured(t *testing.T) {
+	gateway, calls := stub(t, nil, http.StatusOK)
+
+	_, err := gateway.Request(context.Background(), &payment_entity.GatewayEntity{}, payment_gateway_inter.PaymentRequest{Amount: 10000})
+	if !errors.Is(err, payment_gateway_inter.ErrMisconfigured) || len(*calls) != 0 {
+		t.Fatalf("Request() error = %v after %v", err, *calls)
+	}
+}
+
+func TestCallErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		answer      string
+		notVerified bool
+	}{
+		{name: "errors object", status: http.StatusUnprocessableEntity, answer: `{"data":[],"errors":{"code":-9,"message":"The input params invalid, validation error.","validations":[]}}`, notVerified: true},
+		{name: "neither data nor errors", status: http.StatusOK, answer: `{"data":[],"errors":[]}`},
+		{name: "not json", status: http.StatusBadGateway, answer: `<html>bad gateway</html>`},
+		{name: "unexpected code", status: http.StatusOK, answer: `{"data":{"code":102,"message":"merchant not found"},"errors":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, _ := stub(t, map[string]string{"/live/request.json": tt.answer}, tt.status)
+
+			_, err := gateway.Request(context.Background(), account, payment_gateway_inter.PaymentRequest{Amount: 10000})
+			if err == nil {
+				t.Fatal("Request() succeeded")
+			}
+			if errors.Is(err, payment_gateway_inter.ErrNotVerified) != tt.notVerified {
+				t.Fatalf("Request() error = %v, want ErrNotVerified %v", err, tt.notVerified)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	callback := map[string]string{"Authority": "A0000000001", "Status": "OK"}
+	tests := []struct {
+		name     string
+		callback map[string]string
+		answer   string
+		status   int
+		err      error
+		refId    string
+		calls    int
+	}{
+		{name: "paid", callback: callback, answer: `{"data":{"code":100,"ref_id":201,"card_pan":"502229******5995"},"errors":[]}`, status: http.StatusOK, refId: "201", calls: 1},
+		{name: "verified before", callback: callback, answer: `{"data":{"code":101,"ref_id":201},"errors":[]}`, status: http.StatusOK, refId: "201", calls: 1},
+		{name: "cancelled by the customer", callback: map[string]string{"Authority": "A0000000001", "Status": "NOK"}, err: payment_gateway_inter.ErrNotVerified},
+		{name: "callback of another authority", callback: map[string]string{"Authority": "A0000000002", "Status": "OK"}, err: payment_gateway_inter.ErrNotVerified},
+		{name: "not paid", callback: callback, answer: `{"data":[],"errors":{"code":-51,"message":"Session is not valid, session is not active paid try."}}`, status: http.StatusUnprocessableEntity, err: payment_gateway_inter.ErrNotVerified, calls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway, calls := stub(t, map[string]string{"/live/verify.json": tt.answer}, tt.status)
+
+			result, err := gateway.Verify(context.Background(), account, payment_gateway_inter.VerifyRequest{Token: "A0000000001", Amount: 10000, Callback: tt.callback})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && result.ReferenceId != tt.refId {
+				t.Fatalf("Verify() reference = %s, want %s", result.ReferenceId, tt.refId)
+			}
+			if len(*calls) != tt.calls {
+				t.Fatalf("calls = %v, want %d", *calls, tt.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package article_search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/interfaces/search_db/article_search_inter"
+	"site_builder_backend/pkg/elasticsearch"
+	"site_builder_backend/pkg/logger"
+)
+
+const _index = "blogs"
+
+const _mapping = `{
+  "settings": {
+    "number_of_shards": 1,
+    "analysis": {
+      "analyzer": {
+        "article_text": {
+          "type": "custom",
+          "tokenizer": "standard",
+          "char_filter": ["html_strip"],
+          "filter": ["lowercase", "decimal_digit", "arabic_normalization", "persian_normalization"]
+        }
+      }
+    }
+  },
+  "mappings": {
+    "properties": {
+      "site_id":      {"type": "long"},
+      "title":        {"type": "text", "analyzer": "article_text", "term_vector": "with_positions_offsets"},
+      "description":  {"type": "text", "analyzer": "article_text", "term_vector": "with_positions_offsets"},
+      "body":         {"type": "text", "analyzer": "article_text", "term_vector": "with_positions_offsets"},
+      "slug":         {"type": "keyword"},
+      "categories":   {"type": "text", "analyzer": "article_text", "fields": {"raw": {"type": "keyword"}}},
+      "badges":       {"type": "text", "analyzer": "article_text", "fields": {"raw": {"type": "keyword"}}},
+      "status":       {"type": "keyword"},
+      "published_at": {"type": "date"}
+    }
+  }
+}`
+
+type document struct {
+	SiteId      int64     `json:"site_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Body        string    `json:"body"`
+	Slug        string    `json:"slug"`
+	Categories  []string  `json:"categories"`
+	Badges      []string  `json:"badges"`
+	Status      string    `json:"status"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Id        string              `json:"_id"`
+			Score     float64             `json:"_score"`
+			Source    document            `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// ArticleSearch implements article_search_inter.ArticleSearch on the "blogs" Elasticsearch index
+type ArticleSearch struct {
+	es *elasticsearch.Elasticsearch
+	l  *logger.ZapLogger
+}
+
+func NewArticleSearch(es *elasticsearch.Elasticsearch, l *logger.ZapLogger) *ArticleSearch {
+	return &ArticleSearch{
+		es: es,
+		l:  l,
+	}
+}
+
+func (s *ArticleSearch) EnsureIndex(ctx context.Context) error {
+	if !s.es.IsIndexEnabled(_index) {
+		return article_search_inter.ErrSearchDisabled
+	}
+	return s.es.CreateIndexIfNotExists(ctx, _index, _mapping)
+}
+
+func (s *ArticleSearch) Index(ctx context.Context, d article_search_inter.ArticleDocument) error {
+	if !s.es.IsIndexEnabled(_index) {
+		return nil
+	}
+
+	body, err := json.Marshal(document{
+		SiteId:      d.SiteId,
+		Title:       d.Title,
+		Description: d.Description,
+		Body:        plainText(d.Body),
+		Slug:        d.Slug,
+		Categories:  d.Categories,
+		Badges:      d.Badges,
+		Status:      d.Status,
+		PublishedAt: d.PublishedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("article_search - Index - marshal: %w", err)
+	}
+
+	req := esapi.IndexRequest{
+		Index:      _index,
+		DocumentID: d.Id,
+		Body:       bytes.NewReader(body),
+	}
+	res, err := req.Do(ctx, s.es.GetClient())
+	if err != nil {
+		return fmt.Errorf("article_search - Index: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("article_search - Index: %s", res.String())
+	}
+	return nil
+}
+
+func (s *ArticleSearch) Delete(ctx context.Context, id string) error {
+	if !s.es.IsIndexEnabled(_index) {
+		return nil
+	}
+
+	req := esapi.DeleteRequest{
+		Index:      _index,
+		DocumentID: id,
+	}
+	res, err := req.Do(ctx, s.es.GetClient())
+	if err != nil {
+		return fmt.Errorf("article_search - Delete: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("article_search - Delete: %s", res.String())
+	}
+	return nil
+}
+
+func (s *ArticleSearch) Search(ctx context.Context, q article_search_inter.ArticleSearchQuery, now time.Time) (*article_search_inter.ArticleSearchResult, error) {
+	query := map[string]interface{}{
+		"from": q.Offset,
+		"size": q.Limit,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query":     q.Query,
+						"fields":    []string{"title^3", "categories^2", "badges^2", "description^1.5", "body"},
+						"type":      "best_fields",
+						"fuzziness": "AUTO",
+					},
+				},
+				"filter": visibilityFilter(q.SiteId, now),
+			},
+		},
+		// The html encoder escapes the fragment text before the tags are put around the matches
+		"highlight": map[string]interface{}{
+			"encoder":   "html",
+			"pre_tags":  []string{"<mark>"},
+			"post_tags": []string{"</mark>"},
+			"fields": map[string]interface{}{
+				"title":       map[string]interface{}{"number_of_fragments": 0},
+				"description": map[string]interface{}{"fragment_size": 150, "number_of_fragments": 1},
+				"body":        map[string]interface{}{"fragment_size": 150, "number_of_fragments": 3},
+			},
+		},
+	}
+
+	response, err := s.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &article_search_inter.ArticleSearchResult{
+		Hits:  toHits(response),
+		Total: response.Hits.Total.Value,
+	}, nil
+}
+
+func (s *ArticleSearch) MoreLikeThis(ctx context.Context, siteId int64, articleId string, limit int, now time.Time) ([]article_search_inter.ArticleSearchHit, error) {
+	query := map[string]interface{}{
+		"size":    limit,
+		"_source": []string{"title", "description", "slug", "categories", "published_at"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"more_like_this": map[string]interface{}{
+						"fields":          []string{"title", "body", "categories", "badges"},
+						"like":            []map[string]string{{"_index": _index, "_id": articleId}},
+						"min_term_freq":   1,
+						"min_doc_freq":    1,
+						"max_query_terms": 25,
+					},
+				},
+				"filter": visibilityFilter(siteId, now),
+			},
+		},
+	}
+
+	response, err := s.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return toHits(response), nil
+}
+
+func (s *ArticleSearch) search(ctx context.Context, query map[string]interface{}) (*searchResponse, error) {
+	if !s.es.IsIndexEnabled(_index) {
+		return nil, article_search_inter.ErrSearchDisabled
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("article_search - search - marshal: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{_index},
+		Body:  bytes.NewReader(body),
+	}
+	res, err := req.Do(ctx, s.es.GetClient())
+	if err != nil {
+		return nil, fmt.Errorf("article_search - search: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("article_search - search: %s", res.String())
+	}
+
+	var response searchResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("article_search - search - decode: %w", err)
+	}
+	return &response, nil
+}
+
+func visibilityFilter(siteId int64, now time.Time) []map[string]interface{} {
+	return []map[string]interface{}{
+		{"term": map[string]interface{}{"site_id": siteId}},
+		{"terms": map[string]interface{}{"status": []string{blog_entity.ArticleStatusPublished, blog_entity.ArticleStatusScheduled}}},
+		{"range": map[string]interface{}{"published_at": map[string]interface{}{"lte": now.Format(time.RFC3339)}}},
+	}
+}
+
+func toHits(response *searchResponse) []article_search_inter.ArticleSearchHit {
+	hits := make([]article_search_inter.ArticleSearchHit, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		hits = append(hits, article_search_inter.ArticleSearchHit{
+			Id:          hit.Id,
+			Title:       hit.Source.Title,
+			Description: hit.Source.Description,
+			Slug:        hit.Source.Slug,
+			Categories:  hit.Source.Categories,
+			PublishedAt: hit.Source.PublishedAt,
+			Score:       hit.Score,
+			Highlights:  hit.Highlight,
+		})
+	}
+	return hits
+}
+
+// Ensure ArticleSearch implements the ArticleSearch interface
+var _ article_search_inter.ArticleSearch = (*ArticleSearch)(nil)
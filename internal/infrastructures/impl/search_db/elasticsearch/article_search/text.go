@@ -0,0 +1,45 @@
+package article_search
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// _inlineTags do not break words, every other tag separates the text around it
+var _inlineTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "code": true, "em": true, "i": true, "mark": true,
+	"s": true, "small": true, "span": true, "strong": true, "sub": true, "sup": true, "u": true,
+}
+
+// plainText returns the visible text of an article body. Blocks are separated by a space so words
+// of adjacent paragraphs are not glued together, scripts and styles are dropped.
+func plainText(body string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	skip := 0
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if tag == "script" || tag == "style" {
+				if tokenType == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			}
+			if !_inlineTags[tag] {
+				b.WriteByte(' ')
+			}
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(tokenizer.Text())
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package article_search
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	cases := map[string]string{
+		"":                                "",
+		"plain words":                     "plain words",
+		"<p>first</p><p>second</p>":       "first second",
+		"<p>bold <b>word</b>s</p>":        "bold words",
+		"a &lt;script&gt; tag &amp; more": "a <script> tag & more",
+		"<style>p{color:red}</style><p>shown</p>":  "shown",
+		`<img src="x" onerror="alert(1)">caption`:  "caption",
+		"<script>alert('x')</script>after<br/>end": "after end",
+	}
+	for body, want := range cases {
+		if got := plainText(body); got != want {
+			t.Errorf("plainText(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package post_courier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// Provider prices shipments with the rate service of the post at a configured url. The shipment is posted
+// as JSON and answered with its price in rials. A 404 or 422 answer means the post does not deliver to
+// the destination, other failures leave the courier out of the options for now.
+type Provider struct {
+	client *http.Client
+	url    string
+	apiKey string
+	l      *logger.ZapLogger
+}
+
+// NewProvider sends the API key in the X-Api-Key header when it is set
+func NewProvider(client *http.Client, url string, apiKey string, l *logger.ZapLogger) *Provider {
+	return &Provider{
+		client: client,
+		url:    url,
+		apiKey: apiKey,
+		l:      l,
+	}
+}
+
+type requestBody struct {
+	ProvinceId int64  `json:"province_id"`
+	CityId     int64  `json:"city_id"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Weight     int    `json:"weight"`
+	Value      int64  `json:"value"`
+}
+
+type responseBody struct {
+	Price         int64 `json:"price"`
+	EstimatedDays int   `json:"estimated_days"`
+}
+
+func (p *Provider) Quote(ctx context.Context, method *order_entity.ShippingMethodEntity, shipment courier_inter.Shipment) (*courier_inter.Quote, error) {
+	payload, err := json.Marshal(requestBody{
+		ProvinceId: shipment.ProvinceId,
+		CityId:     shipment.CityId,
+		PostalCode: shipment.PostalCode,
+		Weight:     shipment.Weight,
+		Value:      shipment.Value,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("X-Api-Key", p.apiKey)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		p.l.Error("post_courier - Provider - Quote: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+	case res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusUnprocessableEntity:
+		return nil, courier_inter.ErrUnavailable
+	default:
+		return nil, fmt.Errorf("post_courier - Quote: status %d: %s", res.StatusCode, body)
+	}
+
+	var answer responseBody
+	if err := json.Unmarshal(body, &answer); err != nil {
+		return nil, fmt.Errorf("post_courier - Quote: %w", err)
+	}
+	if answer.Price < 0 {
+		return nil, fmt.Errorf("post_courier - Quote: negative price %d", answer.Price)
+	}
+	// The method's own estimate stands in for carriers that give none
+	days := answer.EstimatedDays
+	if days == 0 {
+		days = method.EstimatedDays
+	}
+	return &courier_inter.Quote{Price: answer.Price, EstimatedDays: days}, nil
+}
+
+var _ courier_inter.CourierProvider = (*Provider)(nil)
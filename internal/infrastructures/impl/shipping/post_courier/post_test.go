@@ -0,0 +1,74 @@
+package post_courier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+func TestProviderQuote(t *testing.T) {
+	method := &order_entity.ShippingMethodEntity{EstimatedDays: 5}
+	shipment := courier_inter.Shipment{ProvinceId: 8, CityId: 301, PostalCode: "1234567890", Weight: 1500, Value: 2000000}
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		price  int64
+		days   int
+		err    error
+	}{
+		{name: "priced", status: http.StatusOK, body: `{"price":185000,"estimated_days":2}`, price: 185000, days: 2},
+		{name: "estimate of the method", status: http.StatusOK, body: `{"price":185000}`, price: 185000, days: 5},
+		{name: "not delivered", status: http.StatusUnprocessableEntity, body: `{"error":"no service"}`, err: courier_inter.ErrUnavailable},
+		{name: "unknown destination", status: http.StatusNotFound, err: courier_inter.ErrUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("X-Api-Key") != "key" {
+					t.Errorf("request %s with key %q", r.Method, r.Header.Get("X-Api-Key"))
+				}
+				var got requestBody
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				want := requestBody{ProvinceId: 8, CityId: 301, PostalCode: "1234567890", Weight: 1500, Value: 2000000}
+				if got != want {
+					t.Errorf("request body = %+v, want %+v", got, want)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := NewProvider(server.Client(), server.URL, "key", logger.NewLoggerFromConfig("error", "json", "stdout"))
+			quote, err := provider.Quote(context.Background(), method, shipment)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Quote() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && (quote.Price != tt.price || quote.EstimatedDays != tt.days) {
+				t.Fatalf("Quote() = %+v, want price %d in %d days", quote, tt.price, tt.days)
+			}
+		})
+	}
+}
+
+func TestProviderQuoteFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	provider := NewProvider(server.Client(), server.URL, "", logger.NewLoggerFromConfig("error", "json", "stdout"))
+	_, err := provider.Quote(context.Background(), &order_entity.ShippingMethodEntity{}, courier_inter.Shipment{})
+	if err == nil || errors.Is(err, courier_inter.ErrUnavailable) {
+		t.Fatalf("Quote() error = %v, want a failure other than ErrUnavailable", err)
+	}
+}
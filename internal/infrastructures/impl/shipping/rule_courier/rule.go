@@ -0,0 +1,94 @@
+package rule_courier
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+)
+
+// FlatProvider charges the base price of the method whatever is shipped
+type FlatProvider struct{}
+
+// WeightProvider charges the rate of the lightest weight tier the shipment fits in
+type WeightProvider struct{}
+
+// ProvinceProvider charges the weight tier among the rates of the destination province, falling back
+// to the rates without a province and then to the base price of the method.
+type ProvinceProvider struct{}
+
+func NewFlatProvider() *FlatProvider {
+	return &FlatProvider{}
+}
+
+func NewWeightProvider() *WeightProvider {
+	return &WeightProvider{}
+}
+
+func NewProvinceProvider() *ProvinceProvider {
+	return &ProvinceProvider{}
+}
+
+func (p *FlatProvider) Quote(ctx context.Context, method *order_entity.ShippingMethodEntity, shipment courier_inter.Shipment) (*courier_inter.Quote, error) {
+	return &courier_inter.Quote{Price: method.BasePrice, EstimatedDays: method.EstimatedDays}, nil
+}
+
+func (p *WeightProvider) Quote(ctx context.Context, method *order_entity.ShippingMethodEntity, shipment courier_inter.Shipment) (*courier_inter.Quote, error) {
+	price, ok := tierPrice(ratesFor(method.Rates, ""), shipment.Weight)
+	if !ok {
+		return nil, courier_inter.ErrUnavailable
+	}
+	return &courier_inter.Quote{Price: price, EstimatedDays: method.EstimatedDays}, nil
+}
+
+func (p *ProvinceProvider) Quote(ctx context.Context, method *order_entity.ShippingMethodEntity, shipment courier_inter.Shipment) (*courier_inter.Quote, error) {
+	province := strconv.FormatInt(shipment.ProvinceId, 10)
+	if rates := ratesFor(method.Rates, province); len(rates) > 0 {
+		if price, ok := tierPrice(rates, shipment.Weight); ok {
+			return &courier_inter.Quote{Price: price, EstimatedDays: method.EstimatedDays}, nil
+		}
+		return nil, courier_inter.ErrUnavailable
+	}
+	if price, ok := tierPrice(ratesFor(method.Rates, ""), shipment.Weight); ok {
+		return &courier_inter.Quote{Price: price, EstimatedDays: method.EstimatedDays}, nil
+	}
+	if method.BasePrice > 0 {
+		return &courier_inter.Quote{Price: method.BasePrice, EstimatedDays: method.EstimatedDays}, nil
+	}
+	return nil, courier_inter.ErrUnavailable
+}
+
+func ratesFor(rates []order_entity.ShippingRateEntity, provinceId string) []order_entity.ShippingRateEntity {
+	matched := make([]order_entity.ShippingRateEntity, 0, len(rates))
+	for _, rate := range rates {
+		if rate.ProvinceId == provinceId {
+			matched = append(matched, rate)
+		}
+	}
+	return matched
+}
+
+// tierPrice picks the rate with the smallest MaxWeight not below the weight, a zero MaxWeight being the
+// open ended last tier
+func tierPrice(rates []order_entity.ShippingRateEntity, weight int) (int64, bool) {
+	sort.SliceStable(rates, func(i, j int) bool {
+		if rates[i].MaxWeight == 0 || rates[j].MaxWeight == 0 {
+			return rates[j].MaxWeight == 0 && rates[i].MaxWeight != 0
+		}
+		return rates[i].MaxWeight < rates[j].MaxWeight
+	})
+	for _, rate := range rates {
+		if rate.MaxWeight == 0 || weight <= rate.MaxWeight {
+			return rate.Price, true
+		}
+	}
+	return 0, false
+}
+
+var (
+	_ courier_inter.CourierProvider = (*FlatProvider)(nil)
+	_ courier_inter.CourierProvider = (*WeightProvider)(nil)
+	_ courier_inter.CourierProvider = (*ProvinceProvider)(nil)
+)
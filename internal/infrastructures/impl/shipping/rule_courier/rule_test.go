@@ -0,0 +1,111 @@
+package rule_courier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+)
+
+func TestTierPrice(t *testing.T) {
+	tests := []struct {
+		name   string
+		rates  []order_entity.ShippingRateEntity
+		weight int
+		price  int64
+		ok     bool
+	}{
+		{
+			name:   "no rates",
+			weight: 100,
+		},
+		{
+			name:   "lightest tier that fits",
+			rates:  []order_entity.ShippingRateEntity{{MaxWeight: 5000, Price: 300}, {MaxWeight: 1000, Price: 100}, {MaxWeight: 2000, Price: 200}},
+			weight: 1500,
+			price:  200,
+			ok:     true,
+		},
+		{
+			name:   "weight on a tier bound",
+			rates:  []order_entity.ShippingRateEntity{{MaxWeight: 2000, Price: 200}, {MaxWeight: 1000, Price: 100}},
+			weight: 1000,
+			price:  100,
+			ok:     true,
+		},
+		{
+			name:   "heavier than every tier",
+			rates:  []order_entity.ShippingRateEntity{{MaxWeight: 1000, Price: 100}, {MaxWeight: 2000, Price: 200}},
+			weight: 2001,
+		},
+		{
+			name:   "open ended tier listed first is tried last",
+			rates:  []order_entity.ShippingRateEntity{{MaxWeight: 0, Price: 900}, {MaxWeight: 2000, Price: 200}, {MaxWeight: 1000, Price: 100}},
+			weight: 500,
+			price:  100,
+			ok:     true,
+		},
+		{
+			name:   "open ended tier takes what the others do not",
+			rates:  []order_entity.ShippingRateEntity{{MaxWeight: 1000, Price: 100}, {MaxWeight: 0, Price: 900}, {MaxWeight: 2000, Price: 200}},
+			weight: 30000,
+			price:  900,
+			ok:     true,
+		},
+		{
+			name:   "only an open ended tier",
+			rates:  []order_entity.ShippingRateEntity{{MaxWeight: 0, Price: 700}},
+			weight: 0,
+			price:  700,
+			ok:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := tierPrice(tt.rates, tt.weight)
+			if price != tt.price || ok != tt.ok {
+				t.Fatalf("tierPrice() = %d, %v, want %d, %v", price, ok, tt.price, tt.ok)
+			}
+		})
+	}
+}
+
+func TestProvinceProviderQuote(t *testing.T) {
+	method := &order_entity.ShippingMethodEntity{
+		BasePrice:     50,
+		EstimatedDays: 3,
+		Rates: []order_entity.ShippingRateEntity{
+			{ProvinceId: "8", MaxWeight: 1000, Price: 100},
+			{MaxWeight: 0, Price: 400},
+		},
+	}
+	tests := []struct {
+		name     string
+		shipment courier_inter.Shipment
+		price    int64
+		err      error
+	}{
+		{name: "province rate", shipment: courier_inter.Shipment{ProvinceId: 8, Weight: 800}, price: 100},
+		{name: "too heavy for the province rates", shipment: courier_inter.Shipment{ProvinceId: 8, Weight: 1200}, err: courier_inter.ErrUnavailable},
+		{name: "rates without a province", shipment: courier_inter.Shipment{ProvinceId: 1, Weight: 1200}, price: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := NewProvinceProvider().Quote(context.Background(), method, tt.shipment)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Quote() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && (quote.Price != tt.price || quote.EstimatedDays != 3) {
+				t.Fatalf("Quote() = %+v, want price %d", quote, tt.price)
+			}
+		})
+	}
+
+	base := &order_entity.ShippingMethodEntity{BasePrice: 50}
+	quote, err := NewProvinceProvider().Quote(context.Background(), base, courier_inter.Shipment{ProvinceId: 1})
+	if err != nil || quote.Price != 50 {
+		t.Fatalf("Quote() without rates = %+v, %v, want the base price", quote, err)
+	}
+}
@@ -0,0 +1,259 @@
+package local_storage
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+// uploadsDir keeps the parts of multipart uploads under the root, bucket names cannot start with a dot
+const uploadsDir = ".uploads"
+
+// Storage keeps objects as files under root/bucket/key, for development and tests without an object
+// store
+type Storage struct {
+	root string
+	l    *logger.ZapLogger
+}
+
+func NewStorage(root string, l *logger.ZapLogger) *Storage {
+	return &Storage{
+		root: root,
+		l:    l,
+	}
+}
+
+func (s *Storage) EnsureBucket(ctx context.Context, bucket string) error {
+	dir, err := s.path(bucket, "")
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0o755)
+}
+
+func (s *Storage) Put(ctx context.Context, bucket string, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if _, err := s.write(path, body, size); err != nil {
+		s.l.Error("local_storage - Storage - Put: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) Get(ctx context.Context, bucket string, key string) (*object_storage_inter.Object, error) {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("local_storage - Storage - Get: %v", err)
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &object_storage_inter.Object{
+		Body:        file,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+	}, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, bucket string, key string) error {
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.l.Error("local_storage - Storage - Delete: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) Copy(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	src, err := s.path(bucket, srcKey)
+	if err != nil {
+		return err
+	}
+	dst, err := s.path(bucket, dstKey)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		return object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("local_storage - Storage - Copy: %v", err)
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := s.write(dst, file, info.Size()); err != nil {
+		s.l.Error("local_storage - Storage - Copy: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) CreateMultipart(ctx context.Context, bucket string, key string, contentType string) (string, error) {
+	if _, err := s.path(bucket, key); err != nil {
+		return "", err
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	uploadId := hex.EncodeToString(buf)
+	if err := os.MkdirAll(filepath.Join(s.root, uploadsDir, uploadId), 0o755); err != nil {
+		s.l.Error("local_storage - Storage - CreateMultipart: %v", err)
+		return "", err
+	}
+	return uploadId, nil
+}
+
+func (s *Storage) UploadPart(ctx context.Context, bucket string, key string, uploadId string, number int, body io.Reader, size int64) (string, error) {
+	dir, err := s.uploadDir(uploadId)
+	if err != nil {
+		return "", err
+	}
+	etag, err := s.write(filepath.Join(dir, strconv.Itoa(number)), body, size)
+	if err != nil {
+		s.l.Error("local_storage - Storage - UploadPart: %v", err)
+		return "", err
+	}
+	return etag, nil
+}
+
+func (s *Storage) CompleteMultipart(ctx context.Context, bucket string, key string, uploadId string, parts []object_storage_inter.Part) error {
+	dir, err := s.uploadDir(uploadId)
+	if err != nil {
+		return err
+	}
+	path, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	readers := make([]io.Reader, 0, len(parts))
+	var size int64
+	for i, part := range parts {
+		if part.Number != i+1 {
+			return fmt.Errorf("local_storage - CompleteMultipart: parts must be numbered 1 to %d in order", len(parts))
+		}
+		file, err := os.Open(filepath.Join(dir, strconv.Itoa(part.Number)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("local_storage - CompleteMultipart: part %d was not uploaded", part.Number)
+		}
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		readers = append(readers, file)
+	}
+	if _, err := s.write(path, io.MultiReader(readers...), size); err != nil {
+		s.l.Error("local_storage - Storage - CompleteMultipart: %v", err)
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+func (s *Storage) AbortMultipart(ctx context.Context, bucket string, key string, uploadId string) error {
+	dir, err := s.uploadDir(uploadId)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// write stores exactly size bytes of body at path through a temporary file, so readers never see a
+// partial file, and returns their MD5 as ETag
+func (s *Storage) write(path string, body io.Reader, size int64) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := md5.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(body, size))
+	if err != nil {
+		return "", err
+	}
+	if n != size {
+		return "", fmt.Errorf("local_storage - write: got %d of %d bytes", n, size)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`, nil
+}
+
+// path returns where the key of the bucket is kept, refusing names that would leave the bucket
+func (s *Storage) path(bucket string, key string) (string, error) {
+	if bucket == "" || strings.HasPrefix(bucket, ".") || strings.ContainsAny(bucket, `/\`) {
+		return "", fmt.Errorf("local_storage: invalid bucket name %q", bucket)
+	}
+	dir := filepath.Join(s.root, bucket)
+	if key == "" {
+		return dir, nil
+	}
+	path := filepath.Join(dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("local_storage: invalid key %q", key)
+	}
+	return path, nil
+}
+
+func (s *Storage) uploadDir(uploadId string) (string, error) {
+	if _, err := hex.DecodeString(uploadId); err != nil || uploadId == "" {
+		return "", object_storage_inter.ErrObjectNotFound
+	}
+	dir := filepath.Join(s.root, uploadsDir, uploadId)
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return "", object_storage_inter.ErrObjectNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+var _ object_storage_inter.ObjectStorage = (*Storage)(nil)
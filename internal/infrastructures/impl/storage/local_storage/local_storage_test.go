@@ -0,0 +1,144 @@
+package local_storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+)
+
+func newTestStorage(t *testing.T) (*Storage, string) {
+	t.Helper()
+	root := t.TempDir()
+	return NewStorage(filepath.Join(root, "objects"), logger.NewLoggerFromConfig("error", "json", "stdout")), root
+}
+
+func TestPathRejectsKeysLeavingTheBucket(t *testing.T) {
+	storage, root := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"../escaped", "../../escaped", "a/../../escaped", "..", "a/.."} {
+		if err := storage.Put(ctx, "files", key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put key %q: want an error", key)
+		}
+	}
+	for _, bucket := range []string{"", "..", ".uploads", "a/b", `a\b`} {
+		if err := storage.Put(ctx, bucket, "key", strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put bucket %q: want an error", bucket)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "escaped")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("a file escaped the root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "objects", "escaped")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("a file escaped the bucket: %v", err)
+	}
+
+	if err := storage.Put(ctx, "files", "a/b.txt", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("Put a nested key: %v", err)
+	}
+	object, err := storage.Get(ctx, "files", "a/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Body.Close()
+	if body, _ := io.ReadAll(object.Body); string(body) != "hello" {
+		t.Fatalf("body = %q", body)
+	}
+}
+
+func TestMultipartUploadComplete(t *testing.T) {
+	storage, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	uploadId, err := storage.CreateMultipart(ctx, "files", "video.mp4", "video/mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Parts may arrive in any order, they are joined by number
+	chunks := []string{"first-", "second-", "third"}
+	parts := make([]object_storage_inter.Part, len(chunks))
+	for i := len(chunks) - 1; i >= 0; i-- {
+		etag, err := storage.UploadPart(ctx, "files", "video.mp4", uploadId, i+1, strings.NewReader(chunks[i]), int64(len(chunks[i])))
+		if err != nil {
+			t.Fatalf("UploadPart %d: %v", i+1, err)
+		}
+		parts[i] = object_storage_inter.Part{Number: i + 1, ETag: etag}
+	}
+	if _, err := storage.Get(ctx, "files", "video.mp4"); !errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		t.Fatalf("object visible before completion: %v", err)
+	}
+
+	if err := storage.CompleteMultipart(ctx, "files", "video.mp4", uploadId, parts); err != nil {
+		t.Fatalf("CompleteMultipart: %v", err)
+	}
+	object, err := storage.Get(ctx, "files", "video.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Body.Close()
+	body, _ := io.ReadAll(object.Body)
+	if string(body) != "first-second-third" || object.Size != int64(len(body)) {
+		t.Fatalf("body = %q, size %d", body, object.Size)
+	}
+
+	// The parts are gone with the upload
+	_, err = storage.UploadPart(ctx, "files", "video.mp4", uploadId, 4, strings.NewReader("x"), 1)
+	if !errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		t.Fatalf("UploadPart after completion: %v", err)
+	}
+}
+
+func TestMultipartCompleteRefusesMissingParts(t *testing.T) {
+	storage, _ := newTestStorage(t)
+	ctx := context.Background()
+
+	uploadId, err := storage.CreateMultipart(ctx, "files", "doc.pdf", "application/pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.UploadPart(ctx, "files", "doc.pdf", uploadId, 1, strings.NewReader("a"), 1); err != nil {
+		t.Fatal(err)
+	}
+	parts := []object_storage_inter.Part{{Number: 1}, {Number: 2}}
+	if err := storage.CompleteMultipart(ctx, "files", "doc.pdf", uploadId, parts); err == nil {
+		t.Fatal("CompleteMultipart with a missing part: want an error")
+	}
+	if _, err := storage.Get(ctx, "files", "doc.pdf"); !errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		t.Fatalf("object stored from a failed completion: %v", err)
+	}
+}
+
+func TestMultipartAbort(t *testing.T) {
+	storage, root := newTestStorage(t)
+	ctx := context.Background()
+
+	uploadId, err := storage.CreateMultipart(ctx, "files", "big.bin", "application/octet-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.UploadPart(ctx, "files", "big.bin", uploadId, 1, strings.NewReader("data"), 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.AbortMultipart(ctx, "files", "big.bin", uploadId); err != nil {
+		t.Fatalf("AbortMultipart: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "objects", uploadsDir, uploadId)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("parts kept after abort: %v", err)
+	}
+	err = storage.CompleteMultipart(ctx, "files", "big.bin", uploadId, []object_storage_inter.Part{{Number: 1}})
+	if !errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		t.Fatalf("CompleteMultipart after abort: %v", err)
+	}
+
+	// Upload ids are checked before they become paths
+	if err := storage.AbortMultipart(ctx, "files", "big.bin", "../../files"); !errors.Is(err, object_storage_inter.ErrObjectNotFound) {
+		t.Fatalf("AbortMultipart with a path as id: %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package s3_storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
+	"site_builder_backend/pkg/logger"
+	"site_builder_backend/pkg/s3"
+)
+
+// Storage keeps objects in an S3-compatible store, MinIO in development
+type Storage struct {
+	client *s3.Client
+	l      *logger.ZapLogger
+}
+
+func NewStorage(client *s3.Client, l *logger.ZapLogger) *Storage {
+	return &Storage{
+		client: client,
+		l:      l,
+	}
+}
+
+func (s *Storage) EnsureBucket(ctx context.Context, bucket string) error {
+	if err := s.client.EnsureBucket(ctx, bucket); err != nil {
+		s.l.Error("s3_storage - Storage - EnsureBucket: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) Put(ctx context.Context, bucket string, key string, body io.Reader, size int64, contentType string) error {
+	if _, err := s.client.PutObject(ctx, bucket, key, body, size, contentType); err != nil {
+		s.l.Error("s3_storage - Storage - Put: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) Get(ctx context.Context, bucket string, key string) (*object_storage_inter.Object, error) {
+	object, err := s.client.GetObject(ctx, bucket, key)
+	if errors.Is(err, s3.ErrNotFound) {
+		return nil, object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("s3_storage - Storage - Get: %v", err)
+		return nil, err
+	}
+	return &object_storage_inter.Object{Body: object.Body, Size: object.Size, ContentType: object.ContentType}, nil
+}
+
+func (s *Storage) Delete(ctx context.Context, bucket string, key string) error {
+	err := s.client.DeleteObject(ctx, bucket, key)
+	if err != nil && !errors.Is(err, s3.ErrNotFound) {
+		s.l.Error("s3_storage - Storage - Delete: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) Copy(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	err := s.client.CopyObject(ctx, bucket, srcKey, dstKey)
+	if errors.Is(err, s3.ErrNotFound) {
+		return object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("s3_storage - Storage - Copy: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) CreateMultipart(ctx context.Context, bucket string, key string, contentType string) (string, error) {
+	uploadId, err := s.client.CreateMultipartUpload(ctx, bucket, key, contentType)
+	if err != nil {
+		s.l.Error("s3_storage - Storage - CreateMultipart: %v", err)
+		return "", err
+	}
+	return uploadId, nil
+}
+
+func (s *Storage) UploadPart(ctx context.Context, bucket string, key string, uploadId string, number int, body io.Reader, size int64) (string, error) {
+	etag, err := s.client.UploadPart(ctx, bucket, key, uploadId, number, body, size)
+	if errors.Is(err, s3.ErrNotFound) {
+		return "", object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("s3_storage - Storage - UploadPart: %v", err)
+		return "", err
+	}
+	if etag == "" {
+		return "", fmt.Errorf("s3_storage - UploadPart: part %d of upload %s has no etag", number, uploadId)
+	}
+	return etag, nil
+}
+
+func (s *Storage) CompleteMultipart(ctx context.Context, bucket string, key string, uploadId string, parts []object_storage_inter.Part) error {
+	s3Parts := make([]s3.Part, 0, len(parts))
+	for _, part := range parts {
+		s3Parts = append(s3Parts, s3.Part{PartNumber: part.Number, ETag: part.ETag})
+	}
+	err := s.client.CompleteMultipartUpload(ctx, bucket, key, uploadId, s3Parts)
+	if errors.Is(err, s3.ErrNotFound) {
+		return object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("s3_storage - Storage - CompleteMultipart: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Storage) AbortMultipart(ctx context.Context, bucket string, key string, uploadId string) error {
+	err := s.client.AbortMultipartUpload(ctx, bucket, key, uploadId)
+	if errors.Is(err, s3.ErrNotFound) {
+		return object_storage_inter.ErrObjectNotFound
+	}
+	if err != nil {
+		s.l.Error("s3_storage - Storage - AbortMultipart: %v", err)
+		return err
+	}
+	return nil
+}
+
+var _ object_storage_inter.ObjectStorage = (*Storage)(nil)
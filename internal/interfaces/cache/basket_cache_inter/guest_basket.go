@@ -0,0 +1,27 @@
+package basket_cache_inter
+
+import "context"
+
+// GuestBasket is the basket of a visitor who is not signed in. Only variants and quantities are kept,
+// prices are always recalculated when the basket is read.
+type GuestBasket struct {
+	SiteId     int64             `json:"site_id"`
+	DiscountId int64             `json:"discount_id,omitempty"`
+	Items      []GuestBasketItem `json:"items"`
+}
+
+type GuestBasketItem struct {
+	ProductVariantId int64 `json:"product_variant_id"`
+	Quantity         int   `json:"quantity"`
+}
+
+// GuestBasketStore keeps guest baskets by the token handed to the storefront
+type GuestBasketStore interface {
+	// Get returns nil when the token is unknown or the basket has expired
+	Get(ctx context.Context, token string) (*GuestBasket, error)
+
+	// Save stores the basket and restarts its expiry
+	Save(ctx context.Context, token string, basket *GuestBasket) error
+
+	Delete(ctx context.Context, token string) error
+}
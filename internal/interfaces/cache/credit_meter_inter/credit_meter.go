@@ -0,0 +1,55 @@
+package credit_meter_inter
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotLoaded is returned when the counter of the credit is not cached, the caller loads it and retries
+	ErrNotLoaded = errors.New("credit counter not loaded")
+	// ErrReservationNotFound is returned for reservations committed, released or timed out already
+	ErrReservationNotFound = errors.New("credit reservation not found")
+)
+
+// Reservation holds credits of a user aside for an action until it is committed or released
+type Reservation struct {
+	Id         string
+	UserId     int64
+	CreditType string
+	Amount     int64
+	Action     string
+}
+
+// CreditMeter keeps what users can spend of their credits in counters outside the database.
+// Counters are loaded from the database and dropped whenever the credits change there. Reservations stay
+// pending until they are settled, released or time out, and a counter loaded again leaves out what
+// pending reservations hold, so dropping a counter never frees reserved credits.
+type CreditMeter interface {
+	// Load sets the counter of the credit to available less the pending reservations, unless it is
+	// cached already
+	Load(ctx context.Context, userId int64, creditType string, available int64, ttl time.Duration) error
+
+	// Reserve takes the amount of the reservation from the counter. It reports false when less is available.
+	Reserve(ctx context.Context, reservation Reservation, ttl time.Duration) (bool, error)
+
+	// Take removes the reservation without giving its credits back, for committing it. It stays pending
+	// until it is settled.
+	Take(ctx context.Context, id string) (*Reservation, error)
+
+	// Restore puts a taken reservation back, when committing it failed
+	Restore(ctx context.Context, reservation Reservation, ttl time.Duration) error
+
+	// Settle drops a taken reservation from the pending ones, once the database recorded its usage
+	Settle(ctx context.Context, reservation Reservation) error
+
+	// Release removes the reservation and gives its credits back to the counter
+	Release(ctx context.Context, id string) (*Reservation, error)
+
+	// Credit adds the amount to the counter when it is cached
+	Credit(ctx context.Context, userId int64, creditType string, amount int64) error
+
+	// Invalidate drops the counters of the user, they are loaded again on the next reservation
+	Invalidate(ctx context.Context, userId int64, creditTypes []string) error
+}
@@ -0,0 +1,34 @@
+package notification_hub_inter
+
+import (
+	"context"
+
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+// Kinds of messages pushed to the dashboard
+const (
+	// KindNotification carries a new notification
+	KindNotification = "notification"
+	// KindRead tells other open dashboards of the user that notifications were read
+	KindRead = "read"
+)
+
+// Message is pushed to the open dashboards of a user. Unread is the number of unread notifications
+// after the change, so the badge needs no extra request.
+type Message struct {
+	Kind         string                          `json:"kind"`
+	Notification *user_entity.NotificationEntity `json:"notification,omitempty"`
+	Unread       int64                           `json:"unread"`
+}
+
+// NotificationHub fans messages out to the live connections of a user, on whichever replica of the
+// backend they are held.
+type NotificationHub interface {
+	Publish(ctx context.Context, userId int64, message Message) error
+	// Subscribe returns the messages of the user until cancel is called or the hub is closed, when the
+	// channel is closed. Messages for a subscriber that does not keep up are dropped.
+	Subscribe(userId int64) (messages <-chan Message, cancel func())
+	// Close ends every subscription, so open streams finish before the server shuts down
+	Close()
+}
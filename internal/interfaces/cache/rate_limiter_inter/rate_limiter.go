@@ -0,0 +1,12 @@
+package rate_limiter_inter
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter counts hits per key in fixed windows
+type RateLimiter interface {
+	// Allow records a hit for the key and reports whether it is within limit hits per window
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
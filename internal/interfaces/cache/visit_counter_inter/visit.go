@@ -0,0 +1,50 @@
+package visit_counter_inter
+
+import (
+	"context"
+	"time"
+)
+
+// Target is the kind of page whose visits are counted
+type Target string
+
+const (
+	TargetProduct Target = "product"
+	TargetArticle Target = "article"
+)
+
+// VisitKey identifies a counted page. SiteId travels with the id so the flusher can scope its updates.
+type VisitKey struct {
+	SiteId int64
+	Id     int64
+}
+
+type VisitScore struct {
+	Id     int64
+	Visits int64
+}
+
+// VisitCounter keeps page view counters outside the database.
+// A visitor is counted at most once per page per day.
+type VisitCounter interface {
+	// Track records a visit. It reports whether the visit was counted or deduplicated.
+	Track(ctx context.Context, target Target, key VisitKey, visitorId string, now time.Time) (bool, error)
+
+	// IsKnown reports whether the page was remembered to exist, so its visits are counted without a
+	// database lookup
+	IsKnown(ctx context.Context, target Target, key VisitKey) (bool, error)
+
+	// Remember keeps for ttl that the page exists. Missing pages are never remembered, so made up ids
+	// leave no keys behind.
+	Remember(ctx context.Context, target Target, key VisitKey, ttl time.Duration) error
+
+	// TakePending returns the counts accumulated since the last acknowledged flush.
+	// Until AckPending is called the same batch is returned again, so a failed flush is retried.
+	TakePending(ctx context.Context, target Target) (map[VisitKey]int64, error)
+
+	// AckPending drops the batch returned by TakePending
+	AckPending(ctx context.Context, target Target) error
+
+	// TopVisited returns the most visited pages of a site over the given number of days ending at now
+	TopVisited(ctx context.Context, target Target, siteId int64, days int, limit int, now time.Time) ([]VisitScore, error)
+}
@@ -0,0 +1,43 @@
+package blog_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/blog_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+)
+
+// ArticleFilter narrows article listings. Zero values are ignored.
+type ArticleFilter struct {
+	SiteId     int64
+	UserId     int64
+	CategoryId int64
+	Status     string
+	Offset     int
+	Limit      int
+}
+
+type ArticleReadRepository interface {
+	FindById(id int64) (*blog_entity.ArticleEntity, error)
+	FindBySlug(siteId int64, slug string) (*blog_entity.ArticleEntity, error)
+	ExistsBySlug(siteId int64, slug string, excludeId int64) (bool, error)
+	FindAll(filter ArticleFilter) ([]blog_entity.ArticleEntity, int64, error)
+	// FindVisible lists articles that are published, or scheduled with a publish time not after now.
+	FindVisible(filter ArticleFilter, now time.Time) ([]blog_entity.ArticleEntity, int64, error)
+	// FindRelated returns visible articles of the same site sharing the most categories with the given article.
+	FindRelated(article *blog_entity.ArticleEntity, limit int, now time.Time) ([]blog_entity.ArticleEntity, error)
+	// FindVisibleByIds returns the visible articles of the site among ids, in no particular order.
+	FindVisibleByIds(siteId int64, ids []int64, now time.Time) ([]blog_entity.ArticleEntity, error)
+}
+
+type ArticleWriteRepository interface {
+	// Create inserts the article and links it to the given categories of the same site. It returns
+	// ErrConflict when a live article of the site has the slug.
+	Create(entity *blog_entity.ArticleEntity, categoryIds []int64) error
+	// Update saves the editable columns and replaces the category links, ErrConflict when the slug is
+	// taken and ErrStale when the article's Version changed since it was read.
+	Update(entity *blog_entity.ArticleEntity, categoryIds []int64) error
+	Delete(id int64) error
+	// IncrementVisitedCounts adds the deltas to VisitedCount in a single transaction.
+	IncrementVisitedCounts(deltas []repositories.CounterDelta) error
+}
@@ -0,0 +1,31 @@
+package blog_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/blog_entity"
+)
+
+// ArticleCommentFilter narrows comment listings. Zero values are ignored.
+type ArticleCommentFilter struct {
+	SiteId    int64
+	ArticleId int64
+	Status    string
+	// RootsOnly skips replies
+	RootsOnly bool
+	Offset    int
+	Limit     int
+}
+
+type ArticleCommentReadRepository interface {
+	FindById(id int64) (*blog_entity.ArticleCommentEntity, error)
+	FindAll(filter ArticleCommentFilter) ([]blog_entity.ArticleCommentEntity, int64, error)
+	// FindReplies returns the comments with the given status in the threads of the root comments, oldest first.
+	FindReplies(rootIds []int64, status string) ([]blog_entity.ArticleCommentEntity, error)
+}
+
+// ArticleCommentWriteRepository keeps ReviewCount and Rate of the article in sync with its approved rated comments.
+// New comments are never approved, so only ChangeStatus and Delete recompute them.
+type ArticleCommentWriteRepository interface {
+	Create(entity *blog_entity.ArticleCommentEntity) error
+	ChangeStatus(id int64, status string) error
+	Delete(id int64) error
+}
@@ -0,0 +1,8 @@
+package repositories
+
+// CounterDelta is an amount to add to a counter column of the record Id belonging to SiteId
+type CounterDelta struct {
+	SiteId int64
+	Id     int64
+	Delta  int64
+}
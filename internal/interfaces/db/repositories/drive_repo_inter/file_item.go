@@ -0,0 +1,65 @@
+package drive_repo_inter
+
+import (
+	"errors"
+	"time"
+
+	"site_builder_backend/internal/domain/drive_entity"
+)
+
+// ErrCycle is returned for moves of a folder into itself or a folder below it
+var ErrCycle = errors.New("folder cannot be moved below itself")
+
+// FileItemFilter pages through the live items of a user's folder, ParentId zero is the root
+type FileItemFilter struct {
+	UserId   int64
+	ParentId int64
+	Offset   int
+	Limit    int
+}
+
+type FileItemReadRepository interface {
+	// FindById returns the item with its variants, whether it is deleted or not
+	FindById(id int64) (*drive_entity.FileItemEntity, error)
+	// NameTaken reports whether a live item of the user's folder parentId, zero for the root, has the name
+	NameTaken(userId int64, parentId int64, name string) (bool, error)
+	// FindChildren returns the live items of a folder, folders first, then by name
+	FindChildren(filter FileItemFilter) ([]drive_entity.FileItemEntity, int64, error)
+	// FindTrash returns the deleted items of the user that were not deleted together with their folder,
+	// latest deleted first
+	FindTrash(userId int64, offset int, limit int) ([]drive_entity.FileItemEntity, int64, error)
+	// FindTree returns the item and every item below it, deleted or not, folders before their items
+	FindTree(id int64) ([]drive_entity.FileItemEntity, error)
+	// IsWithin reports whether the item is the folder or below it
+	IsWithin(id int64, folderId int64) (bool, error)
+	// FindImages returns the live items of ids whose images are ready, with their variants
+	FindImages(ids []int64) ([]drive_entity.FileItemEntity, error)
+	// IsPublished reports whether sites may show the item: it is public, or its user put it in the media of
+	// one of their sites
+	IsPublished(id int64) (bool, error)
+	// FindPendingImages returns up to limit live items whose images wait for processing since before, and
+	// the private images that were published since
+	FindPendingImages(before time.Time, limit int) ([]drive_entity.FileItemEntity, error)
+}
+
+type FileItemWriteRepository interface {
+	// Create returns ErrConflict when a live item of the same folder has the name
+	Create(item *drive_entity.FileItemEntity) error
+	// CreateTree creates copied items, items[i] goes in the folder items[parents[i]] but the first one,
+	// whose ParentId is set already. It returns ErrConflict when the first name is taken.
+	CreateTree(items []*drive_entity.FileItemEntity, parents []int) error
+	// Move renames a live item into the folder parentId, zero for the root, and rewrites the paths below
+	// it. It returns ErrNotFound when either is gone, ErrCycle and ErrConflict.
+	Move(id int64, parentId int64, name string, path string) error
+	// Trash deletes a live item together with the live items below it, ErrNotFound when it is gone
+	Trash(id int64, at time.Time) error
+	// Restore brings a deleted item back into the folder parentId, with the items deleted together with
+	// it. It returns ErrNotFound when either is gone and ErrConflict.
+	Restore(id int64, parentId int64, name string, path string) error
+	// Purge removes a deleted item and every item below it for good and returns them with their variants,
+	// ErrNotFound when the item is gone or live
+	Purge(id int64) ([]drive_entity.FileItemEntity, error)
+	// SaveImage settles the image of a pending or private item with the status and replaces its variants.
+	// It returns ErrNotFound when the item is gone and ErrConflict when its image is settled already.
+	SaveImage(id int64, status string, variants []drive_entity.FileVariantEntity) error
+}
@@ -0,0 +1,26 @@
+package drive_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/drive_entity"
+)
+
+type UploadReadRepository interface {
+	// FindById returns the upload with its parts ordered by number
+	FindById(id int64) (*drive_entity.UploadEntity, error)
+	// FindExpired returns up to limit uploads still uploading after their ExpiresAt
+	FindExpired(now time.Time, limit int) ([]drive_entity.UploadEntity, error)
+}
+
+type UploadWriteRepository interface {
+	Create(upload *drive_entity.UploadEntity) error
+	// SavePart records a part, replacing the part of the same number sent before
+	SavePart(part *drive_entity.UploadPartEntity) error
+	// Complete creates the file item of an upload and marks the upload completed with it. It returns
+	// ErrNotFound when the upload is not uploading anymore and ErrConflict when a live item of the
+	// folder has the name of the file.
+	Complete(id int64, item *drive_entity.FileItemEntity) error
+	// End moves an upload still uploading to status, ErrNotFound when it is not uploading anymore
+	End(id int64, status string) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import "errors"
+
+// ErrNotFound is returned by repository implementations when the requested record does not exist.
+// Implementations translate their driver specific "not found" errors into this value so use cases
+// can branch on it without knowing about the persistence framework.
+var ErrNotFound = errors.New("record not found")
+
+// ErrExhausted is returned when a guarded decrement would take a quantity, such as stock or
+// remaining discount usages, below zero. Nothing is changed when it is returned.
+var ErrExhausted = errors.New("quantity exhausted")
+
+// ErrOutOfStock is returned when a variant does not have the stock an order asks for. Like
+// ErrExhausted it leaves everything untouched.
+var ErrOutOfStock = errors.New("out of stock")
+
+// ErrConflict is returned when a record with the same unique key exists already
+var ErrConflict = errors.New("record already exists")
+
+// ErrStale is returned when an update is guarded by the record's Version and the record changed
+// since it was read. Nothing is written when it is returned.
+var ErrStale = errors.New("record changed since it was read")
@@ -0,0 +1,14 @@
+package order_repo_inter
+
+import "site_builder_backend/internal/domain/order_entity"
+
+type BasketReadRepository interface {
+	// FindByCustomer returns the open basket of a customer on a site with its items
+	FindByCustomer(siteId int64, customerId int64) (*order_entity.BasketEntity, error)
+}
+
+type BasketWriteRepository interface {
+	// Save creates the basket when it has no id yet, otherwise updates its totals.
+	// Items are matched by id, new ones are created and the ones no longer present are soft deleted.
+	Save(basket *order_entity.BasketEntity) error
+}
@@ -0,0 +1,79 @@
+package order_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/order_entity"
+	"site_builder_backend/internal/domain/payment_entity"
+)
+
+// OrderFilter narrows order listings. Zero values are ignored.
+type OrderFilter struct {
+	SiteId     int64
+	CustomerId int64
+	Status     string
+	Offset     int
+	Limit      int
+}
+
+type OrderReadRepository interface {
+	// FindById returns the order with its items
+	FindById(id int64) (*order_entity.OrderEntity, error)
+	// FindAll returns orders without their items, newest first
+	FindAll(filter OrderFilter) ([]order_entity.OrderEntity, int64, error)
+	// FindHistory returns the status changes of an order, oldest first
+	FindHistory(orderId int64) ([]order_entity.OrderStatusHistoryEntity, error)
+	// FindItemById returns an order item with its order
+	FindItemById(id int64) (*order_entity.OrderItemEntity, error)
+
+	// HasPurchased reports whether the customer has a non cancelled, paid order containing the product.
+	HasPurchased(customerId int64, productId int64) (bool, error)
+
+	// FindByIdempotencyKey returns the order a customer placed with the key, with its items
+	FindByIdempotencyKey(siteId int64, customerId int64, key string) (*order_entity.OrderEntity, error)
+}
+
+// PlaceOrder is everything written when a basket becomes an order
+type PlaceOrder struct {
+	// Order with its items, ids are filled in on success
+	Order *order_entity.OrderEntity
+	// Payment waiting for the order, its OrderId and TrackingNumber are set from the new order
+	Payment *payment_entity.PaymentEntity
+	// DiscountId is the discount code to redeem, zero when none
+	DiscountId int64
+	// CouponUnits are the coupon usages to redeem by coupon id
+	CouponUnits map[int64]int
+	BasketId    int64
+	// History is the entry of the initial status, its OrderId is set from the new order
+	History *order_entity.OrderStatusHistoryEntity
+}
+
+// Reservation actions applied to the stock reserved for an order when its status changes
+const (
+	ReservationKeep    = ""
+	ReservationCommit  = "commit"
+	ReservationRelease = "release"
+)
+
+// StatusTransition moves an order from one status to another
+type StatusTransition struct {
+	OrderId int64
+	From    string
+	To      string
+	// TrackingCode replaces the tracking code of the order when set
+	TrackingCode string
+	// Reservations is one of the Reservation actions
+	Reservations string
+	History      *order_entity.OrderStatusHistoryEntity
+}
+
+type OrderWriteRepository interface {
+	// Place writes the order and its items, reserves their stock, redeems the offers, creates the pending
+	// payment, records the first history entry and closes the basket in one transaction. It returns
+	// repositories.ErrConflict when the idempotency key was used already, repositories.ErrOutOfStock
+	// when a variant lacks stock and repositories.ErrExhausted when an offer ran out.
+	Place(place PlaceOrder) error
+
+	// ChangeStatus applies the transition and records it in the history in one transaction. It returns
+	// repositories.ErrConflict when the order is no longer in the From status. Releasing gives the stock
+	// of reservations that are not released yet back to their variants.
+	ChangeStatus(transition StatusTransition) error
+}
@@ -0,0 +1,37 @@
+package order_repo_inter
+
+import "site_builder_backend/internal/domain/order_entity"
+
+// ReturnItemFilter narrows return listings. Zero values are ignored.
+type ReturnItemFilter struct {
+	SiteId     int64
+	CustomerId int64
+	Status     int
+	Offset     int
+	Limit      int
+}
+
+type ReturnItemReadRepository interface {
+	// FindById returns the return with its order item
+	FindById(id int64) (*order_entity.ReturnItemEntity, error)
+	// FindAll returns returns with their order items, newest first
+	FindAll(filter ReturnItemFilter) ([]order_entity.ReturnItemEntity, int64, error)
+	FindByOrderId(orderId int64) ([]order_entity.ReturnItemEntity, error)
+}
+
+// ReturnStatusChange moves a return from one state to another
+type ReturnStatusChange struct {
+	Id        int64
+	From      int
+	To        int
+	OwnerNote string
+	// Restock gives the committed stock of the returned item back to its variant through the reservation ledger
+	Restock bool
+}
+
+type ReturnItemWriteRepository interface {
+	// Create returns repositories.ErrConflict when the order item has a return already
+	Create(entity *order_entity.ReturnItemEntity) error
+	// ChangeStatus returns repositories.ErrConflict when the return is no longer in the From state
+	ChangeStatus(change ReturnStatusChange) error
+}
@@ -0,0 +1,21 @@
+package order_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/order_entity"
+)
+
+type ShippingMethodReadRepository interface {
+	// FindById returns the method with its rates loaded.
+	FindById(id int64) (*order_entity.ShippingMethodEntity, error)
+	// FindBySite returns the methods of the site with their rates, only the active ones when activeOnly is set.
+	FindBySite(siteId int64, activeOnly bool) ([]order_entity.ShippingMethodEntity, error)
+	ExistsByCode(siteId int64, code string, excludeId int64) (bool, error)
+}
+
+type ShippingMethodWriteRepository interface {
+	// Create inserts the method together with its rates.
+	Create(entity *order_entity.ShippingMethodEntity) error
+	// Update saves the method and replaces its rates.
+	Update(entity *order_entity.ShippingMethodEntity) error
+	Delete(id int64) error
+}
@@ -0,0 +1,68 @@
+package payment_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/payment_entity"
+)
+
+// PaymentStart records the gateway a payment was sent to. GatewayToken is what the gateway issued and
+// Attempt is stored along with the request transaction, the payment then points at it by TrackingNumber.
+type PaymentStart struct {
+	Id                 int64
+	TrackingNumber     int64
+	Attempt            *payment_entity.ParbadPaymentEntity
+	Gateway            string
+	GatewayAccountName string
+	GatewayToken       string
+	CallVerifyUrl      string
+	ClientIp           string
+}
+
+// PaymentCompletion settles a pending payment and its attempt. TransactionCode is the gateway's reference of
+// a successful one and Transaction is the verify transaction stored with it. A payment that was never sent
+// to a gateway is completed without AttemptId and Transaction.
+type PaymentCompletion struct {
+	Id                  int64
+	TrackingNumber      int64
+	AttemptId           int64
+	Transaction         *payment_entity.ParbadTransactionEntity
+	Status              string
+	TransactionCode     string
+	GatewayResponseCode string
+	Message             string
+}
+
+type PaymentReadRepository interface {
+	FindById(id int64) (*payment_entity.PaymentEntity, error)
+	// FindByOrderId returns the latest payment a service opened for one of its orders.
+	FindByOrderId(serviceName string, orderId int64) (*payment_entity.PaymentEntity, error)
+	// FindAttempt returns the attempt with the tracking number along with its transactions.
+	FindAttempt(trackingNumber int64) (*payment_entity.ParbadPaymentEntity, error)
+	TrackingNumberExists(trackingNumber int64) (bool, error)
+	// FindStale returns up to limit pending payments not touched since before, oldest first.
+	FindStale(before time.Time, limit int) ([]payment_entity.PaymentEntity, error)
+}
+
+type PaymentWriteRepository interface {
+	// Start sends a pending or failed payment to a gateway, making it pending again.
+	// It returns repositories.ErrConflict when the payment is in another status.
+	Start(start PaymentStart) error
+	// Complete settles a pending payment along with its attempt. It returns repositories.ErrConflict when the
+	// payment is not pending anymore or has moved on to another attempt, nothing is stored then.
+	Complete(completion PaymentCompletion) error
+	// CreateAttempt stores an attempt the gateway refused to open, with its transactions.
+	CreateAttempt(attempt *payment_entity.ParbadPaymentEntity) error
+	AddTransaction(transaction *payment_entity.ParbadTransactionEntity) error
+	// StartRefund stores a pending refund before the gateway is asked for it, a failed refund of the attempt
+	// under the same key is made pending again. It returns repositories.ErrConflict when the key is pending
+	// or refunded already.
+	StartRefund(transaction *payment_entity.ParbadTransactionEntity) error
+	// FinishRefund records the outcome of a pending refund
+	FinishRefund(id int64, succeeded bool, message string) error
+}
+
+type GatewayReadRepository interface {
+	// FindBySiteId returns the gateway accounts of the site.
+	FindBySiteId(siteId int64) (*payment_entity.GatewayEntity, error)
+}
@@ -0,0 +1,22 @@
+package product_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/product_entity"
+)
+
+type DiscountReadRepository interface {
+	// FindByCode returns the discount of the site with its product and customer restrictions loaded.
+	FindByCode(siteId int64, code string) (*product_entity.DiscountEntity, error)
+	FindById(id int64) (*product_entity.DiscountEntity, error)
+}
+
+type DiscountWriteRepository interface {
+	// Redeem consumes one usage of the discount, when discountId is set, and the given units of each coupon
+	// in a single transaction. It returns repositories.ErrExhausted, changing nothing, when any of them runs out.
+	Redeem(discountId int64, couponUnits map[int64]int) error
+}
+
+type CouponReadRepository interface {
+	// FindByProductIds returns the coupons of the products, expired or exhausted ones included.
+	FindByProductIds(productIds []int64) ([]product_entity.CouponEntity, error)
+}
@@ -0,0 +1,17 @@
+package product_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/product_entity"
+	"site_builder_backend/internal/interfaces/db/repositories"
+)
+
+type ProductReadRepository interface {
+	FindById(id int64) (*product_entity.ProductEntity, error)
+	// FindListedByIds returns the products of the site among ids that are not deleted or inactive, in no particular order.
+	FindListedByIds(siteId int64, ids []int64) ([]product_entity.ProductEntity, error)
+}
+
+type ProductWriteRepository interface {
+	// IncrementVisitedCounts adds the deltas to VisitedCount in a single transaction.
+	IncrementVisitedCounts(deltas []repositories.CounterDelta) error
+}
@@ -0,0 +1,34 @@
+package product_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/product_entity"
+)
+
+// ProductReviewFilter narrows review listings. Zero values are ignored, Approved is only applied when set.
+type ProductReviewFilter struct {
+	SiteId    int64
+	ProductId int64
+	Approved  *bool
+	Offset    int
+	Limit     int
+}
+
+type ProductReviewReadRepository interface {
+	FindById(id int64) (*product_entity.ProductReviewEntity, error)
+	FindByCustomer(customerId int64, productId int64) (*product_entity.ProductReviewEntity, error)
+	FindAll(filter ProductReviewFilter) ([]product_entity.ProductReviewEntity, int64, error)
+	FindVote(reviewId int64, customerId int64) (*product_entity.ProductReviewVoteEntity, error)
+}
+
+// ProductReviewWriteRepository keeps ReviewCount and Rate of the product in sync with its approved reviews.
+// Every method that can change the set of approved reviews recomputes them in the same transaction.
+type ProductReviewWriteRepository interface {
+	Create(entity *product_entity.ProductReviewEntity) error
+	Update(entity *product_entity.ProductReviewEntity) error
+	Approve(id int64) error
+	Delete(id int64) error
+	// Vote stores the vote of the customer on the review, replacing a previous one, and adjusts Like and Dislike.
+	Vote(reviewId int64, customerId int64, isLike bool) error
+	// RemoveVote deletes the vote of the customer and adjusts Like and Dislike. Missing votes are ignored.
+	RemoveVote(reviewId int64, customerId int64) error
+}
@@ -0,0 +1,10 @@
+package product_repo_inter
+
+import (
+	"site_builder_backend/internal/domain/product_entity"
+)
+
+type ProductVariantReadRepository interface {
+	// FindByIds returns the variants among ids with their product loaded, in no particular order.
+	FindByIds(ids []int64) ([]product_entity.ProductVariantEntity, error)
+}
@@ -0,0 +1,14 @@
+package site_repo_inter
+
+import "site_builder_backend/internal/domain/site_entity"
+
+type NotificationTemplateReadRepository interface {
+	FindBySiteId(siteId int64) ([]site_entity.NotificationTemplateEntity, error)
+	Find(siteId int64, channel string, event string, locale string) (*site_entity.NotificationTemplateEntity, error)
+}
+
+type NotificationTemplateWriteRepository interface {
+	// Save creates the template of the site or replaces its texts
+	Save(template *site_entity.NotificationTemplateEntity) error
+	Delete(siteId int64, channel string, event string, locale string) error
+}
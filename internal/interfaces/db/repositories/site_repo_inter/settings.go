@@ -0,0 +1,12 @@
+package site_repo_inter
+
+import "site_builder_backend/internal/domain/site_entity"
+
+type SettingsReadRepository interface {
+	FindBySiteId(siteId int64) (*site_entity.SettingsEntity, error)
+}
+
+type SettingsWriteRepository interface {
+	// SaveReturnWindow creates the settings of the site when missing
+	SaveReturnWindow(siteId int64, userId int64, days int) error
+}
@@ -0,0 +1,17 @@
+package site_repo_inter
+
+import (
+	"errors"
+
+	"site_builder_backend/internal/domain/site_entity"
+)
+
+// ErrNotOwner is returned by CheckOwner when the site does not exist, was deleted or belongs to
+// someone else. The caller cannot tell these apart, so site ids of other users are not disclosed.
+var ErrNotOwner = errors.New("site not found or access denied")
+
+type SiteReadRepository interface {
+	FindById(id int64) (*site_entity.SiteEntity, error)
+	// CheckOwner returns ErrNotOwner unless the live site siteId belongs to the user
+	CheckOwner(siteId int64, userId int64) error
+}
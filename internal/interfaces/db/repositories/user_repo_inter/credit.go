@@ -0,0 +1,34 @@
+package user_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+// CreditLedgerFilter narrows ledger listings of a user. Zero values are ignored.
+type CreditLedgerFilter struct {
+	UserId     int64
+	CreditType string
+	Offset     int
+	Limit      int
+}
+
+type CreditReadRepository interface {
+	FindUnitPrices() ([]user_entity.UnitPriceEntity, error)
+	FindUnitPriceById(id int64) (*user_entity.UnitPriceEntity, error)
+	FindTopUpById(id int64) (*user_entity.CreditTopUpEntity, error)
+	// FindLedger returns the ledger entries of the user, newest first, with their total count
+	FindLedger(filter CreditLedgerFilter) ([]user_entity.CreditLedgerEntity, int64, error)
+}
+
+type CreditWriteRepository interface {
+	// CreateTopUp stores the top-up and the pending payment paying for it
+	CreateTopUp(topUp *user_entity.CreditTopUpEntity, payment *payment_entity.PaymentEntity) error
+	// ApplyTopUp marks the pending top-up paid and credits it to its user, ErrConflict when it is not
+	// pending. A non-zero storageExpiresAt moves the expiry of the user's storage.
+	ApplyTopUp(id int64, storageExpiresAt time.Time) error
+	// FailTopUp marks the pending top-up failed, ErrConflict when it is not pending
+	FailTopUp(id int64) error
+}
@@ -0,0 +1,13 @@
+package user_repo_inter
+
+import "site_builder_backend/internal/domain/user_entity"
+
+type CustomerReadRepository interface {
+	// FindByEmail returns the live customer of the site registered with the email, ErrNotFound otherwise
+	FindByEmail(siteId int64, email string) (*user_entity.CustomerEntity, error)
+}
+
+type CustomerWriteRepository interface {
+	// Create inserts the customer, ErrConflict when the email is registered already
+	Create(entity *user_entity.CustomerEntity) error
+}
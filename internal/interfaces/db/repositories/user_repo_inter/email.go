@@ -0,0 +1,32 @@
+package user_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+// EmailMessageFilter narrows message listings of a user. Zero values are ignored.
+type EmailMessageFilter struct {
+	UserId int64
+	Status string
+	Offset int
+	Limit  int
+}
+
+type EmailReadRepository interface {
+	FindByMessageId(messageId string) (*user_entity.EmailMessageEntity, error)
+	// FindMessages returns the messages of the user, newest first, with their total count
+	FindMessages(filter EmailMessageFilter) ([]user_entity.EmailMessageEntity, int64, error)
+}
+
+type EmailWriteRepository interface {
+	// Create stores a queued message, ErrConflict when its MessageId is stored already
+	Create(message *user_entity.EmailMessageEntity) error
+	// MarkSent records the delivery of the queued message, ErrConflict when it is not queued
+	MarkSent(id int64, smtp string, attempts int, at time.Time) error
+	// MarkAttempt records a failed attempt of the queued message, which stays queued
+	MarkAttempt(id int64, smtp string, reason string, attempts int) error
+	// MarkFailed gives up on the queued message, ErrConflict when it is not queued
+	MarkFailed(id int64, smtp string, reason string, attempts int) error
+}
@@ -0,0 +1,30 @@
+package user_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+// CreditUsageFilter narrows usage listings of a user. Zero values are ignored.
+type CreditUsageFilter struct {
+	UserId     int64
+	CreditType string
+	Offset     int
+	Limit      int
+}
+
+type MeteringReadRepository interface {
+	// Available returns what the user can spend of the credit at now. Storage is the KB left of the
+	// quota, nothing once the quota expired.
+	Available(userId int64, creditType string, now time.Time) (int64, error)
+	// FindUsages returns the usages of the user, newest first, with their total count
+	FindUsages(filter CreditUsageFilter) ([]user_entity.CreditUsageEntity, int64, error)
+}
+
+type MeteringWriteRepository interface {
+	// Consume records the usage and takes it from the credits of its user, storage is added to the used
+	// space instead. A reservation consumed already changes nothing, credits it would take below zero
+	// return ErrExhausted.
+	Consume(usage *user_entity.CreditUsageEntity) error
+}
@@ -0,0 +1,29 @@
+package user_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+// NotificationFilter narrows the inbox of a user, Unread leaves out what was read
+type NotificationFilter struct {
+	UserId int64
+	Unread bool
+	Offset int
+	Limit  int
+}
+
+type NotificationReadRepository interface {
+	// FindNotifications returns the notifications of a user, newest first
+	FindNotifications(filter NotificationFilter) ([]user_entity.NotificationEntity, int64, error)
+	CountUnread(userId int64) (int64, error)
+}
+
+type NotificationWriteRepository interface {
+	// Create returns ErrConflict when a notification was already made for its EventKey
+	Create(notification *user_entity.NotificationEntity) error
+	// MarkRead marks the notifications among ids of the user as read, all of them when ids is empty.
+	// It returns how many were unread.
+	MarkRead(userId int64, ids []int64, at time.Time) (int64, error)
+}
@@ -0,0 +1,56 @@
+package user_repo_inter
+
+import (
+	"time"
+
+	"site_builder_backend/internal/domain/payment_entity"
+	"site_builder_backend/internal/domain/user_entity"
+)
+
+// PlanActivation applies a paid purchase to its user. Credits are added to what the user has left,
+// storage is a quota and replaced. Roles of RevokeRoleIds are taken from the user before GrantRoleIds
+// are given. StartsAt is when the user's plan started, PeriodStartsAt when the period paid for by the
+// purchase starts, later than StartsAt when a running plan is renewed.
+type PlanActivation struct {
+	PurchaseId     int64
+	UserId         int64
+	PlanId         int64
+	StartsAt       time.Time
+	PeriodStartsAt time.Time
+	ExpiresAt      time.Time
+	SmsCredits     int
+	EmailCredits   int
+	AiCredits      int
+	AiImageCredits int
+	StorageMb      int
+	RevokeRoleIds  []int64
+	GrantRoleIds   []int64
+}
+
+type PlanReadRepository interface {
+	// FindById returns the plan with its roles
+	FindById(id int64) (*user_entity.PlanEntity, error)
+	FindVisible() ([]user_entity.PlanEntity, error)
+	FindPurchaseById(id int64) (*user_entity.PlanPurchaseEntity, error)
+	// FindRunningPurchases returns the user's paid purchases of the plan started since and running after now
+	FindRunningPurchases(userId int64, planId int64, since time.Time, now time.Time) ([]user_entity.PlanPurchaseEntity, error)
+	// FindExpiredUsers returns users whose plan ran out before now
+	FindExpiredUsers(now time.Time, limit int) ([]user_entity.UserEntity, error)
+	// FindExpiringUsers returns users whose plan runs out between now and until and who were not reminded
+	// of it yet
+	FindExpiringUsers(now time.Time, until time.Time, limit int) ([]user_entity.UserEntity, error)
+}
+
+type PlanWriteRepository interface {
+	// CreatePurchase stores the purchase and the pending payment paying for it, ErrConflict when the user
+	// has a pending purchase already
+	CreatePurchase(purchase *user_entity.PlanPurchaseEntity, payment *payment_entity.PaymentEntity) error
+	// Activate marks the pending purchase paid and applies it to the user, ErrConflict when it is not pending
+	Activate(activation PlanActivation) error
+	// FailPurchase marks the pending purchase failed, ErrConflict when it is not pending
+	FailPurchase(id int64) error
+	// ExpirePlan takes the plan and its roles from the user, ErrConflict when the plan was renewed or
+	// changed meanwhile
+	ExpirePlan(userId int64, planId int64, roleIds []int64, now time.Time) error
+	MarkReminded(userId int64, at time.Time) error
+}
@@ -0,0 +1,38 @@
+package image_processor_inter
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("image format is not supported")
+	ErrImageTooLarge    = errors.New("image has more pixels than allowed")
+)
+
+// Size is a derivative to render. The image is scaled down to Width, or cropped to fill Width x Height
+// when Height is set. Images are never scaled up, a size wider than the image renders it at its width.
+type Size struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+// Rendition is a rendered size, encoded
+type Rendition struct {
+	Name     string
+	Width    int
+	Height   int
+	Data     []byte
+	MimeType string
+	// Ext is the file extension of the format, with its dot
+	Ext string
+}
+
+// ImageProcessor renders the derivatives of uploaded images
+type ImageProcessor interface {
+	// Render decodes the image, turns it upright and renders the sizes without its metadata. Sizes that
+	// would come out as large as an earlier one are left out.
+	Render(ctx context.Context, src io.Reader, sizes []Size) ([]Rendition, error)
+}
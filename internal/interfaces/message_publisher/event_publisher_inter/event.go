@@ -0,0 +1,18 @@
+package event_publisher_inter
+
+import "context"
+
+// Exchanges are durable topic exchanges, one per domain. Routing keys follow "<domain>.<entity>.<action>".
+const (
+	BlogExchange    = "blog_exchange"
+	DriveExchange   = "drive_exchange"
+	OrderExchange   = "order_exchange"
+	PaymentExchange = "payment_exchange"
+	PlanExchange    = "plan_exchange"
+)
+
+// EventPublisher publishes domain events for asynchronous consumers
+type EventPublisher interface {
+	// Publish sends the JSON encoded payload to the exchange with the routing key
+	Publish(ctx context.Context, exchange string, routingKey string, payload interface{}) error
+}
@@ -0,0 +1,16 @@
+package email_template_inter
+
+import "errors"
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+type RenderedEmail struct {
+	Subject string
+	Html    string
+	Text    string
+}
+
+// EmailRenderer renders the subject, HTML and text body of a named template with its data
+type EmailRenderer interface {
+	Render(name string, data map[string]interface{}) (*RenderedEmail, error)
+}
@@ -0,0 +1,39 @@
+package mailer_inter
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRejected means the SMTP server refused the message or the account for good, a bad recipient or
+// wrong credentials, so sending it again cannot succeed
+var ErrRejected = errors.New("email rejected by smtp server")
+
+// SmtpAccount is the server mail is sent through. EnableSsl connects with implicit TLS, otherwise the
+// connection is upgraded with STARTTLS when the server offers it. An empty Username sends without
+// authentication. Public accounts are entered by site owners, they are only reached on public addresses
+// and SMTP ports, anything else is ErrRejected.
+type SmtpAccount struct {
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	EnableSsl bool
+	From      string
+	Public    bool
+}
+
+// Mail is one message to one recipient with an HTML and a text body, either may be empty
+type Mail struct {
+	MessageId string
+	To        string
+	Subject   string
+	Html      string
+	Text      string
+}
+
+// Mailer sends mail through an SMTP account. Transport failures and temporary refusals are returned as
+// is so callers can tell them from ErrRejected and try again later.
+type Mailer interface {
+	Send(ctx context.Context, account SmtpAccount, mail Mail) error
+}
@@ -0,0 +1,24 @@
+package sms_provider_inter
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRejected means the provider refused the message itself, a bad number or text, so sending it again
+// cannot succeed
+var ErrRejected = errors.New("sms rejected by provider")
+
+// SmsResult is the provider's answer to an accepted message
+type SmsResult struct {
+	// MessageId is the provider's id of the message, empty when it gives none
+	MessageId string
+	Status    string
+}
+
+// SmsProvider sends text messages through one SMS provider. Transport failures and outages are returned
+// as is so callers can tell them from ErrRejected and try again later.
+type SmsProvider interface {
+	Name() string
+	Send(ctx context.Context, phone string, message string) (*SmsResult, error)
+}
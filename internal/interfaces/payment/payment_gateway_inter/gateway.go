@@ -0,0 +1,90 @@
+package payment_gateway_inter
+
+import (
+	"context"
+	"errors"
+
+	"site_builder_backend/internal/domain/payment_entity"
+)
+
+var (
+	// ErrNotVerified means the gateway reports the payment as not paid, cancelled by the customer or rejected
+	ErrNotVerified = errors.New("payment was not completed at the gateway")
+	// ErrRefundNotSupported means refunds have to be issued from the gateway's own panel
+	ErrRefundNotSupported = errors.New("gateway does not support refunds through its api")
+	// ErrMisconfigured means the site's gateway account lacks the credentials the gateway needs
+	ErrMisconfigured = errors.New("gateway account is missing its credentials")
+)
+
+// PaymentRequest asks the gateway for a token to pay an amount in rials. TrackingNumber identifies the
+// attempt at the gateway and is never reused.
+type PaymentRequest struct {
+	TrackingNumber int64
+	Amount         int64
+	CallbackUrl    string
+	Description    string
+}
+
+// RedirectRequest is what the customer is sent to the gateway with
+type RedirectRequest struct {
+	TrackingNumber int64
+	Token          string
+	Amount         int64
+	CallbackUrl    string
+}
+
+// Redirect sends the customer to the gateway. Form holds the fields to post when Method is POST.
+type Redirect struct {
+	Url    string            `json:"url"`
+	Method string            `json:"method"`
+	Form   map[string]string `json:"form,omitempty"`
+}
+
+// VerifyRequest confirms a payment after the gateway sent the customer back. Callback holds the query
+// and form values of that request.
+type VerifyRequest struct {
+	TrackingNumber int64
+	Token          string
+	Amount         int64
+	Callback       map[string]string
+}
+
+// InquiryRequest asks the gateway about an attempt the customer never came back from
+type InquiryRequest struct {
+	TrackingNumber int64
+	Token          string
+	Amount         int64
+}
+
+type VerifyResult struct {
+	// ReferenceId is the gateway's reference of the settled payment
+	ReferenceId  string
+	CardNumber   string
+	ResponseCode string
+	Message      string
+}
+
+type RefundRequest struct {
+	TrackingNumber int64
+	Token          string
+	ReferenceId    string
+	Amount         int64
+}
+
+type RefundResult struct {
+	ReferenceId string
+}
+
+// PaymentGateway talks to one payment provider on behalf of a site's gateway account. Transport failures
+// are returned as is so callers can tell them from ErrNotVerified and try again later.
+type PaymentGateway interface {
+	// Name is the key of the gateway in the registry and on payments
+	Name() string
+	Request(ctx context.Context, account *payment_entity.GatewayEntity, request PaymentRequest) (string, error)
+	Redirect(account *payment_entity.GatewayEntity, request RedirectRequest) (*Redirect, error)
+	Verify(ctx context.Context, account *payment_entity.GatewayEntity, request VerifyRequest) (*VerifyResult, error)
+	// Inquire settles an attempt without its callback, as Verify would. It returns ErrNotVerified when the
+	// attempt was not paid.
+	Inquire(ctx context.Context, account *payment_entity.GatewayEntity, request InquiryRequest) (*VerifyResult, error)
+	Refund(ctx context.Context, account *payment_entity.GatewayEntity, request RefundRequest) (*RefundResult, error)
+}
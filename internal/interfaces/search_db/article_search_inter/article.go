@@ -0,0 +1,65 @@
+package article_search_inter
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrSearchDisabled is returned when the backing index is not enabled in the configuration
+var ErrSearchDisabled = errors.New("article search is disabled")
+
+// ArticleDocument is the searchable projection of a blog article
+type ArticleDocument struct {
+	Id          string
+	SiteId      int64
+	Title       string
+	Description string
+	Body        string // HTML of the article, only its text is indexed
+	Slug        string
+	Categories  []string
+	Badges      []string
+	Status      string
+	PublishedAt time.Time
+}
+
+type ArticleSearchQuery struct {
+	SiteId int64
+	Query  string
+	Offset int
+	Limit  int
+}
+
+type ArticleSearchHit struct {
+	Id          string              `json:"id"`
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	Slug        string              `json:"slug"`
+	Categories  []string            `json:"categories,omitempty"`
+	PublishedAt time.Time           `json:"published_at"`
+	Score       float64             `json:"score"`
+	Highlights  map[string][]string `json:"highlights,omitempty"`
+}
+
+type ArticleSearchResult struct {
+	Hits  []ArticleSearchHit
+	Total int64
+}
+
+// ArticleSearch indexes and queries blog articles. Only articles visible at the given moment are returned.
+type ArticleSearch interface {
+	// EnsureIndex creates the index with its mapping when it does not exist yet
+	EnsureIndex(ctx context.Context) error
+
+	// Index inserts or replaces the document of an article
+	Index(ctx context.Context, document ArticleDocument) error
+
+	// Delete removes the document of an article, missing documents are ignored
+	Delete(ctx context.Context, id string) error
+
+	// Search runs a full-text query over title, description, body, categories and badges with highlighting
+	Search(ctx context.Context, query ArticleSearchQuery, now time.Time) (*ArticleSearchResult, error)
+
+	// MoreLikeThis returns articles of the same site similar to the given one
+	MoreLikeThis(ctx context.Context, siteId int64, articleId string, limit int, now time.Time) ([]ArticleSearchHit, error)
+}
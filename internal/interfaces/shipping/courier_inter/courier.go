@@ -0,0 +1,32 @@
+package courier_inter
+
+import (
+	"context"
+	"errors"
+
+	"site_builder_backend/internal/domain/order_entity"
+)
+
+// ErrUnavailable means the courier does not deliver this shipment, it is not offered to the customer
+var ErrUnavailable = errors.New("courier does not deliver this shipment")
+
+// Shipment is what a courier prices. Weight is in grams and leaves out products sent for free.
+type Shipment struct {
+	SiteId     int64
+	ProvinceId int64
+	CityId     int64
+	PostalCode string
+	Weight     int
+	Value      int64
+}
+
+type Quote struct {
+	Price         int64
+	EstimatedDays int
+}
+
+// CourierProvider prices shipments for the shipping methods naming it. Rule based providers read the
+// rates of the method, carrier integrations may call the carrier instead.
+type CourierProvider interface {
+	Quote(ctx context.Context, method *order_entity.ShippingMethodEntity, shipment Shipment) (*Quote, error)
+}
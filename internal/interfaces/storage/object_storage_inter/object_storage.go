@@ -0,0 +1,51 @@
+package object_storage_inter
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrObjectNotFound is returned for missing objects and multipart uploads completed or aborted already
+var ErrObjectNotFound = errors.New("object not found")
+
+// Object is the content of a stored object, its Body must be closed
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
+
+// Part is an uploaded part of a multipart upload
+type Part struct {
+	Number int
+	ETag   string
+}
+
+// ObjectStorage keeps file contents under keys in buckets. Large files are uploaded in parts, each
+// part may be sent again until the upload is completed, then the parts are joined into one object.
+type ObjectStorage interface {
+	// EnsureBucket creates the bucket unless it exists
+	EnsureBucket(ctx context.Context, bucket string) error
+
+	Put(ctx context.Context, bucket string, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, bucket string, key string) (*Object, error)
+
+	// Delete removes the object, a missing object is not an error
+	Delete(ctx context.Context, bucket string, key string) error
+
+	// Copy stores a copy of the object srcKey under dstKey of the same bucket
+	Copy(ctx context.Context, bucket string, srcKey string, dstKey string) error
+
+	// CreateMultipart starts a multipart upload to key and returns its upload id
+	CreateMultipart(ctx context.Context, bucket string, key string, contentType string) (string, error)
+
+	// UploadPart stores size bytes of body as part number of the upload and returns its ETag
+	UploadPart(ctx context.Context, bucket string, key string, uploadId string, number int, body io.Reader, size int64) (string, error)
+
+	// CompleteMultipart joins the parts, in the order of their numbers, into the object
+	CompleteMultipart(ctx context.Context, bucket string, key string, uploadId string, parts []Part) error
+
+	// AbortMultipart drops the upload and its parts
+	AbortMultipart(ctx context.Context, bucket string, key string, uploadId string) error
+}
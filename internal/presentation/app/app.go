@@ -8,9 +8,11 @@ import (
 	"site_builder_backend/internal/presentation/routing"
 	"site_builder_backend/internal/presentation/routing/consumer_router"
 	"site_builder_backend/internal/presentation/routing/http_router"
+	"site_builder_backend/internal/presentation/routing/job_router"
 	"site_builder_backend/pkg/httpserver"
 	"site_builder_backend/pkg/logger"
 	"site_builder_backend/pkg/rabbitmq"
+	"site_builder_backend/pkg/scheduler"
 	"syscall"
 )
 
@@ -39,13 +41,17 @@ func Run(cfg *configs.Config) {
 	httpServer.App.Use(middlewares.LoggerMiddleware(l))
 	httpServer.App.Use(middlewares.RecoveryMiddleware(l))
 
-	services := routing.NewServiceRegistration(cfg, l)
+	services := routing.NewServiceRegistration(cfg, l, rmqClient)
 	controllerServices := routing.NewControllerServices(services)
 
 	http_router.Register(httpServer.App, controllerServices, services)
 
 	consumer_router.Register(rmqClient, services)
 
+	jobScheduler := scheduler.New(l)
+	job_router.Register(jobScheduler, cfg.Jobs, services)
+	jobScheduler.Start()
+
 	httpServer.Start()
 	l.Info("HTTP server started on port %s", cfg.HTTP.Port)
 
@@ -60,8 +66,11 @@ func Run(cfg *configs.Config) {
 	}
 
 	l.Info("Shutting down server...")
+	// Open notification streams would hold the shutdown until its timeout
+	services.NotificationHub.Close()
 	err = httpServer.Shutdown()
 	if err != nil {
 		l.Error("app - Run - httpServer.Shutdown: %v", err)
 	}
+	jobScheduler.Stop()
 }
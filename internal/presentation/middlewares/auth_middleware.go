@@ -54,3 +54,28 @@ func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Optional sets user claims in context when a valid bearer token is present and lets anonymous requests through
+func (m *AuthMiddleware) Optional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+
+		claims, err := m.jwtService.ValidateToken(c.Request.Context(), parts[1], auth_inter.AccessToken)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token", "details": err.Error()})
+			return
+		}
+
+		c.Set("claims", claims)
+		c.Set("user_id", claims["sub"])
+		if role, exists := claims["role"]; exists {
+			c.Set("user_role", role)
+		}
+
+		c.Next()
+	}
+}
@@ -2,6 +2,9 @@ package consumer_router
 
 import (
 	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/internal/presentation/routing/consumer_router/drive_consumer_router"
+	"site_builder_backend/internal/presentation/routing/consumer_router/notification_consumer_router"
+	"site_builder_backend/internal/presentation/routing/consumer_router/payment_consumer_router"
 	"site_builder_backend/internal/presentation/routing/consumer_router/user_consumer_router"
 	"site_builder_backend/pkg/rabbitmq"
 )
@@ -9,4 +12,7 @@ import (
 // Register registers all consumer routes
 func Register(rbClient *rabbitmq.Client, services *routing.Services) {
 	user_consumer_router.UserRegister(rbClient, services)
+	payment_consumer_router.PaymentRegister(rbClient, services)
+	notification_consumer_router.NotificationRegister(rbClient, services)
+	drive_consumer_router.DriveRegister(rbClient, services)
 }
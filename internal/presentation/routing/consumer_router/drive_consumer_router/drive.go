@@ -0,0 +1,26 @@
+package drive_consumer_router
+
+import (
+	"site_builder_backend/internal/adapters/consumer/drive_consumer"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/rabbitmq"
+)
+
+func DriveRegister(client *rabbitmq.Client, services *routing.Services) {
+	// Initialize use cases and consumer
+	consumer := drive_consumer.NewDriveConsumer(services.ImageUseCase, services.Logger)
+
+	// Register image consumer
+	err := client.Exchange(event_publisher_inter.DriveExchange).
+		Queue("image_queue").
+		Type("topic").
+		RoutingKey(event_dto.RoutingKeyImageStored).
+		Config(true, false, false, false).
+		Consume(consumer.ImageConsume)
+
+	if err != nil {
+		panic("Failed to register image consumer: " + err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package notification_consumer_router
+
+import (
+	"site_builder_backend/internal/adapters/consumer/notification_consumer"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/rabbitmq"
+)
+
+func NotificationRegister(client *rabbitmq.Client, services *routing.Services) {
+	// Initialize use cases and consumer
+	inboxUseCase := notification_use_case.NewInboxUseCase(services.NotificationReadRepo, services.NotificationWriteRepo, services.SiteReadRepo, services.OrderReadRepo, services.ProductVariantReadRepo, services.NotificationHub, services.Config.Notification.LowStockThreshold, services.Logger)
+	consumer := notification_consumer.NewNotificationConsumer(inboxUseCase, services.Logger)
+
+	// Register new order consumer
+	err := client.Exchange(event_publisher_inter.OrderExchange).
+		Queue("notification_order_queue").
+		Type("topic").
+		RoutingKey(event_dto.RoutingKeyOrderPlaced).
+		Config(true, false, false, false).
+		Consume(consumer.OrderPlacedConsume)
+
+	if err != nil {
+		panic("Failed to register order notification consumer: " + err.Error())
+	}
+
+	// Register return request consumer
+	err = client.Exchange(event_publisher_inter.OrderExchange).
+		Queue("notification_return_queue").
+		Type("topic").
+		RoutingKey(event_dto.RoutingKeyReturnRequested).
+		Config(true, false, false, false).
+		Consume(consumer.ReturnRequestedConsume)
+
+	if err != nil {
+		panic("Failed to register return notification consumer: " + err.Error())
+	}
+
+	// Register plan consumer, a queue of its own next to the plan emails
+	err = client.Exchange(event_publisher_inter.PlanExchange).
+		Queue("notification_plan_queue").
+		Type("topic").
+		RoutingKey(event_dto.RoutingKeyPlanSubscriptions).
+		Config(true, false, false, false).
+		Consume(consumer.PlanConsume)
+
+	if err != nil {
+		panic("Failed to register plan notification consumer: " + err.Error())
+	}
+}
@@ -0,0 +1,32 @@
+package payment_consumer_router
+
+import (
+	"site_builder_backend/internal/adapters/consumer/payment_consumer"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/rabbitmq"
+)
+
+func PaymentRegister(client *rabbitmq.Client, services *routing.Services) {
+	// Initialize use cases and consumer
+	orderUseCase := order_use_case.NewOrderUseCase(services.SiteReadRepo, services.OrderReadRepo, services.OrderWriteRepo, services.EventPublisher, services.Logger)
+	returnUseCase := order_use_case.NewReturnUseCase(services.SiteReadRepo, services.SettingsReadRepo, services.SettingsWriteRepo, services.OrderReadRepo, services.ReturnItemReadRepo, services.ReturnItemWriteRepo, orderUseCase, services.EventPublisher, services.Logger)
+	paymentGateways := payment_use_case.NewGatewayRegistry(services.PaymentGateways...)
+	paymentUseCase := payment_use_case.NewPaymentUseCase(services.PaymentReadRepo, services.PaymentWriteRepo, services.GatewayReadRepo, services.OrderReadRepo, orderUseCase, paymentGateways, services.EventPublisher, services.Config.Payment.CallbackBaseUrl, services.Logger)
+	consumer := payment_consumer.NewPaymentConsumer(paymentUseCase, returnUseCase, services.Logger)
+
+	// Register refund consumer
+	err := client.Exchange(event_publisher_inter.PaymentExchange).
+		Queue("payment_refund_queue").
+		Type("topic").
+		RoutingKey(event_dto.RoutingKeyRefundRequested).
+		Config(true, false, false, false).
+		Consume(consumer.RefundConsume)
+
+	if err != nil {
+		panic("Failed to register refund consumer: " + err.Error())
+	}
+}
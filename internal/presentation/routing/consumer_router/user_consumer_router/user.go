@@ -2,25 +2,72 @@ package user_consumer_router
 
 import (
 	"site_builder_backend/internal/adapters/consumer/user_consumer"
+	"site_builder_backend/internal/application/dto/event_dto"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
 	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
 	"site_builder_backend/internal/presentation/routing"
 	"site_builder_backend/pkg/rabbitmq"
 )
 
 func UserRegister(client *rabbitmq.Client, services *routing.Services) {
-	// Initialize repository, use case, and consumer
-	useCase := user_use_case.NewUserUseCase(services.UserReadRepo, nil, nil)
-	consumer := user_consumer.NewUserConsumer(useCase)
+	// Initialize use cases and consumer
+	meteringUseCase := user_use_case.NewMeteringUseCase(services.CreditMeter, services.MeteringReadRepo, services.MeteringWriteRepo, services.Config.Metering.ReservationTTL, services.Config.Metering.CounterTTL, services.Logger)
+	templateUseCase := notification_use_case.NewTemplateUseCase(services.SiteReadRepo, services.TemplateReadRepo, services.TemplateWriteRepo, services.Config.Notification.DefaultLocale, services.Logger)
+	smsUseCase := user_use_case.NewSmsUseCase(services.SmsReadRepo, services.SmsWriteRepo, services.SmsProvider, meteringUseCase, templateUseCase, services.Config.Sms.MaxAttempts, services.Logger)
+	emailUseCase := user_use_case.NewEmailUseCase(services.EmailReadRepo, services.EmailWriteRepo, services.UserReadRepo, services.Mailer, services.EmailRenderer, meteringUseCase, templateUseCase, services.PlatformSmtp, services.Config.Email.MaxAttempts, services.Logger)
+	consumer := user_consumer.NewUserConsumer(smsUseCase, emailUseCase, services.Logger)
 
-	// Register SMS consumer
+	// Register SMS consumer, failed messages wait in a retry queue before they are tried again and are
+	// moved to a dead queue once they failed as often as the message may be attempted
 	err := client.Exchange("user_exchange").
 		Queue("sms_queue").
 		Type("direct").
 		RoutingKey("user.sms").
 		Config(true, false, false, false).
+		Retry(services.Config.Sms.RetryDelay, services.Config.Sms.MaxAttempts).
 		Consume(consumer.SendSmsConsume)
 
 	if err != nil {
 		panic("Failed to register SMS consumer: " + err.Error())
 	}
+
+	// Register bulk SMS consumer, a queue of its own so campaigns do not hold up single messages
+	err = client.Exchange("user_exchange").
+		Queue("sms_bulk_queue").
+		Type("direct").
+		RoutingKey("user.sms.bulk").
+		Config(true, false, false, false).
+		Retry(services.Config.Sms.RetryDelay, services.Config.Sms.MaxAttempts).
+		Consume(consumer.SendSmsConsume)
+
+	if err != nil {
+		panic("Failed to register bulk SMS consumer: " + err.Error())
+	}
+
+	// Register email consumer, failed messages wait in a retry queue like SMS
+	err = client.Exchange("user_exchange").
+		Queue("email_queue").
+		Type("direct").
+		RoutingKey("user.email").
+		Config(true, false, false, false).
+		Retry(services.Config.Email.RetryDelay, services.Config.Email.MaxAttempts).
+		Consume(consumer.SendEmailConsume)
+
+	if err != nil {
+		panic("Failed to register email consumer: " + err.Error())
+	}
+
+	// Register plan notification consumer
+	err = client.Exchange(event_publisher_inter.PlanExchange).
+		Queue("plan_notification_queue").
+		Type("topic").
+		RoutingKey(event_dto.RoutingKeyPlanSubscriptions).
+		Config(true, false, false, false).
+		Retry(services.Config.Email.RetryDelay, services.Config.Email.MaxAttempts).
+		Consume(consumer.PlanNotifyConsume)
+
+	if err != nil {
+		panic("Failed to register plan notification consumer: " + err.Error())
+	}
 }
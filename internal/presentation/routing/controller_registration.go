@@ -1,13 +1,51 @@
 package routing
 
 import (
+	"site_builder_backend/internal/adapters/http/blog_controller"
+	"site_builder_backend/internal/adapters/http/drive_controller"
+	"site_builder_backend/internal/adapters/http/notification_controller"
+	"site_builder_backend/internal/adapters/http/order_controller"
+	"site_builder_backend/internal/adapters/http/payment_controller"
+	"site_builder_backend/internal/adapters/http/product_controller"
 	"site_builder_backend/internal/adapters/http/user_controller"
+	"site_builder_backend/internal/adapters/http/visit_controller"
+	"site_builder_backend/internal/application/use_cases/basket_use_case"
+	"site_builder_backend/internal/application/use_cases/blog_use_case"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/internal/application/use_cases/notification_use_case"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/application/use_cases/pricing_use_case"
+	"site_builder_backend/internal/application/use_cases/product_use_case"
+	"site_builder_backend/internal/application/use_cases/shipping_use_case"
 	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/application/use_cases/visit_use_case"
 )
 
 type ControllerServices struct {
-	UserController    *user_controller.UserController
-	AddressController *user_controller.AddressController
+	UserController     *user_controller.UserController
+	CustomerController *user_controller.CustomerController
+	AddressController  *user_controller.AddressController
+	PlanController     *user_controller.PlanController
+	CreditController   *user_controller.CreditController
+	SmsController      *user_controller.SmsController
+	EmailController    *user_controller.EmailController
+	ArticleController  *blog_controller.ArticleController
+	VisitController    *visit_controller.VisitController
+	ReviewController   *product_controller.ReviewController
+	CommentController  *blog_controller.CommentController
+	PricingController  *product_controller.PricingController
+	BasketController   *order_controller.BasketController
+	CheckoutController *order_controller.CheckoutController
+	ShippingController *order_controller.ShippingController
+	OrderController    *order_controller.OrderController
+	ReturnController   *order_controller.ReturnController
+	PaymentController  *payment_controller.PaymentController
+	TemplateController *notification_controller.TemplateController
+	InboxController    *notification_controller.InboxController
+	FileController     *drive_controller.FileController
+	UploadController   *drive_controller.UploadController
+	MediaController    *drive_controller.MediaController
 }
 
 func NewControllerServices(services *Services) *ControllerServices {
@@ -15,11 +53,101 @@ func NewControllerServices(services *Services) *ControllerServices {
 	userUseCase := user_use_case.NewUserUseCase(services.UserReadRepo, services.UserWriteRepo, services.Logger)
 	userController := user_controller.NewUserController(userUseCase, services.Logger)
 
+	customerUseCase := user_use_case.NewCustomerUseCase(services.SiteReadRepo, services.CustomerReadRepo, services.CustomerWriteRepo, services.Logger)
+	customerController := user_controller.NewCustomerController(customerUseCase, services.jwtService, services.Logger)
+
 	addressUseCase := user_use_case.NewAddressUseCase(services.AddressReadRepo, services.AddressWriteRepo, services.Logger)
 	addressController := user_controller.NewAddressController(addressUseCase, services.Logger)
 
+	mediaController := drive_controller.NewMediaController(services.ImageUseCase, services.Logger)
+
+	articleUseCase := blog_use_case.NewArticleUseCase(services.SiteReadRepo, services.ArticleReadRepo, services.ArticleWriteRepo, services.ArticleSearch, services.ImageUseCase, services.Logger)
+	articleController := blog_controller.NewArticleController(articleUseCase, services.Logger)
+
+	commentUseCase := blog_use_case.NewCommentUseCase(services.SiteReadRepo, services.ArticleReadRepo, services.ArticleCommentReadRepo, services.ArticleCommentWriteRepo, services.RateLimiter, services.EventPublisher, services.Logger)
+	commentController := blog_controller.NewCommentController(commentUseCase, services.Logger)
+
+	visitUseCase := visit_use_case.NewVisitUseCase(services.VisitCounter, services.ProductReadRepo, services.ProductWriteRepo, services.ArticleReadRepo, services.ArticleWriteRepo, services.ImageUseCase, services.Logger)
+	visitController := visit_controller.NewVisitController(visitUseCase, services.Logger)
+
+	reviewUseCase := product_use_case.NewReviewUseCase(services.SiteReadRepo, services.ProductReadRepo, services.OrderReadRepo, services.ProductReviewReadRepo, services.ProductReviewWriteRepo, services.Logger)
+	reviewController := product_controller.NewReviewController(reviewUseCase, services.Logger)
+
+	pricingEngine, err := pricing_use_case.NewPricingEngine(services.Config.Pricing.StackingOrder, services.Config.Pricing.AllowStacking)
+	if err != nil {
+		services.Logger.Fatal("app - Run - pricing_use_case.NewPricingEngine: %v", err)
+	}
+	pricingUseCase := pricing_use_case.NewPricingUseCase(pricingEngine, services.ProductVariantReadRepo, services.CouponReadRepo, services.DiscountReadRepo, services.DiscountWriteRepo, services.Logger)
+	pricingController := product_controller.NewPricingController(pricingUseCase, services.Logger)
+
+	basketUseCase := basket_use_case.NewBasketUseCase(pricingUseCase, services.ProductVariantReadRepo, services.BasketReadRepo, services.BasketWriteRepo, services.GuestBasketStore, services.Logger)
+	basketController := order_controller.NewBasketController(basketUseCase, services.Logger)
+
+	shippingUseCase := shipping_use_case.NewShippingUseCase(services.SiteReadRepo, services.ShippingMethodReadRepo, services.ShippingMethodWriteRepo, services.CourierProviders, services.Logger)
+	shippingController := order_controller.NewShippingController(shippingUseCase, services.Logger)
+
+	checkoutUseCase := order_use_case.NewCheckoutUseCase(services.SiteReadRepo, services.AddressReadRepo, services.ProductVariantReadRepo, services.BasketReadRepo, services.OrderReadRepo, services.OrderWriteRepo, pricingUseCase, shippingUseCase, services.EventPublisher, services.Logger)
+	checkoutController := order_controller.NewCheckoutController(checkoutUseCase, services.Logger)
+
+	orderUseCase := order_use_case.NewOrderUseCase(services.SiteReadRepo, services.OrderReadRepo, services.OrderWriteRepo, services.EventPublisher, services.Logger)
+	orderController := order_controller.NewOrderController(orderUseCase, services.Logger)
+
+	returnUseCase := order_use_case.NewReturnUseCase(services.SiteReadRepo, services.SettingsReadRepo, services.SettingsWriteRepo, services.OrderReadRepo, services.ReturnItemReadRepo, services.ReturnItemWriteRepo, orderUseCase, services.EventPublisher, services.Logger)
+	returnController := order_controller.NewReturnController(returnUseCase, services.Logger)
+
+	paymentGateways := payment_use_case.NewGatewayRegistry(services.PaymentGateways...)
+	paymentUseCase := payment_use_case.NewPaymentUseCase(services.PaymentReadRepo, services.PaymentWriteRepo, services.GatewayReadRepo, services.OrderReadRepo, orderUseCase, paymentGateways, services.EventPublisher, services.Config.Payment.CallbackBaseUrl, services.Logger)
+	paymentController := payment_controller.NewPaymentController(paymentUseCase, services.Logger)
+
+	meteringUseCase := user_use_case.NewMeteringUseCase(services.CreditMeter, services.MeteringReadRepo, services.MeteringWriteRepo, services.Config.Metering.ReservationTTL, services.Config.Metering.CounterTTL, services.Logger)
+
+	planUseCase := user_use_case.NewPlanUseCase(services.PlanReadRepo, services.PlanWriteRepo, services.UserReadRepo, paymentUseCase, meteringUseCase, services.EventPublisher, services.Config.Plan.PaymentSiteId, services.Logger)
+	planController := user_controller.NewPlanController(planUseCase, services.Logger)
+
+	creditUseCase := user_use_case.NewCreditUseCase(services.CreditReadRepo, services.CreditWriteRepo, services.UserReadRepo, paymentUseCase, meteringUseCase, services.Config.Plan.PaymentSiteId, services.Logger)
+	creditController := user_controller.NewCreditController(creditUseCase, meteringUseCase, services.Logger)
+
+	templateUseCase := notification_use_case.NewTemplateUseCase(services.SiteReadRepo, services.TemplateReadRepo, services.TemplateWriteRepo, services.Config.Notification.DefaultLocale, services.Logger)
+	templateController := notification_controller.NewTemplateController(templateUseCase, services.Logger)
+
+	inboxUseCase := notification_use_case.NewInboxUseCase(services.NotificationReadRepo, services.NotificationWriteRepo, services.SiteReadRepo, services.OrderReadRepo, services.ProductVariantReadRepo, services.NotificationHub, services.Config.Notification.LowStockThreshold, services.Logger)
+	inboxController := notification_controller.NewInboxController(inboxUseCase, services.Config.Notification.StreamHeartbeat, services.Logger)
+
+	smsUseCase := user_use_case.NewSmsUseCase(services.SmsReadRepo, services.SmsWriteRepo, services.SmsProvider, meteringUseCase, templateUseCase, services.Config.Sms.MaxAttempts, services.Logger)
+	smsController := user_controller.NewSmsController(smsUseCase, services.Logger)
+
+	emailUseCase := user_use_case.NewEmailUseCase(services.EmailReadRepo, services.EmailWriteRepo, services.UserReadRepo, services.Mailer, services.EmailRenderer, meteringUseCase, templateUseCase, services.PlatformSmtp, services.Config.Email.MaxAttempts, services.Logger)
+	emailController := user_controller.NewEmailController(emailUseCase, services.Logger)
+
+	fileUseCase := drive_use_case.NewFileUseCase(services.FileItemReadRepo, services.FileItemWriteRepo, services.ObjectStorage, meteringUseCase, services.EventPublisher, services.Logger)
+	fileController := drive_controller.NewFileController(fileUseCase, services.Config.Storage.TransferTimeout, services.Logger)
+
+	uploadUseCase := drive_use_case.NewUploadUseCase(services.FileItemReadRepo, services.FileItemWriteRepo, services.UploadReadRepo, services.UploadWriteRepo, services.ObjectStorage, meteringUseCase, services.EventPublisher, services.Config.Storage.Bucket, services.Config.Storage.PartSize, services.Config.Storage.MaxFileSize, services.Config.Storage.UploadTTL, services.Logger)
+	uploadController := drive_controller.NewUploadController(uploadUseCase, services.Config.Storage.MaxFormSize, services.Config.Storage.TransferTimeout, services.Logger)
+
 	return &ControllerServices{
-		UserController:    userController,
-		AddressController: addressController,
+		UserController:     userController,
+		CustomerController: customerController,
+		AddressController:  addressController,
+		PlanController:     planController,
+		CreditController:   creditController,
+		SmsController:      smsController,
+		EmailController:    emailController,
+		ArticleController:  articleController,
+		VisitController:    visitController,
+		ReviewController:   reviewController,
+		CommentController:  commentController,
+		PricingController:  pricingController,
+		BasketController:   basketController,
+		CheckoutController: checkoutController,
+		ShippingController: shippingController,
+		OrderController:    orderController,
+		ReturnController:   returnController,
+		PaymentController:  paymentController,
+		TemplateController: templateController,
+		InboxController:    inboxController,
+		FileController:     fileController,
+		UploadController:   uploadController,
+		MediaController:    mediaController,
 	}
 }
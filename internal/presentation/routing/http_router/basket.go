@@ -0,0 +1,10 @@
+package http_router
+
+func (r *Router) BasketRegister() {
+	r.publicBasket.GET("Get", r.ControllerServices.BasketController.GetBasket)
+	r.publicBasket.POST("AddItem", r.ControllerServices.BasketController.AddBasketItem)
+	r.publicBasket.PUT("UpdateItem", r.ControllerServices.BasketController.UpdateBasketItem)
+	r.publicBasket.DELETE("RemoveItem", r.ControllerServices.BasketController.RemoveBasketItem)
+	r.publicBasket.POST("ApplyDiscount", r.ControllerServices.BasketController.ApplyBasketDiscount)
+	r.publicBasket.POST("Merge", r.ControllerServices.BasketController.MergeBasket)
+}
@@ -1 +1,25 @@
 package http_router
+
+func (r *Router) ArticleRegister() {
+	r.article.POST("Create", r.ControllerServices.ArticleController.CreateArticle)
+	r.article.PUT("Update", r.ControllerServices.ArticleController.UpdateArticle)
+	r.article.POST("ChangeStatus", r.ControllerServices.ArticleController.ChangeArticleStatus)
+	r.article.DELETE("Delete/:id", r.ControllerServices.ArticleController.DeleteArticle)
+	r.article.GET("Get/:id", r.ControllerServices.ArticleController.GetArticle)
+	r.article.GET("GetAll", r.ControllerServices.ArticleController.GetAllArticles)
+	r.article.POST("Reindex/:site_id", r.ControllerServices.ArticleController.ReindexArticles)
+
+	r.publicArticle.GET("GetAll", r.ControllerServices.ArticleController.PublicGetAllArticles)
+	r.publicArticle.GET("Search", r.ControllerServices.ArticleController.PublicSearchArticles)
+	r.publicArticle.GET("Get/:site_id/:slug", r.ControllerServices.ArticleController.PublicGetArticle)
+}
+
+func (r *Router) CommentRegister() {
+	r.comment.GET("GetAll", r.ControllerServices.CommentController.GetAllComments)
+	r.comment.POST("Approve/:id", r.ControllerServices.CommentController.ApproveComment)
+	r.comment.POST("Spam/:id", r.ControllerServices.CommentController.MarkCommentSpam)
+	r.comment.DELETE("Delete/:id", r.ControllerServices.CommentController.DeleteComment)
+
+	r.publicComment.POST("Create", r.ControllerServices.CommentController.CreateComment)
+	r.publicComment.GET("GetAll", r.ControllerServices.CommentController.PublicGetAllComments)
+}
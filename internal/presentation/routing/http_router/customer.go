@@ -0,0 +1,7 @@
+package http_router
+
+func (r *Router) CustomerRegister() {
+	r.publicCustomer.POST("Register", r.ControllerServices.CustomerController.Register)
+	r.publicCustomer.POST("Login", r.ControllerServices.CustomerController.Login)
+	r.publicCustomer.POST("RefreshToken", r.ControllerServices.CustomerController.RefreshToken)
+}
@@ -0,0 +1,26 @@
+package http_router
+
+func (r *Router) DriveRegister() {
+	r.file.GET("Get/:id", r.ControllerServices.FileController.GetFile)
+	r.file.GET("Download/:id", r.ControllerServices.FileController.Download)
+	r.file.POST("Upload", r.ControllerServices.UploadController.Upload)
+	r.file.POST("Folder", r.ControllerServices.FileController.CreateFolder)
+	r.file.GET("List", r.ControllerServices.FileController.List)
+	r.file.POST("Move/:id", r.ControllerServices.FileController.Move)
+	r.file.POST("Copy/:id", r.ControllerServices.FileController.Copy)
+	r.file.POST("Rename/:id", r.ControllerServices.FileController.Rename)
+	r.file.DELETE("Delete/:id", r.ControllerServices.FileController.Trash)
+
+	r.trash.GET("List", r.ControllerServices.FileController.ListTrash)
+	r.trash.POST("Restore/:id", r.ControllerServices.FileController.Restore)
+	r.trash.DELETE("Purge/:id", r.ControllerServices.FileController.Purge)
+	r.trash.DELETE("Empty", r.ControllerServices.FileController.EmptyTrash)
+
+	r.upload.POST("Start", r.ControllerServices.UploadController.StartUpload)
+	r.upload.GET("Get/:id", r.ControllerServices.UploadController.GetUpload)
+	r.upload.PUT("Part/:id/:number", r.ControllerServices.UploadController.UploadPart)
+	r.upload.POST("Complete/:id", r.ControllerServices.UploadController.CompleteUpload)
+	r.upload.DELETE("Abort/:id", r.ControllerServices.UploadController.AbortUpload)
+
+	r.publicDrive.GET("*key", r.ControllerServices.MediaController.Get)
+}
@@ -0,0 +1,14 @@
+package http_router
+
+func (r *Router) NotificationRegister() {
+	r.template.GET("Catalog", r.ControllerServices.TemplateController.GetCatalog)
+	r.template.GET("GetAll", r.ControllerServices.TemplateController.GetAllTemplates)
+	r.template.PUT("Save", r.ControllerServices.TemplateController.SaveTemplate)
+	r.template.POST("Reset", r.ControllerServices.TemplateController.ResetTemplate)
+	r.template.POST("Preview", r.ControllerServices.TemplateController.PreviewTemplate)
+
+	r.inbox.GET("GetAll", r.ControllerServices.InboxController.GetNotifications)
+	r.inbox.GET("UnreadCount", r.ControllerServices.InboxController.GetUnreadCount)
+	r.inbox.POST("MarkRead", r.ControllerServices.InboxController.MarkRead)
+	r.inbox.GET("Stream", r.ControllerServices.InboxController.Stream)
+}
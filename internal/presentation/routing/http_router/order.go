@@ -0,0 +1,14 @@
+package http_router
+
+func (r *Router) OrderRegister() {
+	r.order.GET("GetAll", r.ControllerServices.OrderController.GetAllOrders)
+	r.order.GET("Get/:id", r.ControllerServices.OrderController.GetOrder)
+	r.order.POST("ChangeStatus", r.ControllerServices.OrderController.ChangeOrderStatus)
+
+	r.customerOrder.POST("Checkout", r.ControllerServices.CheckoutController.Checkout)
+	r.customerOrder.GET("ShippingOptions", r.ControllerServices.CheckoutController.ShippingOptions)
+	r.customerOrder.GET("GetAll", r.ControllerServices.OrderController.CustomerGetAllOrders)
+	r.customerOrder.GET("Get/:id", r.ControllerServices.OrderController.CustomerGetOrder)
+	r.customerOrder.POST("Cancel/:id", r.ControllerServices.OrderController.CancelOrder)
+	r.customerOrder.POST("ConfirmDelivery/:id", r.ControllerServices.OrderController.ConfirmOrderDelivery)
+}
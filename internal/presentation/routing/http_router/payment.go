@@ -0,0 +1,12 @@
+package http_router
+
+func (r *Router) PaymentRegister() {
+	r.publicPayment.GET("Gateways", r.ControllerServices.PaymentController.GetGateways)
+	r.publicPayment.GET("Verify/:id/:tracking_number", r.ControllerServices.PaymentController.VerifyPayment)
+	r.publicPayment.POST("Verify/:id/:tracking_number", r.ControllerServices.PaymentController.VerifyPayment)
+	if r.Config.Payment.VirtualGateway {
+		r.publicPayment.GET("Virtual", r.ControllerServices.PaymentController.VirtualGateway)
+	}
+
+	r.customerPayment.POST("Start", r.ControllerServices.PaymentController.StartPayment)
+}
@@ -0,0 +1,19 @@
+package http_router
+
+func (r *Router) ReviewRegister() {
+	r.customerReview.POST("Create", r.ControllerServices.ReviewController.CreateReview)
+	r.customerReview.PUT("Update", r.ControllerServices.ReviewController.UpdateReview)
+	r.customerReview.DELETE("Delete/:id", r.ControllerServices.ReviewController.DeleteReview)
+	r.customerReview.POST("Vote", r.ControllerServices.ReviewController.VoteReview)
+	r.customerReview.DELETE("Vote/:id", r.ControllerServices.ReviewController.RemoveReviewVote)
+
+	r.review.GET("GetAll", r.ControllerServices.ReviewController.GetAllReviews)
+	r.review.POST("Approve/:id", r.ControllerServices.ReviewController.ApproveReview)
+	r.review.DELETE("Reject/:id", r.ControllerServices.ReviewController.RejectReview)
+
+	r.publicReview.GET("GetAll", r.ControllerServices.ReviewController.PublicGetAllReviews)
+}
+
+func (r *Router) PricingRegister() {
+	r.publicPricing.POST("Quote", r.ControllerServices.PricingController.Quote)
+}
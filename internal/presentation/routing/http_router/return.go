@@ -0,0 +1,15 @@
+package http_router
+
+func (r *Router) ReturnRegister() {
+	r.returnItem.GET("GetAll", r.ControllerServices.ReturnController.GetAllReturns)
+	r.returnItem.POST("Approve", r.ControllerServices.ReturnController.ApproveReturn)
+	r.returnItem.POST("Reject", r.ControllerServices.ReturnController.RejectReturn)
+	r.returnItem.POST("RetryRefund/:id", r.ControllerServices.ReturnController.RetryReturnRefund)
+	r.returnItem.POST("ConfirmRefund/:id", r.ControllerServices.ReturnController.ConfirmReturnRefund)
+	r.returnItem.GET("Window/:site_id", r.ControllerServices.ReturnController.GetReturnWindow)
+	r.returnItem.PUT("Window", r.ControllerServices.ReturnController.UpdateReturnWindow)
+
+	r.customerReturn.POST("Request", r.ControllerServices.ReturnController.RequestReturn)
+	r.customerReturn.POST("Cancel/:id", r.ControllerServices.ReturnController.CancelReturn)
+	r.customerReturn.GET("GetAll", r.ControllerServices.ReturnController.CustomerGetAllReturns)
+}
@@ -10,7 +10,35 @@ type Router struct {
 	*routing.Services
 	ControllerServices *routing.ControllerServices
 	user               *gin.RouterGroup
+	publicCustomer     *gin.RouterGroup
 	address            *gin.RouterGroup
+	plan               *gin.RouterGroup
+	credit             *gin.RouterGroup
+	sms                *gin.RouterGroup
+	email              *gin.RouterGroup
+	article            *gin.RouterGroup
+	publicArticle      *gin.RouterGroup
+	publicVisit        *gin.RouterGroup
+	review             *gin.RouterGroup
+	customerReview     *gin.RouterGroup
+	publicReview       *gin.RouterGroup
+	comment            *gin.RouterGroup
+	publicComment      *gin.RouterGroup
+	publicPricing      *gin.RouterGroup
+	publicBasket       *gin.RouterGroup
+	customerOrder      *gin.RouterGroup
+	order              *gin.RouterGroup
+	returnItem         *gin.RouterGroup
+	customerReturn     *gin.RouterGroup
+	shipping           *gin.RouterGroup
+	publicPayment      *gin.RouterGroup
+	customerPayment    *gin.RouterGroup
+	template           *gin.RouterGroup
+	inbox              *gin.RouterGroup
+	file               *gin.RouterGroup
+	upload             *gin.RouterGroup
+	trash              *gin.RouterGroup
+	publicDrive        *gin.RouterGroup
 }
 
 func NewRouter(g *gin.Engine, services *routing.Services, controllerServices *routing.ControllerServices) *Router {
@@ -18,7 +46,35 @@ func NewRouter(g *gin.Engine, services *routing.Services, controllerServices *ro
 		Services:           services,
 		ControllerServices: controllerServices,
 		user:               g.Group("User", services.AuthMiddleware.Authenticate()),
+		publicCustomer:     g.Group("Public/Customer"),
 		address:            g.Group("Address", services.AuthMiddleware.Authenticate()),
+		plan:               g.Group("Plan", services.AuthMiddleware.Authenticate()),
+		credit:             g.Group("Credit", services.AuthMiddleware.Authenticate()),
+		sms:                g.Group("Sms", services.AuthMiddleware.Authenticate()),
+		email:              g.Group("Email", services.AuthMiddleware.Authenticate()),
+		article:            g.Group("Article", services.AuthMiddleware.Authenticate()),
+		publicArticle:      g.Group("Public/Article"),
+		publicVisit:        g.Group("Public/Visit"),
+		review:             g.Group("Review", services.AuthMiddleware.Authenticate()),
+		customerReview:     g.Group("Customer/Review", services.AuthMiddleware.Authenticate()),
+		publicReview:       g.Group("Public/Review"),
+		comment:            g.Group("Comment", services.AuthMiddleware.Authenticate()),
+		publicComment:      g.Group("Public/Comment", services.AuthMiddleware.Optional()),
+		publicPricing:      g.Group("Public/Pricing", services.AuthMiddleware.Optional()),
+		publicBasket:       g.Group("Public/Basket", services.AuthMiddleware.Optional()),
+		customerOrder:      g.Group("Customer/Order", services.AuthMiddleware.Authenticate()),
+		order:              g.Group("Order", services.AuthMiddleware.Authenticate()),
+		returnItem:         g.Group("Return", services.AuthMiddleware.Authenticate()),
+		customerReturn:     g.Group("Customer/Return", services.AuthMiddleware.Authenticate()),
+		shipping:           g.Group("Shipping", services.AuthMiddleware.Authenticate()),
+		publicPayment:      g.Group("Public/Payment"),
+		customerPayment:    g.Group("Customer/Payment", services.AuthMiddleware.Authenticate()),
+		template:           g.Group("Notification/Template", services.AuthMiddleware.Authenticate()),
+		inbox:              g.Group("Notification/Inbox", services.AuthMiddleware.Authenticate()),
+		file:               g.Group("Drive/File", services.AuthMiddleware.Authenticate()),
+		upload:             g.Group("Drive/Upload", services.AuthMiddleware.Authenticate()),
+		trash:              g.Group("Drive/Trash", services.AuthMiddleware.Authenticate()),
+		publicDrive:        g.Group("Public/Drive"),
 	}
 }
 
@@ -26,6 +82,23 @@ func Register(g *gin.Engine, controllerServices *routing.ControllerServices, ser
 	router := NewRouter(g, services, controllerServices)
 
 	router.UserRegister()
+	router.CustomerRegister()
 	router.AddressRegister()
+	router.PlanRegister()
+	router.CreditRegister()
+	router.SmsRegister()
+	router.EmailRegister()
+	router.ArticleRegister()
+	router.VisitRegister()
+	router.ReviewRegister()
+	router.CommentRegister()
+	router.PricingRegister()
+	router.BasketRegister()
+	router.OrderRegister()
+	router.ReturnRegister()
+	router.ShippingRegister()
+	router.PaymentRegister()
+	router.NotificationRegister()
+	router.DriveRegister()
 
 }
@@ -0,0 +1,8 @@
+package http_router
+
+func (r *Router) ShippingRegister() {
+	r.shipping.POST("Create", r.ControllerServices.ShippingController.CreateShippingMethod)
+	r.shipping.PUT("Update", r.ControllerServices.ShippingController.UpdateShippingMethod)
+	r.shipping.DELETE("Delete/:id", r.ControllerServices.ShippingController.DeleteShippingMethod)
+	r.shipping.GET("GetAll", r.ControllerServices.ShippingController.GetAllShippingMethods)
+}
@@ -11,3 +11,27 @@ func (r *Router) AddressRegister() {
 
 	r.address.POST("Create", r.ControllerServices.AddressController.CreateAddress, r.Services.AuthMiddleware.CheckPolicy())
 }
+
+func (r *Router) PlanRegister() {
+	r.plan.GET("GetAll", r.ControllerServices.PlanController.GetAllPlans)
+	r.plan.GET("Subscription", r.ControllerServices.PlanController.GetSubscription)
+	r.plan.GET("Quote/:id", r.ControllerServices.PlanController.QuotePlan)
+	r.plan.POST("Purchase", r.ControllerServices.PlanController.PurchasePlan)
+}
+
+func (r *Router) CreditRegister() {
+	r.credit.GET("UnitPrices", r.ControllerServices.CreditController.GetUnitPrices)
+	r.credit.GET("Balance", r.ControllerServices.CreditController.GetBalance)
+	r.credit.GET("Ledger", r.ControllerServices.CreditController.GetLedger)
+	r.credit.GET("Usage", r.ControllerServices.CreditController.GetUsage)
+	r.credit.GET("Quote", r.ControllerServices.CreditController.QuoteTopUp)
+	r.credit.POST("TopUp", r.ControllerServices.CreditController.TopUp)
+}
+
+func (r *Router) SmsRegister() {
+	r.sms.GET("Messages", r.ControllerServices.SmsController.GetMessages)
+}
+
+func (r *Router) EmailRegister() {
+	r.email.GET("Messages", r.ControllerServices.EmailController.GetMessages)
+}
@@ -0,0 +1,8 @@
+package http_router
+
+func (r *Router) VisitRegister() {
+	r.publicVisit.POST("Product/:site_id/:id", r.ControllerServices.VisitController.TrackProductVisit)
+	r.publicVisit.POST("Article/:site_id/:id", r.ControllerServices.VisitController.TrackArticleVisit)
+	r.publicVisit.GET("MostViewed/Product/:site_id", r.ControllerServices.VisitController.MostViewedProducts)
+	r.publicVisit.GET("MostViewed/Article/:site_id", r.ControllerServices.VisitController.MostViewedArticles)
+}
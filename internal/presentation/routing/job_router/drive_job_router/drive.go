@@ -0,0 +1,19 @@
+package drive_job_router
+
+import (
+	"site_builder_backend/configs"
+	"site_builder_backend/internal/adapters/job/drive_job"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/scheduler"
+)
+
+func DriveRegister(s *scheduler.Scheduler, cfg configs.Jobs, services *routing.Services) {
+	meteringUseCase := user_use_case.NewMeteringUseCase(services.CreditMeter, services.MeteringReadRepo, services.MeteringWriteRepo, services.Config.Metering.ReservationTTL, services.Config.Metering.CounterTTL, services.Logger)
+	uploadUseCase := drive_use_case.NewUploadUseCase(services.FileItemReadRepo, services.FileItemWriteRepo, services.UploadReadRepo, services.UploadWriteRepo, services.ObjectStorage, meteringUseCase, services.EventPublisher, services.Config.Storage.Bucket, services.Config.Storage.PartSize, services.Config.Storage.MaxFileSize, services.Config.Storage.UploadTTL, services.Logger)
+	job := drive_job.NewDriveJob(uploadUseCase, services.ImageUseCase)
+
+	s.Every("upload_expire", cfg.UploadExpiryInterval, job.ExpireUploads)
+	s.Every("image_requeue", cfg.ImageRequeueInterval, job.RequeueImages)
+}
@@ -0,0 +1,19 @@
+package job_router
+
+import (
+	"site_builder_backend/configs"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/internal/presentation/routing/job_router/drive_job_router"
+	"site_builder_backend/internal/presentation/routing/job_router/payment_job_router"
+	"site_builder_backend/internal/presentation/routing/job_router/plan_job_router"
+	"site_builder_backend/internal/presentation/routing/job_router/visit_job_router"
+	"site_builder_backend/pkg/scheduler"
+)
+
+// Register registers all periodic jobs
+func Register(s *scheduler.Scheduler, cfg configs.Jobs, services *routing.Services) {
+	visit_job_router.VisitRegister(s, cfg, services)
+	payment_job_router.PaymentRegister(s, cfg, services)
+	plan_job_router.PlanRegister(s, cfg, services)
+	drive_job_router.DriveRegister(s, cfg, services)
+}
@@ -0,0 +1,24 @@
+package payment_job_router
+
+import (
+	"site_builder_backend/configs"
+	"site_builder_backend/internal/adapters/job/payment_job"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/scheduler"
+)
+
+func PaymentRegister(s *scheduler.Scheduler, cfg configs.Jobs, services *routing.Services) {
+	orderUseCase := order_use_case.NewOrderUseCase(services.SiteReadRepo, services.OrderReadRepo, services.OrderWriteRepo, services.EventPublisher, services.Logger)
+	paymentGateways := payment_use_case.NewGatewayRegistry(services.PaymentGateways...)
+	useCase := payment_use_case.NewPaymentUseCase(services.PaymentReadRepo, services.PaymentWriteRepo, services.GatewayReadRepo, services.OrderReadRepo, orderUseCase, paymentGateways, services.EventPublisher, services.Config.Payment.CallbackBaseUrl, services.Logger)
+	meteringUseCase := user_use_case.NewMeteringUseCase(services.CreditMeter, services.MeteringReadRepo, services.MeteringWriteRepo, services.Config.Metering.ReservationTTL, services.Config.Metering.CounterTTL, services.Logger)
+	// Registers plan purchases and credit top-ups with the reconciler
+	user_use_case.NewPlanUseCase(services.PlanReadRepo, services.PlanWriteRepo, services.UserReadRepo, useCase, meteringUseCase, services.EventPublisher, services.Config.Plan.PaymentSiteId, services.Logger)
+	user_use_case.NewCreditUseCase(services.CreditReadRepo, services.CreditWriteRepo, services.UserReadRepo, useCase, meteringUseCase, services.Config.Plan.PaymentSiteId, services.Logger)
+	job := payment_job.NewPaymentJob(useCase, services.Config.Payment.ReconcileAfter)
+
+	s.Every("payment_reconcile", cfg.PaymentReconcileInterval, job.Reconcile)
+}
@@ -0,0 +1,23 @@
+package plan_job_router
+
+import (
+	"site_builder_backend/configs"
+	"site_builder_backend/internal/adapters/job/plan_job"
+	"site_builder_backend/internal/application/use_cases/order_use_case"
+	"site_builder_backend/internal/application/use_cases/payment_use_case"
+	"site_builder_backend/internal/application/use_cases/user_use_case"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/scheduler"
+)
+
+func PlanRegister(s *scheduler.Scheduler, cfg configs.Jobs, services *routing.Services) {
+	orderUseCase := order_use_case.NewOrderUseCase(services.SiteReadRepo, services.OrderReadRepo, services.OrderWriteRepo, services.EventPublisher, services.Logger)
+	paymentGateways := payment_use_case.NewGatewayRegistry(services.PaymentGateways...)
+	paymentUseCase := payment_use_case.NewPaymentUseCase(services.PaymentReadRepo, services.PaymentWriteRepo, services.GatewayReadRepo, services.OrderReadRepo, orderUseCase, paymentGateways, services.EventPublisher, services.Config.Payment.CallbackBaseUrl, services.Logger)
+	meteringUseCase := user_use_case.NewMeteringUseCase(services.CreditMeter, services.MeteringReadRepo, services.MeteringWriteRepo, services.Config.Metering.ReservationTTL, services.Config.Metering.CounterTTL, services.Logger)
+	useCase := user_use_case.NewPlanUseCase(services.PlanReadRepo, services.PlanWriteRepo, services.UserReadRepo, paymentUseCase, meteringUseCase, services.EventPublisher, services.Config.Plan.PaymentSiteId, services.Logger)
+	job := plan_job.NewPlanJob(useCase, services.Config.Plan.ReminderBefore)
+
+	s.Every("plan_expire", cfg.PlanExpiryInterval, job.Expire)
+	s.Every("plan_remind", cfg.PlanReminderInterval, job.Remind)
+}
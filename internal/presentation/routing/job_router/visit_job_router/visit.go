@@ -0,0 +1,16 @@
+package visit_job_router
+
+import (
+	"site_builder_backend/configs"
+	"site_builder_backend/internal/adapters/job/visit_job"
+	"site_builder_backend/internal/application/use_cases/visit_use_case"
+	"site_builder_backend/internal/presentation/routing"
+	"site_builder_backend/pkg/scheduler"
+)
+
+func VisitRegister(s *scheduler.Scheduler, cfg configs.Jobs, services *routing.Services) {
+	useCase := visit_use_case.NewVisitUseCase(services.VisitCounter, services.ProductReadRepo, services.ProductWriteRepo, services.ArticleReadRepo, services.ArticleWriteRepo, services.ImageUseCase, services.Logger)
+	job := visit_job.NewVisitJob(useCase)
+
+	s.Every("visit_flush", cfg.VisitFlushInterval, job.FlushVisits)
+}
@@ -1,16 +1,70 @@
 package routing
 
 import (
+	"context"
+	"net/http"
 	"site_builder_backend/configs"
+	"site_builder_backend/internal/application/use_cases/drive_use_case"
+	"site_builder_backend/internal/domain/order_entity"
 	"site_builder_backend/internal/infrastructures/impl/auth"
+	"site_builder_backend/internal/infrastructures/impl/cache/redis/credit_meter"
+	"site_builder_backend/internal/infrastructures/impl/cache/redis/guest_basket"
+	"site_builder_backend/internal/infrastructures/impl/cache/redis/notification_hub"
+	"site_builder_backend/internal/infrastructures/impl/cache/redis/rate_limiter"
+	"site_builder_backend/internal/infrastructures/impl/cache/redis/visit_counter"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/blog_repo"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/drive_repo"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/order_repo"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/payment_repo"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/product_repo"
+	"site_builder_backend/internal/infrastructures/impl/db/mysql/site_repo"
 	"site_builder_backend/internal/infrastructures/impl/db/mysql/user_repo"
+	"site_builder_backend/internal/infrastructures/impl/media/image_processor"
+	"site_builder_backend/internal/infrastructures/impl/message_publisher/rabbitmq/event_publisher"
+	"site_builder_backend/internal/infrastructures/impl/notification/email_template"
+	"site_builder_backend/internal/infrastructures/impl/notification/http_sms"
+	"site_builder_backend/internal/infrastructures/impl/notification/kavenegar"
+	"site_builder_backend/internal/infrastructures/impl/notification/log_sms"
+	"site_builder_backend/internal/infrastructures/impl/notification/smtp_mailer"
+	"site_builder_backend/internal/infrastructures/impl/payment/idpay"
+	"site_builder_backend/internal/infrastructures/impl/payment/parbad_virtual"
+	"site_builder_backend/internal/infrastructures/impl/payment/zarinpal"
+	"site_builder_backend/internal/infrastructures/impl/search_db/elasticsearch/article_search"
+	"site_builder_backend/internal/infrastructures/impl/shipping/post_courier"
+	"site_builder_backend/internal/infrastructures/impl/shipping/rule_courier"
+	"site_builder_backend/internal/infrastructures/impl/storage/local_storage"
+	"site_builder_backend/internal/infrastructures/impl/storage/s3_storage"
 	"site_builder_backend/internal/interfaces/auth_inter"
+	"site_builder_backend/internal/interfaces/cache/basket_cache_inter"
+	"site_builder_backend/internal/interfaces/cache/credit_meter_inter"
+	"site_builder_backend/internal/interfaces/cache/notification_hub_inter"
+	"site_builder_backend/internal/interfaces/cache/rate_limiter_inter"
+	"site_builder_backend/internal/interfaces/cache/visit_counter_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/blog_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/drive_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/order_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/payment_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/product_repo_inter"
+	"site_builder_backend/internal/interfaces/db/repositories/site_repo_inter"
 	"site_builder_backend/internal/interfaces/db/repositories/user_repo_inter"
+	"site_builder_backend/internal/interfaces/media/image_processor_inter"
+	"site_builder_backend/internal/interfaces/message_publisher/event_publisher_inter"
+	"site_builder_backend/internal/interfaces/notification/email_template_inter"
+	"site_builder_backend/internal/interfaces/notification/mailer_inter"
+	"site_builder_backend/internal/interfaces/notification/sms_provider_inter"
+	"site_builder_backend/internal/interfaces/payment/payment_gateway_inter"
+	"site_builder_backend/internal/interfaces/search_db/article_search_inter"
+	"site_builder_backend/internal/interfaces/shipping/courier_inter"
+	"site_builder_backend/internal/interfaces/storage/object_storage_inter"
 	"site_builder_backend/internal/presentation/middlewares"
 	"site_builder_backend/pkg/elasticsearch"
+	"site_builder_backend/pkg/imaging"
 	"site_builder_backend/pkg/logger"
 	"site_builder_backend/pkg/postgres"
+	"site_builder_backend/pkg/rabbitmq"
 	"site_builder_backend/pkg/redis"
+	"site_builder_backend/pkg/s3"
+	"site_builder_backend/pkg/secret"
 	"time"
 )
 
@@ -19,25 +73,104 @@ const (
 )
 
 type Services struct {
-	Logger           *logger.ZapLogger
-	ElasticClient    *elasticsearch.Elasticsearch
-	RedisClient      *redis.Redis
-	PostgresClient   *postgres.Postgres
-	AuthMiddleware   *middlewares.AuthMiddleware
-	jwtService       auth_inter.JWTService
-	UserReadRepo     user_repo_inter.UserReadRepository
-	UserWriteRepo    user_repo_inter.UserWriteRepository
-	AddressWriteRepo user_repo_inter.AddressWriteRepository
-	AddressReadRepo  user_repo_inter.AddressReadRepository
+	Config                  *configs.Config
+	Logger                  *logger.ZapLogger
+	ElasticClient           *elasticsearch.Elasticsearch
+	RedisClient             *redis.Redis
+	PostgresClient          *postgres.Postgres
+	AuthMiddleware          *middlewares.AuthMiddleware
+	jwtService              auth_inter.JWTService
+	UserReadRepo            user_repo_inter.UserReadRepository
+	UserWriteRepo           user_repo_inter.UserWriteRepository
+	CustomerReadRepo        user_repo_inter.CustomerReadRepository
+	CustomerWriteRepo       user_repo_inter.CustomerWriteRepository
+	AddressWriteRepo        user_repo_inter.AddressWriteRepository
+	AddressReadRepo         user_repo_inter.AddressReadRepository
+	PlanReadRepo            user_repo_inter.PlanReadRepository
+	PlanWriteRepo           user_repo_inter.PlanWriteRepository
+	CreditReadRepo          user_repo_inter.CreditReadRepository
+	CreditWriteRepo         user_repo_inter.CreditWriteRepository
+	MeteringReadRepo        user_repo_inter.MeteringReadRepository
+	MeteringWriteRepo       user_repo_inter.MeteringWriteRepository
+	SmsReadRepo             user_repo_inter.SmsReadRepository
+	SmsWriteRepo            user_repo_inter.SmsWriteRepository
+	EmailReadRepo           user_repo_inter.EmailReadRepository
+	EmailWriteRepo          user_repo_inter.EmailWriteRepository
+	NotificationReadRepo    user_repo_inter.NotificationReadRepository
+	NotificationWriteRepo   user_repo_inter.NotificationWriteRepository
+	SiteReadRepo            site_repo_inter.SiteReadRepository
+	SettingsReadRepo        site_repo_inter.SettingsReadRepository
+	SettingsWriteRepo       site_repo_inter.SettingsWriteRepository
+	TemplateReadRepo        site_repo_inter.NotificationTemplateReadRepository
+	TemplateWriteRepo       site_repo_inter.NotificationTemplateWriteRepository
+	ArticleReadRepo         blog_repo_inter.ArticleReadRepository
+	ArticleWriteRepo        blog_repo_inter.ArticleWriteRepository
+	ArticleCommentReadRepo  blog_repo_inter.ArticleCommentReadRepository
+	ArticleCommentWriteRepo blog_repo_inter.ArticleCommentWriteRepository
+	ProductReadRepo         product_repo_inter.ProductReadRepository
+	ProductWriteRepo        product_repo_inter.ProductWriteRepository
+	ProductReviewReadRepo   product_repo_inter.ProductReviewReadRepository
+	ProductReviewWriteRepo  product_repo_inter.ProductReviewWriteRepository
+	ProductVariantReadRepo  product_repo_inter.ProductVariantReadRepository
+	DiscountReadRepo        product_repo_inter.DiscountReadRepository
+	DiscountWriteRepo       product_repo_inter.DiscountWriteRepository
+	CouponReadRepo          product_repo_inter.CouponReadRepository
+	OrderReadRepo           order_repo_inter.OrderReadRepository
+	OrderWriteRepo          order_repo_inter.OrderWriteRepository
+	ReturnItemReadRepo      order_repo_inter.ReturnItemReadRepository
+	ReturnItemWriteRepo     order_repo_inter.ReturnItemWriteRepository
+	BasketReadRepo          order_repo_inter.BasketReadRepository
+	BasketWriteRepo         order_repo_inter.BasketWriteRepository
+	ShippingMethodReadRepo  order_repo_inter.ShippingMethodReadRepository
+	ShippingMethodWriteRepo order_repo_inter.ShippingMethodWriteRepository
+	PaymentReadRepo         payment_repo_inter.PaymentReadRepository
+	PaymentWriteRepo        payment_repo_inter.PaymentWriteRepository
+	GatewayReadRepo         payment_repo_inter.GatewayReadRepository
+	FileItemReadRepo        drive_repo_inter.FileItemReadRepository
+	FileItemWriteRepo       drive_repo_inter.FileItemWriteRepository
+	UploadReadRepo          drive_repo_inter.UploadReadRepository
+	UploadWriteRepo         drive_repo_inter.UploadWriteRepository
+	//Search injection
+	ArticleSearch article_search_inter.ArticleSearch
+	//Cache injection
+	VisitCounter     visit_counter_inter.VisitCounter
+	RateLimiter      rate_limiter_inter.RateLimiter
+	GuestBasketStore basket_cache_inter.GuestBasketStore
+	CreditMeter      credit_meter_inter.CreditMeter
+	NotificationHub  notification_hub_inter.NotificationHub
+	//Message publisher injection
+	EventPublisher event_publisher_inter.EventPublisher
+	//Courier providers by the name shipping methods refer to them with
+	CourierProviders map[string]courier_inter.CourierProvider
+	//Payment gateway injection
+	PaymentGateways []payment_gateway_inter.PaymentGateway
+	//Notification injection
+	SmsProvider   sms_provider_inter.SmsProvider
+	Mailer        mailer_inter.Mailer
+	EmailRenderer email_template_inter.EmailRenderer
+	PlatformSmtp  mailer_inter.SmtpAccount
+	//Storage injection
+	ObjectStorage object_storage_inter.ObjectStorage
+	//Media injection
+	ImageProcessor image_processor_inter.ImageProcessor
+	// ImageUseCase is shared by the controllers, the consumers and the jobs that link or render images
+	ImageUseCase *drive_use_case.ImageUseCase
 }
 
-func NewServiceRegistration(cfg *configs.Config, l *logger.ZapLogger) *Services {
+func NewServiceRegistration(cfg *configs.Config, l *logger.ZapLogger, rmqClient *rabbitmq.Client) *Services {
 	jwtService := auth.NewJWTService(cfg)
 	var pgClient *postgres.Postgres
 	var redisClient *redis.Redis
 	var esClient *elasticsearch.Elasticsearch
 	var err error
 
+	// Credentials are sealed at rest, the serializer has to be in place before the first query
+	keyring, err := secret.Load(cfg.Secret.MasterKeys, cfg.Secret.KeyVersion)
+	if err != nil {
+		l.Fatal("app - Run - secret.Load: %v", err)
+	}
+	secret.RegisterSerializer(keyring)
+
 	// Initialize PostgreSQL with GORM
 	pgClient, err = postgres.New(cfg.PG.URL, l, postgres.MaxPoolSize(cfg.PG.PoolMax))
 	if err != nil {
@@ -76,12 +209,149 @@ func NewServiceRegistration(cfg *configs.Config, l *logger.ZapLogger) *Services
 
 	userReadRepo := user_repo.NewUserReadRepository(pgClient.DB, l)
 	userWriteRepo := user_repo.NewUserWriteRepository(pgClient.DB, l)
+	customerReadRepo := user_repo.NewCustomerReadRepository(pgClient.DB, l)
+	customerWriteRepo := user_repo.NewCustomerWriteRepository(pgClient.DB, l)
 
 	addressReadRepo := user_repo.NewAddressReadRepository(pgClient.DB, l)
 	addressWriteRepo := user_repo.NewAddressWriteRepository(pgClient.DB, l)
+	planReadRepo := user_repo.NewPlanReadRepository(pgClient.DB, l)
+	planWriteRepo := user_repo.NewPlanWriteRepository(pgClient.DB, l)
+	creditReadRepo := user_repo.NewCreditReadRepository(pgClient.DB, l)
+	creditWriteRepo := user_repo.NewCreditWriteRepository(pgClient.DB, l)
+	meteringReadRepo := user_repo.NewMeteringReadRepository(pgClient.DB, l)
+	meteringWriteRepo := user_repo.NewMeteringWriteRepository(pgClient.DB, l)
+	smsReadRepo := user_repo.NewSmsReadRepository(pgClient.DB, l)
+	smsWriteRepo := user_repo.NewSmsWriteRepository(pgClient.DB, l)
+	emailReadRepo := user_repo.NewEmailReadRepository(pgClient.DB, l)
+	emailWriteRepo := user_repo.NewEmailWriteRepository(pgClient.DB, l)
+	notificationReadRepo := user_repo.NewNotificationReadRepository(pgClient.DB, l)
+	notificationWriteRepo := user_repo.NewNotificationWriteRepository(pgClient.DB, l)
+
+	siteReadRepo := site_repo.NewSiteReadRepository(pgClient.DB, l)
+	settingsReadRepo := site_repo.NewSettingsReadRepository(pgClient.DB, l)
+	settingsWriteRepo := site_repo.NewSettingsWriteRepository(pgClient.DB, l)
+	templateReadRepo := site_repo.NewNotificationTemplateReadRepository(pgClient.DB, l)
+	templateWriteRepo := site_repo.NewNotificationTemplateWriteRepository(pgClient.DB, l)
+
+	articleReadRepo := blog_repo.NewArticleReadRepository(pgClient.DB, l)
+	articleWriteRepo := blog_repo.NewArticleWriteRepository(pgClient.DB, l)
+	articleCommentReadRepo := blog_repo.NewArticleCommentReadRepository(pgClient.DB, l)
+	articleCommentWriteRepo := blog_repo.NewArticleCommentWriteRepository(pgClient.DB, l)
+
+	productReadRepo := product_repo.NewProductReadRepository(pgClient.DB, l)
+	productWriteRepo := product_repo.NewProductWriteRepository(pgClient.DB, l)
+	productReviewReadRepo := product_repo.NewProductReviewReadRepository(pgClient.DB, l)
+	productReviewWriteRepo := product_repo.NewProductReviewWriteRepository(pgClient.DB, l)
+	productVariantReadRepo := product_repo.NewProductVariantReadRepository(pgClient.DB, l)
+	discountReadRepo := product_repo.NewDiscountReadRepository(pgClient.DB, l)
+	discountWriteRepo := product_repo.NewDiscountWriteRepository(pgClient.DB, l)
+	couponReadRepo := product_repo.NewCouponReadRepository(pgClient.DB, l)
+
+	orderReadRepo := order_repo.NewOrderReadRepository(pgClient.DB, l)
+	orderWriteRepo := order_repo.NewOrderWriteRepository(pgClient.DB, l)
+	returnItemReadRepo := order_repo.NewReturnItemReadRepository(pgClient.DB, l)
+	returnItemWriteRepo := order_repo.NewReturnItemWriteRepository(pgClient.DB, l)
+	shippingMethodReadRepo := order_repo.NewShippingMethodReadRepository(pgClient.DB, l)
+	shippingMethodWriteRepo := order_repo.NewShippingMethodWriteRepository(pgClient.DB, l)
+	paymentReadRepo := payment_repo.NewPaymentReadRepository(pgClient.DB, l)
+	paymentWriteRepo := payment_repo.NewPaymentWriteRepository(pgClient.DB, l)
+	gatewayReadRepo := payment_repo.NewGatewayReadRepository(pgClient.DB, l)
+	basketReadRepo := order_repo.NewBasketReadRepository(pgClient.DB, l)
+	basketWriteRepo := order_repo.NewBasketWriteRepository(pgClient.DB, l)
+
+	fileItemReadRepo := drive_repo.NewFileItemReadRepository(pgClient.DB, l)
+	fileItemWriteRepo := drive_repo.NewFileItemWriteRepository(pgClient.DB, l)
+	uploadReadRepo := drive_repo.NewUploadReadRepository(pgClient.DB, l)
+	uploadWriteRepo := drive_repo.NewUploadWriteRepository(pgClient.DB, l)
+
+	articleSearch := article_search.NewArticleSearch(esClient, l)
+	if err := articleSearch.EnsureIndex(context.Background()); err != nil {
+		l.Warn("app - Run - articleSearch.EnsureIndex: %v", err)
+	}
+
+	visitCounter := visit_counter.NewVisitCounter(redisClient, l)
+	rateLimiter := rate_limiter.NewRateLimiter(redisClient, l)
+	guestBasketStore := guest_basket.NewGuestBasketStore(redisClient, cfg.Basket.GuestTTL, l)
+	creditMeter := credit_meter.NewCreditMeter(redisClient, l)
+	notificationHub := notification_hub.NewNotificationHub(redisClient, l)
+
+	eventPublisher := event_publisher.NewEventPublisher(rmqClient, l)
+
+	// Carrier integrations register here next to the rule based providers
+	courierProviders := map[string]courier_inter.CourierProvider{
+		order_entity.ShippingProviderFlat:     rule_courier.NewFlatProvider(),
+		order_entity.ShippingProviderWeight:   rule_courier.NewWeightProvider(),
+		order_entity.ShippingProviderProvince: rule_courier.NewProvinceProvider(),
+	}
+	if cfg.Shipping.PostUrl != "" {
+		courierClient := &http.Client{Timeout: cfg.Shipping.Timeout}
+		courierProviders[order_entity.ShippingProviderPost] = post_courier.NewProvider(courierClient, cfg.Shipping.PostUrl, cfg.Shipping.PostApiKey, l)
+	}
+
+	gatewayClient := &http.Client{Timeout: cfg.Payment.GatewayTimeout}
+	paymentGateways := []payment_gateway_inter.PaymentGateway{
+		zarinpal.NewGateway(gatewayClient, zarinpal.DefaultEndpoints, l),
+		idpay.NewGateway(gatewayClient, idpay.DefaultEndpoints, l),
+	}
+	if cfg.Payment.VirtualGateway {
+		paymentGateways = append(paymentGateways, parbad_virtual.NewGateway(cfg.Payment.CallbackBaseUrl+"/Public/Payment/Virtual"))
+	}
+
+	var smsProvider sms_provider_inter.SmsProvider
+	smsClient := &http.Client{Timeout: cfg.Sms.Timeout}
+	switch cfg.Sms.Provider {
+	case "kavenegar":
+		smsProvider = kavenegar.NewProvider(smsClient, kavenegar.DefaultApi, cfg.Sms.KavenegarApiKey, cfg.Sms.Sender, l)
+	case "http":
+		smsProvider = http_sms.NewProvider(smsClient, cfg.Sms.HttpUrl, cfg.Sms.HttpToken, cfg.Sms.Sender, l)
+	case "log":
+		smsProvider = log_sms.NewProvider(cfg.Sms.LogFile, l)
+	default:
+		l.Fatal("app - Run - unknown SMS_PROVIDER %q", cfg.Sms.Provider)
+	}
+
+	mailer := smtp_mailer.NewMailer(cfg.Email.Timeout, l)
+	emailRenderer, err := email_template.NewRenderer()
+	if err != nil {
+		l.Fatal("app - Run - email_template.NewRenderer: %v", err)
+	}
+
+	var objectStorage object_storage_inter.ObjectStorage
+	switch cfg.Storage.Backend {
+	case "s3":
+		s3Client, err := s3.New(cfg.Storage.S3Endpoint, cfg.Storage.S3Region, cfg.Storage.S3AccessKey, cfg.Storage.S3SecretKey,
+			s3.PathStyle(cfg.Storage.S3PathStyle),
+			s3.HTTPClient(&http.Client{Timeout: cfg.Storage.TransferTimeout}))
+		if err != nil {
+			l.Fatal("app - Run - s3.New: %v", err)
+		}
+		objectStorage = s3_storage.NewStorage(s3Client, l)
+	case "local":
+		objectStorage = local_storage.NewStorage(cfg.Storage.LocalPath, l)
+	default:
+		l.Fatal("app - Run - unknown STORAGE_BACKEND %q", cfg.Storage.Backend)
+	}
+	ensureCtx, cancel := context.WithTimeout(context.Background(), _defaultConnectTimeout)
+	if err := objectStorage.EnsureBucket(ensureCtx, cfg.Storage.Bucket); err != nil {
+		l.Warn("app - Run - objectStorage.EnsureBucket: %v", err)
+	}
+	cancel()
+
+	var imageEncoder imaging.Encoder = imaging.NewJPEGEncoder(cfg.Image.Quality)
+	if cfg.Image.Encoder == "webp" {
+		webpEncoder, err := imaging.NewWebPEncoder(cfg.Image.CwebpPath, cfg.Image.Quality)
+		if err != nil {
+			l.Warn("app - Run - imaging.NewWebPEncoder, falling back to JPEG: %v", err)
+		} else {
+			imageEncoder = webpEncoder
+		}
+	}
+	imageProcessor := image_processor.NewProcessor(imageEncoder, cfg.Image.MaxPixels, l)
+	imageUseCase := drive_use_case.NewImageUseCase(fileItemReadRepo, fileItemWriteRepo, objectStorage, imageProcessor, eventPublisher, cfg.Image.Widths, cfg.Image.ThumbnailSize, cfg.Storage.Bucket, cfg.Storage.PublicUrl, cfg.Image.RequeueAfter, l)
 
 	return &Services{
 		//System Injection
+		Config:         cfg,
 		Logger:         l,
 		ElasticClient:  esClient,
 		RedisClient:    redisClient,
@@ -90,9 +360,86 @@ func NewServiceRegistration(cfg *configs.Config, l *logger.ZapLogger) *Services
 		jwtService:     jwtService,
 		AuthMiddleware: middlewares.NewAuthMiddleware(jwtService),
 		//Repository injection
-		UserReadRepo:     userReadRepo,
-		UserWriteRepo:    userWriteRepo,
-		AddressReadRepo:  addressReadRepo,
-		AddressWriteRepo: addressWriteRepo,
+		UserReadRepo:            userReadRepo,
+		UserWriteRepo:           userWriteRepo,
+		CustomerReadRepo:        customerReadRepo,
+		CustomerWriteRepo:       customerWriteRepo,
+		AddressReadRepo:         addressReadRepo,
+		AddressWriteRepo:        addressWriteRepo,
+		PlanReadRepo:            planReadRepo,
+		PlanWriteRepo:           planWriteRepo,
+		CreditReadRepo:          creditReadRepo,
+		CreditWriteRepo:         creditWriteRepo,
+		MeteringReadRepo:        meteringReadRepo,
+		MeteringWriteRepo:       meteringWriteRepo,
+		SmsReadRepo:             smsReadRepo,
+		SmsWriteRepo:            smsWriteRepo,
+		EmailReadRepo:           emailReadRepo,
+		EmailWriteRepo:          emailWriteRepo,
+		NotificationReadRepo:    notificationReadRepo,
+		NotificationWriteRepo:   notificationWriteRepo,
+		SiteReadRepo:            siteReadRepo,
+		SettingsReadRepo:        settingsReadRepo,
+		SettingsWriteRepo:       settingsWriteRepo,
+		TemplateReadRepo:        templateReadRepo,
+		TemplateWriteRepo:       templateWriteRepo,
+		ArticleReadRepo:         articleReadRepo,
+		ArticleWriteRepo:        articleWriteRepo,
+		ArticleCommentReadRepo:  articleCommentReadRepo,
+		ArticleCommentWriteRepo: articleCommentWriteRepo,
+		ProductReadRepo:         productReadRepo,
+		ProductWriteRepo:        productWriteRepo,
+		ProductReviewReadRepo:   productReviewReadRepo,
+		ProductReviewWriteRepo:  productReviewWriteRepo,
+		ProductVariantReadRepo:  productVariantReadRepo,
+		DiscountReadRepo:        discountReadRepo,
+		DiscountWriteRepo:       discountWriteRepo,
+		CouponReadRepo:          couponReadRepo,
+		OrderReadRepo:           orderReadRepo,
+		OrderWriteRepo:          orderWriteRepo,
+		ReturnItemReadRepo:      returnItemReadRepo,
+		ReturnItemWriteRepo:     returnItemWriteRepo,
+		BasketReadRepo:          basketReadRepo,
+		BasketWriteRepo:         basketWriteRepo,
+		ShippingMethodReadRepo:  shippingMethodReadRepo,
+		ShippingMethodWriteRepo: shippingMethodWriteRepo,
+		PaymentReadRepo:         paymentReadRepo,
+		PaymentWriteRepo:        paymentWriteRepo,
+		GatewayReadRepo:         gatewayReadRepo,
+		FileItemReadRepo:        fileItemReadRepo,
+		FileItemWriteRepo:       fileItemWriteRepo,
+		UploadReadRepo:          uploadReadRepo,
+		UploadWriteRepo:         uploadWriteRepo,
+		//Search injection
+		ArticleSearch: articleSearch,
+		//Cache injection
+		VisitCounter:     visitCounter,
+		RateLimiter:      rateLimiter,
+		GuestBasketStore: guestBasketStore,
+		CreditMeter:      creditMeter,
+		NotificationHub:  notificationHub,
+		//Message publisher injection
+		EventPublisher: eventPublisher,
+		//Courier injection
+		CourierProviders: courierProviders,
+		//Payment gateway injection
+		PaymentGateways: paymentGateways,
+		//Notification injection
+		SmsProvider:   smsProvider,
+		Mailer:        mailer,
+		EmailRenderer: emailRenderer,
+		PlatformSmtp: mailer_inter.SmtpAccount{
+			Host:      cfg.Email.SmtpHost,
+			Port:      cfg.Email.SmtpPort,
+			Username:  cfg.Email.SmtpUsername,
+			Password:  cfg.Email.SmtpPassword,
+			EnableSsl: cfg.Email.SmtpEnableSsl,
+			From:      cfg.Email.From,
+		},
+		//Storage injection
+		ObjectStorage: objectStorage,
+		//Media injection
+		ImageProcessor: imageProcessor,
+		ImageUseCase:   imageUseCase,
 	}
 }
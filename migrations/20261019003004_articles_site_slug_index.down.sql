@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "Blog"."IX_Articles_SiteId_Slug";
@@ -0,0 +1,4 @@
+-- Slugs are unique among the live articles of a site, deleted articles keep theirs
+CREATE UNIQUE INDEX IF NOT EXISTS "IX_Articles_SiteId_Slug"
+    ON "Blog"."Articles" ("SiteId", "Slug")
+    WHERE "IsDeleted" = false;
@@ -0,0 +1,115 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// Encoder writes images in one format
+type Encoder interface {
+	Encode(ctx context.Context, w io.Writer, img image.Image) error
+	// MimeType is the type of the images written
+	MimeType() string
+	// Ext is the file extension of the images written, with its dot
+	Ext() string
+}
+
+// JPEGEncoder writes JPEG images, transparent pixels are laid on white
+type JPEGEncoder struct {
+	quality int
+}
+
+func NewJPEGEncoder(quality int) *JPEGEncoder {
+	return &JPEGEncoder{quality: quality}
+}
+
+func (e *JPEGEncoder) Encode(ctx context.Context, w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+	return jpeg.Encode(w, flat, &jpeg.Options{Quality: e.quality})
+}
+
+func (e *JPEGEncoder) MimeType() string {
+	return "image/jpeg"
+}
+
+func (e *JPEGEncoder) Ext() string {
+	return ".jpg"
+}
+
+// WebPEncoder writes lossy WebP images with the cwebp tool of libwebp, Go has no WebP encoder of its own
+type WebPEncoder struct {
+	path    string
+	quality int
+}
+
+// NewWebPEncoder runs the cwebp found at path, a name is looked up in PATH
+func NewWebPEncoder(path string, quality int) (*WebPEncoder, error) {
+	found, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("imaging - NewWebPEncoder: %w", err)
+	}
+	return &WebPEncoder{path: found, quality: quality}, nil
+}
+
+func (e *WebPEncoder) Encode(ctx context.Context, w io.Writer, img image.Image) error {
+	dir, err := os.MkdirTemp("", "cwebp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "in.png"), filepath.Join(dir, "out.webp")
+	file, err := os.Create(in)
+	if err != nil {
+		return err
+	}
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.path, "-quiet", "-q", strconv.Itoa(e.quality), "-metadata", "none", in, "-o", out)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("imaging - WebPEncoder - Encode: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	webp, err := os.Open(out)
+	if err != nil {
+		return err
+	}
+	defer webp.Close()
+	_, err = io.Copy(w, webp)
+	return err
+}
+
+func (e *WebPEncoder) MimeType() string {
+	return "image/webp"
+}
+
+func (e *WebPEncoder) Ext() string {
+	return ".webp"
+}
+
+var (
+	_ Encoder = (*JPEGEncoder)(nil)
+	_ Encoder = (*WebPEncoder)(nil)
+)
@@ -0,0 +1,195 @@
+// Package imaging decodes JPEG, PNG and GIF images, turns photos upright from their EXIF orientation and
+// scales them down. Images are worked on as RGBA, the metadata of the source is never carried over.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+var (
+	// ErrUnsupported is returned for data that is not a JPEG, PNG or GIF image
+	ErrUnsupported = errors.New("imaging: unsupported image format")
+	// ErrTooLarge is returned for images of more pixels than allowed, before they are decoded
+	ErrTooLarge = errors.New("imaging: image is too large")
+)
+
+// Decode reads an image of at most maxPixels pixels, upright
+func Decode(r io.Reader, maxPixels int) (*image.RGBA, error) {
+	// Compressed images are smaller than their pixels, but for a few bytes of headers
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxPixels)*4+1<<20))
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	rgba := toRGBA(img)
+	if format == "jpeg" {
+		rgba = orient(rgba, orientation(data))
+	}
+	return rgba, nil
+}
+
+// Fit scales the image down to width, keeping its proportions. Narrower images are returned as they are.
+func Fit(img *image.RGBA, width int) *image.RGBA {
+	bounds := img.Bounds()
+	if width >= bounds.Dx() {
+		return img
+	}
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	return resize(img, width, height)
+}
+
+// Fill crops the middle of the image to the proportions of width x height, then scales it down to them.
+// Smaller images are cropped only.
+func Fill(img *image.RGBA, width int, height int) *image.RGBA {
+	bounds := img.Bounds()
+	cropW, cropH := bounds.Dx(), bounds.Dx()*height/width
+	if cropH > bounds.Dy() {
+		cropW, cropH = bounds.Dy()*width/height, bounds.Dy()
+	}
+	x := bounds.Min.X + (bounds.Dx()-cropW)/2
+	y := bounds.Min.Y + (bounds.Dy()-cropH)/2
+	cropped := img.SubImage(image.Rect(x, y, x+cropW, y+cropH)).(*image.RGBA)
+	if width >= cropW {
+		return cropped
+	}
+	return resize(cropped, width, height)
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Bounds().Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+// orient turns an image stored with the EXIF orientation upright
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			s := img.PixOffset(img.Rect.Min.X+sx, img.Rect.Min.Y+sy)
+			d := dst.PixOffset(x, y)
+			copy(dst.Pix[d:d+4], img.Pix[s:s+4])
+		}
+	}
+	return dst
+}
+
+// orientation reads the EXIF orientation of a JPEG file, 1, upright, when it has none
+func orientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || marker >= 0xD0 && marker <= 0xD7 || marker == 0x01 || marker == 0xFF {
+			i += 2
+			if marker == 0xFF {
+				i--
+			}
+			continue
+		}
+		// Start of scan, the metadata comes before it
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag of the first IFD of the TIFF header of EXIF data
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		// The orientation is a SHORT, stored in the first bytes of the value
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 1
+}
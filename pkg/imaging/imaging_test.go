@@ -0,0 +1,151 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// letters is a 3 x 2 image whose pixels are told apart by their red value
+//
+//	A B C
+//	D E F
+func letters() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i, letter := range "ABCDEF" {
+		img.Set(i%3, i/3, color.RGBA{R: uint8(letter), A: 255})
+	}
+	return img
+}
+
+// rows reads the image back as the letters of its rows
+func rows(img *image.RGBA) []string {
+	bounds := img.Bounds()
+	var all []string
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := ""
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			row += string(rune(img.RGBAAt(x, y).R))
+		}
+		all = append(all, row)
+	}
+	return all
+}
+
+// exif is the APP1 payload of an EXIF block holding only the orientation
+func exif(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	return append([]byte("Exif\x00\x00"), tiff...)
+}
+
+// withExif puts the EXIF block right after the start of image of a JPEG file
+func withExif(jpegData []byte, payload []byte) []byte {
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	data := append([]byte{}, jpegData[:2]...)
+	data = append(data, segment...)
+	data = append(data, payload...)
+	return append(data, jpegData[2:]...)
+}
+
+func TestOrientTurnsEveryOrientationUpright(t *testing.T) {
+	cases := []struct {
+		orientation int
+		want        []string
+	}{
+		{1, []string{"ABC", "DEF"}},
+		{2, []string{"CBA", "FED"}},
+		{3, []string{"FED", "CBA"}},
+		{4, []string{"DEF", "ABC"}},
+		{5, []string{"AD", "BE", "CF"}},
+		{6, []string{"DA", "EB", "FC"}},
+		{7, []string{"FC", "EB", "DA"}},
+		{8, []string{"CF", "BE", "AD"}},
+		// Values out of the range leave the image as it is
+		{0, []string{"ABC", "DEF"}},
+		{9, []string{"ABC", "DEF"}},
+	}
+	for _, c := range cases {
+		got := rows(orient(letters(), c.orientation))
+		if len(got) != len(c.want) {
+			t.Errorf("orientation %d: rows %q, want %q", c.orientation, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("orientation %d: rows %q, want %q", c.orientation, got, c.want)
+				break
+			}
+		}
+	}
+
+	// Sub images are read from their own origin
+	sub := letters().SubImage(image.Rect(1, 0, 3, 2)).(*image.RGBA)
+	if got := rows(orient(sub, 6)); len(got) != 2 || got[0] != "EB" || got[1] != "FC" {
+		t.Errorf("orientation 6 of a sub image: rows %q", got)
+	}
+}
+
+func TestOrientationReadsTheExifTag(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, letters(), nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for want := 1; want <= 8; want++ {
+			data := withExif(encoded.Bytes(), exif(order, uint16(want)))
+			if got := orientation(data); got != want {
+				t.Errorf("%v orientation %d: read %d", order, want, got)
+			}
+		}
+	}
+
+	if got := orientation(encoded.Bytes()); got != 1 {
+		t.Errorf("without EXIF: read %d, want 1", got)
+	}
+	truncated := withExif(encoded.Bytes(), exif(binary.BigEndian, 6))[:20]
+	if got := orientation(truncated); got != 1 {
+		t.Errorf("truncated EXIF: read %d, want 1", got)
+	}
+	if got := orientation([]byte("not a jpeg")); got != 1 {
+		t.Errorf("not a JPEG: read %d, want 1", got)
+	}
+}
+
+func TestDecodeTurnsPhotosUpright(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 40, 20)), nil); err != nil {
+		t.Fatal(err)
+	}
+	for orientation, want := range map[int]image.Point{1: {40, 20}, 3: {40, 20}, 6: {20, 40}, 8: {20, 40}} {
+		img, err := Decode(bytes.NewReader(withExif(encoded.Bytes(), exif(binary.BigEndian, uint16(orientation)))), 10000)
+		if err != nil {
+			t.Fatalf("orientation %d: %v", orientation, err)
+		}
+		if got := img.Bounds().Size(); got != want {
+			t.Errorf("orientation %d: size %v, want %v", orientation, got, want)
+		}
+	}
+
+	if _, err := Decode(bytes.NewReader(encoded.Bytes()), 799); err != ErrTooLarge {
+		t.Errorf("800 pixels over a limit of 799: err = %v, want ErrTooLarge", err)
+	}
+	if _, err := Decode(bytes.NewReader([]byte("plain text")), 10000); err != ErrUnsupported {
+		t.Errorf("text: err = %v, want ErrUnsupported", err)
+	}
+}
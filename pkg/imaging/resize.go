@@ -0,0 +1,91 @@
+package imaging
+
+import "image"
+
+// contribution is the share of a source pixel in a scaled pixel
+type contribution struct {
+	index  int
+	weight float64
+}
+
+// resize scales the image down to width x height by averaging the source pixels each scaled pixel
+// covers, which keeps photos smooth however much they shrink. The image is premultiplied, so transparent
+// pixels do not darken their neighbours.
+func resize(src *image.RGBA, width int, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	columns := contributions(srcW, width)
+	rows := contributions(srcH, height)
+
+	// Source rows are read once, in order, and added to the few scaled rows they are part of
+	parts := make([][]contribution, srcH)
+	for y, weights := range rows {
+		for _, c := range weights {
+			parts[c.index] = append(parts[c.index], contribution{index: y, weight: c.weight})
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	line := make([]float64, width*4)
+	sums := make(map[int][]float64, 2)
+	for sy := 0; sy < srcH; sy++ {
+		offset := src.PixOffset(bounds.Min.X, bounds.Min.Y+sy)
+		for x, weights := range columns {
+			var r, g, b, a float64
+			for _, c := range weights {
+				p := offset + c.index*4
+				r += float64(src.Pix[p]) * c.weight
+				g += float64(src.Pix[p+1]) * c.weight
+				b += float64(src.Pix[p+2]) * c.weight
+				a += float64(src.Pix[p+3]) * c.weight
+			}
+			line[x*4], line[x*4+1], line[x*4+2], line[x*4+3] = r, g, b, a
+		}
+
+		for _, part := range parts[sy] {
+			sum := sums[part.index]
+			if sum == nil {
+				sum = make([]float64, width*4)
+				sums[part.index] = sum
+			}
+			for i, v := range line {
+				sum[i] += v * part.weight
+			}
+			if weights := rows[part.index]; weights[len(weights)-1].index == sy {
+				d := dst.PixOffset(0, part.index)
+				for i, v := range sum {
+					dst.Pix[d+i] = clamp(v)
+				}
+				delete(sums, part.index)
+			}
+		}
+	}
+	return dst
+}
+
+// contributions returns, for every one of the size scaled pixels, the source pixels it covers with the
+// part of it each one takes
+func contributions(srcSize int, size int) [][]contribution {
+	scale := float64(srcSize) / float64(size)
+	all := make([][]contribution, size)
+	for i := range all {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for j := int(start); j < srcSize && float64(j) < end; j++ {
+			covered := min(end, float64(j+1)) - max(start, float64(j))
+			if covered > 0 {
+				all[i] = append(all[i], contribution{index: j, weight: covered / scale})
+			}
+		}
+	}
+	return all
+}
+
+func clamp(v float64) uint8 {
+	v += 0.5
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return uint8(v)
+}
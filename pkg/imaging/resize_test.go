@@ -0,0 +1,101 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// filled is an image of the size in a single colour
+func filled(width int, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+func TestFitBounds(t *testing.T) {
+	cases := []struct {
+		name          string
+		width, height int
+		fit           int
+		want          image.Point
+	}{
+		{"landscape", 1000, 500, 320, image.Point{320, 160}},
+		{"portrait", 500, 1000, 320, image.Point{320, 640}},
+		{"odd ratio rounds down", 333, 100, 100, image.Point{100, 30}},
+		{"a thin strip keeps a row", 1000, 1, 10, image.Point{10, 1}},
+		{"as wide is kept", 320, 200, 320, image.Point{320, 200}},
+		{"narrower is not scaled up", 200, 100, 640, image.Point{200, 100}},
+	}
+	for _, c := range cases {
+		got := Fit(filled(c.width, c.height, color.RGBA{A: 255}), c.fit).Bounds()
+		if got.Size() != c.want || got.Min != (image.Point{}) {
+			t.Errorf("%s: bounds %v, want size %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestFillBounds(t *testing.T) {
+	cases := []struct {
+		name          string
+		width, height int
+		fillW, fillH  int
+		want          image.Point
+	}{
+		{"landscape to a square", 1000, 500, 150, 150, image.Point{150, 150}},
+		{"portrait to a square", 500, 1000, 150, 150, image.Point{150, 150}},
+		{"square to a banner", 600, 600, 300, 100, image.Point{300, 100}},
+		{"square to a column", 600, 600, 100, 300, image.Point{100, 300}},
+		{"smaller is cropped only", 100, 60, 150, 150, image.Point{60, 60}},
+		{"smaller banner is cropped only", 90, 90, 300, 100, image.Point{90, 30}},
+	}
+	for _, c := range cases {
+		got := Fill(filled(c.width, c.height, color.RGBA{A: 255}), c.fillW, c.fillH).Bounds()
+		if got.Size() != c.want {
+			t.Errorf("%s: bounds %v, want size %v", c.name, got, c.want)
+		}
+	}
+
+	// The crop is taken from the middle
+	img := filled(30, 10, color.RGBA{R: 255, A: 255})
+	for y := 0; y < 10; y++ {
+		for x := 10; x < 20; x++ {
+			img.SetRGBA(x, y, color.RGBA{G: 255, A: 255})
+		}
+	}
+	cropped := Fill(img, 10, 10)
+	if got := cropped.Bounds(); got != image.Rect(10, 0, 20, 10) {
+		t.Fatalf("crop of the middle: bounds %v", got)
+	}
+	if got := cropped.RGBAAt(10, 0); got.G != 255 || got.R != 0 {
+		t.Fatalf("crop of the middle: pixel %v", got)
+	}
+}
+
+func TestResizeKeepsColours(t *testing.T) {
+	opaque := color.RGBA{R: 200, G: 100, B: 50, A: 255}
+	for _, size := range []image.Point{{1, 1}, {7, 3}, {64, 64}, {99, 50}} {
+		scaled := resize(filled(100, 50, opaque), size.X, size.Y)
+		if got := scaled.Bounds().Size(); got != size {
+			t.Fatalf("size %v, want %v", got, size)
+		}
+		for y := 0; y < size.Y; y++ {
+			for x := 0; x < size.X; x++ {
+				if got := scaled.RGBAAt(x, y); got != opaque {
+					t.Fatalf("size %v: pixel %d,%d = %v, want %v", size, x, y, got, opaque)
+				}
+			}
+		}
+	}
+
+	// Half black, half white averages to grey where they meet
+	img := filled(4, 1, color.RGBA{A: 255})
+	for x := 2; x < 4; x++ {
+		img.SetRGBA(x, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	}
+	if got := resize(img, 1, 1).RGBAAt(0, 0); got.R < 127 || got.R > 128 || got.A != 255 {
+		t.Fatalf("average of black and white = %v", got)
+	}
+}
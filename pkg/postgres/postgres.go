@@ -50,6 +50,8 @@ func New(dsn string, log logger.Logger, opts ...Option) (*Postgres, error) {
 	for pg.connAttempts > 0 {
 		pg.DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
 			Logger: gormLogger,
+			// Unique violations come back as gorm.ErrDuplicatedKey
+			TranslateError: true,
 		})
 
 		if err == nil {
@@ -3,10 +3,22 @@ package rabbitmq
 import (
 	"context"
 	"fmt"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	// retriesHeader counts how often a message went through a retry queue
+	retriesHeader = "x-retries"
+	// originalRoutingKeyHeader keeps the key a message was published with, retried messages are
+	// dead-lettered back to the queue with its name as their routing key
+	originalRoutingKeyHeader = "x-original-routing-key"
+)
+
+// maxRetryDoublings caps the backoff at the retry delay doubled this often
+const maxRetryDoublings = 6
+
 // ConsumerHandlerFunc defines a function that handles a RabbitMQ delivery
 type ConsumerHandlerFunc func(ctx context.Context, msg amqp.Delivery) error
 
@@ -22,6 +34,8 @@ type Builder struct {
 	exclusive     bool
 	noWait        bool
 	prefetchCount int
+	retryDelay    time.Duration
+	maxRetries    int
 	args          amqp.Table
 }
 
@@ -71,6 +85,16 @@ func (b *Builder) PrefetchCount(count int) *Builder {
 	return b
 }
 
+// Retry sends messages whose handler failed to a retry queue instead of requeueing them right away. They
+// wait there for the delay, doubled on every further retry, and are dead-lettered back to the queue. Each
+// delay has a queue of its own, so a long delay never holds up a shorter one. A message that failed after
+// maxRetries retries is moved to the <queue>.dead queue and left there.
+func (b *Builder) Retry(delay time.Duration, maxRetries int) *Builder {
+	b.retryDelay = delay
+	b.maxRetries = maxRetries
+	return b
+}
+
 // Args sets additional arguments for queue/exchange declaration
 func (b *Builder) Args(args amqp.Table) *Builder {
 	b.args = args
@@ -118,6 +142,39 @@ func (b *Builder) build() error {
 		return fmt.Errorf("failed to bind queue %s to exchange %s: %w", b.queueName, b.exchangeName, err)
 	}
 
+	// Declare the retry queues, their expired messages go back to the queue through the default exchange
+	if b.retryDelay > 0 {
+		for retries := 0; retries < min(b.maxRetries, maxRetryDoublings+1); retries++ {
+			_, err = b.client.channel.QueueDeclare(
+				b.retryQueueName(retries),
+				b.durable,
+				b.autoDelete,
+				false, // exclusive
+				b.noWait,
+				amqp.Table{
+					"x-message-ttl":             b.retryDelay.Milliseconds() << retries,
+					"x-dead-letter-exchange":    "",
+					"x-dead-letter-routing-key": b.queueName,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to declare queue %s: %w", b.retryQueueName(retries), err)
+			}
+		}
+
+		_, err = b.client.channel.QueueDeclare(
+			b.deadQueueName(),
+			b.durable,
+			false, // autoDelete, dead messages wait for someone to look at them
+			false, // exclusive
+			b.noWait,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", b.deadQueueName(), err)
+		}
+	}
+
 	// Set QoS for channel
 	err = b.client.channel.Qos(b.prefetchCount, 0, false)
 	if err != nil {
@@ -155,7 +212,7 @@ func (b *Builder) Consume(handler ConsumerHandlerFunc) error {
 			err := handler(ctx, msg)
 			if err != nil {
 				b.client.logger.Error("Error processing message: %v", err)
-				msg.Nack(false, true) // Negative acknowledgment with requeue
+				b.retry(msg)
 			} else {
 				msg.Ack(false) // Acknowledge message
 			}
@@ -166,6 +223,79 @@ func (b *Builder) Consume(handler ConsumerHandlerFunc) error {
 	return nil
 }
 
+// retry hands a failed message to its retry queue, or to the dead queue once it ran out of retries. It is
+// requeued when there is no retry queue or it cannot be published to the next one.
+func (b *Builder) retry(msg amqp.Delivery) {
+	if b.retryDelay <= 0 {
+		msg.Nack(false, true) // Negative acknowledgment with requeue
+		return
+	}
+
+	queue, headers := b.retryTarget(msg)
+	if queue == b.deadQueueName() {
+		b.client.logger.Error("Message %s moved to %s after %d retries", msg.MessageId, queue, b.maxRetries)
+	}
+	err := b.client.channel.PublishWithContext(
+		context.Background(),
+		"", // default exchange, straight to the queue
+		queue,
+		false, // mandatory
+		false, // immediate
+		amqp.Publishing{
+			Headers:      headers,
+			ContentType:  msg.ContentType,
+			DeliveryMode: msg.DeliveryMode,
+			MessageId:    msg.MessageId,
+			Body:         msg.Body,
+		},
+	)
+	if err != nil {
+		b.client.logger.Error("Failed to publish message to %s: %v", queue, err)
+		msg.Nack(false, true)
+		return
+	}
+	msg.Ack(false)
+}
+
+// retryTarget returns the queue a failed message goes to next with the headers it is published with
+func (b *Builder) retryTarget(msg amqp.Delivery) (string, amqp.Table) {
+	retries := 0
+	if value, ok := msg.Headers[retriesHeader].(int32); ok {
+		retries = int(value)
+	}
+	headers := amqp.Table{}
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+	headers[retriesHeader] = int32(retries + 1)
+	if _, ok := headers[originalRoutingKeyHeader]; !ok {
+		headers[originalRoutingKeyHeader] = msg.RoutingKey
+	}
+
+	if retries >= b.maxRetries {
+		return b.deadQueueName(), headers
+	}
+	return b.retryQueueName(retries), headers
+}
+
+// retryQueueName names the queue of a retry after its delay, so a changed delay gets new queues instead of
+// clashing with the arguments of the old ones
+func (b *Builder) retryQueueName(retries int) string {
+	return fmt.Sprintf("%s.retry.%s", b.queueName, b.retryDelay<<min(retries, maxRetryDoublings))
+}
+
+func (b *Builder) deadQueueName() string {
+	return b.queueName + ".dead"
+}
+
+// RoutingKey returns the key a message was published with, also when it comes back from a retry queue
+func RoutingKey(msg amqp.Delivery) string {
+	if key, ok := msg.Headers[originalRoutingKeyHeader].(string); ok && key != "" {
+		return key
+	}
+	return msg.RoutingKey
+}
+
 // Publish publishes a message to the configured exchange
 func (b *Builder) Publish(ctx context.Context, message []byte) error {
 	// Build exchange, queue and binding if they don't exist
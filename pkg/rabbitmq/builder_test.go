@@ -0,0 +1,64 @@
+package rabbitmq
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// redeliver is the message as the queue receives it back from a retry queue, dead-lettered through the
+// default exchange with the queue name as routing key
+func redeliver(b *Builder, msg amqp.Delivery, headers amqp.Table) amqp.Delivery {
+	return amqp.Delivery{
+		Headers:    headers,
+		RoutingKey: b.queueName,
+		MessageId:  msg.MessageId,
+		Body:       msg.Body,
+	}
+}
+
+func TestRetryTargetWalksTheDelaysThenTheDeadQueue(t *testing.T) {
+	b := &Builder{queueName: "plan_notification_queue", retryDelay: time.Second, maxRetries: 4}
+	msg := amqp.Delivery{RoutingKey: "plan.subscription.expiring", MessageId: "m1", Body: []byte("{}")}
+
+	want := []string{
+		"plan_notification_queue.retry.1s",
+		"plan_notification_queue.retry.2s",
+		"plan_notification_queue.retry.4s",
+		"plan_notification_queue.retry.8s",
+		"plan_notification_queue.dead",
+	}
+	for i, wantQueue := range want {
+		queue, headers := b.retryTarget(msg)
+		if queue != wantQueue {
+			t.Fatalf("failure %d goes to %s, want %s", i+1, queue, wantQueue)
+		}
+		if retries := headers[retriesHeader]; retries != int32(i+1) {
+			t.Fatalf("failure %d: %s = %v", i+1, retriesHeader, retries)
+		}
+		msg = redeliver(b, msg, headers)
+		if key := RoutingKey(msg); key != "plan.subscription.expiring" {
+			t.Fatalf("after failure %d RoutingKey = %q, want the published key", i+1, key)
+		}
+	}
+}
+
+func TestRetryQueueDelaysStopDoubling(t *testing.T) {
+	b := &Builder{queueName: "sms_queue", retryDelay: 30 * time.Second, maxRetries: 20}
+	capped := b.retryQueueName(maxRetryDoublings)
+	if capped != "sms_queue.retry.32m0s" {
+		t.Fatalf("longest retry queue = %s", capped)
+	}
+	for retries := maxRetryDoublings; retries < b.maxRetries; retries++ {
+		if queue, _ := b.retryTarget(amqp.Delivery{Headers: amqp.Table{retriesHeader: int32(retries)}}); queue != capped {
+			t.Fatalf("retry %d goes to %s, want %s", retries+1, queue, capped)
+		}
+	}
+}
+
+func TestRoutingKeyOfAFirstDelivery(t *testing.T) {
+	if key := RoutingKey(amqp.Delivery{RoutingKey: "user.sms"}); key != "user.sms" {
+		t.Fatalf("RoutingKey = %q", key)
+	}
+}
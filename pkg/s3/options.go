@@ -0,0 +1,21 @@
+package s3
+
+import "net/http"
+
+// Option -.
+type Option func(*Client)
+
+// PathStyle addresses buckets as the first segment of the path rather than as a subdomain, which is
+// what MinIO and most self hosted stores expect
+func PathStyle(pathStyle bool) Option {
+	return func(c *Client) {
+		c.pathStyle = pathStyle
+	}
+}
+
+// HTTPClient -.
+func HTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
@@ -0,0 +1,410 @@
+// Package s3 is a small client of the S3 API, enough to store and read objects in Amazon S3 or an
+// S3-compatible store such as MinIO. Requests are signed with AWS Signature Version 4, streamed bodies
+// are sent unsigned so they do not have to be read twice.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_algorithm       = "AWS4-HMAC-SHA256"
+	_service         = "s3"
+	_unsignedPayload = "UNSIGNED-PAYLOAD"
+	_timeFormat      = "20060102T150405Z"
+	_dateFormat      = "20060102"
+)
+
+var (
+	// ErrNotFound is returned for missing buckets, keys and multipart uploads
+	ErrNotFound = errors.New("s3: not found")
+)
+
+// Error is an error answered by the store
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("s3: status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case "NoSuchKey", "NoSuchBucket", "NoSuchUpload":
+		return ErrNotFound
+	}
+	if e.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Object is the content of a stored object, its Body must be closed
+type Object struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+	ETag        string
+}
+
+// Part is an uploaded part of a multipart upload
+type Part struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// Client -.
+type Client struct {
+	endpoint  *url.URL
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+}
+
+// New -.
+func New(endpoint string, region string, accessKey string, secretKey string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3 - New - url.Parse: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("s3 - New: endpoint %q is not an http(s) url", endpoint)
+	}
+	c := &Client{
+		endpoint:  u,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    http.DefaultClient,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// EnsureBucket creates the bucket unless it exists
+func (c *Client) EnsureBucket(ctx context.Context, bucket string) error {
+	res, err := c.do(ctx, http.MethodHead, bucket, "", nil, nil, nil, 0)
+	if err == nil {
+		res.Body.Close()
+		return nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	var body []byte
+	if c.region != "" && c.region != "us-east-1" {
+		body = []byte(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>` +
+			c.region + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+	res, err = c.do(ctx, http.MethodPut, bucket, "", nil, nil, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// PutObject stores size bytes of body under key
+func (c *Client) PutObject(ctx context.Context, bucket string, key string, body io.Reader, size int64, contentType string) (string, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	res, err := c.do(ctx, http.MethodPut, bucket, key, nil, header, body, size)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	return res.Header.Get("ETag"), nil
+}
+
+// GetObject reads the object stored under key
+func (c *Client) GetObject(ctx context.Context, bucket string, key string) (*Object, error) {
+	res, err := c.do(ctx, http.MethodGet, bucket, key, nil, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Object{
+		Body:        res.Body,
+		Size:        res.ContentLength,
+		ContentType: res.Header.Get("Content-Type"),
+		ETag:        res.Header.Get("ETag"),
+	}, nil
+}
+
+// DeleteObject removes the object stored under key, a missing object is not an error
+func (c *Client) DeleteObject(ctx context.Context, bucket string, key string) error {
+	res, err := c.do(ctx, http.MethodDelete, bucket, key, nil, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// CopyObject copies the object stored under srcKey to dstKey of the same bucket, up to 5 GB
+func (c *Client) CopyObject(ctx context.Context, bucket string, srcKey string, dstKey string) error {
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", encodePath("/"+bucket+"/"+srcKey))
+	res, err := c.do(ctx, http.MethodPut, bucket, dstKey, nil, header, nil, 0)
+	if err != nil {
+		return err
+	}
+	return lateError(res)
+}
+
+// CreateMultipartUpload starts a multipart upload to key and returns its upload id
+func (c *Client) CreateMultipartUpload(ctx context.Context, bucket string, key string, contentType string) (string, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	res, err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, header, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var result struct {
+		UploadId string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(res.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("s3 - CreateMultipartUpload: %w", err)
+	}
+	return result.UploadId, nil
+}
+
+// UploadPart stores size bytes of body as part partNumber of the upload and returns its ETag. Parts
+// but the last one must be 5 MB at least.
+func (c *Client) UploadPart(ctx context.Context, bucket string, key string, uploadId string, partNumber int, body io.Reader, size int64) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadId}}
+	res, err := c.do(ctx, http.MethodPut, bucket, key, query, nil, body, size)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	return res.Header.Get("ETag"), nil
+}
+
+// CompleteMultipartUpload joins the parts, in the order of their numbers, into the object
+func (c *Client) CompleteMultipartUpload(ctx context.Context, bucket string, key string, uploadId string, parts []Part) error {
+	sorted := append([]Part(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []Part   `xml:"Part"`
+	}{Parts: sorted})
+	if err != nil {
+		return err
+	}
+
+	res, err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {uploadId}}, nil, bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+	return lateError(res)
+}
+
+// AbortMultipartUpload drops the upload and its parts
+func (c *Client) AbortMultipartUpload(ctx context.Context, bucket string, key string, uploadId string) error {
+	res, err := c.do(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {uploadId}}, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+// do sends a signed request and returns the answer when it succeeded, the store's error otherwise
+func (c *Client) do(ctx context.Context, method string, bucket string, key string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u := *c.endpoint
+	u.RawQuery = canonicalQuery(query)
+	path := strings.TrimSuffix(u.Path, "/")
+	if c.pathStyle {
+		path += "/" + bucket
+	} else {
+		u.Host = bucket + "." + u.Host
+	}
+	if key != "" {
+		path += "/" + key
+	}
+	if path == "" {
+		path = "/"
+	}
+	u.Path, u.RawPath = path, encodePath(path)
+
+	if body == nil || size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	payloadHash := _unsignedPayload
+	if r, ok := body.(*bytes.Reader); ok {
+		// Small bodies built here are signed, they can be read again
+		payload := make([]byte, r.Len())
+		if _, err := r.Read(payload); err != nil && err != io.EOF {
+			return nil, err
+		}
+		r.Reset(payload)
+		payloadHash = hashHex(payload)
+	} else if body == http.NoBody {
+		payloadHash = hashHex(nil)
+	}
+	c.sign(req, payloadHash, time.Now().UTC())
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	failure := &Error{StatusCode: res.StatusCode}
+	if method != http.MethodHead {
+		_ = xml.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(failure)
+	}
+	if failure.Code == "" {
+		failure.Code = http.StatusText(res.StatusCode)
+	}
+	return nil, failure
+}
+
+// lateError closes the answer of a request the store may fail after it answered 200, the error is then
+// the body of the answer
+func lateError(res *http.Response) error {
+	defer res.Body.Close()
+	answer, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	var failure struct {
+		XMLName xml.Name
+		Error
+	}
+	if xml.Unmarshal(answer, &failure) == nil && failure.XMLName.Local == "Error" {
+		failure.Error.StatusCode = res.StatusCode
+		return &failure.Error
+	}
+	return nil
+}
+
+// sign adds the Signature Version 4 authorization of the request
+func (c *Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format(_timeFormat)
+	scope := now.Format(_dateFormat) + "/" + c.region + "/" + _service + "/aws4_request"
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"host"}
+	for name := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			signed = append(signed, name)
+		}
+	}
+	sort.Strings(signed)
+	var headers strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers.String(),
+		strings.Join(signed, ";"),
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{_algorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+c.secretKey), now.Format(_dateFormat))
+	key = hmacSha256(key, c.region)
+	key = hmacSha256(key, _service)
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		_algorithm, c.accessKey, scope, strings.Join(signed, ";"), signature))
+}
+
+// canonicalQuery encodes the query sorted by name, the way it is signed
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, encode(name, true)+"="+encode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func encodePath(path string) string {
+	return encode(path, false)
+}
+
+// encode escapes every byte but the unreserved characters of RFC 3986, and slashes unless encodeSlash
+func encode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if 'A' <= ch && ch <= 'Z' || 'a' <= ch && ch <= 'z' || '0' <= ch && ch <= '9' ||
+			ch == '-' || ch == '_' || ch == '.' || ch == '~' || ch == '/' && !encodeSlash {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,20 @@
+package scheduler
+
+import "time"
+
+// Option -.
+type Option func(*Scheduler)
+
+// JobTimeout -.
+func JobTimeout(timeout time.Duration) Option {
+	return func(s *Scheduler) {
+		s.jobTimeout = timeout
+	}
+}
+
+// ShutdownTimeout -.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Scheduler) {
+		s.shutdownTimeout = timeout
+	}
+}
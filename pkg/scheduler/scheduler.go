@@ -0,0 +1,124 @@
+// Package scheduler runs periodic background jobs.
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"site_builder_backend/pkg/logger"
+)
+
+const (
+	_defaultJobTimeout      = time.Minute
+	_defaultShutdownTimeout = 10 * time.Second
+)
+
+// JobFunc -.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler -.
+type Scheduler struct {
+	jobs   []job
+	logger logger.Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	jobTimeout      time.Duration
+	shutdownTimeout time.Duration
+}
+
+// New -.
+func New(l logger.Logger, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		logger:          l,
+		jobTimeout:      _defaultJobTimeout,
+		shutdownTimeout: _defaultShutdownTimeout,
+	}
+
+	// Custom options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Every registers a job that runs once per interval. Runs of the same job never overlap.
+// Jobs with a non positive interval are ignored so they can be disabled from the configuration.
+func (s *Scheduler) Every(name string, interval time.Duration, run JobFunc) *Scheduler {
+	if interval <= 0 {
+		s.logger.Info("scheduler - job %s is disabled", name)
+		return s
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+	return s
+}
+
+// Start -.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(s.shutdownTimeout):
+		s.logger.Warn("scheduler - Stop: jobs did not finish in %s", s.shutdownTimeout)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, j)
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("scheduler - job %s panicked: %v", j.name, r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.jobTimeout)
+	defer cancel()
+
+	if err := j.run(ctx); err != nil {
+		s.logger.Error("scheduler - job %s: %v", j.name, err)
+	}
+}
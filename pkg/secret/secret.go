@@ -0,0 +1,245 @@
+// Package secret encrypts credentials stored in the database with envelope encryption. Every value is
+// sealed with AES-GCM under its own random data key, the data key is sealed under a versioned master key
+// and the version is kept with the value so master keys can be rotated without losing older values. The
+// table, column and row of a value are authenticated along with it, so a sealed value copied to another
+// row or column does not open.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	prefix = "enc:"
+	// boundScheme seals values to their row, unboundScheme values were sealed before that and can only be
+	// opened until they are sealed again
+	boundScheme   = "r"
+	unboundScheme = "v"
+	dataKeyLen    = 32
+)
+
+var (
+	ErrUnknownKeyVersion = errors.New("secret: value was sealed with an unknown master key version")
+	ErrMalformed         = errors.New("secret: malformed sealed value")
+)
+
+// Keyring holds the master keys by version. New values are sealed with the current version, every
+// version in the ring can still be opened.
+type Keyring struct {
+	current uint32
+	keys    map[uint32]cipher.AEAD
+}
+
+// NewKeyring builds a keyring of 32 byte master keys
+func NewKeyring(keys map[uint32][]byte, current uint32) (*Keyring, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("secret - NewKeyring: no master key with version %d", current)
+	}
+	k := &Keyring{current: current, keys: make(map[uint32]cipher.AEAD, len(keys))}
+	for version, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("secret - NewKeyring: master key %d must be 32 bytes", version)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[version] = aead
+	}
+	return k, nil
+}
+
+// Load builds the keyring from master keys written as ParseKeys reads them
+func Load(spec string, current uint32) (*Keyring, error) {
+	keys, err := ParseKeys(spec)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring(keys, current)
+}
+
+// ParseKeys reads master keys written as "1:<base64>,2:<base64>"
+func ParseKeys(spec string) (map[uint32][]byte, error) {
+	keys := make(map[uint32][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		version, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("secret - ParseKeys: %q is not version:key", entry)
+		}
+		v, err := strconv.ParseUint(version, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("secret - ParseKeys: version %q: %w", version, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("secret - ParseKeys: key %d: %w", v, err)
+		}
+		keys[uint32(v)] = key
+	}
+	return keys, nil
+}
+
+// AdditionalData names where a value is stored, the value only opens there
+func AdditionalData(table string, column string, rowId string) []byte {
+	return []byte(table + "\x00" + column + "\x00" + rowId)
+}
+
+// Encrypt seals a value as "enc:r<version>:<sealed data key>:<sealed value>" for the place named by aad.
+// Empty values stay empty so an unset credential still reads as unset.
+func (k *Keyring) Encrypt(plaintext string, aad []byte) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dataKey := make([]byte, dataKeyLen)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := seal(aead, []byte(plaintext), aad)
+	if err != nil {
+		return "", err
+	}
+	sealedKey, err := seal(k.keys[k.current], dataKey, aad)
+	if err != nil {
+		return "", err
+	}
+	return prefix + boundScheme + strconv.FormatUint(uint64(k.current), 10) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedKey) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedValue), nil
+}
+
+// Decrypt opens a value sealed for the place named by aad. Values that are not sealed, including those that
+// merely start like one, were stored before encryption was introduced and are returned as they are until
+// they are encrypted again. A sealed value that does not open, such as one copied from another row, is an
+// error.
+func (k *Keyring) Decrypt(value string, aad []byte) (string, error) {
+	parsed, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+	master, found := k.keys[parsed.version]
+	if !found {
+		return "", ErrUnknownKeyVersion
+	}
+	if !parsed.bound {
+		aad = nil
+	}
+	dataKey, err := open(master, parsed.sealedKey, aad)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, parsed.sealedValue, aad)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Current tells whether a value is sealed to its row with the current master key, empty values count as
+// current
+func (k *Keyring) Current(value string) bool {
+	parsed, ok := parse(value)
+	return value == "" || (ok && parsed.bound && parsed.version == k.current)
+}
+
+// Sealed tells whether a value is sealed rather than stored in plaintext
+func Sealed(value string) bool {
+	_, ok := parse(value)
+	return ok
+}
+
+// LooksSealed tells whether a plaintext value starts like a sealed one, as a credential might by chance
+func LooksSealed(value string) bool {
+	_, ok := parse(value)
+	return !ok && strings.HasPrefix(value, prefix)
+}
+
+// Version returns the master key version a value was sealed with, ok is false for plaintext values
+func Version(value string) (version uint32, ok bool) {
+	parsed, ok := parse(value)
+	return parsed.version, ok
+}
+
+type envelope struct {
+	version     uint32
+	bound       bool
+	sealedKey   []byte
+	sealedValue []byte
+}
+
+// parse reads a sealed value, ok is false for anything else
+func parse(value string) (envelope, bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return envelope{}, false
+	}
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return envelope{}, false
+	}
+	var parsed envelope
+	switch parts[0][:1] {
+	case boundScheme:
+		parsed.bound = true
+	case unboundScheme:
+	default:
+		return envelope{}, false
+	}
+	v, err := strconv.ParseUint(parts[0][1:], 10, 32)
+	if err != nil {
+		return envelope{}, false
+	}
+	parsed.version = uint32(v)
+	if parsed.sealedKey, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return envelope{}, false
+	}
+	if parsed.sealedValue, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return envelope{}, false
+	}
+	return parsed, true
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal prepends the random nonce to the ciphertext
+func seal(aead cipher.AEAD, plaintext []byte, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, fmt.Errorf("secret - open: %w", err)
+	}
+	return plaintext, nil
+}
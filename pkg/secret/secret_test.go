@@ -0,0 +1,107 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func testKeyring(t *testing.T, current uint32) *Keyring {
+	t.Helper()
+	keyring, err := NewKeyring(map[uint32][]byte{
+		1: bytes.Repeat([]byte{1}, 32),
+		2: bytes.Repeat([]byte{2}, 32),
+	}, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func TestEncryptBindsToRow(t *testing.T) {
+	keyring := testKeyring(t, 1)
+	aad := AdditionalData("Payment.Gateways", "Saman_Password", "7")
+	sealed, err := keyring.Encrypt("hunter2", aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !keyring.Current(sealed) || !Sealed(sealed) {
+		t.Fatalf("%q is not current", sealed)
+	}
+
+	plaintext, err := keyring.Decrypt(sealed, aad)
+	if err != nil || plaintext != "hunter2" {
+		t.Fatalf("Decrypt() = %q, %v", plaintext, err)
+	}
+	for _, other := range [][]byte{
+		AdditionalData("Payment.Gateways", "Saman_Password", "8"),
+		AdditionalData("Payment.Gateways", "Mellat_UserPassword", "7"),
+		AdditionalData("User.Users", "Saman_Password", "7"),
+	} {
+		if _, err := keyring.Decrypt(sealed, other); err == nil {
+			t.Fatalf("Decrypt() opened the value for %q", other)
+		}
+	}
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	keyring := testKeyring(t, 1)
+	for _, value := range []string{"", "hunter2", "enc:v1:not sealed", "enc:v1:abc:def:ghi", "enc:x1:YWJj:YWJj", "enc:vx:YWJj:YWJj"} {
+		plaintext, err := keyring.Decrypt(value, nil)
+		if err != nil || plaintext != value {
+			t.Fatalf("Decrypt(%q) = %q, %v", value, plaintext, err)
+		}
+		if value != "" && keyring.Current(value) {
+			t.Fatalf("plaintext %q is current", value)
+		}
+	}
+	if !LooksSealed("enc:v1:not sealed") || LooksSealed("hunter2") {
+		t.Fatal("LooksSealed() does not tell look-alikes")
+	}
+}
+
+func TestDecryptUnbound(t *testing.T) {
+	keyring := testKeyring(t, 1)
+	dataKey := bytes.Repeat([]byte{9}, dataKeyLen)
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealedValue, _ := seal(aead, []byte("hunter2"), nil)
+	sealedKey, _ := seal(keyring.keys[1], dataKey, nil)
+	unbound := prefix + unboundScheme + strconv.Itoa(1) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealedKey) + ":" + base64.RawStdEncoding.EncodeToString(sealedValue)
+
+	plaintext, err := keyring.Decrypt(unbound, AdditionalData("User.Users", "Smtp_Password", "3"))
+	if err != nil || plaintext != "hunter2" {
+		t.Fatalf("Decrypt() = %q, %v", plaintext, err)
+	}
+	if keyring.Current(unbound) {
+		t.Fatal("a value sealed before binding is current")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	aad := AdditionalData("User.Users", "Smtp_Password", "3")
+	sealed, err := testKeyring(t, 1).Encrypt("hunter2", aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := testKeyring(t, 2)
+	if rotated.Current(sealed) {
+		t.Fatal("a value of the older key is current")
+	}
+	if plaintext, err := rotated.Decrypt(sealed, aad); err != nil || plaintext != "hunter2" {
+		t.Fatalf("Decrypt() = %q, %v", plaintext, err)
+	}
+
+	only, err := NewKeyring(map[uint32][]byte{2: bytes.Repeat([]byte{2}, 32)}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := only.Decrypt(sealed, aad); !errors.Is(err, ErrUnknownKeyVersion) {
+		t.Fatalf("Decrypt() error = %v, want ErrUnknownKeyVersion", err)
+	}
+}
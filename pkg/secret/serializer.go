@@ -0,0 +1,73 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is used in gorm tags as `gorm:"serializer:secret"` on string fields
+const SerializerName = "secret"
+
+// Serializer encrypts string fields on their way into the database and decrypts them when read. Values are
+// sealed to their table, column and primary key, so rows have to exist before a secret is written to them
+// and their primary key has to be read along with their secrets.
+type Serializer struct {
+	keyring *Keyring
+}
+
+// RegisterSerializer makes the keyring seal every field tagged with the secret serializer. It has to run
+// before the first query on such an entity.
+func RegisterSerializer(keyring *Keyring) {
+	schema.RegisterSerializer(SerializerName, Serializer{keyring: keyring})
+}
+
+func (s Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var sealed string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		sealed = v
+	case []byte:
+		sealed = string(v)
+	default:
+		return fmt.Errorf("secret - Serializer - Scan: unsupported value %T of %s", dbValue, field.Name)
+	}
+	rowId := primaryKey(ctx, field, dst)
+	if rowId == "" && Sealed(sealed) {
+		return fmt.Errorf("secret - Serializer - Scan %s: the primary key has to be read before the secret", field.Name)
+	}
+	plaintext, err := s.keyring.Decrypt(sealed, AdditionalData(field.Schema.Table, field.DBName, rowId))
+	if err != nil {
+		return fmt.Errorf("secret - Serializer - Scan %s: %w", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (s Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("secret - Serializer - Value: %s is not a string", field.Name)
+	}
+	rowId := primaryKey(ctx, field, dst)
+	if rowId == "" && plaintext != "" {
+		return nil, fmt.Errorf("secret - Serializer - Value %s: the row has to be stored before its secret", field.Name)
+	}
+	return s.keyring.Encrypt(plaintext, AdditionalData(field.Schema.Table, field.DBName, rowId))
+}
+
+// primaryKey returns the primary key of the row dst as text, empty while it is not set
+func primaryKey(ctx context.Context, field *schema.Field, dst reflect.Value) string {
+	key := field.Schema.PrioritizedPrimaryField
+	if key == nil || !dst.IsValid() {
+		return ""
+	}
+	value, zero := key.ValueOf(ctx, dst)
+	if zero {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
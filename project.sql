@@ -12,6 +12,8 @@ create table Blog.Articles
     Rate         int                                       not null,
     Badges       longtext                                  null,
     SeoTags      longtext                                  null,
+    Status       varchar(20)  default 'draft'              not null,
+    PublishedAt  datetime(6)                               null,
     UserId       bigint                                    not null,
     CreatedAt    datetime(6)                               not null,
     UpdatedAt    datetime(6)                               not null,
@@ -20,6 +22,12 @@ create table Blog.Articles
     DeletedAt    datetime(6)                               null
 );
 
+-- Slugs are unique among the live articles of a site, deleted articles keep theirs. The partial
+-- unique index IX_Articles_SiteId_Slug is created by the Postgres migrations in migrations/.
+
+create index IX_Articles_SiteId_Status_PublishedAt
+    on Blog.Articles (SiteId, Status, PublishedAt);
+
 create table Blog.ArticleMedia
 (
     Id        bigint auto_increment
@@ -34,6 +42,43 @@ create table Blog.ArticleMedia
 create index IX_ArticleMedia_ArticleId
     on Blog.ArticleMedia (ArticleId);
 
+create table Blog.ArticleComments
+(
+    Id         bigint auto_increment
+        primary key,
+    ArticleId  bigint                                    not null,
+    SiteId     bigint                                    not null,
+    ParentId   bigint                                    null,
+    RootId     bigint                                    null,
+    CustomerId bigint                                    null,
+    GuestName  varchar(100)                              null,
+    GuestEmail varchar(255)                              null,
+    Body       longtext                                  not null,
+    Rating     int          default 0                    not null,
+    Status     varchar(20)  default 'pending'            not null,
+    IpAddress  varchar(45)                               null,
+    UserAgent  longtext                                  null,
+    CreatedAt  datetime(6)                               not null,
+    UpdatedAt  datetime(6)                               not null,
+    Version    timestamp(6) default current_timestamp(6) not null on update current_timestamp(6),
+    IsDeleted  tinyint(1)                                not null,
+    DeletedAt  datetime(6)                               null,
+    constraint FK_ArticleComments_Articles_ArticleId
+        foreign key (ArticleId) references Blog.Articles (Id)
+            on delete cascade,
+    constraint FK_ArticleComments_ArticleComments_ParentId
+        foreign key (ParentId) references Blog.ArticleComments (Id)
+);
+
+create index IX_ArticleComments_ArticleId_Status
+    on Blog.ArticleComments (ArticleId, Status);
+
+create index IX_ArticleComments_RootId
+    on Blog.ArticleComments (RootId);
+
+create index IX_ArticleComments_SiteId_Status
+    on Blog.ArticleComments (SiteId, Status);
+
 create table `Order`.Baskets
 (
     Id                           bigint auto_increment
@@ -335,6 +380,7 @@ create table Drive.FileItems
     Version     timestamp(6) default current_timestamp(6) not null on update current_timestamp(6),
     IsDeleted   tinyint(1)                                not null,
     DeletedAt   datetime(6)                               null,
+    ImageStatus varchar(20)  default ''                   not null,
     constraint FK_FileItems_FileItems_ParentId
         foreign key (ParentId) references Drive.FileItems (Id)
 );
@@ -342,6 +388,9 @@ create table Drive.FileItems
 create index IX_FileItems_ParentId
     on Drive.FileItems (ParentId);
 
+create index IX_FileItems_UserId_ParentId
+    on Drive.FileItems (UserId, ParentId);
+
 create table Payment.Gateways
 (
     Id                                   bigint auto_increment
@@ -437,13 +486,22 @@ create table `Order`.Orders
     DiscountId                   bigint                                    null,
     AddressId                    bigint                                    not null,
     CustomerId                   bigint                                    not null,
+    IdempotencyKey               varchar(100)                              null,
     CreatedAt                    datetime(6)                               not null,
     UpdatedAt                    datetime(6)                               not null,
     Version                      timestamp(6) default current_timestamp(6) not null on update current_timestamp(6),
     IsDeleted                    tinyint(1)                                not null,
-    DeletedAt                    datetime(6)                               null
+    DeletedAt                    datetime(6)                               null,
+    constraint IX_Orders_SiteId_CustomerId_IdempotencyKey
+        unique (SiteId, CustomerId, IdempotencyKey)
 );
 
+create index IX_Orders_SiteId_OrderStatus
+    on `Order`.Orders (SiteId, OrderStatus(20));
+
+create index IX_Orders_CustomerId
+    on `Order`.Orders (CustomerId);
+
 create table `Order`.OrderItems
 (
     Id                           bigint auto_increment
@@ -470,6 +528,82 @@ create table `Order`.OrderItems
 create index IX_OrderItems_OrderId
     on `Order`.OrderItems (OrderId);
 
+create table `Order`.InventoryReservations
+(
+    Id               bigint auto_increment
+        primary key,
+    SiteId           bigint      not null,
+    OrderId          bigint      not null,
+    ProductVariantId bigint      not null,
+    Quantity         int         not null,
+    Status           varchar(20) not null,
+    CreatedAt        datetime(6) not null,
+    UpdatedAt        datetime(6) not null,
+    constraint FK_InventoryReservations_Orders_OrderId
+        foreign key (OrderId) references `Order`.Orders (Id)
+            on delete cascade
+);
+
+create index IX_InventoryReservations_OrderId
+    on `Order`.InventoryReservations (OrderId);
+
+create table `Order`.OrderStatusHistories
+(
+    Id         bigint auto_increment
+        primary key,
+    OrderId    bigint       not null,
+    SiteId     bigint       not null,
+    FromStatus varchar(20)  null,
+    ToStatus   varchar(20)  not null,
+    ActorType  varchar(20)  not null,
+    ActorId    bigint       null,
+    Note       varchar(500) null,
+    CreatedAt  datetime(6)  not null,
+    constraint FK_OrderStatusHistories_Orders_OrderId
+        foreign key (OrderId) references `Order`.Orders (Id)
+            on delete cascade
+);
+
+create index IX_OrderStatusHistories_OrderId
+    on `Order`.OrderStatusHistories (OrderId);
+
+create table `Order`.ShippingMethods
+(
+    Id             bigint auto_increment
+        primary key,
+    SiteId         bigint       not null,
+    Code           varchar(50)  not null,
+    Title          varchar(100) not null,
+    Provider       varchar(30)  not null,
+    BasePrice      bigint       not null,
+    EstimatedDays  int          not null,
+    FreeOverAmount bigint       not null,
+    IsActive       tinyint(1)   not null,
+    CreatedAt      datetime(6)  not null,
+    UpdatedAt      datetime(6)  not null,
+    IsDeleted      tinyint(1)   not null,
+    DeletedAt      datetime(6)  null
+);
+
+create index IX_ShippingMethods_SiteId_Code
+    on `Order`.ShippingMethods (SiteId, Code);
+
+create table `Order`.ShippingRates
+(
+    Id               bigint auto_increment
+        primary key,
+    ShippingMethodId bigint not null,
+    ProvinceId       bigint null,
+    MaxWeight        int    not null,
+    Price            bigint not null,
+    constraint FK_ShippingRates_ShippingMethods_ShippingMethodId
+        foreign key (ShippingMethodId) references `Order`.ShippingMethods (Id)
+            on delete cascade
+);
+
+create index IX_ShippingRates_ShippingMethodId
+    on `Order`.ShippingRates (ShippingMethodId);
+
 create table Site.PageArticleUsages
 (
     Id        bigint auto_increment
@@ -564,7 +698,9 @@ create table Payment.ParbadPayments
     GatewayName        longtext        null,
     GatewayAccountName longtext        null,
     IsCompleted        tinyint(1)      not null,
-    IsPaid             tinyint(1)      not null
+    IsPaid             tinyint(1)      not null,
+    constraint IX_ParbadPayments_TrackingNumber
+        unique (TrackingNumber)
 );
 
 create table Payment.ParbadTransactions
@@ -574,11 +710,24 @@ create table Payment.ParbadTransactions
     Amount         decimal(65, 30)  not null,
     Type           tinyint unsigned not null,
     IsSucceed      tinyint(1)       not null,
+    IsPending      tinyint(1)       default 0 not null,
     Message        longtext         null,
     AdditionalData longtext         null,
-    PaymentId      bigint           not null
+    PaymentId      bigint           not null,
+    IdempotencyKey varchar(100)     null,
+    CreatedAt      datetime(6)      not null,
+    constraint FK_ParbadTransactions_ParbadPayments_PaymentId
+        foreign key (PaymentId) references Payment.ParbadPayments (Id)
+            on delete cascade
 );
 
+create index IX_ParbadTransactions_PaymentId
+    on Payment.ParbadTransactions (PaymentId);
+
+-- A refund key is refunded at most once per attempt, transactions without a key are left out
+create unique index IX_ParbadTransactions_PaymentId_IdempotencyKey
+    on Payment.ParbadTransactions (PaymentId, IdempotencyKey);
+
 create table Payment.Payments
 (
     Id                  bigint auto_increment
@@ -599,6 +748,7 @@ create table Payment.Payments
     Message             longtext                                  null,
     GatewayResponseCode longtext                                  null,
     TransactionCode     longtext                                  null,
+    GatewayToken        varchar(200)                              null,
     AdditionalData      longtext                                  null,
     OrderData           longtext                                  null,
     UserId              bigint                                    not null,
@@ -610,6 +760,12 @@ create table Payment.Payments
     DeletedAt           datetime(6)                               null
 );
 
+create index IX_Payments_OrderId
+    on Payment.Payments (OrderId);
+
+create index IX_Payments_TrackingNumber
+    on Payment.Payments (TrackingNumber);
+
 create table User.Permissions
 (
     Id   bigint auto_increment
@@ -656,6 +812,28 @@ create table Product.ProductReviews
     DeletedAt  datetime(6)                               null
 );
 
+create index IX_ProductReviews_ProductId_Approved
+    on Product.ProductReviews (ProductId, Approved);
+
+create index IX_ProductReviews_SiteId_Approved
+    on Product.ProductReviews (SiteId, Approved);
+
+create table Product.ProductReviewVotes
+(
+    Id         bigint auto_increment
+        primary key,
+    ReviewId   bigint      not null,
+    CustomerId bigint      not null,
+    IsLike     tinyint(1)  not null,
+    CreatedAt  datetime(6) not null,
+    UpdatedAt  datetime(6) not null,
+    constraint IX_ProductReviewVotes_ReviewId_CustomerId
+        unique (ReviewId, CustomerId),
+    constraint FK_ProductReviewVotes_ProductReviews_ReviewId
+        foreign key (ReviewId) references Product.ProductReviews (Id)
+            on delete cascade
+);
+
 create table Product.Products
 (
     Id              bigint auto_increment
@@ -877,6 +1055,10 @@ create table `Order`.ReturnItem
         primary key,
     ReturnReason longtext                                  not null,
     OrderStatus  int                                       not null,
+    OwnerNote    varchar(500)                              null,
+    RefundAmount bigint                                    not null,
+    SiteId       bigint                                    not null,
+    OrderId      bigint                                    not null,
     OrderItemId  bigint                                    not null,
     ProductId    bigint                                    not null,
     UserId       bigint                                    not null,
@@ -893,6 +1075,12 @@ create table `Order`.ReturnItem
             on delete cascade
 );
 
+create index IX_ReturnItem_SiteId_OrderStatus
+    on `Order`.ReturnItem (SiteId, OrderStatus);
+
+create index IX_ReturnItem_OrderId
+    on `Order`.ReturnItem (OrderId);
+
 create table User.Roles
 (
     Id   bigint auto_increment
@@ -960,6 +1148,31 @@ create index IX_RolePlan_PlanId
 create index IX_RolePlan_RoleId
     on User.RolePlan (RoleId);
 
+create table User.PlanPurchases
+(
+    Id        bigint auto_increment
+        primary key,
+    UserId    bigint      not null,
+    PlanId    bigint      not null,
+    Action    varchar(20) not null,
+    Amount    bigint      not null,
+    Credit    bigint      not null,
+    Status    varchar(20) not null,
+    StartsAt  datetime(6) null,
+    ExpiresAt datetime(6) null,
+    CreatedAt datetime(6) not null,
+    UpdatedAt datetime(6) not null,
+    constraint FK_PlanPurchases_Plans_PlanId
+        foreign key (PlanId) references User.Plans (Id)
+            on delete cascade
+);
+
+create index IX_PlanPurchases_PlanId
+    on User.PlanPurchases (PlanId);
+
+create index IX_PlanPurchases_UserId
+    on User.PlanPurchases (UserId);
+
 create table Site.Sites
 (
     Id         bigint auto_increment
@@ -979,16 +1192,17 @@ create table Site.Sites
 
 create table Site.Settings
 (
-    Id         bigint auto_increment
+    Id               bigint auto_increment
         primary key,
-    SiteId     bigint                                    not null,
-    UserId     bigint                                    not null,
-    CustomerId bigint                                    not null,
-    CreatedAt  datetime(6)                               not null,
-    UpdatedAt  datetime(6)                               not null,
-    Version    timestamp(6) default current_timestamp(6) not null on update current_timestamp(6),
-    IsDeleted  tinyint(1)                                not null,
-    DeletedAt  datetime(6)                               null,
+    SiteId           bigint                                    not null,
+    UserId           bigint                                    not null,
+    CustomerId       bigint                                    not null,
+    ReturnWindowDays int          default 14                   not null,
+    CreatedAt        datetime(6)                               not null,
+    UpdatedAt        datetime(6)                               not null,
+    Version          timestamp(6) default current_timestamp(6) not null on update current_timestamp(6),
+    IsDeleted        tinyint(1)                                not null,
+    DeletedAt        datetime(6)                               null,
     constraint IX_Settings_SiteId
         unique (SiteId),
     constraint FK_Settings_Sites_SiteId
@@ -996,6 +1210,27 @@ create table Site.Settings
             on delete cascade
 );
 
+create table Site.NotificationTemplates
+(
+    Id        bigint auto_increment
+        primary key,
+    SiteId    bigint        not null,
+    UserId    bigint        not null,
+    Channel   varchar(10)   not null,
+    Event     varchar(50)   not null,
+    Locale    varchar(5)    not null,
+    Subject   varchar(250)  null,
+    Body      varchar(2000) not null,
+    Html      longtext      null,
+    CreatedAt datetime(6)   not null,
+    UpdatedAt datetime(6)   not null,
+    constraint IX_NotificationTemplates_SiteId_Channel_Event_Locale
+        unique (SiteId, Channel, Event, Locale),
+    constraint FK_NotificationTemplates_Sites_SiteId
+        foreign key (SiteId) references Site.Sites (Id)
+            on delete cascade
+);
+
 create table Drive.Storages
 (
     Id          bigint auto_increment
@@ -1012,6 +1247,75 @@ create table Drive.Storages
     DeletedAt   datetime(6)                               null
 );
 
+create table Drive.Uploads
+(
+    Id              bigint auto_increment
+        primary key,
+    UserId          bigint        not null,
+    Name            varchar(255)  not null,
+    ParentId        bigint        null,
+    MimeType        varchar(255)  not null,
+    Size            bigint        not null,
+    PartSize        bigint        not null,
+    BucketName      varchar(63)   not null,
+    ServerKey       varchar(500)  not null,
+    StorageUploadId varchar(1000) not null,
+    ReservationId   varchar(40)   null,
+    Status          varchar(20)   not null,
+    FileItemId      bigint        null,
+    ExpiresAt       datetime(6)   not null,
+    CreatedAt       datetime(6)   not null,
+    UpdatedAt       datetime(6)   not null,
+    constraint FK_Uploads_FileItems_FileItemId
+        foreign key (FileItemId) references Drive.FileItems (Id)
+            on delete set null
+);
+
+create index IX_Uploads_UserId
+    on Drive.Uploads (UserId);
+
+create index IX_Uploads_Status_ExpiresAt
+    on Drive.Uploads (Status, ExpiresAt);
+
+create table Drive.UploadParts
+(
+    Id         bigint auto_increment
+        primary key,
+    UploadId   bigint       not null,
+    PartNumber int          not null,
+    Size       bigint       not null,
+    ETag       varchar(100) not null,
+    CreatedAt  datetime(6)  not null,
+    constraint IX_UploadParts_UploadId_PartNumber
+        unique (UploadId, PartNumber),
+    constraint FK_UploadParts_Uploads_UploadId
+        foreign key (UploadId) references Drive.Uploads (Id)
+            on delete cascade
+);
+
+create table Drive.FileVariants
+(
+    Id         bigint auto_increment
+        primary key,
+    FileItemId bigint       not null,
+    Name       varchar(20)  not null,
+    Width      int          not null,
+    Height     int          not null,
+    Size       bigint       not null,
+    MimeType   varchar(100) not null,
+    BucketName longtext     not null,
+    ServerKey  longtext     not null,
+    CreatedAt  datetime(6)  not null,
+    constraint IX_FileVariants_FileItemId_Name
+        unique (FileItemId, Name),
+    constraint FK_FileVariants_FileItems_FileItemId
+        foreign key (FileItemId) references Drive.FileItems (Id)
+            on delete cascade
+);
+
+create index IX_FileItems_ImageStatus
+    on Drive.FileItems (ImageStatus, UpdatedAt);
+
 create table Support.Tickets
 (
     Id         bigint auto_increment
@@ -1065,6 +1369,78 @@ create table Support.TicketMedia
 create index IX_TicketMedia_TicketId
     on Support.TicketMedia (TicketId);
 
+create table User.EmailMessages
+(
+    Id        bigint auto_increment
+        primary key,
+    MessageId varchar(40)  not null,
+    UserId    bigint       not null,
+    Recipient varchar(254) not null,
+    Subject   varchar(500) not null,
+    Template  varchar(50)  null,
+    Smtp      varchar(20)  null,
+    Status    varchar(20)  not null,
+    Error     varchar(500) null,
+    Attempts  int          not null,
+    SentAt    datetime(6)  null,
+    CreatedAt datetime(6)  not null,
+    UpdatedAt datetime(6)  not null,
+    constraint IX_EmailMessages_MessageId
+        unique (MessageId)
+);
+
+create index IX_EmailMessages_UserId
+    on User.EmailMessages (UserId);
+
+create table User.Notifications
+(
+    Id            bigint auto_increment
+        primary key,
+    UserId        bigint        not null,
+    SiteId        bigint        not null,
+    Type          varchar(30)   not null,
+    Title         varchar(200)  not null,
+    Body          varchar(1000) not null,
+    ReferenceType varchar(30)   null,
+    ReferenceId   bigint        null,
+    EventKey      varchar(100)  not null,
+    IsRead        tinyint(1)    not null,
+    ReadAt        datetime(6)   null,
+    CreatedAt     datetime(6)   not null,
+    constraint IX_Notifications_EventKey
+        unique (EventKey),
+    constraint FK_Notifications_Users_UserId
+        foreign key (UserId) references User.Users (Id)
+            on delete cascade
+);
+
+create index IX_Notifications_UserId_IsRead
+    on User.Notifications (UserId, IsRead);
+
+create table User.SmsMessages
+(
+    Id                bigint auto_increment
+        primary key,
+    MessageId         varchar(40)   not null,
+    UserId            bigint        not null,
+    Phone             varchar(20)   not null,
+    Message           varchar(1000) not null,
+    Parts             int           not null,
+    Provider          varchar(20)   null,
+    ProviderMessageId varchar(100)  null,
+    Status            varchar(20)   not null,
+    Error             varchar(500)  null,
+    Attempts          int           not null,
+    SentAt            datetime(6)   null,
+    CreatedAt         datetime(6)   not null,
+    UpdatedAt         datetime(6)   not null,
+    constraint IX_SmsMessages_MessageId
+        unique (MessageId)
+);
+
+create index IX_SmsMessages_UserId
+    on User.SmsMessages (UserId);
+
 create table User.UnitPrices
 (
     Id           bigint auto_increment
@@ -1076,6 +1452,60 @@ create table User.UnitPrices
     Discount     bigint     null
 );
 
+create table User.CreditLedger
+(
+    Id            bigint auto_increment
+        primary key,
+    UserId        bigint      not null,
+    CreditType    varchar(20) not null,
+    Amount        int         not null,
+    Balance       int         not null,
+    Reason        varchar(20) not null,
+    ReferenceType varchar(30) null,
+    ReferenceId   bigint      null,
+    CreatedAt     datetime(6) not null
+);
+
+create index IX_CreditLedger_UserId_CreditType
+    on User.CreditLedger (UserId, CreditType);
+
+create table User.CreditUsages
+(
+    Id            bigint auto_increment
+        primary key,
+    UserId        bigint      not null,
+    CreditType    varchar(20) not null,
+    Amount        bigint      not null,
+    Action        varchar(50) not null,
+    ReservationId varchar(40) not null,
+    CreatedAt     datetime(6) not null,
+    constraint IX_CreditUsages_ReservationId
+        unique (ReservationId)
+);
+
+create index IX_CreditUsages_UserId_CreditType
+    on User.CreditUsages (UserId, CreditType);
+
+create table User.CreditTopUps
+(
+    Id          bigint auto_increment
+        primary key,
+    UserId      bigint      not null,
+    UnitPriceId bigint      not null,
+    CreditType  varchar(20) not null,
+    Quantity    int         not null,
+    Days        int         not null,
+    Amount      bigint      not null,
+    Status      varchar(20) not null,
+    CreatedAt   datetime(6) not null,
+    UpdatedAt   datetime(6) not null,
+    constraint FK_CreditTopUps_UnitPrices_UnitPriceId
+        foreign key (UnitPriceId) references User.UnitPrices (Id)
+);
+
+create index IX_CreditTopUps_UserId
+    on User.CreditTopUps (UserId);
+
 create table User.Users
 (
     Id                       bigint auto_increment
@@ -1096,6 +1526,7 @@ create table User.Users
     PlanId                   bigint                                    null,
     PlanStartedAt            datetime(6)                               null,
     PlanExpiredAt            datetime(6)                               null,
+    PlanRemindedAt           datetime(6)                               null,
     VerifyCode               int                                       null,
     ExpireVerifyCodeAt       datetime(6)                               null,
     AiCredits                int                                       not null,